@@ -31,9 +31,11 @@ import (
 	"github.com/alchemillahq/sylve/internal/services/cluster"
 	"github.com/alchemillahq/sylve/internal/services/disk"
 	"github.com/alchemillahq/sylve/internal/services/info"
+	"github.com/alchemillahq/sylve/internal/services/iscsi"
 	"github.com/alchemillahq/sylve/internal/services/jail"
 	"github.com/alchemillahq/sylve/internal/services/libvirt"
 	"github.com/alchemillahq/sylve/internal/services/network"
+	"github.com/alchemillahq/sylve/internal/services/nfs"
 	"github.com/alchemillahq/sylve/internal/services/samba"
 	"github.com/alchemillahq/sylve/internal/services/system"
 	"github.com/alchemillahq/sylve/internal/services/utilities"
@@ -64,6 +66,8 @@ func main() {
 	sysS := serviceRegistry.SystemService
 	lvS := serviceRegistry.LibvirtService
 	smbS := serviceRegistry.SambaService
+	nfsS := serviceRegistry.NfsService
+	iscsiS := serviceRegistry.IscsiService
 	jS := serviceRegistry.JailService
 	cS := serviceRegistry.ClusterService
 
@@ -110,6 +114,8 @@ func main() {
 		sysS.(*system.Service),
 		lvS.(*libvirt.Service),
 		smbS.(*samba.Service),
+		nfsS.(*nfs.Service),
+		iscsiS.(*iscsi.Service),
 		jS.(*jail.Service),
 		cS.(*cluster.Service),
 		fsm,
@@ -89,6 +89,11 @@ func SetupDataPath() error {
 		filepath.Join(dataPath, "vms"),
 		filepath.Join(dataPath, "jails"),
 		filepath.Join(dataPath, "raft"),
+		filepath.Join(dataPath, "vuxml"),
+		filepath.Join(dataPath, "firewall"),
+		filepath.Join(dataPath, "firewall", "tables"),
+		filepath.Join(dataPath, "dnsmasq"),
+		filepath.Join(dataPath, "wireguard"),
 		filepath.Join(dataPath, "downloads"),
 		filepath.Join(dataPath, "downloads", "torrents"),
 		filepath.Join(dataPath, "downloads", "http"),
@@ -151,6 +156,50 @@ func GetJailsPath() (string, error) {
 	return jailsPath, nil
 }
 
+func GetVuxmlPath() (string, error) {
+	dataPath, err := GetDataPath()
+	if err != nil {
+		return "", fmt.Errorf("failed to get data path: %w", err)
+	}
+
+	vuxmlPath := filepath.Join(dataPath, "vuxml", "vuln.xml")
+
+	return vuxmlPath, nil
+}
+
+func GetFirewallPath() (string, error) {
+	dataPath, err := GetDataPath()
+	if err != nil {
+		return "", fmt.Errorf("failed to get data path: %w", err)
+	}
+
+	firewallPath := filepath.Join(dataPath, "firewall")
+
+	return firewallPath, nil
+}
+
+func GetDnsmasqPath() (string, error) {
+	dataPath, err := GetDataPath()
+	if err != nil {
+		return "", fmt.Errorf("failed to get data path: %w", err)
+	}
+
+	dnsmasqPath := filepath.Join(dataPath, "dnsmasq")
+
+	return dnsmasqPath, nil
+}
+
+func GetWireGuardPath() (string, error) {
+	dataPath, err := GetDataPath()
+	if err != nil {
+		return "", fmt.Errorf("failed to get data path: %w", err)
+	}
+
+	wireguardPath := filepath.Join(dataPath, "wireguard")
+
+	return wireguardPath, nil
+}
+
 func GetRaftPath() (string, error) {
 	dataPath, err := GetDataPath()
 	if err != nil {
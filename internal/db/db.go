@@ -12,9 +12,12 @@ import (
 	"github.com/alchemillahq/sylve/internal"
 	"github.com/alchemillahq/sylve/internal/db/models"
 	clusterModels "github.com/alchemillahq/sylve/internal/db/models/cluster"
+	diskModels "github.com/alchemillahq/sylve/internal/db/models/disk"
 	infoModels "github.com/alchemillahq/sylve/internal/db/models/info"
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
 	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
 	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	nfsModels "github.com/alchemillahq/sylve/internal/db/models/nfs"
 	sambaModels "github.com/alchemillahq/sylve/internal/db/models/samba"
 	utilitiesModels "github.com/alchemillahq/sylve/internal/db/models/utilities"
 	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
@@ -68,6 +71,10 @@ func SetupDatabase(cfg *internal.SylveConfig, isTest bool) *gorm.DB {
 		&jailModels.Network{},
 		&jailModels.JailStats{},
 		&jailModels.Jail{},
+		&jailModels.DelegatedDataset{},
+		&jailModels.ResourceLimit{},
+		&jailModels.PackageVulnerability{},
+		&jailModels.PackageAuditJob{},
 
 		&models.PassedThroughIDs{},
 		&models.Triggers{},
@@ -75,6 +82,8 @@ func SetupDatabase(cfg *internal.SylveConfig, isTest bool) *gorm.DB {
 		&networkModels.Object{},
 		&networkModels.ObjectEntry{},
 		&networkModels.ObjectResolution{},
+		&networkModels.ObjectResolutionLog{},
+		&networkModels.GeoIPDatabase{},
 
 		&infoModels.CPU{},
 		&infoModels.RAM{},
@@ -85,12 +94,38 @@ func SetupDatabase(cfg *internal.SylveConfig, isTest bool) *gorm.DB {
 		&infoModels.AuditRecord{},
 
 		&infoModels.ZPoolHistorical{},
+		&infoModels.ARCHistorical{},
+		&infoModels.DatasetIOHistorical{},
 
 		&zfsModels.PeriodicSnapshot{},
+		&zfsModels.SnapshotPruneLog{},
+		&zfsModels.PoolMaintenance{},
+		&zfsModels.PoolScrubResult{},
+		&zfsModels.PoolEvent{},
+		&zfsModels.PoolHealthState{},
+
+		&diskModels.SmartTestSchedule{},
+		&diskModels.SmartReading{},
+		&diskModels.SmartThresholds{},
+		&diskModels.SmartAlert{},
 
 		&networkModels.ManualSwitch{},
 		&networkModels.StandardSwitch{},
 		&networkModels.NetworkPort{},
+		&networkModels.SwitchRoute{},
+		&networkModels.FirewallRuleSet{},
+		&networkModels.FirewallRule{},
+		&networkModels.PortForward{},
+		&networkModels.StaticRoute{},
+		&networkModels.Lagg{},
+		&networkModels.WireGuardInterface{},
+		&networkModels.WireGuardPeer{},
+		&networkModels.IPPool{},
+		&networkModels.IPPoolAllocation{},
+		&networkModels.DHCPConfig{},
+		&networkModels.DHCPRanges{},
+		&networkModels.DHCPStaticMapping{},
+		&networkModels.DHCPOption{},
 
 		&utilitiesModels.DownloadedFile{},
 		&utilitiesModels.Downloads{},
@@ -100,6 +135,14 @@ func SetupDatabase(cfg *internal.SylveConfig, isTest bool) *gorm.DB {
 		&sambaModels.SambaShare{},
 		&sambaModels.SambaAuditLog{},
 
+		&nfsModels.NFSSettings{},
+		&nfsModels.NFSExport{},
+
+		&iscsiModels.ISCSISettings{},
+		&iscsiModels.ISCSIPortalGroup{},
+		&iscsiModels.ISCSITarget{},
+		&iscsiModels.ISCSILUN{},
+
 		&clusterModels.Cluster{},
 		&clusterModels.ClusterNode{},
 		&clusterModels.ClusterS3Config{},
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package diskModels
+
+import "time"
+
+const (
+	SelfTestShort = "short"
+	SelfTestLong  = "long"
+)
+
+type SmartTestSchedule struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	DiskUUID  string    `gorm:"uniqueIndex:uniq_disk_test,priority:1" json:"diskUuid"`
+	Serial    string    `json:"serial"`
+	TestType  string    `gorm:"uniqueIndex:uniq_disk_test,priority:2" json:"testType"`
+	CronExpr  string    `json:"cronExpr"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt,omitempty"`
+	LastRunAt time.Time `json:"lastRunAt,omitempty"`
+	LastError string    `json:"lastError"`
+}
+
+// SmartReading is one sample of the attributes that predict disk failure.
+// ATA and NVMe disks only fill in the fields that apply to them.
+type SmartReading struct {
+	ID                   uint      `gorm:"primaryKey" json:"id"`
+	DiskUUID             string    `gorm:"index" json:"diskUuid"`
+	Device               string    `json:"device"`
+	Serial               string    `json:"serial"`
+	Type                 string    `json:"type"`
+	Passed               bool      `json:"passed"`
+	Temperature          int       `json:"temperature"`
+	PowerOnHours         int       `json:"powerOnHours"`
+	ReallocatedSectors   int64     `json:"reallocatedSectors"`
+	PendingSectors       int64     `json:"pendingSectors"`
+	OfflineUncorrectable int64     `json:"offlineUncorrectable"`
+	MediaErrors          int64     `json:"mediaErrors"`
+	PercentageUsed       int       `json:"percentageUsed"`
+	AvailableSpare       int       `json:"availableSpare"`
+	SelfTestFailed       bool      `json:"selfTestFailed"`
+	SelfTestResult       string    `json:"selfTestResult"`
+	CreatedAt            time.Time `gorm:"autoCreateTime;index" json:"createdAt"`
+}
+
+// SmartThresholds warn once an attribute reaches the given value; a zero
+// threshold is disabled. The row with an empty DiskUUID is the default for
+// disks without their own.
+type SmartThresholds struct {
+	ID                   uint   `gorm:"primaryKey" json:"id"`
+	DiskUUID             string `gorm:"uniqueIndex" json:"diskUuid"`
+	Temperature          int    `json:"temperature"`
+	ReallocatedSectors   int64  `json:"reallocatedSectors"`
+	PendingSectors       int64  `json:"pendingSectors"`
+	OfflineUncorrectable int64  `json:"offlineUncorrectable"`
+	MediaErrors          int64  `json:"mediaErrors"`
+	PercentageUsed       int    `json:"percentageUsed"`
+}
+
+var DefaultSmartThresholds = SmartThresholds{
+	Temperature:          55,
+	ReallocatedSectors:   1,
+	PendingSectors:       1,
+	OfflineUncorrectable: 1,
+	MediaErrors:          1,
+	PercentageUsed:       80,
+}
+
+type SmartAlert struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	DiskUUID     string    `gorm:"index" json:"diskUuid"`
+	Device       string    `json:"device"`
+	Serial       string    `json:"serial"`
+	Pool         string    `json:"pool"`
+	Vdev         string    `json:"vdev"`
+	Attribute    string    `json:"attribute"`
+	Value        int64     `json:"value"`
+	Threshold    int64     `json:"threshold"`
+	Message      string    `json:"message"`
+	Acknowledged bool      `gorm:"index" json:"acknowledged"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
@@ -39,3 +39,35 @@ type ZPoolHistorical struct {
 	Pools     ZpoolJSON `json:"pools" gorm:"type:text"`
 	CreatedAt int64     `json:"created_at" gorm:"autoCreateTime:milli"`
 }
+
+// ARCHistorical is one sample of the ARC. Sizes are gauges, hit and miss
+// counts are the deltas since the previous sample.
+type ARCHistorical struct {
+	ID               int64  `json:"id" gorm:"primaryKey"`
+	Size             uint64 `json:"size"`
+	Target           uint64 `json:"target"`
+	MFUSize          uint64 `json:"mfuSize"`
+	MRUSize          uint64 `json:"mruSize"`
+	CompressedSize   uint64 `json:"compressedSize"`
+	UncompressedSize uint64 `json:"uncompressedSize"`
+	L2Size           uint64 `json:"l2Size"`
+	Hits             uint64 `json:"hits"`
+	Misses           uint64 `json:"misses"`
+	MFUHits          uint64 `json:"mfuHits"`
+	MRUHits          uint64 `json:"mruHits"`
+	L2Hits           uint64 `json:"l2Hits"`
+	L2Misses         uint64 `json:"l2Misses"`
+	CreatedAt        int64  `json:"created_at" gorm:"autoCreateTime:milli;index"`
+}
+
+// DatasetIOHistorical holds the average throughput of a dataset over the
+// interval ending at CreatedAt.
+type DatasetIOHistorical struct {
+	ID         int64   `json:"id" gorm:"primaryKey"`
+	Dataset    string  `json:"dataset" gorm:"index"`
+	ReadBytes  float64 `json:"readBytes"`
+	WriteBytes float64 `json:"writeBytes"`
+	ReadOps    float64 `json:"readOps"`
+	WriteOps   float64 `json:"writeOps"`
+	CreatedAt  int64   `json:"created_at" gorm:"autoCreateTime:milli;index"`
+}
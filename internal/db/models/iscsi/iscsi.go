@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package iscsiModels
+
+import (
+	"time"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+)
+
+const (
+	AuthTypeNone       = "none"
+	AuthTypeCHAP       = "chap"
+	AuthTypeCHAPMutual = "chap-mutual"
+)
+
+type ISCSISettings struct {
+	ID      int  `json:"id" gorm:"primaryKey"`
+	Enabled bool `json:"enabled" gorm:"default:false"`
+}
+
+// ISCSIPortalGroup listens on every address of Interfaces, resolved each
+// time ctl.conf is rendered.
+type ISCSIPortalGroup struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Name       string    `json:"name" gorm:"uniqueIndex"`
+	Interfaces []string  `json:"interfaces" gorm:"serializer:json"`
+	Port       int       `json:"port" gorm:"default:3260"`
+	Comment    string    `json:"comment"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+type ISCSITarget struct {
+	ID            uint             `json:"id" gorm:"primaryKey"`
+	Name          string           `json:"name" gorm:"uniqueIndex"`
+	Alias         string           `json:"alias"`
+	PortalGroupID uint             `json:"portalGroupId" gorm:"index"`
+	PortalGroup   ISCSIPortalGroup `json:"portalGroup" gorm:"foreignKey:PortalGroupID"`
+
+	AuthType     string `json:"authType" gorm:"default:'none'"`
+	CHAPUser     string `json:"chapUser"`
+	CHAPSecret   string `json:"-"`
+	MutualUser   string `json:"mutualUser"`
+	MutualSecret string `json:"-"`
+
+	InitiatorNames []string               `json:"initiatorNames" gorm:"serializer:json"`
+	Initiators     []networkModels.Object `json:"initiators" gorm:"many2many:iscsi_target_initiators;"`
+
+	LUNs []ISCSILUN `json:"luns" gorm:"foreignKey:TargetID;constraint:OnDelete:CASCADE"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+type ISCSILUN struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TargetID  uint      `json:"targetId" gorm:"uniqueIndex:idx_iscsi_lun_number"`
+	Number    int       `json:"number" gorm:"uniqueIndex:idx_iscsi_lun_number"`
+	Dataset   string    `json:"dataset" gorm:"uniqueIndex"`
+	BlockSize int       `json:"blockSize"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
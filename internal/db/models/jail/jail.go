@@ -77,6 +77,71 @@ type JailStats struct {
 	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
 }
 
+func (DelegatedDataset) TableName() string {
+	return "jail_delegated_datasets"
+}
+
+type DelegatedDataset struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	JailID uint   `json:"jailId" gorm:"index;not null"`
+	GUID   string `json:"guid" gorm:"uniqueIndex;not null"`
+	Name   string `json:"name"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+type Parameters struct {
+	Allow         []string `json:"allow"`
+	Securelevel   *int     `json:"securelevel"`
+	EnforceStatfs int      `json:"enforceStatfs"`
+	ChildrenMax   int      `json:"childrenMax"`
+	OSRelease     string   `json:"osRelease"`
+	SysVShm       string   `json:"sysvshm"`
+	SysVSem       string   `json:"sysvsem"`
+	SysVMsg       string   `json:"sysvmsg"`
+}
+
+func DefaultParameters() Parameters {
+	return Parameters{
+		Allow:         []string{"sysvipc", "reserved_ports", "raw_sockets", "socket_af"},
+		EnforceStatfs: 2,
+	}
+}
+
+func (ResourceLimit) TableName() string {
+	return "jail_resource_limits"
+}
+
+type ResourceLimit struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	JailID   uint   `json:"jailId" gorm:"not null;uniqueIndex:uniq_jail_resource_action,priority:1"`
+	Resource string `json:"resource" gorm:"not null;uniqueIndex:uniq_jail_resource_action,priority:2"`
+	Action   string `json:"action" gorm:"not null;uniqueIndex:uniq_jail_resource_action,priority:3"`
+	Amount   uint64 `json:"amount" gorm:"not null"`
+}
+
+func (PackageVulnerability) TableName() string {
+	return "jail_package_vulnerabilities"
+}
+
+type PackageVulnerability struct {
+	ID      uint     `json:"id" gorm:"primaryKey"`
+	JailID  uint     `json:"jailId" gorm:"index;not null"`
+	Package string   `json:"package"`
+	Topic   string   `json:"topic"`
+	CVEs    []string `json:"cves" gorm:"serializer:json;type:json"`
+	URL     string   `json:"url"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+type PackageAuditJob struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Enabled   bool      `json:"enabled"`
+	CronExpr  string    `json:"cronExpr"`
+	LastRunAt time.Time `json:"lastRunAt,omitempty"`
+}
+
 type Jail struct {
 	ID          uint   `json:"id" gorm:"primaryKey"`
 	CTID        int    `json:"ctId" gorm:"unique;not null;uniqueIndex"`
@@ -95,9 +160,19 @@ type Jail struct {
 	CPUSet         []int `json:"cpuSet" gorm:"serializer:json;type:json"`
 	Memory         int   `json:"memory"`
 
+	Limits []ResourceLimit `json:"limits" gorm:"foreignKey:JailID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	Parameters *Parameters `json:"parameters" gorm:"serializer:json;type:json"`
+	DevfsRules []string    `json:"devfsRules" gorm:"serializer:json;type:json"`
+
+	Vulnerabilities []PackageVulnerability `json:"vulnerabilities,omitempty" gorm:"foreignKey:JailID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	LastAuditAt     *time.Time             `json:"lastAuditAt" gorm:"default:null"`
+
 	Networks []Network   `json:"networks" gorm:"foreignKey:CTID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
 	Stats    []JailStats `json:"-" gorm:"foreignKey:CTID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 
+	DelegatedDatasets []DelegatedDataset `json:"delegatedDatasets" gorm:"foreignKey:JailID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
 	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
 
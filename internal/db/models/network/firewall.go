@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkModels
+
+import "time"
+
+type FirewallRuleSet struct {
+	ID       uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name     string `json:"name" gorm:"uniqueIndex;not null"`
+	Enabled  bool   `json:"enabled" gorm:"default:true"`
+	Priority int    `json:"priority" gorm:"default:0"`
+
+	// SwitchID nil means the rule set applies to the host as a whole
+	SwitchID *uint           `json:"switchId" gorm:"index"`
+	Switch   *StandardSwitch `json:"switch,omitempty" gorm:"foreignKey:SwitchID"`
+
+	Rules []FirewallRule `json:"rules" gorm:"foreignKey:RuleSetID;constraint:OnDelete:CASCADE"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+type FirewallRule struct {
+	ID        uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	RuleSetID uint   `json:"ruleSetId" gorm:"index;not null"`
+	Position  int    `json:"position" gorm:"default:0"`
+	Enabled   bool   `json:"enabled" gorm:"default:true"`
+	Action    string `json:"action" gorm:"not null"`    // "pass", "block", "reject"
+	Direction string `json:"direction" gorm:"not null"` // "in", "out", "any"
+	Protocol  string `json:"protocol" gorm:"default:any"`
+	Family    string `json:"family"` // "", "inet", "inet6"
+	Quick     bool   `json:"quick" gorm:"default:true"`
+	Log       bool   `json:"log" gorm:"default:false"`
+	Comment   string `json:"comment"`
+
+	SourceID  *uint   `json:"sourceId" gorm:"column:source_object_id"`
+	SourceObj *Object `json:"sourceObj" gorm:"foreignKey:SourceID"`
+
+	SourcePortID  *uint   `json:"sourcePortId" gorm:"column:source_port_object_id"`
+	SourcePortObj *Object `json:"sourcePortObj" gorm:"foreignKey:SourcePortID"`
+
+	DestinationID  *uint   `json:"destinationId" gorm:"column:destination_object_id"`
+	DestinationObj *Object `json:"destinationObj" gorm:"foreignKey:DestinationID"`
+
+	DestinationPortID  *uint   `json:"destinationPortId" gorm:"column:destination_port_object_id"`
+	DestinationPortObj *Object `json:"destinationPortObj" gorm:"foreignKey:DestinationPortID"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+func (r *FirewallRule) ObjectIDs() []uint {
+	var ids []uint
+	for _, id := range []*uint{r.SourceID, r.SourcePortID, r.DestinationID, r.DestinationPortID} {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+
+	return ids
+}
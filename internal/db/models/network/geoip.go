@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkModels
+
+import "time"
+
+// GeoIPDatabase describes the local country database that Country objects are
+// expanded from. Only a single row (ID 1) is ever stored.
+type GeoIPDatabase struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Path         string    `json:"path"`
+	DownloadUUID string    `json:"downloadUuid"`
+	Size         int64     `json:"size"`
+	ModTime      time.Time `json:"modTime"`
+	Networks     int       `json:"networks"`
+	LoadedAt     time.Time `json:"loadedAt"`
+	Error        string    `json:"error"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkModels
+
+import "time"
+
+type IPPool struct {
+	ID         uint     `json:"id" gorm:"primaryKey"`
+	Name       string   `json:"name" gorm:"uniqueIndex;not null"`
+	SwitchID   uint     `json:"switchId" gorm:"index;not null"`
+	SwitchType string   `json:"switchType" gorm:"index;not null;default:standard"`
+	Subnet     string   `json:"subnet" gorm:"not null"`
+	Gateway    string   `json:"gateway" gorm:"not null"`
+	Exclusions []string `json:"exclusions" gorm:"serializer:json;type:json"`
+	Comment    string   `json:"comment"`
+
+	Allocations []IPPoolAllocation `json:"allocations" gorm:"foreignKey:PoolID;constraint:OnDelete:CASCADE"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// IPPoolAllocation records an address handed out by the allocator, backed
+// either by a Network object (jails) or a DHCP static mapping (VMs).
+type IPPoolAllocation struct {
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	PoolID  uint   `json:"poolId" gorm:"index;not null"`
+	Address string `json:"address" gorm:"not null"`
+	Owner   string `json:"owner"`
+
+	ObjectID            *uint `json:"objectId" gorm:"index"`
+	DHCPStaticMappingID *uint `json:"dhcpStaticMappingId" gorm:"index"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
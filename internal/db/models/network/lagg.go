@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkModels
+
+import "time"
+
+type Lagg struct {
+	ID       uint     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name     string   `json:"name" gorm:"uniqueIndex;not null"`
+	LaggName string   `json:"laggName" gorm:"uniqueIndex;not null"`
+	Protocol string   `json:"protocol" gorm:"not null"` // "lacp", "failover", "loadbalance", "roundrobin"
+	Members  []string `json:"members" gorm:"serializer:json"`
+	MTU      int      `json:"mtu" gorm:"default:0"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
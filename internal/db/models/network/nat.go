@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkModels
+
+import "time"
+
+type PortForward struct {
+	ID       uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name     string `json:"name" gorm:"uniqueIndex;not null"`
+	Enabled  bool   `json:"enabled" gorm:"default:true"`
+	Protocol string `json:"protocol" gorm:"not null"` // "tcp", "udp", "tcp/udp"
+	Comment  string `json:"comment"`
+
+	SwitchID uint            `json:"switchId" gorm:"index;not null"`
+	Switch   *StandardSwitch `json:"switch,omitempty" gorm:"foreignKey:SwitchID"`
+
+	HostPortID  uint    `json:"hostPortId" gorm:"column:host_port_object_id;not null"`
+	HostPortObj *Object `json:"hostPortObj" gorm:"foreignKey:HostPortID"`
+
+	DestinationID  uint    `json:"destinationId" gorm:"column:destination_object_id;not null"`
+	DestinationObj *Object `json:"destinationObj" gorm:"foreignKey:DestinationID"`
+
+	DestinationPortID  uint    `json:"destinationPortId" gorm:"column:destination_port_object_id;not null"`
+	DestinationPortObj *Object `json:"destinationPortObj" gorm:"foreignKey:DestinationPortID"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
@@ -13,14 +13,19 @@ import "time"
 type Object struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	Name      string    `json:"name" gorm:"uniqueIndex;not null"`
-	Type      string    `json:"type" gorm:"not null"` // "Host", "Mac", "Network", "Port", "Country", "List"
+	Type      string    `json:"type" gorm:"not null"` // "Host", "Mac", "Network", "Port", "Country", "List", "FQDN"
 	Comment   string    `json:"description"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 	IsUsed    bool      `json:"isUsed" gorm:"-"`
 
-	Entries     []ObjectEntry      `json:"entries" gorm:"foreignKey:ObjectID"`
-	Resolutions []ObjectResolution `json:"resolutions" gorm:"foreignKey:ObjectID"`
+	// ResolvedAt is the last resolution attempt of an FQDN object, whether
+	// or not any of its entries resolved
+	ResolvedAt *time.Time `json:"resolvedAt"`
+
+	Entries        []ObjectEntry         `json:"entries" gorm:"foreignKey:ObjectID"`
+	Resolutions    []ObjectResolution    `json:"resolutions" gorm:"foreignKey:ObjectID"`
+	ResolutionLogs []ObjectResolutionLog `json:"resolutionLogs,omitempty" gorm:"foreignKey:ObjectID"`
 }
 
 type ObjectEntry struct {
@@ -35,6 +40,20 @@ type ObjectResolution struct {
 	ID         uint      `json:"id" gorm:"primaryKey"`
 	ObjectID   uint      `json:"objectId" gorm:"index"`
 	ResolvedIP string    `json:"resolvedIp"` // actual IP resolved only in the case of FQDN
+	Entry      string    `json:"entry"`      // the entry this was resolved from
+	TTL        uint32    `json:"ttl"`
+	ExpiresAt  time.Time `json:"expiresAt"`
 	CreatedAt  time.Time `json:"createdAt"`
 	UpdatedAt  time.Time `json:"updatedAt"`
 }
+
+type ObjectResolutionLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ObjectID  uint      `json:"objectId" gorm:"index"`
+	Entry     string    `json:"entry"`
+	Success   bool      `json:"success"`
+	Addresses []string  `json:"addresses" gorm:"serializer:json;type:json"`
+	Changed   bool      `json:"changed"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"createdAt"`
+}
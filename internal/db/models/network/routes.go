@@ -7,3 +7,33 @@
 // under sponsorship from the FreeBSD Foundation.
 
 package networkModels
+
+import "time"
+
+type StaticRoute struct {
+	ID      uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name    string `json:"name" gorm:"uniqueIndex;not null"`
+	Enabled bool   `json:"enabled" gorm:"default:true"`
+	Comment string `json:"comment"`
+
+	DestinationID  uint    `json:"destinationId" gorm:"column:destination_object_id;not null"`
+	DestinationObj *Object `json:"destinationObj" gorm:"foreignKey:DestinationID"`
+
+	// Exactly one of GatewayID or Interface is set
+	GatewayID  *uint   `json:"gatewayId" gorm:"column:gateway_object_id"`
+	GatewayObj *Object `json:"gatewayObj" gorm:"foreignKey:GatewayID"`
+	Interface  string  `json:"interface"`
+
+	// Weight is the multipath weight route(8) takes with -weight, it only
+	// splits traffic between routes to the same destination. 0 keeps the
+	// kernel default.
+	Weight uint32 `json:"weight"`
+	FIB    uint   `json:"fib" gorm:"default:0"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+
+	// Live state from the kernel routing table: "active", "missing", "mismatch" or "disabled"
+	Status        string `json:"status" gorm:"-"`
+	KernelGateway string `json:"kernelGateway" gorm:"-"`
+}
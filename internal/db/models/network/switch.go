@@ -51,6 +51,7 @@ type StandardSwitch struct {
 	DisableIPv6  bool `json:"disableIPv6" gorm:"default:false"`
 	Private      bool `json:"private" gorm:"default:false"`
 	DefaultRoute bool `json:"defaultRoute" gorm:"default:false"`
+	NAT          bool `json:"nat" gorm:"default:false"`
 
 	Ports []NetworkPort `json:"ports" gorm:"foreignKey:SwitchID;constraint:OnDelete:CASCADE"`
 
@@ -61,6 +62,18 @@ type StandardSwitch struct {
 	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
 }
 
+// SwitchRoute is a route a standard switch sync installed. The kernel cannot
+// tell it apart from one an admin added, so it is kept across restarts to
+// know which routes Sylve may change or remove.
+type SwitchRoute struct {
+	ID          uint     `json:"id" gorm:"primaryKey"`
+	SwitchID    uint     `json:"switchId" gorm:"uniqueIndex:idx_switch_route_kind;not null"`
+	Kind        string   `json:"kind" gorm:"uniqueIndex:idx_switch_route_kind;not null"`
+	Destination string   `json:"destination" gorm:"not null"`
+	Args        []string `json:"args" gorm:"serializer:json;type:json"`
+	Gateway     string   `json:"gateway" gorm:"not null"`
+}
+
 type NetworkPort struct {
 	ID       int            `json:"id" gorm:"primaryKey;autoIncrement"`
 	Name     string         `json:"name" gorm:"not null"`
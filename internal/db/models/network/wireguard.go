@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkModels
+
+import "time"
+
+type WireGuardInterface struct {
+	ID         uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name       string `json:"name" gorm:"uniqueIndex;not null"`
+	IfName     string `json:"ifName" gorm:"uniqueIndex;not null"`
+	Enabled    bool   `json:"enabled" gorm:"default:true"`
+	PrivateKey string `json:"-" gorm:"not null"`
+	PublicKey  string `json:"publicKey" gorm:"not null"`
+	ListenPort int    `json:"listenPort"`
+	MTU        int    `json:"mtu" gorm:"default:0"`
+
+	AddressID  *uint   `json:"addressId" gorm:"column:address_object_id"`
+	AddressObj *Object `json:"addressObj" gorm:"foreignKey:AddressID"`
+
+	Address6ID  *uint   `json:"address6Id" gorm:"column:address6_object_id"`
+	Address6Obj *Object `json:"address6Obj" gorm:"foreignKey:Address6ID"`
+
+	Peers []WireGuardPeer `json:"peers" gorm:"foreignKey:InterfaceID;constraint:OnDelete:CASCADE"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+type WireGuardPeer struct {
+	ID                  uint     `json:"id" gorm:"primaryKey;autoIncrement"`
+	InterfaceID         uint     `json:"interfaceId" gorm:"index;not null"`
+	Name                string   `json:"name" gorm:"not null"`
+	Enabled             bool     `json:"enabled" gorm:"default:true"`
+	PublicKey           string   `json:"publicKey" gorm:"not null"`
+	PresharedKey        string   `json:"-"`
+	Endpoint            string   `json:"endpoint"`
+	PersistentKeepalive int      `json:"persistentKeepalive"`
+	AllowedIPs          []Object `json:"allowedIps" gorm:"many2many:wireguard_peer_allowed_ips;constraint:OnDelete:CASCADE"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+
+	// Live statistics from wg(8)
+	LiveEndpoint    string     `json:"liveEndpoint" gorm:"-"`
+	LatestHandshake *time.Time `json:"latestHandshake" gorm:"-"`
+	RxBytes         uint64     `json:"rxBytes" gorm:"-"`
+	TxBytes         uint64     `json:"txBytes" gorm:"-"`
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package nfsModels
+
+import (
+	"time"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+)
+
+const (
+	SecuritySys   = "sys"
+	SecurityKrb5  = "krb5"
+	SecurityKrb5i = "krb5i"
+	SecurityKrb5p = "krb5p"
+)
+
+type NFSSettings struct {
+	ID         int      `json:"id" gorm:"primaryKey"`
+	Enabled    bool     `json:"enabled" gorm:"default:false"`
+	V4Enabled  bool     `json:"v4Enabled" gorm:"default:true"`
+	V4Only     bool     `json:"v4Only" gorm:"default:false"`
+	V4Root     string   `json:"v4Root" gorm:"default:'/'"`
+	V4Security []string `json:"v4Security" gorm:"serializer:json"`
+}
+
+// NFSExport exports the mountpoint of a ZFS filesystem to the hosts,
+// networks and FQDNs held by Networks.
+type NFSExport struct {
+	ID        uint                   `json:"id" gorm:"primaryKey"`
+	Dataset   string                 `json:"dataset" gorm:"uniqueIndex"`
+	Networks  []networkModels.Object `json:"networks" gorm:"many2many:nfs_export_networks;"`
+	ReadOnly  bool                   `json:"readOnly" gorm:"default:false"`
+	MapRoot   string                 `json:"mapRoot"`
+	MapAll    string                 `json:"mapAll"`
+	Security  []string               `json:"security" gorm:"serializer:json"`
+	AllDirs   bool                   `json:"allDirs" gorm:"default:false"`
+	Comment   string                 `json:"comment"`
+	CreatedAt time.Time              `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time              `json:"updatedAt" gorm:"autoUpdateTime"`
+}
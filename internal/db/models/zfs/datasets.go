@@ -19,4 +19,36 @@ type PeriodicSnapshot struct {
 	CronExpr  string    `json:"cronExpr"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt,omitempty"`
 	LastRunAt time.Time `json:"lastRunAt,omitempty"`
+
+	Retention    SnapshotRetention `gorm:"embedded;embeddedPrefix:retention_" json:"retention"`
+	LastPrunedAt time.Time         `json:"lastPrunedAt,omitempty"`
+}
+
+// SnapshotRetention is a grandfather-father-son policy. A snapshot is kept
+// if any of the counts selects it, and MaxAge (seconds) overrides them all.
+// With every field at zero nothing is ever pruned.
+type SnapshotRetention struct {
+	KeepLast    int `json:"keepLast"`
+	KeepHourly  int `json:"keepHourly"`
+	KeepDaily   int `json:"keepDaily"`
+	KeepWeekly  int `json:"keepWeekly"`
+	KeepMonthly int `json:"keepMonthly"`
+	MaxAge      int `json:"maxAge"`
+}
+
+func (r SnapshotRetention) Enabled() bool {
+	return r.HasCounts() || r.MaxAge > 0
+}
+
+func (r SnapshotRetention) HasCounts() bool {
+	return r.KeepLast > 0 || r.KeepHourly > 0 || r.KeepDaily > 0 || r.KeepWeekly > 0 || r.KeepMonthly > 0
+}
+
+type SnapshotPruneLog struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	JobID     uint      `gorm:"index" json:"jobId"`
+	Snapshot  string    `json:"snapshot"`
+	Reason    string    `json:"reason"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
 }
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfsModels
+
+import "time"
+
+const (
+	PoolTaskScrub = "scrub"
+	PoolTaskTrim  = "trim"
+)
+
+type PoolMaintenance struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PoolGUID  string    `gorm:"uniqueIndex:uniq_pool_task,priority:1" json:"poolGuid"`
+	Task      string    `gorm:"uniqueIndex:uniq_pool_task,priority:2" json:"task"`
+	CronExpr  string    `json:"cronExpr"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt,omitempty"`
+	LastRunAt time.Time `json:"lastRunAt,omitempty"`
+	LastError string    `json:"lastError"`
+}
+
+type PoolScrubResult struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PoolGUID  string    `gorm:"index" json:"poolGuid"`
+	Pool      string    `json:"pool"`
+	Function  string    `json:"function"`
+	State     string    `json:"state"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+	Duration  int64     `json:"duration"`
+	Repaired  uint64    `json:"repaired"`
+	Errors    uint64    `json:"errors"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+const (
+	PoolEventSeverityInfo     = "info"
+	PoolEventSeverityWarning  = "warning"
+	PoolEventSeverityCritical = "critical"
+)
+
+// PoolEvent is one entry of a pool's event history. Events with a warning
+// or critical severity are alerts until they are acknowledged.
+type PoolEvent struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	PoolGUID     string    `gorm:"index" json:"poolGuid"`
+	Pool         string    `json:"pool"`
+	Type         string    `json:"type"`
+	Severity     string    `json:"severity"`
+	Device       string    `json:"device"`
+	Previous     string    `json:"previous"`
+	Current      string    `json:"current"`
+	Message      string    `json:"message"`
+	Acknowledged bool      `gorm:"index" json:"acknowledged"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+type DeviceHealth struct {
+	State string `json:"state"`
+	Read  int64  `json:"read"`
+	Write int64  `json:"write"`
+	Cksum int64  `json:"cksum"`
+}
+
+// PoolHealthState is the last health seen for a pool, kept so that changes
+// are still noticed across restarts.
+type PoolHealthState struct {
+	PoolGUID  string                  `gorm:"primaryKey" json:"poolGuid"`
+	Pool      string                  `json:"pool"`
+	State     string                  `json:"state"`
+	Devices   map[string]DeviceHealth `gorm:"serializer:json;type:json" json:"devices"`
+	UpdatedAt time.Time               `gorm:"autoUpdateTime" json:"updatedAt"`
+}
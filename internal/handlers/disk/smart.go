@@ -0,0 +1,380 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package diskHandlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alchemillahq/sylve/internal"
+	diskModels "github.com/alchemillahq/sylve/internal/db/models/disk"
+	diskServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/disk"
+	"github.com/alchemillahq/sylve/internal/services/disk"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func smartError(c *gin.Context, err error, failure string) {
+	status := http.StatusInternalServerError
+	switch {
+	case strings.Contains(err.Error(), "_not_found"):
+		status = http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "invalid_"), strings.HasPrefix(err.Error(), "smart_not_supported"):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, internal.APIResponse[any]{
+		Status:  "error",
+		Message: failure,
+		Error:   err.Error(),
+		Data:    nil,
+	})
+}
+
+func smartDone(c *gin.Context, err error, failure string, success string) {
+	if err != nil {
+		smartError(c, err, failure)
+		return
+	}
+
+	c.JSON(http.StatusOK, internal.APIResponse[any]{
+		Status:  "success",
+		Message: success,
+		Error:   "",
+		Data:    nil,
+	})
+}
+
+// @Summary Disk Health
+// @Description Latest SMART reading, thresholds and pool membership of every SMART capable disk
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]diskServiceInterfaces.DiskHealth] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/health [get]
+func DiskHealth(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		health, err := diskService.GetDiskHealth()
+		if err != nil {
+			smartError(c, err, "error_getting_disk_health")
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]diskServiceInterfaces.DiskHealth]{
+			Status:  "success",
+			Message: "disk_health",
+			Error:   "",
+			Data:    health,
+		})
+	}
+}
+
+// @Summary SMART History
+// @Description Stored SMART readings of a disk, newest first
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "Disk UUID"
+// @Param limit query int false "Number of readings (default one week)"
+// @Success 200 {object} internal.APIResponse[[]diskModels.SmartReading] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/{uuid}/history [get]
+func SmartHistory(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+
+		readings, err := diskService.GetSmartHistory(c.Param("uuid"), limit)
+		if err != nil {
+			smartError(c, err, "error_getting_smart_history")
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]diskModels.SmartReading]{
+			Status:  "success",
+			Message: "smart_history",
+			Error:   "",
+			Data:    readings,
+		})
+	}
+}
+
+// @Summary Self-Test Log
+// @Description Self-test log of a disk as reported by the drive
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "Disk UUID"
+// @Success 200 {object} internal.APIResponse[diskServiceInterfaces.SelfTestLog] "Success"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/{uuid}/self-tests [get]
+func SelfTestLog(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log, err := diskService.GetSelfTestLog(c.Param("uuid"))
+		if err != nil {
+			smartError(c, err, "error_getting_self_test_log")
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[diskServiceInterfaces.SelfTestLog]{
+			Status:  "success",
+			Message: "self_test_log",
+			Error:   "",
+			Data:    log,
+		})
+	}
+}
+
+// @Summary Run Self-Test
+// @Description Start a short or long SMART self-test (NVMe device self-test on NVMe disks)
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "Disk UUID"
+// @Param request body diskServiceInterfaces.RunSelfTest true "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/{uuid}/self-tests [post]
+func RunSelfTest(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var r diskServiceInterfaces.RunSelfTest
+		if err := c.ShouldBindJSON(&r); err != nil {
+			validationErrors := utils.MapValidationErrors(err, diskServiceInterfaces.RunSelfTest{})
+
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_payload",
+				Error:   "validation_error",
+				Data:    validationErrors,
+			})
+			return
+		}
+
+		err := diskService.RunSelfTest(c.Param("uuid"), r.TestType)
+		smartDone(c, err, "error_starting_self_test", "self_test_started")
+	}
+}
+
+// @Summary SMART Test Schedules
+// @Description List scheduled SMART self-tests of all disks
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]diskModels.SmartTestSchedule] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/schedules [get]
+func SmartTestSchedules(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schedules, err := diskService.GetSmartTestSchedules()
+		if err != nil {
+			smartError(c, err, "error_listing_smart_schedules")
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]diskModels.SmartTestSchedule]{
+			Status:  "success",
+			Message: "smart_schedules",
+			Error:   "",
+			Data:    schedules,
+		})
+	}
+}
+
+// @Summary Set SMART Test Schedule
+// @Description Create or update the short or long self-test schedule of a disk
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "Disk UUID"
+// @Param request body diskServiceInterfaces.SmartTestSchedule true "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/{uuid}/schedules [put]
+func SetSmartTestSchedule(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var r diskServiceInterfaces.SmartTestSchedule
+		if err := c.ShouldBindJSON(&r); err != nil {
+			validationErrors := utils.MapValidationErrors(err, diskServiceInterfaces.SmartTestSchedule{})
+
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_payload",
+				Error:   "validation_error",
+				Data:    validationErrors,
+			})
+			return
+		}
+
+		err := diskService.SetSmartTestSchedule(c.Param("uuid"), r)
+		smartDone(c, err, "error_saving_smart_schedule", "smart_schedule_saved")
+	}
+}
+
+// @Summary Delete SMART Test Schedule
+// @Description Delete the short or long self-test schedule of a disk
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "Disk UUID"
+// @Param type path string true "Test type (short or long)"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/{uuid}/schedules/{type} [delete]
+func DeleteSmartTestSchedule(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := diskService.DeleteSmartTestSchedule(c.Param("uuid"), c.Param("type"))
+		smartDone(c, err, "error_deleting_smart_schedule", "smart_schedule_deleted")
+	}
+}
+
+// @Summary SMART Thresholds
+// @Description Thresholds applied to a disk, or the defaults when no disk is given
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param disk query string false "Disk UUID"
+// @Success 200 {object} internal.APIResponse[diskModels.SmartThresholds] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/thresholds [get]
+func SmartThresholds(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		thresholds, err := diskService.GetSmartThresholds(c.Query("disk"))
+		if err != nil {
+			smartError(c, err, "error_getting_smart_thresholds")
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[diskModels.SmartThresholds]{
+			Status:  "success",
+			Message: "smart_thresholds",
+			Error:   "",
+			Data:    thresholds,
+		})
+	}
+}
+
+// @Summary Set SMART Thresholds
+// @Description Set the warning thresholds of a disk, or the defaults when no disk is given. A zero threshold is disabled.
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param disk query string false "Disk UUID"
+// @Param request body diskModels.SmartThresholds true "Thresholds"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/thresholds [put]
+func SetSmartThresholds(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var r diskModels.SmartThresholds
+		if err := c.ShouldBindJSON(&r); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_payload",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := diskService.SetSmartThresholds(c.Query("disk"), r)
+		smartDone(c, err, "error_saving_smart_thresholds", "smart_thresholds_saved")
+	}
+}
+
+// @Summary Reset SMART Thresholds
+// @Description Drop the thresholds of a disk so the defaults apply again, or reset the defaults when no disk is given
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param disk query string false "Disk UUID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/thresholds [delete]
+func DeleteSmartThresholds(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := diskService.DeleteSmartThresholds(c.Query("disk"))
+		smartDone(c, err, "error_resetting_smart_thresholds", "smart_thresholds_reset")
+	}
+}
+
+// @Summary SMART Alerts
+// @Description Unacknowledged SMART threshold alerts of all disks
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]diskModels.SmartAlert] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/alerts [get]
+func SmartAlerts(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		alerts, err := diskService.GetSmartAlerts()
+		if err != nil {
+			smartError(c, err, "error_listing_smart_alerts")
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]diskModels.SmartAlert]{
+			Status:  "success",
+			Message: "smart_alerts",
+			Error:   "",
+			Data:    alerts,
+		})
+	}
+}
+
+// @Summary Acknowledge SMART Alert
+// @Description Acknowledge a SMART alert so it is no longer listed
+// @Tags Disk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Alert ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /disk/smart/alerts/{id}/acknowledge [post]
+func AcknowledgeSmartAlert(diskService *disk.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err = diskService.AcknowledgeSmartAlert(uint(id))
+		smartDone(c, err, "error_acknowledging_smart_alert", "smart_alert_acknowledged")
+	}
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package iscsiHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+	"github.com/alchemillahq/sylve/internal/services/iscsi"
+	"github.com/alchemillahq/sylve/pkg/system/ctl"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ISCSIConfigRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// @Summary Get iSCSI Configuration
+// @Description Retrieve the iSCSI target settings
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Success 200 {object} internal.APIResponse[iscsiModels.ISCSISettings] "iSCSI configuration"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /iscsi/config [get]
+func GetConfig(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		settings, err := iscsiService.GetSettings()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_iscsi_config",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[iscsiModels.ISCSISettings]{
+			Status:  "success",
+			Message: "iscsi_config_retrieved",
+			Error:   "",
+			Data:    settings,
+		})
+	}
+}
+
+// @Summary Set iSCSI Configuration
+// @Description Enable or disable the iSCSI target (ctld)
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Param request body ISCSIConfigRequest true "iSCSI Configuration"
+// @Success 200 {object} internal.APIResponse[any] "iSCSI configuration updated"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /iscsi/config [post]
+func SetConfig(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ISCSIConfigRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := iscsiService.SetSettings(*req.Enabled); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_set_iscsi_config",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "iscsi_config_updated",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary List iSCSI Sessions
+// @Description List the initiators currently logged in to any target
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Success 200 {object} internal.APIResponse[[]ctl.Session] "Active sessions"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /iscsi/sessions [get]
+func GetSessions(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessions, err := iscsiService.GetSessions()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_sessions",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]ctl.Session]{
+			Status:  "success",
+			Message: "sessions_retrieved",
+			Error:   "",
+			Data:    sessions,
+		})
+	}
+}
+
+// @Summary Log Out iSCSI Session
+// @Description Drop a single iSCSI connection
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Param id path int true "Connection ID"
+// @Success 200 {object} internal.APIResponse[any] "Session logged out"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /iscsi/sessions/{id} [delete]
+func LogoutSession(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_session_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := iscsiService.LogoutSession(id); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_logout_session",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "session_logged_out",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package iscsiHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+	iscsiServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/iscsi"
+	"github.com/alchemillahq/sylve/internal/services/iscsi"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdatePortalGroupRequest struct {
+	ID uint `json:"id" binding:"required"`
+	iscsiServiceInterfaces.PortalGroup
+}
+
+// @Summary Get iSCSI Portal Groups
+// @Description Retrieve all iSCSI portal groups
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Success 200 {object} internal.APIResponse[[]iscsiModels.ISCSIPortalGroup] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /iscsi/portal-groups [get]
+func GetPortalGroups(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := iscsiService.GetPortalGroups()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_portal_groups",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]iscsiModels.ISCSIPortalGroup]{
+			Status:  "success",
+			Message: "portal_groups_retrieved",
+			Error:   "",
+			Data:    items,
+		})
+	}
+}
+
+// @Summary Create iSCSI Portal Group
+// @Description Create a new iSCSI portal group
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Param request body iscsiServiceInterfaces.PortalGroup true "Create Portal Group Request"
+// @Success 200 {object} internal.APIResponse[any] "Portal Group created"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /iscsi/portal-groups [post]
+func CreatePortalGroup(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req iscsiServiceInterfaces.PortalGroup
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := iscsiService.CreatePortalGroup(req); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_portal_group",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "portal_group_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Update iSCSI Portal Group
+// @Description Update an existing iSCSI portal group
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Param request body UpdatePortalGroupRequest true "Update Portal Group Request"
+// @Success 200 {object} internal.APIResponse[any] "Portal Group updated"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /iscsi/portal-groups [put]
+func UpdatePortalGroup(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req UpdatePortalGroupRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := iscsiService.UpdatePortalGroup(req.ID, req.PortalGroup); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_update_portal_group",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "portal_group_updated",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete iSCSI Portal Group
+// @Description Delete an iSCSI portal group by ID
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Param id path uint true "Portal Group ID"
+// @Success 200 {object} internal.APIResponse[any] "Portal Group deleted"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /iscsi/portal-groups/{id} [delete]
+func DeletePortalGroup(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_portal_group_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := iscsiService.DeletePortalGroup(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_portal_group",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "portal_group_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
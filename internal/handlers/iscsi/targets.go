@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package iscsiHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+	iscsiServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/iscsi"
+	"github.com/alchemillahq/sylve/internal/services/iscsi"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateTargetRequest struct {
+	ID uint `json:"id" binding:"required"`
+	iscsiServiceInterfaces.Target
+}
+
+// @Summary Get iSCSI Targets
+// @Description Retrieve all iSCSI targets
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Success 200 {object} internal.APIResponse[[]iscsiModels.ISCSITarget] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /iscsi/targets [get]
+func GetTargets(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		items, err := iscsiService.GetTargets()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_targets",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]iscsiModels.ISCSITarget]{
+			Status:  "success",
+			Message: "targets_retrieved",
+			Error:   "",
+			Data:    items,
+		})
+	}
+}
+
+// @Summary Create iSCSI Target
+// @Description Create a new iSCSI target
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Param request body iscsiServiceInterfaces.Target true "Create Target Request"
+// @Success 200 {object} internal.APIResponse[any] "Target created"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /iscsi/targets [post]
+func CreateTarget(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req iscsiServiceInterfaces.Target
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := iscsiService.CreateTarget(req); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_target",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "target_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Update iSCSI Target
+// @Description Update an existing iSCSI target
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Param request body UpdateTargetRequest true "Update Target Request"
+// @Success 200 {object} internal.APIResponse[any] "Target updated"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /iscsi/targets [put]
+func UpdateTarget(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req UpdateTargetRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := iscsiService.UpdateTarget(req.ID, req.Target); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_update_target",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "target_updated",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete iSCSI Target
+// @Description Delete an iSCSI target by ID
+// @Tags iSCSI
+// @Accept json
+// @Produce json
+// @Param id path uint true "Target ID"
+// @Success 200 {object} internal.APIResponse[any] "Target deleted"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /iscsi/targets/{id} [delete]
+func DeleteTarget(iscsiService *iscsi.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_target_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := iscsiService.DeleteTarget(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_target",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "target_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
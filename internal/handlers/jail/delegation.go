@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package jailHandlers
+
+import (
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	"github.com/alchemillahq/sylve/internal/services/jail"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JailDelegateDatasetRequest struct {
+	CTID uint   `json:"ctId" binding:"required"`
+	GUID string `json:"guid" binding:"required"`
+}
+
+// @Summary List Delegated Datasets
+// @Description List the ZFS datasets delegated to a jail
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ctId path uint true "Container ID"
+// @Success 200 {object} internal.APIResponse[[]jailModels.DelegatedDataset] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/delegated-datasets/{ctId} [get]
+func GetDelegatedDatasets(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctId, err := strconv.ParseUint(c.Param("ctId"), 10, 32)
+		if err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_ct_id",
+				Data:    nil,
+				Error:   "Invalid CT ID: " + err.Error(),
+			})
+			return
+		}
+
+		datasets, err := jailService.GetDelegatedDatasets(uint(ctId))
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_delegated_datasets",
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[[]jailModels.DelegatedDataset]{
+			Status:  "success",
+			Message: "delegated_datasets_listed",
+			Data:    datasets,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Delegate Dataset to Jail
+// @Description Delegate a ZFS filesystem to a jail so it can manage its own snapshots and children
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body JailDelegateDatasetRequest true "Delegate Dataset Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/delegated-datasets [post]
+func DelegateDataset(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req JailDelegateDatasetRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_data",
+				Data:    nil,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+
+		if err := jailService.DelegateDataset(req.CTID, req.GUID); err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delegate_dataset",
+				Data:    nil,
+				Error:   "failed_to_delegate_dataset: " + err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "dataset_delegated",
+			Data:    nil,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Remove Delegated Dataset
+// @Description Detach a delegated ZFS dataset from a jail and reset its jailed property
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ctId path uint true "Container ID"
+// @Param id path uint true "Delegated Dataset ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/delegated-datasets/{ctId}/{id} [delete]
+func RemoveDelegatedDataset(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctId, err := strconv.ParseUint(c.Param("ctId"), 10, 32)
+		if err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_ct_id",
+				Data:    nil,
+				Error:   "Invalid CT ID: " + err.Error(),
+			})
+			return
+		}
+
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Data:    nil,
+				Error:   "Invalid ID: " + err.Error(),
+			})
+			return
+		}
+
+		if err := jailService.RemoveDelegatedDataset(uint(ctId), uint(id)); err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_remove_delegated_dataset",
+				Data:    nil,
+				Error:   "failed_to_remove_delegated_dataset: " + err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "delegated_dataset_removed",
+			Data:    nil,
+			Error:   "",
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package jailHandlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alchemillahq/sylve/internal"
+	jailServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/jail"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/internal/services/jail"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+type execStreamWriter struct {
+	header byte
+	write  func(int, []byte) error
+}
+
+func (w execStreamWriter) Write(p []byte) (int, error) {
+	msg := make([]byte, 0, len(p)+1)
+	msg = append(msg, w.header)
+	msg = append(msg, p...)
+
+	if err := w.write(websocket.BinaryMessage, msg); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// @Summary Execute Command in Jail
+// @Description Run a non-interactive command inside a running jail via jexec
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ctId path uint true "Container ID"
+// @Param request body jailServiceInterfaces.ExecRequest true "Exec Request"
+// @Success 200 {object} internal.APIResponse[jailServiceInterfaces.ExecResult] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/{ctId}/exec [post]
+func ExecInJail(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctId, err := strconv.ParseUint(c.Param("ctId"), 10, 32)
+		if err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_ct_id",
+				Data:    nil,
+				Error:   "Invalid CT ID: " + err.Error(),
+			})
+			return
+		}
+
+		var req jailServiceInterfaces.ExecRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_data",
+				Data:    nil,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+
+		result, err := jailService.Exec(uint(ctId), req)
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_exec_in_jail",
+				Data:    nil,
+				Error:   "failed_to_exec_in_jail: " + err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[jailServiceInterfaces.ExecResult]{
+			Status:  "success",
+			Message: "command_executed",
+			Data:    result,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Stream Command Execution in Jail
+// @Description Run a command inside a running jail and stream stdin/stdout/stderr over a WebSocket
+// @Tags Jail
+// @Security BearerAuth
+// @Param ctId query uint true "Container ID"
+// @Param user query string false "User to run the command as"
+// @Param timeout query int false "Timeout in seconds"
+// @Param command query []string true "Command and arguments" collectionFormat(multi)
+// @Param env query []string false "Environment variables as KEY=VALUE" collectionFormat(multi)
+// @Router /jail/exec/stream [get]
+func ExecInJailStream(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctId, err := strconv.ParseUint(c.Query("ctId"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ctId"})
+			return
+		}
+
+		req := jailServiceInterfaces.ExecRequest{
+			User:    c.Query("user"),
+			Command: c.QueryArray("command"),
+			Env:     map[string]string{},
+		}
+
+		if t := c.Query("timeout"); t != "" {
+			req.Timeout, err = strconv.Atoi(t)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timeout"})
+				return
+			}
+		}
+
+		for _, kv := range c.QueryArray("env") {
+			k, v, found := strings.Cut(kv, "=")
+			if !found {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid env " + kv})
+				return
+			}
+			req.Env[k] = v
+		}
+
+		conn, err := WSUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			logger.L.Error().Err(err).Msg("WebSocket upgrade failed")
+			return
+		}
+		defer conn.Close()
+
+		var wsWriteMu sync.Mutex
+		safeWrite := func(mt int, data []byte) error {
+			wsWriteMu.Lock()
+			defer wsWriteMu.Unlock()
+			return conn.WriteMessage(mt, data)
+		}
+
+		stdinReader, stdinWriter := io.Pipe()
+
+		go func() {
+			defer stdinWriter.Close()
+			for {
+				messageType, data, err := conn.ReadMessage()
+				if err != nil || messageType != websocket.BinaryMessage || len(data) == 0 {
+					return
+				}
+
+				switch data[0] {
+				case 0: // stdin
+					if _, err := stdinWriter.Write(data[1:]); err != nil {
+						return
+					}
+				case 1: // close stdin
+					return
+				}
+			}
+		}()
+
+		exitCode, timedOut, err := jailService.ExecStream(
+			uint(ctId),
+			req,
+			stdinReader,
+			execStreamWriter{header: 1, write: safeWrite},
+			execStreamWriter{header: 2, write: safeWrite},
+		)
+		stdinReader.Close()
+
+		status := struct {
+			ExitCode int    `json:"exitCode"`
+			TimedOut bool   `json:"timedOut"`
+			Error    string `json:"error,omitempty"`
+		}{
+			ExitCode: exitCode,
+			TimedOut: timedOut,
+		}
+
+		if err != nil {
+			status.Error = err.Error()
+		}
+
+		if data, err := json.Marshal(status); err == nil {
+			safeWrite(websocket.TextMessage, data)
+		}
+
+		wsWriteMu.Lock()
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		wsWriteMu.Unlock()
+	}
+}
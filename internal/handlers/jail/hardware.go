@@ -9,7 +9,11 @@
 package jailHandlers
 
 import (
+	"strconv"
+
 	"github.com/alchemillahq/sylve/internal"
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	jailServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/jail"
 	"github.com/alchemillahq/sylve/internal/services/jail"
 
 	"github.com/gin-gonic/gin"
@@ -110,3 +114,95 @@ func UpdateJailCPU(jailService *jail.Service) gin.HandlerFunc {
 		})
 	}
 }
+
+type JailUpdateRctlRequest struct {
+	CTID  uint                             `json:"ctId" binding:"required"`
+	Rules []jailServiceInterfaces.RctlRule `json:"rules"`
+}
+
+// @Summary Get Jail RCTL Limits
+// @Description Get the RCTL limits configured for a jail
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ctId path uint true "Container ID"
+// @Success 200 {object} internal.APIResponse[[]jailModels.ResourceLimit] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/rctl/{ctId} [get]
+func GetJailRctlLimits(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctId, err := strconv.ParseUint(c.Param("ctId"), 10, 32)
+		if err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_ct_id",
+				Data:    nil,
+				Error:   "Invalid CT ID: " + err.Error(),
+			})
+			return
+		}
+
+		limits, err := jailService.GetResourceLimits(uint(ctId))
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_rctl_limits",
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[[]jailModels.ResourceLimit]{
+			Status:  "success",
+			Message: "rctl_limits_retrieved",
+			Data:    limits,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Update Jail RCTL Limits
+// @Description Replace the RCTL limits of a jail and apply them live if it is running
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body JailUpdateRctlRequest true "Update Jail RCTL Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/rctl [put]
+func UpdateJailRctlLimits(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req JailUpdateRctlRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_data",
+				Data:    nil,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+
+		if err := jailService.SetRctlLimits(req.CTID, req.Rules); err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_update_rctl_limits",
+				Data:    nil,
+				Error:   "failed_to_update_rctl_limits: " + err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "jail_rctl_limits_updated",
+			Data:    nil,
+			Error:   "",
+		})
+	}
+}
@@ -33,6 +33,8 @@ type AddNetworkRequest struct {
 	IP6GW      *uint  `json:"ip6gw"`
 	DHCP       *bool  `json:"dhcp"`
 	SLAAC      *bool  `json:"slaac"`
+	AutoIP4    *bool  `json:"autoIp4"`
+	AutoIP6    *bool  `json:"autoIp6"`
 }
 
 // @Summary Update Jail to Inherit Hosts Network
@@ -212,7 +214,10 @@ func AddNetwork(jailService *jail.Service) gin.HandlerFunc {
 			macId = *req.MacID
 		}
 
-		err := jailService.AddNetwork(req.CTID, req.SwitchName, macId, ipv4, ipv4gw, ipv6, ipv6gw, dhcp, slaac)
+		autoIP4 := req.AutoIP4 != nil && *req.AutoIP4
+		autoIP6 := req.AutoIP6 != nil && *req.AutoIP6
+
+		err := jailService.AddNetwork(req.CTID, req.SwitchName, macId, ipv4, ipv4gw, ipv6, ipv6gw, dhcp, slaac, autoIP4, autoIP6)
 		if err != nil {
 			c.JSON(500, internal.APIResponse[any]{
 				Status:  "error",
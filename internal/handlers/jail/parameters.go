@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package jailHandlers
+
+import (
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	"github.com/alchemillahq/sylve/internal/services/jail"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JailParametersResponse struct {
+	Parameters jailModels.Parameters `json:"parameters"`
+	DevfsRules []string              `json:"devfsRules"`
+}
+
+type JailUpdateParametersRequest struct {
+	CTID       uint                  `json:"ctId" binding:"required"`
+	Parameters jailModels.Parameters `json:"parameters"`
+	DevfsRules []string              `json:"devfsRules"`
+}
+
+// @Summary Get Jail Parameters
+// @Description Get the jail parameters and devfs unhide rules of a jail
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ctId path uint true "Container ID"
+// @Success 200 {object} internal.APIResponse[JailParametersResponse] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/parameters/{ctId} [get]
+func GetJailParameters(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctId, err := strconv.ParseUint(c.Param("ctId"), 10, 32)
+		if err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_ct_id",
+				Data:    nil,
+				Error:   "Invalid CT ID: " + err.Error(),
+			})
+			return
+		}
+
+		params, devfsRules, err := jailService.GetParameters(uint(ctId))
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_jail_parameters",
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[JailParametersResponse]{
+			Status:  "success",
+			Message: "jail_parameters_retrieved",
+			Data: JailParametersResponse{
+				Parameters: params,
+				DevfsRules: devfsRules,
+			},
+			Error: "",
+		})
+	}
+}
+
+// @Summary Update Jail Parameters
+// @Description Update the jail parameters and devfs unhide rules of a jail, changes apply on the next start
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body JailUpdateParametersRequest true "Update Jail Parameters Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/parameters [put]
+func UpdateJailParameters(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req JailUpdateParametersRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_data",
+				Data:    nil,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+
+		if err := jailService.UpdateParameters(req.CTID, req.Parameters, req.DevfsRules); err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_update_jail_parameters",
+				Data:    nil,
+				Error:   "failed_to_update_jail_parameters: " + err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "jail_parameters_updated",
+			Data:    nil,
+			Error:   "",
+		})
+	}
+}
@@ -0,0 +1,404 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package jailHandlers
+
+import (
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	jailServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/jail"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/internal/services/jail"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JailPackagesRequest struct {
+	CTID     uint     `json:"ctId" binding:"required"`
+	Packages []string `json:"packages" binding:"required"`
+}
+
+type JailUpgradePackagesRequest struct {
+	CTID   uint  `json:"ctId" binding:"required"`
+	DryRun *bool `json:"dryRun"`
+}
+
+type PackageAuditJobRequest struct {
+	Enabled  bool   `json:"enabled"`
+	CronExpr string `json:"cronExpr"`
+}
+
+// @Summary List Jail Packages
+// @Description List the packages installed in a jail
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ctId path uint true "Container ID"
+// @Success 200 {object} internal.APIResponse[[]jailServiceInterfaces.Package] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/pkg/{ctId} [get]
+func ListJailPackages(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctId, err := strconv.ParseUint(c.Param("ctId"), 10, 32)
+		if err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_ct_id",
+				Data:    nil,
+				Error:   "Invalid CT ID: " + err.Error(),
+			})
+			return
+		}
+
+		packages, err := jailService.ListPackages(uint(ctId))
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_list_packages",
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[[]jailServiceInterfaces.Package]{
+			Status:  "success",
+			Message: "packages_listed",
+			Data:    packages,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Install Jail Packages
+// @Description Install packages in a jail
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body JailPackagesRequest true "Install Packages Request"
+// @Success 200 {object} internal.APIResponse[string] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/pkg/install [post]
+func InstallJailPackages(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req JailPackagesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_data",
+				Data:    nil,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+
+		output, err := jailService.InstallPackages(req.CTID, req.Packages)
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_install_packages",
+				Data:    output,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[string]{
+			Status:  "success",
+			Message: "packages_installed",
+			Data:    output,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Remove Jail Packages
+// @Description Remove packages from a jail
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body JailPackagesRequest true "Remove Packages Request"
+// @Success 200 {object} internal.APIResponse[string] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/pkg/remove [post]
+func RemoveJailPackages(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req JailPackagesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_data",
+				Data:    nil,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+
+		output, err := jailService.RemovePackages(req.CTID, req.Packages)
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_remove_packages",
+				Data:    output,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[string]{
+			Status:  "success",
+			Message: "packages_removed",
+			Data:    output,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Upgrade Jail Packages
+// @Description Upgrade packages in a jail, defaults to a dry-run that returns the planned changes
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body JailUpgradePackagesRequest true "Upgrade Packages Request"
+// @Success 200 {object} internal.APIResponse[jailServiceInterfaces.UpgradePlan] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/pkg/upgrade [post]
+func UpgradeJailPackages(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req JailUpgradePackagesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_data",
+				Data:    nil,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+
+		dryRun := true
+		if req.DryRun != nil {
+			dryRun = *req.DryRun
+		}
+
+		plan, err := jailService.UpgradePackages(req.CTID, dryRun)
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_upgrade_packages",
+				Data:    plan,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[jailServiceInterfaces.UpgradePlan]{
+			Status:  "success",
+			Message: "packages_upgrade_planned",
+			Data:    plan,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Get Jail Package Vulnerabilities
+// @Description Get the stored results of the last package audit of a jail
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ctId path uint true "Container ID"
+// @Success 200 {object} internal.APIResponse[[]jailModels.PackageVulnerability] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/pkg/audit/{ctId} [get]
+func GetJailPackageVulnerabilities(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctId, err := strconv.ParseUint(c.Param("ctId"), 10, 32)
+		if err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_ct_id",
+				Data:    nil,
+				Error:   "Invalid CT ID: " + err.Error(),
+			})
+			return
+		}
+
+		vulns, err := jailService.GetPackageVulnerabilities(uint(ctId))
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_package_vulnerabilities",
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[[]jailModels.PackageVulnerability]{
+			Status:  "success",
+			Message: "package_vulnerabilities_retrieved",
+			Data:    vulns,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Audit Jail Packages
+// @Description Audit the packages of a jail against the locally mirrored vuxml database
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ctId path uint true "Container ID"
+// @Success 200 {object} internal.APIResponse[[]jailModels.PackageVulnerability] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/pkg/audit/{ctId} [post]
+func AuditJailPackages(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctId, err := strconv.ParseUint(c.Param("ctId"), 10, 32)
+		if err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_ct_id",
+				Data:    nil,
+				Error:   "Invalid CT ID: " + err.Error(),
+			})
+			return
+		}
+
+		vulns, err := jailService.AuditPackages(uint(ctId))
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_audit_packages",
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[[]jailModels.PackageVulnerability]{
+			Status:  "success",
+			Message: "packages_audited",
+			Data:    vulns,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Audit All Jail Packages
+// @Description Start a package audit across all jails in the background
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Router /jail/pkg/audit [post]
+func AuditAllJailPackages(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		go func() {
+			if err := jailService.AuditAllJails(); err != nil {
+				logger.L.Error().Err(err).Msg("failed to audit packages across jails")
+			}
+		}()
+
+		c.JSON(200, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "package_audit_started",
+			Data:    nil,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Get Package Audit Schedule
+// @Description Get the schedule of the package audit job that runs across all jails
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[jailModels.PackageAuditJob] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/pkg/audit-schedule [get]
+func GetPackageAuditSchedule(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, err := jailService.GetPackageAuditJob()
+		if err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_package_audit_schedule",
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[jailModels.PackageAuditJob]{
+			Status:  "success",
+			Message: "package_audit_schedule_retrieved",
+			Data:    job,
+			Error:   "",
+		})
+	}
+}
+
+// @Summary Update Package Audit Schedule
+// @Description Enable, disable or reschedule the package audit job that runs across all jails
+// @Tags Jail
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PackageAuditJobRequest true "Package Audit Schedule Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /jail/pkg/audit-schedule [put]
+func UpdatePackageAuditSchedule(jailService *jail.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req PackageAuditJobRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request_data",
+				Data:    nil,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+
+		if err := jailService.UpdatePackageAuditJob(req.Enabled, req.CronExpr); err != nil {
+			c.JSON(500, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_update_package_audit_schedule",
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "package_audit_schedule_updated",
+			Data:    nil,
+			Error:   "",
+		})
+	}
+}
@@ -22,6 +22,15 @@ import (
 	"github.com/alchemillahq/sylve/pkg/utils"
 )
 
+// Query parameters that carry credentials. They are read by the auth
+// middleware and must never end up in the audit log.
+const (
+	authQueryParam = "auth"
+	hashQueryParam = "hash"
+)
+
+var credentialQueryParams = []string{authQueryParam, hashQueryParam}
+
 func EnsureAuthenticated(authService *authService.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		path := c.Request.URL.Path
@@ -38,7 +47,7 @@ func EnsureAuthenticated(authService *authService.Service) gin.HandlerFunc {
 		}
 
 		if strings.HasPrefix(path, "/api/vnc/") {
-			if authHex := c.Query("auth"); authHex != "" {
+			if authHex := c.Query(authQueryParam); authHex != "" {
 				var wssAuth struct {
 					Hash     string `json:"hash"`
 					Hostname string `json:"hostname"`
@@ -120,7 +129,7 @@ func EnsureAuthenticated(authService *authService.Service) gin.HandlerFunc {
 		}
 
 		var localJWT string
-		if hash := c.Query("hash"); hash != "" {
+		if hash := c.Query(hashQueryParam); hash != "" {
 			tok, err := authService.GetTokenBySHA256(hash)
 			if err != nil {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "error", "error": "invalid_hash"})
@@ -28,7 +28,7 @@ import (
 )
 
 var hostname string
-var importantGetPaths = []string{"/api/vnc"}
+var importantGetPaths = []string{"/api/vnc", "/api/jail/exec/stream"}
 
 type claim struct {
 	UserID   *uint
@@ -48,7 +48,7 @@ func getClaims(c *gin.Context, authService *authService.Service) (claim, error)
 	token := c.GetString("Token")
 
 	if token == "" {
-		if hash := c.Query("hash"); hash != "" {
+		if hash := c.Query(hashQueryParam); hash != "" {
 			t, err := authService.GetTokenBySHA256(hash)
 
 			if err != nil {
@@ -166,6 +166,16 @@ func RequestLoggerMiddleware(db *gorm.DB, authService *authService.Service) gin.
 			c.Request.Body = io.NopCloser(buf)
 		}
 
+		if c.Request.Method == "GET" && c.Request.URL.RawQuery != "" {
+			query := c.Request.URL.Query()
+			for _, param := range credentialQueryParams {
+				query.Del(param)
+			}
+			if len(query) > 0 {
+				act.Body = query
+			}
+		}
+
 		actJSON, err := json.Marshal(act)
 		if err != nil {
 			logger.L.Error().Msgf("Failed to marshal action: %v", err)
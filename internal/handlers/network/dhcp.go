@@ -0,0 +1,508 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	"github.com/alchemillahq/sylve/internal/services/network"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModifyDHCPConfigRequest struct {
+	SwitchIDs   []uint   `json:"switchIds"`
+	DNSServers  []string `json:"dnsServers"`
+	Domain      string   `json:"domain"`
+	ExpandHosts *bool    `json:"expandHosts"`
+}
+
+type CreateDHCPRangeRequest struct {
+	SwitchID uint   `json:"switchId" binding:"required"`
+	StartIP  string `json:"startIp" binding:"required"`
+	EndIP    string `json:"endIp" binding:"required"`
+}
+
+type CreateDHCPStaticMappingRequest struct {
+	SwitchID uint   `json:"switchId" binding:"required"`
+	Hostname string `json:"hostname" binding:"required"`
+	MAC      string `json:"mac" binding:"required"`
+	IP       string `json:"ip" binding:"required"`
+	Comments string `json:"comments"`
+	Expiry   int    `json:"expiry"`
+}
+
+type CreateDHCPOptionRequest struct {
+	SwitchID uint   `json:"switchId" binding:"required"`
+	Option   string `json:"option" binding:"required"`
+	Value    string `json:"value" binding:"required"`
+	Comments string `json:"comments"`
+}
+
+// @Summary Get DHCP Config
+// @Description Get the global DHCP/DNS settings and the switches being served
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[networkModels.DHCPConfig] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/config [get]
+func GetDHCPConfig(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := svc.GetDHCPConfig()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_dhcp_config",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[networkModels.DHCPConfig]{
+			Status:  "success",
+			Message: "dhcp_config_retrieved",
+			Error:   "",
+			Data:    data,
+		})
+	}
+}
+
+// @Summary Modify DHCP Config
+// @Description Set the served switches, upstream DNS servers and local domain
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ModifyDHCPConfigRequest true "Modify DHCP Config Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/config [put]
+func ModifyDHCPConfig(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request ModifyDHCPConfigRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		expandHosts := request.ExpandHosts == nil || *request.ExpandHosts
+
+		if err := svc.ModifyDHCPConfig(request.SwitchIDs, request.DNSServers, request.Domain, expandHosts); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_modify_dhcp_config",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "dhcp_config_modified",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary List DHCP Ranges
+// @Description List DHCP address ranges for all switches
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkModels.DHCPRanges] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/range [get]
+func ListDHCPRanges(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := svc.GetDHCPRanges()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_dhcp_ranges",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkModels.DHCPRanges]{
+			Status:  "success",
+			Message: "dhcp_ranges_retrieved",
+			Error:   "",
+			Data:    data,
+		})
+	}
+}
+
+// @Summary Create DHCP Range
+// @Description Add a DHCP address range to a standard switch
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateDHCPRangeRequest true "Create DHCP Range Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/range [post]
+func CreateDHCPRange(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request CreateDHCPRangeRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.CreateDHCPRange(request.SwitchID, request.StartIP, request.EndIP); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_dhcp_range",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "dhcp_range_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete DHCP Range
+// @Description Delete a DHCP address range
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Range ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/range/{id} [delete]
+func DeleteDHCPRange(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "range ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.DeleteDHCPRange(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_dhcp_range",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "dhcp_range_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary List DHCP Static Mappings
+// @Description List static DHCP leases for all switches
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkModels.DHCPStaticMapping] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/static-map [get]
+func ListDHCPStaticMappings(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := svc.GetDHCPStaticMappings()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_dhcp_static_mappings",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkModels.DHCPStaticMapping]{
+			Status:  "success",
+			Message: "dhcp_static_mappings_retrieved",
+			Error:   "",
+			Data:    data,
+		})
+	}
+}
+
+// @Summary Create DHCP Static Mapping
+// @Description Pin a MAC address to an IP and hostname on a standard switch
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateDHCPStaticMappingRequest true "Create DHCP Static Mapping Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/static-map [post]
+func CreateDHCPStaticMapping(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request CreateDHCPStaticMappingRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.CreateDHCPStaticMapping(request.SwitchID, request.Hostname, request.MAC, request.IP, request.Comments, request.Expiry); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_dhcp_static_mapping",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "dhcp_static_mapping_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete DHCP Static Mapping
+// @Description Delete a static DHCP lease
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Mapping ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/static-map/{id} [delete]
+func DeleteDHCPStaticMapping(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "mapping ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.DeleteDHCPStaticMapping(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_dhcp_static_mapping",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "dhcp_static_mapping_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary List DHCP Options
+// @Description List custom DHCP options for all switches
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkModels.DHCPOption] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/option [get]
+func ListDHCPOptions(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := svc.GetDHCPOptions()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_dhcp_options",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkModels.DHCPOption]{
+			Status:  "success",
+			Message: "dhcp_options_retrieved",
+			Error:   "",
+			Data:    data,
+		})
+	}
+}
+
+// @Summary Create DHCP Option
+// @Description Add a custom DHCP option to a standard switch
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateDHCPOptionRequest true "Create DHCP Option Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/option [post]
+func CreateDHCPOption(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request CreateDHCPOptionRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.CreateDHCPOption(request.SwitchID, request.Option, request.Value, request.Comments); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_dhcp_option",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "dhcp_option_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete DHCP Option
+// @Description Delete a custom DHCP option
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Option ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/option/{id} [delete]
+func DeleteDHCPOption(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "option ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.DeleteDHCPOption(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_dhcp_option",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "dhcp_option_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary List DHCP Leases
+// @Description List active leases handed out by the DHCP server
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkServiceInterfaces.DHCPLease] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/dhcp/leases [get]
+func ListDHCPLeases(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := svc.GetDHCPLeases()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_dhcp_leases",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkServiceInterfaces.DHCPLease]{
+			Status:  "success",
+			Message: "dhcp_leases_retrieved",
+			Error:   "",
+			Data:    data,
+		})
+	}
+}
@@ -0,0 +1,401 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	"github.com/alchemillahq/sylve/internal/services/network"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateOrEditFirewallRuleSetRequest struct {
+	Name     string `json:"name" binding:"required"`
+	SwitchID *uint  `json:"switchId"`
+	Priority int    `json:"priority"`
+	Enabled  *bool  `json:"enabled"`
+}
+
+type SetFirewallRulesRequest struct {
+	Rules []networkModels.FirewallRule `json:"rules"`
+}
+
+type ApplyFirewallRequest struct {
+	ConfirmTimeout int `json:"confirmTimeout"`
+}
+
+// @Summary List Firewall Rule Sets
+// @Description List all firewall rule sets with their rules
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkModels.FirewallRuleSet] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/firewall [get]
+func ListFirewallRuleSets(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sets, err := svc.GetFirewallRuleSets()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_firewall_rule_sets",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkModels.FirewallRuleSet]{
+			Status:  "success",
+			Message: "firewall_rule_sets_retrieved",
+			Error:   "",
+			Data:    sets,
+		})
+	}
+}
+
+// @Summary Create Firewall Rule Set
+// @Description Create a host-level rule set, or one bound to a standard switch
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOrEditFirewallRuleSetRequest true "Create Firewall Rule Set Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/firewall/rule-set [post]
+func CreateFirewallRuleSet(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request CreateOrEditFirewallRuleSetRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		enabled := request.Enabled == nil || *request.Enabled
+
+		if err := svc.CreateFirewallRuleSet(request.Name, request.SwitchID, request.Priority, enabled); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_firewall_rule_set",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "firewall_rule_set_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Edit Firewall Rule Set
+// @Description Edit the name, scope, priority or state of a firewall rule set
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rule Set ID"
+// @Param request body CreateOrEditFirewallRuleSetRequest true "Edit Firewall Rule Set Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/firewall/rule-set/{id} [put]
+func EditFirewallRuleSet(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "rule set ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		var request CreateOrEditFirewallRuleSetRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		enabled := request.Enabled == nil || *request.Enabled
+
+		if err := svc.EditFirewallRuleSet(uint(id), request.Name, request.SwitchID, request.Priority, enabled); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_edit_firewall_rule_set",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "firewall_rule_set_updated",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete Firewall Rule Set
+// @Description Delete a firewall rule set and all of its rules
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rule Set ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/firewall/rule-set/{id} [delete]
+func DeleteFirewallRuleSet(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "rule set ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.DeleteFirewallRuleSet(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_firewall_rule_set",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "firewall_rule_set_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Set Firewall Rules
+// @Description Replace the ordered list of rules in a firewall rule set
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rule Set ID"
+// @Param request body SetFirewallRulesRequest true "Set Firewall Rules Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/firewall/rule-set/{id}/rules [put]
+func SetFirewallRules(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "rule set ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		var request SetFirewallRulesRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.SetFirewallRules(uint(id), request.Rules); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_set_firewall_rules",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "firewall_rules_updated",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Get Firewall Status
+// @Description Get pf state along with the applied and staged rule sets
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[networkServiceInterfaces.FirewallStatus] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/firewall/status [get]
+func FirewallStatus(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status, err := svc.GetFirewallStatus()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_firewall_status",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[networkServiceInterfaces.FirewallStatus]{
+			Status:  "success",
+			Message: "firewall_status_retrieved",
+			Error:   "",
+			Data:    status,
+		})
+	}
+}
+
+// @Summary Apply Firewall
+// @Description Validate and load the staged rules; a non-zero confirmTimeout rolls back unless confirmed in time
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ApplyFirewallRequest true "Apply Firewall Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/firewall/apply [post]
+func ApplyFirewall(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request ApplyFirewallRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.ApplyFirewall(request.ConfirmTimeout); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_apply_firewall",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "firewall_applied",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Confirm Firewall
+// @Description Keep the rules loaded by the last pending apply
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/firewall/confirm [post]
+func ConfirmFirewall(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := svc.ConfirmFirewall(); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_confirm_firewall",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "firewall_confirmed",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Rollback Firewall
+// @Description Restore the last confirmed rules without waiting for the confirm timeout
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/firewall/rollback [post]
+func RollbackFirewall(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := svc.RollbackFirewall(); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_rollback_firewall",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "firewall_rolled_back",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkHandlers
+
+import (
+	"net/http"
+
+	"github.com/alchemillahq/sylve/internal"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	"github.com/alchemillahq/sylve/internal/services/network"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SetGeoIPDatabaseRequest struct {
+	Path         string `json:"path"`
+	DownloadUUID string `json:"downloadUuid"`
+}
+
+// @Summary Get GeoIP Database
+// @Description Get the GeoIP country database used to expand Country objects
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[networkModels.GeoIPDatabase] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/geoip [get]
+func GetGeoIPDatabase(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		database, err := svc.GetGeoIPDatabase()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_geoip_database",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[networkModels.GeoIPDatabase]{
+			Status:  "success",
+			Message: "geoip_database_retrieved",
+			Error:   "",
+			Data:    database,
+		})
+	}
+}
+
+// @Summary Set GeoIP Database
+// @Description Set the GeoIP country database from a local CSV path or a completed HTTP download, and expand all Country objects
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SetGeoIPDatabaseRequest true "Set GeoIP Database Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/geoip [put]
+func SetGeoIPDatabase(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request SetGeoIPDatabaseRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.SetGeoIPDatabase(request.Path, request.DownloadUUID); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_set_geoip_database",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "geoip_database_set",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Remove GeoIP Database
+// @Description Remove the GeoIP country database and clear the resolutions of all Country objects
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/geoip [delete]
+func RemoveGeoIPDatabase(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := svc.RemoveGeoIPDatabase(); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_remove_geoip_database",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "geoip_database_removed",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
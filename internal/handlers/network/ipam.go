@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	"github.com/alchemillahq/sylve/internal/services/network"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateOrEditIPPoolRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	SwitchID   uint     `json:"switchId" binding:"required"`
+	SwitchType string   `json:"switchType" binding:"required"`
+	Subnet     string   `json:"subnet" binding:"required"`
+	Gateway    string   `json:"gateway" binding:"required"`
+	Exclusions []string `json:"exclusions"`
+	Comment    string   `json:"comment"`
+}
+
+func (r CreateOrEditIPPoolRequest) toModel() networkModels.IPPool {
+	return networkModels.IPPool{
+		Name:       r.Name,
+		SwitchID:   r.SwitchID,
+		SwitchType: r.SwitchType,
+		Subnet:     r.Subnet,
+		Gateway:    r.Gateway,
+		Exclusions: r.Exclusions,
+		Comment:    r.Comment,
+	}
+}
+
+// @Summary List IP Pools
+// @Description List IP pools with their allocations, utilization and address conflicts
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkServiceInterfaces.IPPoolStatus] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/ip-pool [get]
+func ListIPPools(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pools, err := svc.GetIPPools()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_ip_pools",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkServiceInterfaces.IPPoolStatus]{
+			Status:  "success",
+			Message: "ip_pools_retrieved",
+			Error:   "",
+			Data:    pools,
+		})
+	}
+}
+
+// @Summary Create IP Pool
+// @Description Create an IP pool on a switch for automatic address allocation
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOrEditIPPoolRequest true "Create IP Pool Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/ip-pool [post]
+func CreateIPPool(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request CreateOrEditIPPoolRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.CreateIPPool(request.toModel()); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_ip_pool",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "ip_pool_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Edit IP Pool
+// @Description Edit an IP pool; the subnet and switch can only change while nothing is allocated
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "IP Pool ID"
+// @Param request body CreateOrEditIPPoolRequest true "Edit IP Pool Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/ip-pool/{id} [put]
+func EditIPPool(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		var request CreateOrEditIPPoolRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.EditIPPool(uint(id), request.toModel()); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_edit_ip_pool",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "ip_pool_edited",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete IP Pool
+// @Description Delete an IP pool that has no allocations left
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "IP Pool ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/ip-pool/{id} [delete]
+func DeleteIPPool(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.DeleteIPPool(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_ip_pool",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "ip_pool_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	"github.com/alchemillahq/sylve/internal/services/network"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateOrEditLaggRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Protocol string   `json:"protocol" binding:"required"`
+	Members  []string `json:"members" binding:"required"`
+	MTU      int      `json:"mtu"`
+}
+
+func (r CreateOrEditLaggRequest) toModel() networkModels.Lagg {
+	return networkModels.Lagg{
+		Name:     r.Name,
+		Protocol: r.Protocol,
+		Members:  r.Members,
+		MTU:      r.MTU,
+	}
+}
+
+// @Summary List Laggs
+// @Description List all link aggregation interfaces with their live port and LACP state
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkServiceInterfaces.LaggStatus] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/lagg [get]
+func ListLaggs(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		laggs, err := svc.GetLaggs()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_laggs",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkServiceInterfaces.LaggStatus]{
+			Status:  "success",
+			Message: "laggs_retrieved",
+			Error:   "",
+			Data:    laggs,
+		})
+	}
+}
+
+// @Summary Create Lagg
+// @Description Create a link aggregation interface from physical NICs
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOrEditLaggRequest true "Create Lagg Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/lagg [post]
+func CreateLagg(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request CreateOrEditLaggRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.CreateLagg(request.toModel()); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_lagg",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "lagg_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Edit Lagg
+// @Description Edit the protocol, member ports or MTU of a link aggregation interface
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Lagg ID"
+// @Param request body CreateOrEditLaggRequest true "Edit Lagg Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/lagg/{id} [put]
+func EditLagg(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		var request CreateOrEditLaggRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.EditLagg(uint(id), request.toModel()); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_edit_lagg",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "lagg_edited",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete Lagg
+// @Description Delete a link aggregation interface that is not used by any switch or route
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Lagg ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/lagg/{id} [delete]
+func DeleteLagg(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.DeleteLagg(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_lagg",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "lagg_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
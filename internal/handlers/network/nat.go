@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	"github.com/alchemillahq/sylve/internal/services/network"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SwitchNATRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+type CreateOrEditPortForwardRequest struct {
+	Name              string `json:"name" binding:"required"`
+	Enabled           *bool  `json:"enabled"`
+	Protocol          string `json:"protocol" binding:"required"`
+	Comment           string `json:"comment"`
+	SwitchID          uint   `json:"switchId" binding:"required"`
+	HostPortID        uint   `json:"hostPortId" binding:"required"`
+	DestinationID     uint   `json:"destinationId" binding:"required"`
+	DestinationPortID uint   `json:"destinationPortId" binding:"required"`
+}
+
+func (r CreateOrEditPortForwardRequest) toModel() networkModels.PortForward {
+	return networkModels.PortForward{
+		Name:              r.Name,
+		Enabled:           r.Enabled == nil || *r.Enabled,
+		Protocol:          r.Protocol,
+		Comment:           r.Comment,
+		SwitchID:          r.SwitchID,
+		HostPortID:        r.HostPortID,
+		DestinationID:     r.DestinationID,
+		DestinationPortID: r.DestinationPortID,
+	}
+}
+
+// @Summary Set Switch NAT
+// @Description Enable or disable outbound NAT on the WAN interfaces for a private standard switch
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Switch ID"
+// @Param request body SwitchNATRequest true "Switch NAT Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/switch/standard/{id}/nat [put]
+func SetSwitchNAT(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "switch ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		var request SwitchNATRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.SetSwitchNAT(uint(id), *request.Enabled); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_set_switch_nat",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "switch_nat_updated",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary List Port Forwards
+// @Description List all port forwarding rules
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkModels.PortForward] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/port-forward [get]
+func ListPortForwards(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		forwards, err := svc.GetPortForwards()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_port_forwards",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkModels.PortForward]{
+			Status:  "success",
+			Message: "port_forwards_retrieved",
+			Error:   "",
+			Data:    forwards,
+		})
+	}
+}
+
+// @Summary Create Port Forward
+// @Description Forward a host port to a guest address and port on a private switch
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOrEditPortForwardRequest true "Create Port Forward Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/port-forward [post]
+func CreatePortForward(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request CreateOrEditPortForwardRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.CreatePortForward(request.toModel()); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_port_forward",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "port_forward_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Edit Port Forward
+// @Description Edit an existing port forwarding rule
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Port Forward ID"
+// @Param request body CreateOrEditPortForwardRequest true "Edit Port Forward Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/port-forward/{id} [put]
+func EditPortForward(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "port forward ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		var request CreateOrEditPortForwardRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.EditPortForward(uint(id), request.toModel()); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_edit_port_forward",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "port_forward_updated",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete Port Forward
+// @Description Delete a port forwarding rule
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Port Forward ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/port-forward/{id} [delete]
+func DeletePortForward(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "port forward ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.DeletePortForward(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_port_forward",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "port_forward_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
@@ -57,6 +57,93 @@ func ListNetworkObjects(svc *network.Service) gin.HandlerFunc {
 	}
 }
 
+// @Summary Get Network Object
+// @Description Get a network object by ID, including resolutions and resolution history
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Object ID"
+// @Success 200 {object} internal.APIResponse[networkModels.Object] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/object/{id} [get]
+func GetNetworkObject(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "object ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		object, err := svc.GetObject(uint(id))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_object",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[networkModels.Object]{
+			Status:  "success",
+			Message: "object_retrieved",
+			Error:   "",
+			Data:    object,
+		})
+	}
+}
+
+// @Summary Resolve Network Object
+// @Description Resolve an FQDN network object immediately instead of waiting for its TTL to expire
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Object ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/object/{id}/resolve [post]
+func ResolveNetworkObject(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   "object ID must be an integer",
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.ResolveFQDNObject(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_resolve_object",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "object_resolved",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
 // @Summary Create Network Object
 // @Description Create a new network object with specified type and values
 // @Tags Network
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	"github.com/alchemillahq/sylve/internal/services/network"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateOrEditStaticRouteRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Enabled       *bool  `json:"enabled"`
+	Comment       string `json:"comment"`
+	DestinationID uint   `json:"destinationId" binding:"required"`
+	GatewayID     *uint  `json:"gatewayId"`
+	Interface     string `json:"interface"`
+	Weight        uint32 `json:"weight"`
+	FIB           uint   `json:"fib"`
+}
+
+func (r CreateOrEditStaticRouteRequest) toModel() networkModels.StaticRoute {
+	return networkModels.StaticRoute{
+		Name:          r.Name,
+		Enabled:       r.Enabled == nil || *r.Enabled,
+		Comment:       r.Comment,
+		DestinationID: r.DestinationID,
+		GatewayID:     r.GatewayID,
+		Interface:     r.Interface,
+		Weight:        r.Weight,
+		FIB:           r.FIB,
+	}
+}
+
+// @Summary List Static Routes
+// @Description List all static routes with their live state in the kernel routing table
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkModels.StaticRoute] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/route [get]
+func ListStaticRoutes(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		routes, err := svc.GetStaticRoutes()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_static_routes",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkModels.StaticRoute]{
+			Status:  "success",
+			Message: "static_routes_retrieved",
+			Error:   "",
+			Data:    routes,
+		})
+	}
+}
+
+// @Summary Create Static Route
+// @Description Create a static route to a Network object via a Host object gateway or an interface
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOrEditStaticRouteRequest true "Create Static Route Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/route [post]
+func CreateStaticRoute(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request CreateOrEditStaticRouteRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.CreateStaticRoute(request.toModel()); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_static_route",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "static_route_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Edit Static Route
+// @Description Edit an existing static route
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Route ID"
+// @Param request body CreateOrEditStaticRouteRequest true "Edit Static Route Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/route/{id} [put]
+func EditStaticRoute(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		var request CreateOrEditStaticRouteRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.EditStaticRoute(uint(id), request.toModel()); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_edit_static_route",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "static_route_edited",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete Static Route
+// @Description Delete a static route and withdraw it from the kernel routing table
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Route ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/route/{id} [delete]
+func DeleteStaticRoute(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := svc.DeleteStaticRoute(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_static_route",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "static_route_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Sync Static Routes
+// @Description Reconcile static routes against the kernel routing table
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/route/sync [post]
+func SyncStaticRoutes(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := svc.SyncStaticRoutes(); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_sync_static_routes",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "static_routes_synced",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"strconv"
 
 	"github.com/alchemillahq/sylve/internal"
+	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
 	"github.com/alchemillahq/sylve/internal/services/network"
 
 	"github.com/gin-gonic/gin"
@@ -416,3 +417,64 @@ func UpdateStandardSwitch(networkService *network.Service) gin.HandlerFunc {
 		})
 	}
 }
+
+// @Summary Plan Standard Switch Reconcile
+// @Description Dry-run a reconcile of all standard switches and list the changes that would be applied
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkServiceInterfaces.SwitchChange] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/switch/standard/reconcile [get]
+func PlanStandardSwitchReconcile(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		changes, err := svc.PlanStandardSwitches()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_plan_switch_reconcile",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkServiceInterfaces.SwitchChange]{
+			Status:  "success",
+			Message: "switch_reconcile_planned",
+			Error:   "",
+			Data:    changes,
+		})
+	}
+}
+
+// @Summary Reconcile Standard Switches
+// @Description Apply only the interface and route changes needed to bring all standard switches in line with the database
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/switch/standard/reconcile [post]
+func ReconcileStandardSwitches(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := svc.SyncStandardSwitches(nil, "reconcile"); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_reconcile_switches",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "switches_reconciled",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
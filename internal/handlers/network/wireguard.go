@@ -0,0 +1,337 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package networkHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	"github.com/alchemillahq/sylve/internal/services/network"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateOrEditWireGuardRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Enabled    *bool  `json:"enabled"`
+	PrivateKey string `json:"privateKey"`
+	ListenPort int    `json:"listenPort"`
+	MTU        int    `json:"mtu"`
+	AddressID  *uint  `json:"addressId"`
+	Address6ID *uint  `json:"address6Id"`
+}
+
+func (r CreateOrEditWireGuardRequest) toModel() networkModels.WireGuardInterface {
+	return networkModels.WireGuardInterface{
+		Name:       r.Name,
+		Enabled:    r.Enabled == nil || *r.Enabled,
+		PrivateKey: r.PrivateKey,
+		ListenPort: r.ListenPort,
+		MTU:        r.MTU,
+		AddressID:  r.AddressID,
+		Address6ID: r.Address6ID,
+	}
+}
+
+type CreateOrEditWireGuardPeerRequest struct {
+	Name                string `json:"name" binding:"required"`
+	Enabled             *bool  `json:"enabled"`
+	PublicKey           string `json:"publicKey" binding:"required"`
+	PresharedKey        string `json:"presharedKey"`
+	ClearPresharedKey   bool   `json:"clearPresharedKey"`
+	Endpoint            string `json:"endpoint"`
+	PersistentKeepalive int    `json:"persistentKeepalive"`
+	AllowedIPs          []uint `json:"allowedIps"`
+}
+
+func (r CreateOrEditWireGuardPeerRequest) toModel() networkModels.WireGuardPeer {
+	return networkModels.WireGuardPeer{
+		Name:                r.Name,
+		Enabled:             r.Enabled == nil || *r.Enabled,
+		PublicKey:           r.PublicKey,
+		PresharedKey:        r.PresharedKey,
+		Endpoint:            r.Endpoint,
+		PersistentKeepalive: r.PersistentKeepalive,
+	}
+}
+
+func wireGuardParamID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+			Status:  "error",
+			Message: "invalid_id",
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func wireGuardResult(c *gin.Context, err error, failure string, success string) {
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+			Status:  "error",
+			Message: failure,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, internal.APIResponse[any]{
+		Status:  "success",
+		Message: success,
+		Error:   "",
+		Data:    nil,
+	})
+}
+
+// @Summary List WireGuard Interfaces
+// @Description List WireGuard interfaces and their peers with handshake and transfer statistics
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]networkModels.WireGuardInterface] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/wireguard [get]
+func ListWireGuardInterfaces(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		wgs, err := svc.GetWireGuardInterfaces()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_wireguard_interfaces",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]networkModels.WireGuardInterface]{
+			Status:  "success",
+			Message: "wireguard_interfaces_retrieved",
+			Error:   "",
+			Data:    wgs,
+		})
+	}
+}
+
+// @Summary Create WireGuard Interface
+// @Description Create a WireGuard interface, generating a key pair unless a private key is given
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOrEditWireGuardRequest true "Create WireGuard Interface Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/wireguard [post]
+func CreateWireGuardInterface(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request CreateOrEditWireGuardRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := svc.CreateWireGuardInterface(request.toModel())
+		wireGuardResult(c, err, "failed_to_create_wireguard_interface", "wireguard_interface_created")
+	}
+}
+
+// @Summary Edit WireGuard Interface
+// @Description Edit a WireGuard interface; the private key is kept
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "WireGuard Interface ID"
+// @Param request body CreateOrEditWireGuardRequest true "Edit WireGuard Interface Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/wireguard/{id} [put]
+func EditWireGuardInterface(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := wireGuardParamID(c, "id")
+		if !ok {
+			return
+		}
+
+		var request CreateOrEditWireGuardRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := svc.EditWireGuardInterface(id, request.toModel())
+		wireGuardResult(c, err, "failed_to_edit_wireguard_interface", "wireguard_interface_edited")
+	}
+}
+
+// @Summary Delete WireGuard Interface
+// @Description Delete a WireGuard interface and all of its peers
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "WireGuard Interface ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/wireguard/{id} [delete]
+func DeleteWireGuardInterface(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := wireGuardParamID(c, "id")
+		if !ok {
+			return
+		}
+
+		err := svc.DeleteWireGuardInterface(id)
+		wireGuardResult(c, err, "failed_to_delete_wireguard_interface", "wireguard_interface_deleted")
+	}
+}
+
+// @Summary Rotate WireGuard Key
+// @Description Generate a new key pair for a WireGuard interface; peers must be updated with the new public key
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "WireGuard Interface ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/wireguard/{id}/rotate-key [post]
+func RotateWireGuardKey(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := wireGuardParamID(c, "id")
+		if !ok {
+			return
+		}
+
+		err := svc.RotateWireGuardKey(id)
+		wireGuardResult(c, err, "failed_to_rotate_wireguard_key", "wireguard_key_rotated")
+	}
+}
+
+// @Summary Create WireGuard Peer
+// @Description Add a peer to a WireGuard interface, with allowed IPs taken from network objects
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "WireGuard Interface ID"
+// @Param request body CreateOrEditWireGuardPeerRequest true "Create WireGuard Peer Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/wireguard/{id}/peer [post]
+func CreateWireGuardPeer(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := wireGuardParamID(c, "id")
+		if !ok {
+			return
+		}
+
+		var request CreateOrEditWireGuardPeerRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		peer := request.toModel()
+		peer.InterfaceID = id
+
+		err := svc.CreateWireGuardPeer(peer, request.AllowedIPs)
+		wireGuardResult(c, err, "failed_to_create_wireguard_peer", "wireguard_peer_created")
+	}
+}
+
+// @Summary Edit WireGuard Peer
+// @Description Edit a WireGuard peer
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "WireGuard Interface ID"
+// @Param peerId path int true "Peer ID"
+// @Param request body CreateOrEditWireGuardPeerRequest true "Edit WireGuard Peer Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/wireguard/{id}/peer/{peerId} [put]
+func EditWireGuardPeer(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		peerID, ok := wireGuardParamID(c, "peerId")
+		if !ok {
+			return
+		}
+
+		var request CreateOrEditWireGuardPeerRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := svc.EditWireGuardPeer(peerID, request.toModel(), request.AllowedIPs, request.ClearPresharedKey)
+		wireGuardResult(c, err, "failed_to_edit_wireguard_peer", "wireguard_peer_edited")
+	}
+}
+
+// @Summary Delete WireGuard Peer
+// @Description Remove a peer from a WireGuard interface
+// @Tags Network
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "WireGuard Interface ID"
+// @Param peerId path int true "Peer ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /network/wireguard/{id}/peer/{peerId} [delete]
+func DeleteWireGuardPeer(svc *network.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		peerID, ok := wireGuardParamID(c, "peerId")
+		if !ok {
+			return
+		}
+
+		err := svc.DeleteWireGuardPeer(peerID)
+		wireGuardResult(c, err, "failed_to_delete_wireguard_peer", "wireguard_peer_deleted")
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package nfsHandlers
+
+import (
+	"net/http"
+
+	"github.com/alchemillahq/sylve/internal"
+	nfsModels "github.com/alchemillahq/sylve/internal/db/models/nfs"
+	nfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/nfs"
+	"github.com/alchemillahq/sylve/internal/services/nfs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get NFS Configuration
+// @Description Retrieve the NFS server settings
+// @Tags NFS
+// @Accept json
+// @Produce json
+// @Success 200 {object} internal.APIResponse[nfsModels.NFSSettings] "NFS configuration"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /nfs/config [get]
+func GetConfig(nfsService *nfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		settings, err := nfsService.GetSettings()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_nfs_config",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[nfsModels.NFSSettings]{
+			Status:  "success",
+			Message: "nfs_config_retrieved",
+			Error:   "",
+			Data:    settings,
+		})
+	}
+}
+
+// @Summary Set NFS Configuration
+// @Description Enable or disable the NFS server, NFSv4 and its root
+// @Tags NFS
+// @Accept json
+// @Produce json
+// @Param request body nfsServiceInterfaces.Settings true "NFS Configuration"
+// @Success 200 {object} internal.APIResponse[any] "NFS configuration updated"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /nfs/config [post]
+func SetConfig(nfsService *nfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req nfsServiceInterfaces.Settings
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := nfsService.SetSettings(req); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_set_nfs_config",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "nfs_config_updated",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package nfsHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	nfsModels "github.com/alchemillahq/sylve/internal/db/models/nfs"
+	nfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/nfs"
+	"github.com/alchemillahq/sylve/internal/services/nfs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateExportRequest struct {
+	ID uint `json:"id" binding:"required"`
+	nfsServiceInterfaces.Export
+}
+
+// @Summary Get NFS Exports
+// @Description Retrieve all NFS exports
+// @Tags NFS
+// @Accept json
+// @Produce json
+// @Success 200 {object} internal.APIResponse[[]nfsModels.NFSExport] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /nfs/exports [get]
+func GetExports(nfsService *nfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		exports, err := nfsService.GetExports()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_exports",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]nfsModels.NFSExport]{
+			Status:  "success",
+			Message: "exports_retrieved",
+			Error:   "",
+			Data:    exports,
+		})
+	}
+}
+
+// @Summary Create NFS Export
+// @Description Export a ZFS filesystem over NFS to the given network objects
+// @Tags NFS
+// @Accept json
+// @Produce json
+// @Param request body nfsServiceInterfaces.Export true "Create NFS Export Request"
+// @Success 200 {object} internal.APIResponse[any] "Export created"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /nfs/exports [post]
+func CreateExport(nfsService *nfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req nfsServiceInterfaces.Export
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := nfsService.CreateExport(req); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_create_export",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "export_created",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Update NFS Export
+// @Description Update an existing NFS export
+// @Tags NFS
+// @Accept json
+// @Produce json
+// @Param request body UpdateExportRequest true "Update NFS Export Request"
+// @Success 200 {object} internal.APIResponse[any] "Export updated"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /nfs/exports [put]
+func UpdateExport(nfsService *nfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req UpdateExportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := nfsService.UpdateExport(req.ID, req.Export); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_update_export",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "export_updated",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Delete NFS Export
+// @Description Delete an NFS export by ID
+// @Tags NFS
+// @Accept json
+// @Produce json
+// @Param id path uint true "Export ID"
+// @Success 200 {object} internal.APIResponse[any] "Export deleted"
+// @Failure 400 {object} internal.APIResponse[any] "Invalid request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal server error"
+// @Router /nfs/exports/{id} [delete]
+func DeleteExport(nfsService *nfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_export_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := nfsService.DeleteExport(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_delete_export",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "export_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
@@ -22,9 +22,11 @@ import (
 	clusterHandlers "github.com/alchemillahq/sylve/internal/handlers/cluster"
 	diskHandlers "github.com/alchemillahq/sylve/internal/handlers/disk"
 	infoHandlers "github.com/alchemillahq/sylve/internal/handlers/info"
+	iscsiHandlers "github.com/alchemillahq/sylve/internal/handlers/iscsi"
 	jailHandlers "github.com/alchemillahq/sylve/internal/handlers/jail"
 	"github.com/alchemillahq/sylve/internal/handlers/middleware"
 	networkHandlers "github.com/alchemillahq/sylve/internal/handlers/network"
+	nfsHandlers "github.com/alchemillahq/sylve/internal/handlers/nfs"
 	sambaHandlers "github.com/alchemillahq/sylve/internal/handlers/samba"
 	systemHandlers "github.com/alchemillahq/sylve/internal/handlers/system"
 	utilitiesHandlers "github.com/alchemillahq/sylve/internal/handlers/utilities"
@@ -34,9 +36,11 @@ import (
 	"github.com/alchemillahq/sylve/internal/services/cluster"
 	diskService "github.com/alchemillahq/sylve/internal/services/disk"
 	infoService "github.com/alchemillahq/sylve/internal/services/info"
+	"github.com/alchemillahq/sylve/internal/services/iscsi"
 	"github.com/alchemillahq/sylve/internal/services/jail"
 	"github.com/alchemillahq/sylve/internal/services/libvirt"
 	networkService "github.com/alchemillahq/sylve/internal/services/network"
+	"github.com/alchemillahq/sylve/internal/services/nfs"
 	"github.com/alchemillahq/sylve/internal/services/samba"
 	systemService "github.com/alchemillahq/sylve/internal/services/system"
 	utilitiesService "github.com/alchemillahq/sylve/internal/services/utilities"
@@ -76,6 +80,8 @@ func RegisterRoutes(r *gin.Engine,
 	systemService *systemService.Service,
 	libvirtService *libvirt.Service,
 	sambaService *samba.Service,
+	nfsService *nfs.Service,
+	iscsiService *iscsi.Service,
 	jailService *jail.Service,
 	clusterService *cluster.Service,
 	fsm *clusterModels.FSMDispatcher,
@@ -130,28 +136,60 @@ func RegisterRoutes(r *gin.Engine,
 		zfs.GET("/pool/stats/:interval/:limit", zfsHandlers.PoolStats(zfsService))
 		zfs.GET("/pool/io-delay", zfsHandlers.AvgIODelay(zfsService))
 		zfs.GET("/pool/io-delay/historical", zfsHandlers.AvgIODelayHistorical(zfsService))
+		zfs.GET("/arc", zfsHandlers.ArcStats(zfsService))
+		zfs.GET("/arc/stats/:interval/:limit", zfsHandlers.ArcHistoricalStats(zfsService))
 
 		pools := zfs.Group("/pools")
 		{
 			pools.GET("", zfsHandlers.GetPools(zfsService))
 			pools.GET("/disks-usage", zfsHandlers.GetDisksUsage(zfsService))
+			pools.GET("/importable", zfsHandlers.ImportablePools(zfsService))
+			pools.POST("/import", zfsHandlers.ImportPool(zfsService))
 			pools.POST("", zfsHandlers.CreatePool(infoService, zfsService))
 			pools.PATCH("", zfsHandlers.EditPool(infoService, zfsService))
 			pools.POST("/:guid/scrub", zfsHandlers.ScrubPool(infoService, zfsService))
 			pools.DELETE("/:guid", zfsHandlers.DeletePool(infoService, zfsService))
+			pools.POST("/:guid/export", zfsHandlers.ExportPool(zfsService))
 			pools.POST("/:guid/replace-device", zfsHandlers.ReplaceDevice(infoService, zfsService))
+
+			pools.GET("/:guid/layout", zfsHandlers.GetPoolLayout(zfsService))
+			pools.POST("/:guid/vdevs", zfsHandlers.AddVdev(zfsService))
+			pools.POST("/:guid/vdevs/preview", zfsHandlers.PreviewAddVdev(zfsService))
+			pools.POST("/:guid/vdevs/remove", zfsHandlers.RemoveVdev(zfsService))
+			pools.POST("/:guid/vdevs/remove/preview", zfsHandlers.PreviewRemoveVdev(zfsService))
+			pools.POST("/:guid/attach", zfsHandlers.AttachDevice(zfsService))
+			pools.POST("/:guid/attach/preview", zfsHandlers.PreviewAttachDevice(zfsService))
+			pools.POST("/:guid/detach", zfsHandlers.DetachDevice(zfsService))
+			pools.POST("/:guid/detach/preview", zfsHandlers.PreviewDetachDevice(zfsService))
+
+			pools.GET("/alerts", zfsHandlers.GetPoolAlerts(zfsService))
+			pools.POST("/events/:id/acknowledge", zfsHandlers.AcknowledgePoolEvent(zfsService))
+			pools.GET("/:guid/events", zfsHandlers.GetPoolEvents(zfsService))
+			pools.GET("/:guid/scrubs", zfsHandlers.GetScrubHistory(zfsService))
+			pools.POST("/:guid/trim", zfsHandlers.TrimPool(zfsService))
+			pools.GET("/:guid/maintenance", zfsHandlers.GetPoolMaintenance(zfsService))
+			pools.PUT("/:guid/maintenance", zfsHandlers.SetPoolMaintenance(zfsService))
+			pools.DELETE("/:guid/maintenance/:task", zfsHandlers.DeletePoolMaintenance(zfsService))
 		}
 
 		datasets := zfs.Group("/datasets")
 		{
 			datasets.GET("", zfsHandlers.GetDatasets(zfsService))
+			datasets.GET("/io/:interval/:limit", zfsHandlers.DatasetIOStats(zfsService))
 			datasets.POST("/snapshot", zfsHandlers.CreateSnapshot(zfsService))
 			datasets.POST("/snapshot/rollback", zfsHandlers.RollbackSnapshot(zfsService))
 			datasets.DELETE("/snapshot/:guid", zfsHandlers.DeleteSnapshot(zfsService))
+			datasets.GET("/snapshot/:guid/browse", zfsHandlers.BrowseSnapshot(zfsService, systemService))
+			datasets.GET("/snapshot/:guid/diff", zfsHandlers.DiffSnapshot(zfsService))
+			datasets.POST("/snapshot/:guid/restore", zfsHandlers.RestoreFromSnapshot(zfsService))
 
 			datasets.GET("/snapshot/periodic", zfsHandlers.GetPeriodicSnapshots(zfsService))
 			datasets.POST("/snapshot/periodic", zfsHandlers.CreatePeriodicSnapshot(zfsService))
 			datasets.DELETE("/snapshot/periodic/:guid", zfsHandlers.DeletePeriodicSnapshot(zfsService))
+			datasets.PUT("/snapshot/periodic/:id/retention", zfsHandlers.SetSnapshotRetention(zfsService))
+			datasets.GET("/snapshot/periodic/:id/retention/preview", zfsHandlers.PreviewSnapshotRetention(zfsService))
+			datasets.POST("/snapshot/periodic/:id/prune", zfsHandlers.PruneSnapshots(zfsService))
+			datasets.GET("/snapshot/periodic/:id/prune-log", zfsHandlers.GetSnapshotPruneLogs(zfsService))
 
 			datasets.POST("/filesystem", zfsHandlers.CreateFilesystem(zfsService))
 			datasets.PATCH("/filesystem", zfsHandlers.EditFilesystem(zfsService))
@@ -163,6 +201,11 @@ func RegisterRoutes(r *gin.Engine,
 			datasets.DELETE("/volume/:guid", zfsHandlers.DeleteVolume(zfsService))
 
 			datasets.POST("/bulk-delete", zfsHandlers.BulkDeleteDataset(zfsService))
+
+			datasets.GET("/encryption", zfsHandlers.GetEncryptionRoots(zfsService))
+			datasets.POST("/encryption/:guid/load-key", zfsHandlers.LoadKey(zfsService))
+			datasets.POST("/encryption/:guid/unload-key", zfsHandlers.UnloadKey(zfsService))
+			datasets.POST("/encryption/:guid/change-key", zfsHandlers.ChangeKey(zfsService))
 		}
 	}
 
@@ -182,6 +225,42 @@ func RegisterRoutes(r *gin.Engine,
 		samba.GET("/audit-logs", sambaHandlers.GetAuditLogs(sambaService))
 	}
 
+	nfs := api.Group("/nfs")
+	nfs.Use(EnsureCorrectHost(db))
+	nfs.Use(middleware.EnsureAuthenticated(authService))
+	nfs.Use(middleware.RequestLoggerMiddleware(db, authService))
+	{
+		nfs.GET("/config", nfsHandlers.GetConfig(nfsService))
+		nfs.POST("/config", nfsHandlers.SetConfig(nfsService))
+
+		nfs.GET("/exports", nfsHandlers.GetExports(nfsService))
+		nfs.POST("/exports", nfsHandlers.CreateExport(nfsService))
+		nfs.PUT("/exports", nfsHandlers.UpdateExport(nfsService))
+		nfs.DELETE("/exports/:id", nfsHandlers.DeleteExport(nfsService))
+	}
+
+	iscsi := api.Group("/iscsi")
+	iscsi.Use(EnsureCorrectHost(db))
+	iscsi.Use(middleware.EnsureAuthenticated(authService))
+	iscsi.Use(middleware.RequestLoggerMiddleware(db, authService))
+	{
+		iscsi.GET("/config", iscsiHandlers.GetConfig(iscsiService))
+		iscsi.POST("/config", iscsiHandlers.SetConfig(iscsiService))
+
+		iscsi.GET("/portal-groups", iscsiHandlers.GetPortalGroups(iscsiService))
+		iscsi.POST("/portal-groups", iscsiHandlers.CreatePortalGroup(iscsiService))
+		iscsi.PUT("/portal-groups", iscsiHandlers.UpdatePortalGroup(iscsiService))
+		iscsi.DELETE("/portal-groups/:id", iscsiHandlers.DeletePortalGroup(iscsiService))
+
+		iscsi.GET("/targets", iscsiHandlers.GetTargets(iscsiService))
+		iscsi.POST("/targets", iscsiHandlers.CreateTarget(iscsiService))
+		iscsi.PUT("/targets", iscsiHandlers.UpdateTarget(iscsiService))
+		iscsi.DELETE("/targets/:id", iscsiHandlers.DeleteTarget(iscsiService))
+
+		iscsi.GET("/sessions", iscsiHandlers.GetSessions(iscsiService))
+		iscsi.DELETE("/sessions/:id", iscsiHandlers.LogoutSession(iscsiService))
+	}
+
 	disk := api.Group("/disk")
 	disk.Use(EnsureCorrectHost(db))
 	disk.Use(middleware.EnsureAuthenticated(authService))
@@ -192,6 +271,19 @@ func RegisterRoutes(r *gin.Engine,
 		disk.POST("/initialize-gpt", diskHandlers.InitializeGPT(diskService, infoService))
 		disk.POST("/create-partitions", diskHandlers.CreatePartition(infoService))
 		disk.POST("/delete-partition", diskHandlers.DeletePartition(infoService))
+
+		disk.GET("/smart/health", diskHandlers.DiskHealth(diskService))
+		disk.GET("/smart/schedules", diskHandlers.SmartTestSchedules(diskService))
+		disk.GET("/smart/thresholds", diskHandlers.SmartThresholds(diskService))
+		disk.PUT("/smart/thresholds", diskHandlers.SetSmartThresholds(diskService))
+		disk.DELETE("/smart/thresholds", diskHandlers.DeleteSmartThresholds(diskService))
+		disk.GET("/smart/alerts", diskHandlers.SmartAlerts(diskService))
+		disk.POST("/smart/alerts/:id/acknowledge", diskHandlers.AcknowledgeSmartAlert(diskService))
+		disk.GET("/smart/:uuid/history", diskHandlers.SmartHistory(diskService))
+		disk.GET("/smart/:uuid/self-tests", diskHandlers.SelfTestLog(diskService))
+		disk.POST("/smart/:uuid/self-tests", diskHandlers.RunSelfTest(diskService))
+		disk.PUT("/smart/:uuid/schedules", diskHandlers.SetSmartTestSchedule(diskService))
+		disk.DELETE("/smart/:uuid/schedules/:type", diskHandlers.DeleteSmartTestSchedule(diskService))
 	}
 
 	network := api.Group("/network")
@@ -200,10 +292,16 @@ func RegisterRoutes(r *gin.Engine,
 	network.Use(middleware.RequestLoggerMiddleware(db, authService))
 	{
 		network.GET("/object", networkHandlers.ListNetworkObjects(networkService))
+		network.GET("/object/:id", networkHandlers.GetNetworkObject(networkService))
+		network.POST("/object/:id/resolve", networkHandlers.ResolveNetworkObject(networkService))
 		network.POST("/object", networkHandlers.CreateNetworkObject(networkService))
 		network.DELETE("/object/:id", networkHandlers.DeleteNetworkObject(networkService))
 		network.PUT("/object/:id", networkHandlers.EditNetworkObject(networkService))
 
+		network.GET("/geoip", networkHandlers.GetGeoIPDatabase(networkService))
+		network.PUT("/geoip", networkHandlers.SetGeoIPDatabase(networkService))
+		network.DELETE("/geoip", networkHandlers.RemoveGeoIPDatabase(networkService))
+
 		network.GET("/interface", networkHandlers.ListInterfaces(networkService))
 
 		network.POST("/manual-switch", networkHandlers.CreateManualSwitch(networkService))
@@ -213,6 +311,62 @@ func RegisterRoutes(r *gin.Engine,
 		network.POST("/switch/standard", networkHandlers.CreateStandardSwitch(networkService))
 		network.DELETE("/switch/standard/:id", networkHandlers.DeleteStandardSwitch(networkService))
 		network.PUT("/switch/standard", networkHandlers.UpdateStandardSwitch(networkService))
+		network.PUT("/switch/standard/:id/nat", networkHandlers.SetSwitchNAT(networkService))
+		network.GET("/switch/standard/reconcile", networkHandlers.PlanStandardSwitchReconcile(networkService))
+		network.POST("/switch/standard/reconcile", networkHandlers.ReconcileStandardSwitches(networkService))
+
+		network.GET("/port-forward", networkHandlers.ListPortForwards(networkService))
+		network.POST("/port-forward", networkHandlers.CreatePortForward(networkService))
+		network.PUT("/port-forward/:id", networkHandlers.EditPortForward(networkService))
+		network.DELETE("/port-forward/:id", networkHandlers.DeletePortForward(networkService))
+
+		network.GET("/route", networkHandlers.ListStaticRoutes(networkService))
+		network.POST("/route", networkHandlers.CreateStaticRoute(networkService))
+		network.POST("/route/sync", networkHandlers.SyncStaticRoutes(networkService))
+		network.PUT("/route/:id", networkHandlers.EditStaticRoute(networkService))
+		network.DELETE("/route/:id", networkHandlers.DeleteStaticRoute(networkService))
+
+		network.GET("/lagg", networkHandlers.ListLaggs(networkService))
+		network.POST("/lagg", networkHandlers.CreateLagg(networkService))
+		network.PUT("/lagg/:id", networkHandlers.EditLagg(networkService))
+		network.DELETE("/lagg/:id", networkHandlers.DeleteLagg(networkService))
+
+		network.GET("/wireguard", networkHandlers.ListWireGuardInterfaces(networkService))
+		network.POST("/wireguard", networkHandlers.CreateWireGuardInterface(networkService))
+		network.PUT("/wireguard/:id", networkHandlers.EditWireGuardInterface(networkService))
+		network.DELETE("/wireguard/:id", networkHandlers.DeleteWireGuardInterface(networkService))
+		network.POST("/wireguard/:id/rotate-key", networkHandlers.RotateWireGuardKey(networkService))
+		network.POST("/wireguard/:id/peer", networkHandlers.CreateWireGuardPeer(networkService))
+		network.PUT("/wireguard/:id/peer/:peerId", networkHandlers.EditWireGuardPeer(networkService))
+		network.DELETE("/wireguard/:id/peer/:peerId", networkHandlers.DeleteWireGuardPeer(networkService))
+
+		network.GET("/ip-pool", networkHandlers.ListIPPools(networkService))
+		network.POST("/ip-pool", networkHandlers.CreateIPPool(networkService))
+		network.PUT("/ip-pool/:id", networkHandlers.EditIPPool(networkService))
+		network.DELETE("/ip-pool/:id", networkHandlers.DeleteIPPool(networkService))
+
+		network.GET("/dhcp/config", networkHandlers.GetDHCPConfig(networkService))
+		network.PUT("/dhcp/config", networkHandlers.ModifyDHCPConfig(networkService))
+		network.GET("/dhcp/range", networkHandlers.ListDHCPRanges(networkService))
+		network.POST("/dhcp/range", networkHandlers.CreateDHCPRange(networkService))
+		network.DELETE("/dhcp/range/:id", networkHandlers.DeleteDHCPRange(networkService))
+		network.GET("/dhcp/static-map", networkHandlers.ListDHCPStaticMappings(networkService))
+		network.POST("/dhcp/static-map", networkHandlers.CreateDHCPStaticMapping(networkService))
+		network.DELETE("/dhcp/static-map/:id", networkHandlers.DeleteDHCPStaticMapping(networkService))
+		network.GET("/dhcp/option", networkHandlers.ListDHCPOptions(networkService))
+		network.POST("/dhcp/option", networkHandlers.CreateDHCPOption(networkService))
+		network.DELETE("/dhcp/option/:id", networkHandlers.DeleteDHCPOption(networkService))
+		network.GET("/dhcp/leases", networkHandlers.ListDHCPLeases(networkService))
+
+		network.GET("/firewall", networkHandlers.ListFirewallRuleSets(networkService))
+		network.GET("/firewall/status", networkHandlers.FirewallStatus(networkService))
+		network.POST("/firewall/rule-set", networkHandlers.CreateFirewallRuleSet(networkService))
+		network.PUT("/firewall/rule-set/:id", networkHandlers.EditFirewallRuleSet(networkService))
+		network.DELETE("/firewall/rule-set/:id", networkHandlers.DeleteFirewallRuleSet(networkService))
+		network.PUT("/firewall/rule-set/:id/rules", networkHandlers.SetFirewallRules(networkService))
+		network.POST("/firewall/apply", networkHandlers.ApplyFirewall(networkService))
+		network.POST("/firewall/confirm", networkHandlers.ConfirmFirewall(networkService))
+		network.POST("/firewall/rollback", networkHandlers.RollbackFirewall(networkService))
 	}
 
 	system := api.Group("/system")
@@ -255,7 +409,7 @@ func RegisterRoutes(r *gin.Engine,
 		vm.POST("/:action/:id", vmHandlers.VMActionHandler(libvirtService))
 		vm.GET("/simple", vmHandlers.ListVMsSimple(libvirtService))
 		vm.GET("", vmHandlers.ListVMs(libvirtService))
-		vm.POST("", vmHandlers.CreateVM(libvirtService))
+		vm.POST("", vmHandlers.CreateVM(libvirtService, networkService))
 		vm.DELETE("/:id", vmHandlers.RemoveVM(libvirtService))
 		vm.GET("/domain/:id", vmHandlers.GetLvDomain(libvirtService))
 		vm.GET("/stats/:vmId/:limit", vmHandlers.GetVMStats(libvirtService))
@@ -292,11 +446,31 @@ func RegisterRoutes(r *gin.Engine,
 		jail.PUT("/cpu", jailHandlers.UpdateJailCPU(jailService))
 		jail.GET("/stats/:ctId/:limit", jailHandlers.GetJailStats(jailService))
 		jail.PUT("/resource-limits/:ctId", jailHandlers.UpdateResourceLimits(jailService))
+		jail.GET("/rctl/:ctId", jailHandlers.GetJailRctlLimits(jailService))
+		jail.PUT("/rctl", jailHandlers.UpdateJailRctlLimits(jailService))
+		jail.GET("/parameters/:ctId", jailHandlers.GetJailParameters(jailService))
+		jail.PUT("/parameters", jailHandlers.UpdateJailParameters(jailService))
+
+		jail.GET("/pkg/:ctId", jailHandlers.ListJailPackages(jailService))
+		jail.POST("/pkg/install", jailHandlers.InstallJailPackages(jailService))
+		jail.POST("/pkg/remove", jailHandlers.RemoveJailPackages(jailService))
+		jail.POST("/pkg/upgrade", jailHandlers.UpgradeJailPackages(jailService))
+		jail.GET("/pkg/audit/:ctId", jailHandlers.GetJailPackageVulnerabilities(jailService))
+		jail.POST("/pkg/audit/:ctId", jailHandlers.AuditJailPackages(jailService))
+		jail.POST("/pkg/audit", jailHandlers.AuditAllJailPackages(jailService))
+		jail.GET("/pkg/audit-schedule", jailHandlers.GetPackageAuditSchedule(jailService))
+		jail.PUT("/pkg/audit-schedule", jailHandlers.UpdatePackageAuditSchedule(jailService))
+
+		jail.GET("/delegated-datasets/:ctId", jailHandlers.GetDelegatedDatasets(jailService))
+		jail.POST("/delegated-datasets", jailHandlers.DelegateDataset(jailService))
+		jail.DELETE("/delegated-datasets/:ctId/:id", jailHandlers.RemoveDelegatedDataset(jailService))
 
 		jail.POST("", jailHandlers.CreateJail(jailService))
 		jail.DELETE("/:ctid", jailHandlers.DeleteJail(jailService))
 
 		jail.GET("/console", jailHandlers.HandleJailTerminalWebsocket)
+		jail.POST("/:ctId/exec", jailHandlers.ExecInJail(jailService))
+		jail.GET("/exec/stream", jailHandlers.ExecInJailStream(jailService))
 		jail.POST("/network/inheritance", jailHandlers.InheritJailNetwork(jailService))
 		jail.DELETE("/network/disinherit/:ctId", jailHandlers.DisinheritJailNetwork(jailService))
 
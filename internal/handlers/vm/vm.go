@@ -15,6 +15,7 @@ import (
 	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
 	libvirtServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/libvirt"
 	"github.com/alchemillahq/sylve/internal/services/libvirt"
+	"github.com/alchemillahq/sylve/internal/services/network"
 
 	"github.com/gin-gonic/gin"
 )
@@ -127,7 +128,7 @@ func GetLvDomain(libvirtService *libvirt.Service) gin.HandlerFunc {
 // @Failure 400 {object} internal.APIResponse[any] "Bad Request"
 // @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
 // @Router /vm [post]
-func CreateVM(libvirtService *libvirt.Service) gin.HandlerFunc {
+func CreateVM(libvirtService *libvirt.Service, networkService *network.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req libvirtServiceInterfaces.CreateVMRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -140,7 +141,12 @@ func CreateVM(libvirtService *libvirt.Service) gin.HandlerFunc {
 			return
 		}
 
-		err := libvirtService.CreateVM(req)
+		var err error
+		if req.AutoIP != nil && *req.AutoIP {
+			err = networkService.CreateVMWithPoolAddress(req)
+		} else {
+			err = libvirtService.CreateVM(req)
+		}
 
 		if err != nil {
 			c.JSON(500, internal.APIResponse[any]{Error: "failed_to_create: " + err.Error()})
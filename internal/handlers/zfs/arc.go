@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfsHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	"github.com/alchemillahq/sylve/internal/db"
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/internal/services/zfs"
+
+	"github.com/gin-gonic/gin"
+
+	zfsUtils "github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+type ArcStatsResponse struct {
+	zfsUtils.ArcStats
+	HitRatio      float64 `json:"hitRatio"`
+	L2HitRatio    float64 `json:"l2HitRatio"`
+	CompressRatio float64 `json:"compressRatio"`
+}
+
+type ArcStatPointResponse struct {
+	ArcStatPoint []zfsServiceInterfaces.ArcStatPoint `json:"arcStatPoint"`
+	IntervalMap  []db.IntervalOption                 `json:"intervalMap"`
+}
+
+type DatasetIOPointResponse struct {
+	DatasetIOPoint map[string][]zfsServiceInterfaces.DatasetIOPoint `json:"datasetIOPoint"`
+	IntervalMap    []db.IntervalOption                              `json:"intervalMap"`
+}
+
+func intervalAndLimit(c *gin.Context) (int, int, bool) {
+	interval, err := strconv.Atoi(c.Param("interval"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+			Status:  "error",
+			Message: "invalid_interval",
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return 0, 0, false
+	}
+
+	limit, err := strconv.Atoi(c.Param("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+			Status:  "error",
+			Message: "invalid_limit",
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return 0, 0, false
+	}
+
+	return interval, limit, true
+}
+
+// @Summary Get ARC Stats
+// @Description Get the current size, hit ratios and compression of the ZFS ARC and L2ARC
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[ArcStatsResponse] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/arc [get]
+func ArcStats(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats, err := zfsUtils.GetArcStats()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_arc_stats",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[ArcStatsResponse]{
+			Status:  "success",
+			Message: "arc_stats",
+			Error:   "",
+			Data: ArcStatsResponse{
+				ArcStats:      stats,
+				HitRatio:      stats.HitRatio(),
+				L2HitRatio:    stats.L2HitRatio(),
+				CompressRatio: stats.CompressRatio(),
+			},
+		})
+	}
+}
+
+// @Summary Get ARC Historical Stats
+// @Description Get the historical size and hit ratios of the ZFS ARC
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param interval path int true "Interval in minutes"
+// @Param limit path int true "Limit"
+// @Success 200 {object} internal.APIResponse[ArcStatPointResponse] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/arc/stats/{interval}/{limit} [get]
+func ArcHistoricalStats(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		interval, limit, ok := intervalAndLimit(c)
+		if !ok {
+			return
+		}
+
+		stats, count, err := zfsService.GetArcHistoricalStats(interval, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[ArcStatPointResponse]{
+			Status:  "success",
+			Message: "arc_stats",
+			Error:   "",
+			Data: ArcStatPointResponse{
+				ArcStatPoint: stats,
+				IntervalMap:  db.IntervalToMap(count),
+			},
+		})
+	}
+}
+
+// @Summary Get Dataset IO Stats
+// @Description Get the historical read and write throughput of ZFS datasets
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param interval path int true "Interval in minutes"
+// @Param limit path int true "Limit"
+// @Param dataset query string false "Only return this dataset"
+// @Success 200 {object} internal.APIResponse[DatasetIOPointResponse] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/io/{interval}/{limit} [get]
+func DatasetIOStats(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		interval, limit, ok := intervalAndLimit(c)
+		if !ok {
+			return
+		}
+
+		stats, count, err := zfsService.GetDatasetIOHistoricalStats(interval, limit, c.Query("dataset"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[DatasetIOPointResponse]{
+			Status:  "success",
+			Message: "dataset_io_stats",
+			Error:   "",
+			Data: DatasetIOPointResponse{
+				DatasetIOPoint: stats,
+				IntervalMap:    db.IntervalToMap(count),
+			},
+		})
+	}
+}
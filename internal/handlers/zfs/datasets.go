@@ -31,6 +31,8 @@ type CreatePeriodicSnapshotJobRequest struct {
 	Recursive bool   `json:"recursive"`
 	Interval  *int   `json:"interval" binding:"required"`
 	CronExpr  string `json:"cronExpr"`
+
+	Retention zfsModels.SnapshotRetention `json:"retention"`
 }
 
 type CreateFilesystemRequest struct {
@@ -315,7 +317,7 @@ func CreatePeriodicSnapshot(zfsService *zfs.Service) gin.HandlerFunc {
 			cronExpr = request.CronExpr
 		}
 
-		err := zfsService.AddPeriodicSnapshot(request.GUID, request.Prefix, request.Recursive, interval, cronExpr)
+		err := zfsService.AddPeriodicSnapshot(request.GUID, request.Prefix, request.Recursive, interval, cronExpr, request.Retention)
 
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfsHandlers
+
+import (
+	"net/http"
+
+	"github.com/alchemillahq/sylve/internal"
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/internal/services/zfs"
+	"github.com/gin-gonic/gin"
+
+	zfsUtils "github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+func encryptionResult(c *gin.Context, err error, failure string, success string) {
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "dataset_not_found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, internal.APIResponse[any]{
+			Status:  "error",
+			Message: failure,
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, internal.APIResponse[any]{
+		Status:  "success",
+		Message: success,
+		Error:   "",
+		Data:    nil,
+	})
+}
+
+// @Summary List Encryption Roots
+// @Description List encrypted datasets that own a key, along with their key format, location and status
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]zfsUtils.EncryptionInfo] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/encryption [get]
+func GetEncryptionRoots(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roots, err := zfsService.GetEncryptionRoots()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "failed_to_get_encryption_roots",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]zfsUtils.EncryptionInfo]{
+			Status:  "success",
+			Message: "encryption_roots",
+			Error:   "",
+			Data:    roots,
+		})
+	}
+}
+
+// @Summary Load Encryption Key
+// @Description Unlock the encryption root of a dataset and mount its filesystems. The key may be omitted when it is stored by Sylve.
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Dataset GUID"
+// @Param request body zfsServiceInterfaces.LoadEncryptionKey false "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/encryption/{guid}/load-key [post]
+func LoadKey(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.LoadEncryptionKey
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&request); err != nil {
+				c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+					Status:  "error",
+					Message: "invalid_request",
+					Error:   err.Error(),
+					Data:    nil,
+				})
+				return
+			}
+		}
+
+		err := zfsService.LoadKey(c.Param("guid"), request.Key)
+		encryptionResult(c, err, "failed_to_load_key", "key_loaded")
+	}
+}
+
+// @Summary Unload Encryption Key
+// @Description Unmount the filesystems of an encryption root and unload its key
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Dataset GUID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/encryption/{guid}/unload-key [post]
+func UnloadKey(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := zfsService.UnloadKey(c.Param("guid"))
+		encryptionResult(c, err, "failed_to_unload_key", "key_unloaded")
+	}
+}
+
+// @Summary Change Encryption Key
+// @Description Rewrap an encryption root with a new passphrase, hex or raw key, optionally storing it in Sylve
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Dataset GUID"
+// @Param request body zfsServiceInterfaces.ChangeEncryptionKey true "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/encryption/{guid}/change-key [post]
+func ChangeKey(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.ChangeEncryptionKey
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := zfsService.ChangeKey(c.Param("guid"), request)
+		encryptionResult(c, err, "failed_to_change_key", "key_changed")
+	}
+}
@@ -324,6 +324,139 @@ func DeletePool(infoService *info.Service, zfsService *zfs.Service) gin.HandlerF
 	}
 }
 
+// @Summary List Importable Pools
+// @Description Scan attached devices for exported ZFS pools that can be imported
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param dir query []string false "Directories to search for devices"
+// @Success 200 {object} internal.APIResponse[[]zfsUtils.ImportablePool] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/importable [get]
+func ImportablePools(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pools, err := zfsService.ImportablePools(c.QueryArray("dir"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]zfsUtils.ImportablePool]{
+			Status:  "success",
+			Message: "importable_pools",
+			Error:   "",
+			Data:    pools,
+		})
+	}
+}
+
+// @Summary Import Pool
+// @Description Import an exported ZFS pool by name or GUID, optionally renaming it
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body zfsServiceInterfaces.ImportPool true "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/import [post]
+func ImportPool(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.ImportPool
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := zfsService.ImportPool(request); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "pool_import_failed",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "pool_imported",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Export Pool
+// @Description Export a ZFS pool so it can be moved to another machine
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param request body zfsServiceInterfaces.ExportPool false "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/export [post]
+func ExportPool(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		guid := c.Param("guid")
+
+		var request zfsServiceInterfaces.ExportPool
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&request); err != nil {
+				c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+					Status:  "error",
+					Message: "invalid_request",
+					Error:   err.Error(),
+					Data:    nil,
+				})
+				return
+			}
+		}
+
+		if err := zfsService.ExportPool(guid, request.Force); err != nil {
+			if strings.HasPrefix(err.Error(), "pool_not_found") {
+				c.JSON(http.StatusNotFound, internal.APIResponse[any]{
+					Status:  "error",
+					Message: "pool_not_found",
+					Error:   err.Error(),
+					Data:    nil,
+				})
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "pool_export_failed",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "pool_exported",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
 // @Summary Replace Device
 // @Description Replace a device in a ZFS pool
 // @Tags ZFS
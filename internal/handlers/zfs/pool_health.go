@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfsHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	zfsModels "github.com/alchemillahq/sylve/internal/db/models/zfs"
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/internal/services/zfs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Pool Maintenance
+// @Description Get the scheduled scrub and TRIM jobs of a pool
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Success 200 {object} internal.APIResponse[[]zfsModels.PoolMaintenance] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/maintenance [get]
+func GetPoolMaintenance(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		jobs, err := zfsService.GetPoolMaintenance(c.Param("guid"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]zfsModels.PoolMaintenance]{
+			Status:  "success",
+			Message: "pool_maintenance",
+			Error:   "",
+			Data:    jobs,
+		})
+	}
+}
+
+// @Summary Set Pool Maintenance
+// @Description Create or update the scrub or TRIM schedule of a pool
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param request body zfsServiceInterfaces.PoolMaintenance true "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/maintenance [put]
+func SetPoolMaintenance(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.PoolMaintenance
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := zfsService.SetPoolMaintenance(c.Param("guid"), request)
+		poolChanged(c, err, "pool_maintenance_update_failed", "pool_maintenance_updated")
+	}
+}
+
+// @Summary Delete Pool Maintenance
+// @Description Delete the scrub or TRIM schedule of a pool
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param task path string true "Task (scrub or trim)"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/maintenance/{task} [delete]
+func DeletePoolMaintenance(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := zfsService.DeletePoolMaintenance(c.Param("guid"), c.Param("task")); err != nil {
+			status := http.StatusInternalServerError
+			if err.Error() == "maintenance_not_found" {
+				status = http.StatusNotFound
+			}
+
+			c.JSON(status, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "pool_maintenance_delete_failed",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "pool_maintenance_deleted",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Trim Pool
+// @Description Start a manual TRIM of all devices in a pool that support it
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/trim [post]
+func TrimPool(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := zfsService.TrimPool(c.Param("guid"))
+		poolChanged(c, err, "pool_trim_failed", "pool_trim_started")
+	}
+}
+
+// @Summary Get Scrub History
+// @Description Get the recorded results of past scrubs and resilvers of a pool
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Success 200 {object} internal.APIResponse[[]zfsModels.PoolScrubResult] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/scrubs [get]
+func GetScrubHistory(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		results, err := zfsService.GetScrubHistory(c.Param("guid"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]zfsModels.PoolScrubResult]{
+			Status:  "success",
+			Message: "scrub_history",
+			Error:   "",
+			Data:    results,
+		})
+	}
+}
+
+// @Summary Get Pool Events
+// @Description Get the health event history of a pool, newest first
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Success 200 {object} internal.APIResponse[[]zfsModels.PoolEvent] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/events [get]
+func GetPoolEvents(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		events, err := zfsService.GetPoolEvents(c.Param("guid"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]zfsModels.PoolEvent]{
+			Status:  "success",
+			Message: "pool_events",
+			Error:   "",
+			Data:    events,
+		})
+	}
+}
+
+// @Summary Get Pool Alerts
+// @Description Get unacknowledged warning and critical events of all pools
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} internal.APIResponse[[]zfsModels.PoolEvent] "Success"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/alerts [get]
+func GetPoolAlerts(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		events, err := zfsService.GetPoolAlerts()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]zfsModels.PoolEvent]{
+			Status:  "success",
+			Message: "pool_alerts",
+			Error:   "",
+			Data:    events,
+		})
+	}
+}
+
+// @Summary Acknowledge Pool Event
+// @Description Acknowledge a pool event so it no longer shows up as an alert
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Event ID"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/events/{id}/acknowledge [post]
+func AcknowledgePoolEvent(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_id",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := zfsService.AcknowledgePoolEvent(uint(id)); err != nil {
+			status := http.StatusInternalServerError
+			if err.Error() == "pool_event_not_found" {
+				status = http.StatusNotFound
+			}
+
+			c.JSON(status, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "pool_event_acknowledge_failed",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "pool_event_acknowledged",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
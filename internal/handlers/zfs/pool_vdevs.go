@@ -0,0 +1,332 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfsHandlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alchemillahq/sylve/internal"
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/internal/services/zfs"
+	"github.com/gin-gonic/gin"
+
+	zfsUtils "github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+func poolChangeError(c *gin.Context, err error, failure string) {
+	status := http.StatusInternalServerError
+	if strings.HasPrefix(err.Error(), "pool_not_found") {
+		status = http.StatusNotFound
+		failure = "pool_not_found"
+	}
+
+	c.JSON(status, internal.APIResponse[any]{
+		Status:  "error",
+		Message: failure,
+		Error:   err.Error(),
+		Data:    nil,
+	})
+}
+
+func poolChangeResult(c *gin.Context, preview zfsServiceInterfaces.PoolChangePreview, err error) {
+	if err != nil {
+		poolChangeError(c, err, "pool_change_preview_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, internal.APIResponse[zfsServiceInterfaces.PoolChangePreview]{
+		Status:  "success",
+		Message: "pool_change_preview",
+		Error:   "",
+		Data:    preview,
+	})
+}
+
+func poolChanged(c *gin.Context, err error, failure string, success string) {
+	if err != nil {
+		poolChangeError(c, err, failure)
+		return
+	}
+
+	c.JSON(http.StatusOK, internal.APIResponse[any]{
+		Status:  "success",
+		Message: success,
+		Error:   "",
+		Data:    nil,
+	})
+}
+
+// @Summary Get Pool Layout
+// @Description Get the vdev layout of a pool grouped by allocation class
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Success 200 {object} internal.APIResponse[zfsUtils.PoolLayout] "Success"
+// @Failure 404 {object} internal.APIResponse[any] "Not Found"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/layout [get]
+func GetPoolLayout(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		layout, err := zfsService.GetPoolLayout(c.Param("guid"))
+		if err != nil {
+			poolChangeError(c, err, "failed_to_get_pool_layout")
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[zfsUtils.PoolLayout]{
+			Status:  "success",
+			Message: "pool_layout",
+			Error:   "",
+			Data:    layout,
+		})
+	}
+}
+
+// @Summary Preview Adding Vdev
+// @Description Validate adding a data, log, cache, special or dedup vdev and show the resulting size, redundancy and configuration
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param request body zfsServiceInterfaces.PoolVdevAdd true "Request"
+// @Success 200 {object} internal.APIResponse[zfsServiceInterfaces.PoolChangePreview] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/vdevs/preview [post]
+func PreviewAddVdev(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.PoolVdevAdd
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		preview, err := zfsService.PreviewAddVdev(c.Param("guid"), request)
+		poolChangeResult(c, preview, err)
+	}
+}
+
+// @Summary Add Vdev
+// @Description Add a data, log, cache, special or dedup vdev to a pool
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param request body zfsServiceInterfaces.PoolVdevAdd true "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/vdevs [post]
+func AddVdev(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.PoolVdevAdd
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := zfsService.AddVdev(c.Param("guid"), request)
+		poolChanged(c, err, "vdev_add_failed", "vdev_added")
+	}
+}
+
+// @Summary Preview Removing Vdev
+// @Description Validate removing a top-level vdev and show the resulting size and redundancy
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param request body zfsServiceInterfaces.PoolDeviceRef true "Request"
+// @Success 200 {object} internal.APIResponse[zfsServiceInterfaces.PoolChangePreview] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/vdevs/remove/preview [post]
+func PreviewRemoveVdev(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.PoolDeviceRef
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		preview, err := zfsService.PreviewRemoveVdev(c.Param("guid"), request.Device)
+		poolChangeResult(c, preview, err)
+	}
+}
+
+// @Summary Remove Vdev
+// @Description Remove a top-level data, log, cache, spare, special or dedup vdev from a pool
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param request body zfsServiceInterfaces.PoolDeviceRef true "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/vdevs/remove [post]
+func RemoveVdev(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.PoolDeviceRef
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := zfsService.RemoveVdev(c.Param("guid"), request.Device)
+		poolChanged(c, err, "vdev_remove_failed", "vdev_removed")
+	}
+}
+
+// @Summary Preview Attaching Device
+// @Description Validate attaching a device to a disk, mirror or raidz vdev and show the resulting size and redundancy
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param request body zfsServiceInterfaces.PoolDeviceAttach true "Request"
+// @Success 200 {object} internal.APIResponse[zfsServiceInterfaces.PoolChangePreview] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/attach/preview [post]
+func PreviewAttachDevice(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.PoolDeviceAttach
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		preview, err := zfsService.PreviewAttachDevice(c.Param("guid"), request)
+		poolChangeResult(c, preview, err)
+	}
+}
+
+// @Summary Attach Device
+// @Description Attach a device to turn a disk into a mirror, widen a mirror or expand a raidz vdev
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param request body zfsServiceInterfaces.PoolDeviceAttach true "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/attach [post]
+func AttachDevice(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.PoolDeviceAttach
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := zfsService.AttachDevice(c.Param("guid"), request)
+		poolChanged(c, err, "device_attach_failed", "device_attached")
+	}
+}
+
+// @Summary Preview Detaching Device
+// @Description Validate detaching a mirror member and show the resulting redundancy
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param request body zfsServiceInterfaces.PoolDeviceRef true "Request"
+// @Success 200 {object} internal.APIResponse[zfsServiceInterfaces.PoolChangePreview] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/detach/preview [post]
+func PreviewDetachDevice(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.PoolDeviceRef
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		preview, err := zfsService.PreviewDetachDevice(c.Param("guid"), request.Device)
+		poolChangeResult(c, preview, err)
+	}
+}
+
+// @Summary Detach Device
+// @Description Detach a member from a mirror
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Pool GUID"
+// @Param request body zfsServiceInterfaces.PoolDeviceRef true "Request"
+// @Success 200 {object} internal.APIResponse[any] "Success"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/pools/{guid}/detach [post]
+func DetachDevice(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request zfsServiceInterfaces.PoolDeviceRef
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := zfsService.DetachDevice(c.Param("guid"), request.Device)
+		poolChanged(c, err, "device_detach_failed", "device_detached")
+	}
+}
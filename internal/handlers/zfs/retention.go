@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfsHandlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/internal"
+	zfsModels "github.com/alchemillahq/sylve/internal/db/models/zfs"
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/internal/services/zfs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func periodicSnapshotID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+			Status:  "error",
+			Message: "invalid_id",
+			Error:   err.Error(),
+			Data:    nil,
+		})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// @Summary Set Snapshot Retention
+// @Description Set the grandfather-father-son retention policy of a periodic snapshot job
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Periodic Snapshot ID"
+// @Param request body zfsModels.SnapshotRetention true "Retention Policy"
+// @Success 200 {object} internal.APIResponse[any] "OK"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/snapshot/periodic/{id}/retention [put]
+func SetSnapshotRetention(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := periodicSnapshotID(c)
+		if !ok {
+			return
+		}
+
+		var request zfsModels.SnapshotRetention
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		if err := zfsService.SetSnapshotRetention(id, request); err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "snapshot_retention_updated",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
+
+// @Summary Preview Snapshot Retention
+// @Description Dry run of a periodic snapshot job's retention policy, showing which snapshots would be kept or destroyed
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Periodic Snapshot ID"
+// @Success 200 {object} internal.APIResponse[[]zfsServiceInterfaces.SnapshotRetentionItem] "OK"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/snapshot/periodic/{id}/retention/preview [get]
+func PreviewSnapshotRetention(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := periodicSnapshotID(c)
+		if !ok {
+			return
+		}
+
+		items, err := zfsService.PreviewSnapshotRetention(id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]zfsServiceInterfaces.SnapshotRetentionItem]{
+			Status:  "success",
+			Message: "snapshot_retention_preview",
+			Error:   "",
+			Data:    items,
+		})
+	}
+}
+
+// @Summary Prune Snapshots
+// @Description Destroy the snapshots of a periodic job that its retention policy no longer keeps
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Periodic Snapshot ID"
+// @Success 200 {object} internal.APIResponse[[]zfsServiceInterfaces.SnapshotRetentionItem] "OK"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/snapshot/periodic/{id}/prune [post]
+func PruneSnapshots(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := periodicSnapshotID(c)
+		if !ok {
+			return
+		}
+
+		items, err := zfsService.PruneSnapshots(id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]zfsServiceInterfaces.SnapshotRetentionItem]{
+			Status:  "success",
+			Message: "snapshots_pruned",
+			Error:   "",
+			Data:    items,
+		})
+	}
+}
+
+// @Summary Get Snapshot Prune Log
+// @Description Snapshots destroyed by a periodic job's retention policy, newest first
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Periodic Snapshot ID"
+// @Success 200 {object} internal.APIResponse[[]zfsModels.SnapshotPruneLog] "OK"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/snapshot/periodic/{id}/prune-log [get]
+func GetSnapshotPruneLogs(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := periodicSnapshotID(c)
+		if !ok {
+			return
+		}
+
+		logs, err := zfsService.GetSnapshotPruneLogs(id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]zfsModels.SnapshotPruneLog]{
+			Status:  "success",
+			Message: "snapshot_prune_log",
+			Error:   "",
+			Data:    logs,
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfsHandlers
+
+import (
+	"net/http"
+
+	"github.com/alchemillahq/sylve/internal"
+	systemServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/system"
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/internal/services/system"
+	"github.com/alchemillahq/sylve/internal/services/zfs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RestoreFromSnapshotRequest struct {
+	Paths     []string `json:"paths" binding:"required"`
+	Target    string   `json:"target"`
+	Overwrite bool     `json:"overwrite"`
+}
+
+// @Summary Browse Snapshot
+// @Description List a directory inside a snapshot. Returned IDs are paths under .zfs/snapshot and work with the file explorer listing and download endpoints.
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Snapshot GUID"
+// @Param path query string false "Directory relative to the dataset root"
+// @Success 200 {object} internal.APIResponse[[]systemServiceInterfaces.FileNode] "OK"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/snapshot/{guid}/browse [get]
+func BrowseSnapshot(zfsService *zfs.Service, systemService *system.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path, err := zfsService.SnapshotPath(c.Param("guid"), c.Query("path"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		nodes, err := systemService.Traverse(path)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]systemServiceInterfaces.FileNode]{
+			Status:  "success",
+			Message: "snapshot_files_listed",
+			Error:   "",
+			Data:    nodes,
+		})
+	}
+}
+
+// @Summary Diff Snapshot
+// @Description List what changed between a snapshot and the live dataset
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Snapshot GUID"
+// @Success 200 {object} internal.APIResponse[[]zfsServiceInterfaces.SnapshotChange] "OK"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/snapshot/{guid}/diff [get]
+func DiffSnapshot(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		changes, err := zfsService.DiffSnapshot(c.Param("guid"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[[]zfsServiceInterfaces.SnapshotChange]{
+			Status:  "success",
+			Message: "snapshot_diff",
+			Error:   "",
+			Data:    changes,
+		})
+	}
+}
+
+// @Summary Restore From Snapshot
+// @Description Restore files or directories from a snapshot to their original location or into a target directory
+// @Tags ZFS
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param guid path string true "Snapshot GUID"
+// @Param request body RestoreFromSnapshotRequest true "Restore From Snapshot Request"
+// @Success 200 {object} internal.APIResponse[any] "OK"
+// @Failure 400 {object} internal.APIResponse[any] "Bad Request"
+// @Failure 500 {object} internal.APIResponse[any] "Internal Server Error"
+// @Router /zfs/datasets/snapshot/{guid}/restore [post]
+func RestoreFromSnapshot(zfsService *zfs.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request RestoreFromSnapshotRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "invalid_request",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		err := zfsService.RestoreFromSnapshot(c.Param("guid"), request.Paths, request.Target, request.Overwrite)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, internal.APIResponse[any]{
+				Status:  "error",
+				Message: "internal_server_error",
+				Error:   err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, internal.APIResponse[any]{
+			Status:  "success",
+			Message: "restored_from_snapshot",
+			Error:   "",
+			Data:    nil,
+		})
+	}
+}
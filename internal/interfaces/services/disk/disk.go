@@ -8,6 +8,12 @@
 
 package diskServiceInterfaces
 
+import (
+	"context"
+
+	diskModels "github.com/alchemillahq/sylve/internal/db/models/disk"
+)
+
 type Partition struct {
 	UUID  string `json:"uuid"`
 	Name  string `json:"name"`
@@ -74,7 +80,9 @@ type SmartData struct {
 	Temperature       Temperature  `json:"temperature"`
 
 	ATASmartAttributes  *ATASmartAttributes  `json:"ata_smart_attributes,omitempty"`
+	ATADeviceStatistics *ATADeviceStatistics `json:"ata_device_statistics,omitempty"`
 	SCSISmartAttributes *SCSISmartAttributes `json:"scsi_smart_attributes,omitempty"`
+	EnduranceUsed       *EnduranceUsed       `json:"endurance_used,omitempty"`
 }
 
 type SmartctlInfo struct {
@@ -148,10 +156,73 @@ type RawData struct {
 	String string `json:"string"`
 }
 
+type ATADeviceStatistics struct {
+	Pages []ATADeviceStatisticsPage `json:"pages"`
+}
+
+type ATADeviceStatisticsPage struct {
+	Number int                        `json:"number"`
+	Name   string                     `json:"name"`
+	Table  []ATADeviceStatisticsEntry `json:"table"`
+}
+
+type ATADeviceStatisticsEntry struct {
+	Offset int    `json:"offset"`
+	Name   string `json:"name"`
+	Value  int64  `json:"value"`
+	Flags  struct {
+		Valid bool `json:"valid"`
+	} `json:"flags"`
+}
+
+type EnduranceUsed struct {
+	CurrentPercent int `json:"current_percent"`
+}
+
 type SCSISmartAttributes struct {
 	Temperature int `json:"scsi_temperature,omitempty"`
 }
 
+type SelfTestEntry struct {
+	Type          string `json:"type"`
+	Status        string `json:"status"`
+	Failed        bool   `json:"failed"`
+	LifetimeHours int    `json:"lifetimeHours"`
+}
+
+type SelfTestLog struct {
+	InProgress bool            `json:"inProgress"`
+	Entries    []SelfTestEntry `json:"entries"`
+}
+
+// DiskVdev places a disk (or one of its partitions) in a pool.
+type DiskVdev struct {
+	Pool   string `json:"pool"`
+	Class  string `json:"class"`
+	Vdev   string `json:"vdev"`
+	Device string `json:"device"`
+}
+
+type DiskHealth struct {
+	UUID       string                     `json:"uuid"`
+	Device     string                     `json:"device"`
+	Serial     string                     `json:"serial"`
+	Type       string                     `json:"type"`
+	Vdevs      []DiskVdev                 `json:"vdevs"`
+	Latest     *diskModels.SmartReading   `json:"latest"`
+	Thresholds diskModels.SmartThresholds `json:"thresholds"`
+}
+
+type SmartTestSchedule struct {
+	TestType string `json:"testType" binding:"required,oneof=short long"`
+	CronExpr string `json:"cronExpr" binding:"required"`
+	Enabled  bool   `json:"enabled"`
+}
+
+type RunSelfTest struct {
+	TestType string `json:"testType" binding:"required,oneof=short long"`
+}
+
 type DiskServiceInterface interface {
 	GetDiskDevices() ([]Disk, error)
 	GetSmartData(disk DiskInfo) (any, error)
@@ -159,4 +230,19 @@ type DiskServiceInterface interface {
 	GetDiskSize(device string) (uint64, error)
 	DestroyPartitionTable(device string) error
 	IsDiskGPT(device string) bool
+
+	GetDiskHealth() ([]DiskHealth, error)
+	GetSmartHistory(uuid string, limit int) ([]diskModels.SmartReading, error)
+	GetSelfTestLog(uuid string) (SelfTestLog, error)
+	RunSelfTest(uuid string, testType string) error
+	GetSmartTestSchedules() ([]diskModels.SmartTestSchedule, error)
+	SetSmartTestSchedule(uuid string, req SmartTestSchedule) error
+	DeleteSmartTestSchedule(uuid string, testType string) error
+	GetSmartThresholds(uuid string) (diskModels.SmartThresholds, error)
+	SetSmartThresholds(uuid string, thresholds diskModels.SmartThresholds) error
+	DeleteSmartThresholds(uuid string) error
+	GetSmartAlerts() ([]diskModels.SmartAlert, error)
+	AcknowledgeSmartAlert(id uint) error
+	CollectSmartReadings()
+	StartSmartMonitor(ctx context.Context)
 }
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package iscsiServiceInterfaces
+
+import "context"
+
+type IscsiServiceInterface interface {
+	WriteConfig(reload bool) error
+	ApplyService(restart bool) error
+	StartConfigWatcher(ctx context.Context)
+}
+
+type PortalGroup struct {
+	Name       string   `json:"name" binding:"required"`
+	Interfaces []string `json:"interfaces" binding:"required,min=1"`
+	Port       int      `json:"port" binding:"omitempty,min=1,max=65535"`
+	Comment    string   `json:"comment"`
+}
+
+type LUN struct {
+	Number    int    `json:"number" binding:"min=0,max=1023"`
+	Dataset   string `json:"dataset" binding:"required"`
+	BlockSize int    `json:"blockSize" binding:"omitempty,oneof=512 4096"`
+}
+
+// Target carries CHAP secrets in clear, they are never returned by the
+// API. Leaving a secret empty on update keeps the stored one.
+type Target struct {
+	Name           string   `json:"name" binding:"required"`
+	Alias          string   `json:"alias"`
+	PortalGroupID  uint     `json:"portalGroupId" binding:"required"`
+	AuthType       string   `json:"authType" binding:"omitempty,oneof=none chap chap-mutual"`
+	CHAPUser       string   `json:"chapUser"`
+	CHAPSecret     string   `json:"chapSecret"`
+	MutualUser     string   `json:"mutualUser"`
+	MutualSecret   string   `json:"mutualSecret"`
+	InitiatorNames []string `json:"initiatorNames"`
+	Initiators     []uint   `json:"initiators"`
+	LUNs           []LUN    `json:"luns" binding:"dive"`
+}
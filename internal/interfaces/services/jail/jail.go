@@ -8,6 +8,12 @@
 
 package jailServiceInterfaces
 
+import (
+	"context"
+
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+)
+
 type CreateJailRequest struct {
 	Name        string `json:"name" binding:"required"`
 	CTID        *int   `json:"ctId" binding:"required"`
@@ -29,6 +35,11 @@ type CreateJailRequest struct {
 	IPv6   *int `json:"ipv6"`
 	IPv6Gw *int `json:"ipv6Gw"`
 
+	// AutoIPv4/AutoIPv6 allocate the address from the switch's IP pool
+	// instead of taking IPv4/IPv6 object IDs.
+	AutoIPv4 *bool `json:"autoIpv4"`
+	AutoIPv6 *bool `json:"autoIpv6"`
+
 	MAC *int `json:"mac"`
 
 	ResourceLimits *bool `json:"resourceLimits"`
@@ -37,6 +48,9 @@ type CreateJailRequest struct {
 
 	StartAtBoot *bool `json:"startAtBoot"`
 	StartOrder  int   `json:"startOrder"`
+
+	Parameters *jailModels.Parameters `json:"parameters"`
+	DevfsRules []string               `json:"devfsRules"`
 }
 
 type SimpleList struct {
@@ -46,15 +60,65 @@ type SimpleList struct {
 	State string `json:"state"`
 }
 
+type RctlRule struct {
+	Resource string `json:"resource" binding:"required"`
+	Action   string `json:"action" binding:"required"`
+	Amount   uint64 `json:"amount" binding:"required"`
+}
+
+type RctlUsage struct {
+	Resource string  `json:"resource"`
+	Action   string  `json:"action"`
+	Amount   uint64  `json:"amount"`
+	Usage    uint64  `json:"usage"`
+	Percent  float64 `json:"percent"`
+}
+
+type ExecRequest struct {
+	User    string            `json:"user"`
+	Command []string          `json:"command" binding:"required"`
+	Stdin   string            `json:"stdin"`
+	Env     map[string]string `json:"env"`
+	Timeout int               `json:"timeout"`
+}
+
+type ExecResult struct {
+	ExitCode int    `json:"exitCode"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	TimedOut bool   `json:"timedOut"`
+}
+
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Size    uint64 `json:"size"`
+	Comment string `json:"comment"`
+}
+
+type PackageChange struct {
+	Name       string `json:"name"`
+	Action     string `json:"action"`
+	OldVersion string `json:"oldVersion"`
+	NewVersion string `json:"newVersion"`
+}
+
+type UpgradePlan struct {
+	Changes []PackageChange `json:"changes"`
+	Output  string          `json:"output"`
+}
+
 type State struct {
-	CTID   int     `json:"ctId"`
-	State  string  `json:"state"`
-	PCPU   float64 `json:"pcpu"`
-	Memory int64   `json:"memory"`
+	CTID   int         `json:"ctId"`
+	State  string      `json:"state"`
+	PCPU   float64     `json:"pcpu"`
+	Memory int64       `json:"memory"`
+	Limits []RctlUsage `json:"limits,omitempty"`
 }
 
 type JailServiceInterface interface {
 	StoreJailUsage() error
 	PruneOrphanedJailStats([]uint) error
 	WatchNetworkObjectChanges() error
+	StartPackageAuditScheduler(ctx context.Context)
 }
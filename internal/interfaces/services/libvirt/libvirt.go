@@ -19,6 +19,8 @@ type LibvirtServiceInterface interface {
 	DeleteStoragePool(name string) error
 	RescanStoragePools() error
 
+	CreateVM(data CreateVMRequest) error
+
 	NetworkDetach(vmId int, networkId int) error
 	NetworkAttach(vmId int, switchName string, emulation string, macObjId uint) error
 	FindAndChangeMAC(vmId int, oldMac string, newMac string) error
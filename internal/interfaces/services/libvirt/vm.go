@@ -22,6 +22,7 @@ type CreateVMRequest struct {
 	SwitchName           string  `json:"switchName"`
 	SwitchEmulationType  string  `json:"switchEmulationType"`
 	MacId                *uint   `json:"macId"`
+	AutoIP               *bool   `json:"autoIp"`
 	CPUSockets           int     `json:"cpuSockets" binding:"required"`
 	CPUCores             int     `json:"cpuCores" binding:"required"`
 	CPUThreads           int     `json:"cpuThreads" binding:"required"`
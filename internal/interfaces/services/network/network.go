@@ -8,7 +8,60 @@
 
 package networkServiceInterfaces
 
-import networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+import (
+	"context"
+	"time"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	"github.com/alchemillahq/sylve/pkg/network/iface"
+)
+
+type DHCPLease struct {
+	Expiry   time.Time `json:"expiry"`
+	MAC      string    `json:"mac"`
+	IP       string    `json:"ip"`
+	Hostname string    `json:"hostname"`
+	ClientID string    `json:"clientId"`
+	SwitchID *uint     `json:"switchId"`
+}
+
+type FirewallStatus struct {
+	Running    bool       `json:"running"`
+	Pending    bool       `json:"pending"`
+	RollbackAt *time.Time `json:"rollbackAt"`
+	Dirty      bool       `json:"dirty"`
+	Applied    string     `json:"applied"`
+	Staged     string     `json:"staged"`
+}
+
+type LaggStatus struct {
+	networkModels.Lagg
+	Live *iface.Lagg `json:"live"`
+}
+
+// IPConflict is an address inside a pool that is assigned more than once,
+// or assigned although the pool reserves it.
+type IPConflict struct {
+	Address string   `json:"address"`
+	Reason  string   `json:"reason"`
+	Sources []string `json:"sources"`
+}
+
+type IPPoolStatus struct {
+	networkModels.IPPool
+	Total     uint64       `json:"total"`
+	Used      uint64       `json:"used"`
+	Reserved  uint64       `json:"reserved"`
+	Free      uint64       `json:"free"`
+	Conflicts []IPConflict `json:"conflicts"`
+}
+
+// SwitchChange is a single step of a switch reconcile plan.
+type SwitchChange struct {
+	Switch  string `json:"switch"`
+	Action  string `json:"action"`
+	Command string `json:"command"`
+}
 
 type NetworkServiceInterface interface {
 	SyncStandardSwitches(previous *networkModels.StandardSwitch, action string) error
@@ -47,4 +100,12 @@ type NetworkServiceInterface interface {
 	CreateEpair(name string) error
 	SyncEpairs() error
 	DeleteEpair(name string) error
+	InitFirewall() error
+	SyncLaggs() error
+	SyncWireGuard() error
+	AllocatePoolAddress(switchID uint, switchType string, family int, owner string) (uint, uint, error)
+	SyncDHCP() error
+	StartDHCPWatcher(ctx context.Context)
+	StartFQDNResolver(ctx context.Context)
+	StartGeoIPWatcher(ctx context.Context)
 }
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package nfsServiceInterfaces
+
+import "context"
+
+type NfsServiceInterface interface {
+	WriteExports(reload bool) error
+	ApplyServices(restart bool) error
+	StartExportWatcher(ctx context.Context)
+}
+
+type Export struct {
+	Dataset  string   `json:"dataset" binding:"required"`
+	Networks []uint   `json:"networks" binding:"required,min=1"`
+	ReadOnly bool     `json:"readOnly"`
+	MapRoot  string   `json:"mapRoot"`
+	MapAll   string   `json:"mapAll"`
+	Security []string `json:"security" binding:"omitempty,dive,oneof=sys krb5 krb5i krb5p"`
+	AllDirs  bool     `json:"allDirs"`
+	Comment  string   `json:"comment"`
+}
+
+type Settings struct {
+	Enabled    bool     `json:"enabled"`
+	V4Enabled  bool     `json:"v4Enabled"`
+	V4Only     bool     `json:"v4Only"`
+	V4Root     string   `json:"v4Root"`
+	V4Security []string `json:"v4Security" binding:"omitempty,dive,oneof=sys krb5 krb5i krb5p"`
+}
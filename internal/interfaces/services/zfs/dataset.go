@@ -8,6 +8,8 @@
 
 package zfsServiceInterfaces
 
+import "time"
+
 // type zDataset struct {
 // 	Dataset zfs.Dataset
 // }
@@ -37,3 +39,33 @@ type Dataset struct {
 	PrimaryCache  string `json:"primarycache"`
 	VolMode       string `json:"volmode"`
 }
+
+// SnapshotRetentionItem is one snapshot of a periodic job as seen by its
+// retention policy. Blocked is set when a snapshot due for pruning is held
+// or has clones and will be left in place.
+type SnapshotRetentionItem struct {
+	Snapshot  string    `json:"snapshot"`
+	CreatedAt time.Time `json:"createdAt"`
+	Keep      bool      `json:"keep"`
+	Reasons   []string  `json:"reasons"`
+	Blocked   string    `json:"blocked,omitempty"`
+}
+
+// SnapshotChange is one entry of `zfs diff` between a snapshot and the live
+// dataset. Paths are absolute paths in the live dataset.
+type SnapshotChange struct {
+	Change  string `json:"change"`
+	Type    string `json:"type"`
+	Path    string `json:"path"`
+	NewPath string `json:"newPath,omitempty"`
+}
+
+type LoadEncryptionKey struct {
+	Key string `json:"key"`
+}
+
+type ChangeEncryptionKey struct {
+	KeyFormat string `json:"keyFormat" binding:"required,oneof=passphrase hex raw"`
+	Key       string `json:"key" binding:"required"`
+	StoreKey  bool   `json:"storeKey"`
+}
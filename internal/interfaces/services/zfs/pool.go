@@ -34,3 +34,69 @@ type PoolStatPoint struct {
 	Size       uint64  `json:"size"`
 	DedupRatio float64 `json:"dedupRatio"`
 }
+
+type ImportPool struct {
+	Pool     string   `json:"pool" binding:"required"`
+	NewName  string   `json:"newName"`
+	ReadOnly bool     `json:"readOnly"`
+	AltRoot  string   `json:"altRoot"`
+	Force    bool     `json:"force"`
+	Dirs     []string `json:"dirs"`
+}
+
+type ExportPool struct {
+	Force bool `json:"force"`
+}
+
+type PoolVdevAdd struct {
+	Class    string   `json:"class" binding:"required,oneof=data log cache special dedup"`
+	RaidType string   `json:"raidType" binding:"omitempty,oneof=mirror raidz raidz2 raidz3"`
+	Devices  []string `json:"devices" binding:"required,min=1"`
+	Force    bool     `json:"force"`
+}
+
+type PoolDeviceAttach struct {
+	Target string `json:"target" binding:"required"`
+	Device string `json:"device" binding:"required"`
+}
+
+type PoolDeviceRef struct {
+	Device string `json:"device" binding:"required"`
+}
+
+type PoolChangePreview struct {
+	Class             string   `json:"class"`
+	CurrentUsable     uint64   `json:"currentUsable"`
+	ResultUsable      uint64   `json:"resultUsable"`
+	CurrentRedundancy int      `json:"currentRedundancy"`
+	ResultRedundancy  int      `json:"resultRedundancy"`
+	Config            string   `json:"config,omitempty"`
+	Warnings          []string `json:"warnings"`
+}
+
+type PoolMaintenance struct {
+	Task     string `json:"task" binding:"required,oneof=scrub trim"`
+	CronExpr string `json:"cronExpr" binding:"required"`
+	Enabled  bool   `json:"enabled"`
+}
+
+type ArcStatPoint struct {
+	Time          int64   `json:"time"`
+	Size          uint64  `json:"size"`
+	Target        uint64  `json:"target"`
+	MFUSize       uint64  `json:"mfuSize"`
+	MRUSize       uint64  `json:"mruSize"`
+	L2Size        uint64  `json:"l2Size"`
+	HitRatio      float64 `json:"hitRatio"`
+	MFUHitRatio   float64 `json:"mfuHitRatio"`
+	L2HitRatio    float64 `json:"l2HitRatio"`
+	CompressRatio float64 `json:"compressRatio"`
+}
+
+type DatasetIOPoint struct {
+	Time       int64   `json:"time"`
+	ReadBytes  float64 `json:"readBytes"`
+	WriteBytes float64 `json:"writeBytes"`
+	ReadOps    float64 `json:"readOps"`
+	WriteOps   float64 `json:"writeOps"`
+}
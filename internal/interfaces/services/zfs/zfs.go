@@ -13,14 +13,41 @@ import (
 
 	infoModels "github.com/alchemillahq/sylve/internal/db/models/info"
 	zfsModels "github.com/alchemillahq/sylve/internal/db/models/zfs"
+	"github.com/alchemillahq/sylve/pkg/zfs"
 )
 
 type ZfsServiceInterface interface {
 	GetTotalIODelayHisorical() ([]infoModels.IODelay, error)
 	GetZpoolHistoricalStats(intervalMinutes int, limit int) (map[string][]PoolStatPoint, int, error)
+	GetArcHistoricalStats(intervalMinutes int, limit int) ([]ArcStatPoint, int, error)
+	GetDatasetIOHistoricalStats(intervalMinutes int, limit int, dataset string) (map[string][]DatasetIOPoint, int, error)
 
 	CreatePool(Zpool) error
 	DeletePool(poolName string) error
+	ImportablePools(dirs []string) ([]zfs.ImportablePool, error)
+	ImportPool(req ImportPool) error
+	ExportPool(guid string, force bool) error
+
+	GetPoolLayout(guid string) (zfs.PoolLayout, error)
+	PreviewAddVdev(guid string, req PoolVdevAdd) (PoolChangePreview, error)
+	AddVdev(guid string, req PoolVdevAdd) error
+	PreviewAttachDevice(guid string, req PoolDeviceAttach) (PoolChangePreview, error)
+	AttachDevice(guid string, req PoolDeviceAttach) error
+	PreviewDetachDevice(guid string, device string) (PoolChangePreview, error)
+	DetachDevice(guid string, device string) error
+	PreviewRemoveVdev(guid string, vdev string) (PoolChangePreview, error)
+	RemoveVdev(guid string, vdev string) error
+
+	GetPoolMaintenance(guid string) ([]zfsModels.PoolMaintenance, error)
+	SetPoolMaintenance(guid string, req PoolMaintenance) error
+	DeletePoolMaintenance(guid string, task string) error
+	TrimPool(guid string) error
+	GetScrubHistory(guid string) ([]zfsModels.PoolScrubResult, error)
+	GetPoolEvents(guid string) ([]zfsModels.PoolEvent, error)
+	GetPoolAlerts() ([]zfsModels.PoolEvent, error)
+	AcknowledgePoolEvent(id uint) error
+	CheckPoolHealth()
+	StartPoolMaintenanceScheduler(ctx context.Context)
 
 	GetDatasets(t string) ([]*Dataset, error)
 	BulkDeleteDataset(guids []string) error
@@ -30,13 +57,22 @@ type ZfsServiceInterface interface {
 	DeleteSnapshot(guid string, recursive bool) error
 
 	GetPeriodicSnapshots() ([]zfsModels.PeriodicSnapshot, error)
-	AddPeriodicSnapshot(guid string, prefix string, recursive bool, interval int, cronExpr string) error
+	AddPeriodicSnapshot(guid string, prefix string, recursive bool, interval int, cronExpr string, retention zfsModels.SnapshotRetention) error
 	DeletePeriodicSnapshot(guid string) error
+	SetSnapshotRetention(id uint, retention zfsModels.SnapshotRetention) error
+	PreviewSnapshotRetention(id uint) ([]SnapshotRetentionItem, error)
+	PruneSnapshots(id uint) ([]SnapshotRetentionItem, error)
 	StartSnapshotScheduler(ctx context.Context)
 
 	CreateFilesystem(name string, props map[string]string) error
 	DeleteFilesystem(guid string) error
 
+	GetEncryptionRoots() ([]zfs.EncryptionInfo, error)
+	LoadKey(guid string, key string) error
+	UnloadKey(guid string) error
+	ChangeKey(guid string, req ChangeEncryptionKey) error
+	UnlockEncryptedDatasets() error
+
 	SyncLibvirtPools() error
 
 	StoreStats(interval int)
@@ -147,20 +147,14 @@ func ExtractDiskInfo(mesh *diskServiceInterfaces.Mesh) ([]diskServiceInterfaces.
 func (s *Service) GetDiskDevices() ([]diskServiceInterfaces.Disk, error) {
 	var disks []diskServiceInterfaces.Disk
 
-	mesh, err := s.ParseGeomOutput()
-	if err != nil {
-		return nil, err
-	}
-
-	dinfo, err := ExtractDiskInfo(&mesh)
-
+	dinfo, err := s.diskInfos()
 	if err != nil {
 		return nil, err
 	}
 
 	for _, d := range dinfo {
 		var disk diskServiceInterfaces.Disk
-		disk.UUID = utils.GenerateDeterministicUUID(fmt.Sprintf("%s-%s", d.LunID, d.Serial))
+		disk.UUID = diskUUID(d)
 		disk.Device = d.Name
 		disk.Type = d.Type
 		disk.Size = uint64(d.MediaSize)
@@ -0,0 +1,480 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package disk
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alchemillahq/sylve/internal/db"
+	diskModels "github.com/alchemillahq/sylve/internal/db/models/disk"
+	diskServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/disk"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+func diskUUID(d diskServiceInterfaces.DiskInfo) string {
+	return utils.GenerateDeterministicUUID(fmt.Sprintf("%s-%s", d.LunID, d.Serial))
+}
+
+func smartCapable(d diskServiceInterfaces.DiskInfo) bool {
+	return d.Type == "NVMe" || d.Type == "SSD" || d.Type == "HDD"
+}
+
+func (s *Service) diskInfos() ([]diskServiceInterfaces.DiskInfo, error) {
+	mesh, err := s.ParseGeomOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	return ExtractDiskInfo(&mesh)
+}
+
+func (s *Service) findDisk(uuid string) (diskServiceInterfaces.DiskInfo, error) {
+	disks, err := s.diskInfos()
+	if err != nil {
+		return diskServiceInterfaces.DiskInfo{}, err
+	}
+
+	for _, d := range disks {
+		if diskUUID(d) == uuid {
+			return d, nil
+		}
+	}
+
+	return diskServiceInterfaces.DiskInfo{}, fmt.Errorf("disk_not_found: %s", uuid)
+}
+
+func poolLayouts() map[string]zfs.PoolLayout {
+	layouts := map[string]zfs.PoolLayout{}
+
+	pools, err := zfs.ListZpools()
+	if err != nil {
+		logger.L.Debug().Err(err).Msg("smart: failed to list zpools")
+		return layouts
+	}
+
+	for _, pool := range pools {
+		layout, err := zfs.GetPoolLayout(pool.Name)
+		if err != nil {
+			logger.L.Debug().Err(err).Msgf("smart: failed to get layout of %s", pool.Name)
+			continue
+		}
+		layouts[pool.Name] = layout
+	}
+
+	return layouts
+}
+
+// diskVdevs finds the vdevs a disk takes part in, either whole or through
+// one of its partitions, under any of the names GEOM knows it by.
+func diskVdevs(d diskServiceInterfaces.DiskInfo, layouts map[string]zfs.PoolLayout) []diskServiceInterfaces.DiskVdev {
+	names := map[string]bool{d.Name: true}
+	for _, alias := range d.Aliases {
+		names[alias] = true
+	}
+
+	for _, p := range d.Partitions {
+		names[p.Name] = true
+		for _, alias := range p.Aliases {
+			names[alias] = true
+		}
+	}
+
+	vdevs := []diskServiceInterfaces.DiskVdev{}
+
+	for pool, layout := range layouts {
+		for _, class := range []string{zfs.VdevClassData, zfs.VdevClassLog, zfs.VdevClassCache,
+			zfs.VdevClassSpare, zfs.VdevClassSpecial, zfs.VdevClassDedup} {
+			for _, top := range *layout.Class(class) {
+				for _, leaf := range top.Leaves() {
+					name := strings.TrimSuffix(strings.TrimPrefix(leaf.Name, "/dev/"), ".eli")
+					if !names[name] {
+						continue
+					}
+
+					vdevs = append(vdevs, diskServiceInterfaces.DiskVdev{
+						Pool:   pool,
+						Class:  class,
+						Vdev:   top.Name,
+						Device: leaf.Name,
+					})
+				}
+			}
+		}
+	}
+
+	return vdevs
+}
+
+// ataPercentageUsed prefers the endurance indicator from the device
+// statistics log. Vendor attributes are only trusted when smartctl's drive
+// database names them as remaining life, since IDs like 231 mean
+// temperature or something else entirely on many drives.
+func ataPercentageUsed(smart diskServiceInterfaces.SmartData) int {
+	if smart.EnduranceUsed != nil {
+		return smart.EnduranceUsed.CurrentPercent
+	}
+
+	if smart.ATADeviceStatistics != nil {
+		for _, page := range smart.ATADeviceStatistics.Pages {
+			if page.Number != 7 {
+				continue
+			}
+
+			for _, entry := range page.Table {
+				if entry.Offset == 8 && entry.Flags.Valid {
+					return int(entry.Value)
+				}
+			}
+		}
+	}
+
+	if smart.ATASmartAttributes != nil {
+		for _, attr := range smart.ATASmartAttributes.Table {
+			switch attr.Name {
+			case "SSD_Life_Left", "Percent_Lifetime_Remain":
+				if attr.Value > 0 && attr.Value <= 100 {
+					return 100 - attr.Value
+				}
+			}
+		}
+	}
+
+	return 0
+}
+
+func (s *Service) readSmart(d diskServiceInterfaces.DiskInfo) (diskModels.SmartReading, error) {
+	reading := diskModels.SmartReading{
+		DiskUUID: diskUUID(d),
+		Device:   d.Name,
+		Serial:   d.Serial,
+		Type:     d.Type,
+	}
+
+	data, err := s.GetSmartData(d)
+	if err != nil {
+		return reading, err
+	}
+
+	switch smart := data.(type) {
+	case diskServiceInterfaces.SmartData:
+		reading.Passed = smart.SmartStatus.Passed
+		reading.Temperature = smart.Temperature.Current
+		reading.PowerOnHours = smart.PowerOnTime.Hours
+
+		if smart.ATASmartAttributes != nil {
+			for _, attr := range smart.ATASmartAttributes.Table {
+				switch attr.ID {
+				case 5:
+					reading.ReallocatedSectors = attr.Raw.Value
+				case 197:
+					reading.PendingSectors = attr.Raw.Value
+				case 198:
+					reading.OfflineUncorrectable = attr.Raw.Value
+				}
+			}
+		}
+
+		reading.PercentageUsed = ataPercentageUsed(smart)
+	case diskServiceInterfaces.SMARTNvme:
+		reading.Passed = smart.CriticalWarning == "" || smart.CriticalWarning == "0x00"
+		// nvmecontrol reports the composite temperature in Kelvin
+		if smart.Temperature > 0 {
+			reading.Temperature = smart.Temperature - 273
+		}
+		reading.PowerOnHours = smart.PowerOnHours
+		reading.MediaErrors = int64(smart.MediaErrors)
+		reading.PercentageUsed = smart.PercentageUsed
+		reading.AvailableSpare = smart.AvailableSpare
+	default:
+		return reading, fmt.Errorf("smart_not_supported: %s", d.Name)
+	}
+
+	if log, err := selfTestLog(d); err == nil && len(log.Entries) > 0 {
+		reading.SelfTestResult = log.Entries[0].Status
+		reading.SelfTestFailed = log.Entries[0].Failed
+	}
+
+	return reading, nil
+}
+
+func (s *Service) GetSmartThresholds(uuid string) (diskModels.SmartThresholds, error) {
+	var rows []diskModels.SmartThresholds
+	if err := s.DB.Where("disk_uuid IN ?", []string{uuid, ""}).Find(&rows).Error; err != nil {
+		return diskModels.SmartThresholds{}, err
+	}
+
+	thresholds := diskModels.DefaultSmartThresholds
+	for _, row := range rows {
+		if row.DiskUUID == uuid {
+			return row, nil
+		}
+		thresholds = row
+	}
+
+	thresholds.ID = 0
+	thresholds.DiskUUID = uuid
+	return thresholds, nil
+}
+
+func (s *Service) SetSmartThresholds(uuid string, thresholds diskModels.SmartThresholds) error {
+	if thresholds.Temperature < 0 || thresholds.ReallocatedSectors < 0 || thresholds.PendingSectors < 0 ||
+		thresholds.OfflineUncorrectable < 0 || thresholds.MediaErrors < 0 ||
+		thresholds.PercentageUsed < 0 || thresholds.PercentageUsed > 100 {
+		return fmt.Errorf("invalid_thresholds")
+	}
+
+	if uuid != "" {
+		if _, err := s.findDisk(uuid); err != nil {
+			return err
+		}
+	}
+
+	var existing diskModels.SmartThresholds
+	if err := s.DB.Where("disk_uuid = ?", uuid).Limit(1).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	thresholds.ID = existing.ID
+	thresholds.DiskUUID = uuid
+
+	return s.DB.Save(&thresholds).Error
+}
+
+func (s *Service) DeleteSmartThresholds(uuid string) error {
+	return s.DB.Where("disk_uuid = ?", uuid).Delete(&diskModels.SmartThresholds{}).Error
+}
+
+type smartBreach struct {
+	attribute string
+	value     int64
+	threshold int64
+	counter   bool
+}
+
+func smartBreaches(r diskModels.SmartReading, t diskModels.SmartThresholds) []smartBreach {
+	var breaches []smartBreach
+
+	check := func(attribute string, value, threshold int64, counter bool) {
+		if threshold > 0 && value >= threshold {
+			breaches = append(breaches, smartBreach{attribute, value, threshold, counter})
+		}
+	}
+
+	check("temperature", int64(r.Temperature), int64(t.Temperature), false)
+	check("reallocated_sectors", r.ReallocatedSectors, t.ReallocatedSectors, true)
+	check("pending_sectors", r.PendingSectors, t.PendingSectors, true)
+	check("offline_uncorrectable", r.OfflineUncorrectable, t.OfflineUncorrectable, true)
+	check("media_errors", r.MediaErrors, t.MediaErrors, true)
+	check("percentage_used", int64(r.PercentageUsed), int64(t.PercentageUsed), false)
+
+	if !r.Passed {
+		breaches = append(breaches, smartBreach{attribute: "health"})
+	}
+
+	if r.SelfTestFailed {
+		breaches = append(breaches, smartBreach{attribute: "self_test"})
+	}
+
+	return breaches
+}
+
+// newBreaches keeps the breaches that deserve an alert: ones that were not
+// present in the previous reading, and error counters that kept growing.
+func newBreaches(previous *diskModels.SmartReading, current diskModels.SmartReading, t diskModels.SmartThresholds) []smartBreach {
+	breaches := smartBreaches(current, t)
+	if previous == nil {
+		return breaches
+	}
+
+	before := map[string]smartBreach{}
+	for _, b := range smartBreaches(*previous, t) {
+		before[b.attribute] = b
+	}
+
+	var fresh []smartBreach
+	for _, b := range breaches {
+		old, seen := before[b.attribute]
+		if !seen || (b.counter && b.value > old.value) {
+			fresh = append(fresh, b)
+		}
+	}
+
+	return fresh
+}
+
+func breachMessage(b smartBreach, reading diskModels.SmartReading) string {
+	switch b.attribute {
+	case "health":
+		return fmt.Sprintf("disk %s (%s) failed its SMART health check", reading.Device, reading.Serial)
+	case "self_test":
+		return fmt.Sprintf("disk %s (%s) failed a self-test: %s", reading.Device, reading.Serial, reading.SelfTestResult)
+	}
+
+	return fmt.Sprintf("disk %s (%s) %s is %d, threshold %d", reading.Device, reading.Serial,
+		strings.ReplaceAll(b.attribute, "_", " "), b.value, b.threshold)
+}
+
+// CollectSmartReadings samples every SMART capable disk, stores the reading
+// and raises alerts for thresholds crossed since the previous sample.
+func (s *Service) CollectSmartReadings() {
+	disks, err := s.diskInfos()
+	if err != nil {
+		logger.L.Debug().Err(err).Msg("smart: failed to list disks")
+		return
+	}
+
+	layouts := poolLayouts()
+
+	for _, d := range disks {
+		if !smartCapable(d) {
+			continue
+		}
+
+		reading, err := s.readSmart(d)
+		if err != nil {
+			logger.L.Debug().Err(err).Msgf("smart: failed to read %s", d.Name)
+			continue
+		}
+
+		thresholds, err := s.GetSmartThresholds(reading.DiskUUID)
+		if err != nil {
+			logger.L.Debug().Err(err).Msgf("smart: failed to load thresholds for %s", d.Name)
+			continue
+		}
+
+		var last diskModels.SmartReading
+		var previous *diskModels.SmartReading
+		if err := s.DB.Where("disk_uuid = ?", reading.DiskUUID).Order("id DESC").Limit(1).Find(&last).Error; err == nil && last.ID != 0 {
+			previous = &last
+		}
+
+		if err := s.DB.Create(&reading).Error; err != nil {
+			logger.L.Debug().Err(err).Msgf("smart: failed to store reading for %s", d.Name)
+			continue
+		}
+
+		breaches := newBreaches(previous, reading, thresholds)
+		if len(breaches) == 0 {
+			continue
+		}
+
+		var pool, vdev string
+		if vdevs := diskVdevs(d, layouts); len(vdevs) > 0 {
+			pool, vdev = vdevs[0].Pool, vdevs[0].Vdev
+		}
+
+		for _, b := range breaches {
+			alert := diskModels.SmartAlert{
+				DiskUUID:  reading.DiskUUID,
+				Device:    reading.Device,
+				Serial:    reading.Serial,
+				Pool:      pool,
+				Vdev:      vdev,
+				Attribute: b.attribute,
+				Value:     b.value,
+				Threshold: b.threshold,
+				Message:   breachMessage(b, reading),
+			}
+
+			if pool != "" {
+				alert.Message += fmt.Sprintf(", member of %s in pool %s", vdev, pool)
+			}
+
+			logger.L.Warn().Msgf("smart: %s", alert.Message)
+			db.StoreAndTrimRecords(s.DB, &alert, 1000)
+		}
+	}
+}
+
+func (s *Service) trimSmartReadings() {
+	cutoff := time.Now().Add(-365 * 24 * time.Hour)
+	if err := s.DB.Where("created_at < ?", cutoff).Delete(&diskModels.SmartReading{}).Error; err != nil {
+		logger.L.Debug().Err(err).Msg("smart: failed to delete old readings")
+	}
+}
+
+func (s *Service) GetDiskHealth() ([]diskServiceInterfaces.DiskHealth, error) {
+	disks, err := s.diskInfos()
+	if err != nil {
+		return nil, err
+	}
+
+	layouts := poolLayouts()
+	health := []diskServiceInterfaces.DiskHealth{}
+
+	for _, d := range disks {
+		if !smartCapable(d) {
+			continue
+		}
+
+		entry := diskServiceInterfaces.DiskHealth{
+			UUID:   diskUUID(d),
+			Device: d.Name,
+			Serial: d.Serial,
+			Type:   d.Type,
+			Vdevs:  diskVdevs(d, layouts),
+		}
+
+		var latest diskModels.SmartReading
+		if err := s.DB.Where("disk_uuid = ?", entry.UUID).Order("id DESC").Limit(1).Find(&latest).Error; err != nil {
+			return nil, err
+		}
+
+		if latest.ID != 0 {
+			entry.Latest = &latest
+		}
+
+		if entry.Thresholds, err = s.GetSmartThresholds(entry.UUID); err != nil {
+			return nil, err
+		}
+
+		health = append(health, entry)
+	}
+
+	return health, nil
+}
+
+func (s *Service) GetSmartHistory(uuid string, limit int) ([]diskModels.SmartReading, error) {
+	if limit <= 0 {
+		limit = 24 * 7
+	}
+
+	var readings []diskModels.SmartReading
+	if err := s.DB.Where("disk_uuid = ?", uuid).Order("id DESC").Limit(limit).Find(&readings).Error; err != nil {
+		return nil, err
+	}
+
+	return readings, nil
+}
+
+func (s *Service) GetSmartAlerts() ([]diskModels.SmartAlert, error) {
+	var alerts []diskModels.SmartAlert
+	if err := s.DB.Where("acknowledged = ?", false).Order("id DESC").Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+func (s *Service) AcknowledgeSmartAlert(id uint) error {
+	result := s.DB.Model(&diskModels.SmartAlert{}).Where("id = ?", id).Update("acknowledged", true)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("smart_alert_not_found")
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package disk
+
+import (
+	"encoding/json"
+	"testing"
+
+	diskServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/disk"
+)
+
+func TestATAPercentageUsed(t *testing.T) {
+	// Trimmed smartctl -j -A -H -l devstat output from the named drives.
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{"Samsung SSD 860 EVO (devstat)", `{
+			"ata_smart_attributes": {"table": [
+				{"id": 177, "name": "Wear_Leveling_Count", "value": 97, "raw": {"value": 41}},
+				{"id": 241, "name": "Total_LBAs_Written", "value": 99, "raw": {"value": 51874103296}}
+			]},
+			"ata_device_statistics": {"pages": [
+				{"number": 1, "name": "General Statistics", "table": [
+					{"offset": 8, "name": "Lifetime Power-On Resets", "value": 212, "flags": {"valid": true}}
+				]},
+				{"number": 7, "name": "Solid State Device Statistics", "table": [
+					{"offset": 8, "name": "Percentage Used Endurance Indicator", "value": 3, "flags": {"valid": true}}
+				]}
+			]}
+		}`, 3},
+		{"smartctl 7.4 endurance summary", `{
+			"ata_smart_attributes": {"table": [
+				{"id": 231, "name": "SSD_Life_Left", "value": 90, "raw": {"value": 90}}
+			]},
+			"endurance_used": {"current_percent": 5}
+		}`, 5},
+		{"Kingston SA400 (SSD_Life_Left)", `{
+			"ata_smart_attributes": {"table": [
+				{"id": 231, "name": "SSD_Life_Left", "value": 92, "raw": {"value": 92}},
+				{"id": 241, "name": "Lifetime_Writes_GiB", "value": 100, "raw": {"value": 10240}}
+			]}
+		}`, 8},
+		{"Crucial MX500 (invalid devstat, Percent_Lifetime_Remain)", `{
+			"ata_smart_attributes": {"table": [
+				{"id": 202, "name": "Percent_Lifetime_Remain", "value": 97, "raw": {"value": 3}}
+			]},
+			"ata_device_statistics": {"pages": [
+				{"number": 7, "name": "Solid State Device Statistics", "table": [
+					{"offset": 8, "name": "Percentage Used Endurance Indicator", "flags": {"valid": false}}
+				]}
+			]}
+		}`, 3},
+		{"HDD reporting temperature on 231", `{
+			"ata_smart_attributes": {"table": [
+				{"id": 194, "name": "Temperature_Celsius", "value": 35, "raw": {"value": 35}},
+				{"id": 231, "name": "Temperature_Celsius", "value": 35, "raw": {"value": 35}}
+			]}
+		}`, 0},
+		{"SCSI disk", `{"scsi_smart_attributes": {"scsi_temperature": 31}}`, 0},
+	}
+
+	for _, tt := range tests {
+		var data diskServiceInterfaces.SmartData
+		if err := json.Unmarshal([]byte(tt.input), &data); err != nil {
+			t.Fatalf("%s: bad fixture: %v", tt.name, err)
+		}
+
+		if got := ataPercentageUsed(data); got != tt.expected {
+			t.Errorf("ataPercentageUsed(%s) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package disk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	diskModels "github.com/alchemillahq/sylve/internal/db/models/disk"
+	diskServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/disk"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/robfig/cron/v3"
+)
+
+type smartctlCode struct {
+	Value  int    `json:"value"`
+	String string `json:"string"`
+}
+
+type smartctlSelfTestLog struct {
+	ATA struct {
+		Standard struct {
+			Table []struct {
+				Type   smartctlCode `json:"type"`
+				Status struct {
+					Value  int    `json:"value"`
+					String string `json:"string"`
+					Passed *bool  `json:"passed"`
+				} `json:"status"`
+				LifetimeHours int `json:"lifetime_hours"`
+			} `json:"table"`
+		} `json:"standard"`
+	} `json:"ata_smart_self_test_log"`
+	ATAData struct {
+		SelfTest struct {
+			Status smartctlCode `json:"status"`
+		} `json:"self_test"`
+	} `json:"ata_smart_data"`
+	NVMe struct {
+		Current smartctlCode `json:"current_self_test_operation"`
+		Table   []struct {
+			Code         smartctlCode `json:"self_test_code"`
+			Result       smartctlCode `json:"self_test_result"`
+			PowerOnHours int          `json:"power_on_hours"`
+		} `json:"table"`
+	} `json:"nvme_self_test_log"`
+}
+
+// smartDevice is the node smartctl and nvmecontrol want for disk. NVMe
+// namespaces (nda/nvd) are addressed through their controller.
+func smartDevice(disk diskServiceInterfaces.DiskInfo) (string, error) {
+	switch disk.Type {
+	case "NVMe":
+		return nvmeController(disk.Serial)
+	case "HDD", "SSD":
+		return disk.Name, nil
+	}
+
+	return "", fmt.Errorf("smart_not_supported: %s", disk.Name)
+}
+
+func runSelfTest(disk diskServiceInterfaces.DiskInfo, testType string) error {
+	if testType != diskModels.SelfTestShort && testType != diskModels.SelfTestLong {
+		return fmt.Errorf("invalid_test_type: %s", testType)
+	}
+
+	device, err := smartDevice(disk)
+	if err != nil {
+		return err
+	}
+
+	if disk.Type == "NVMe" {
+		code := "1"
+		if testType == diskModels.SelfTestLong {
+			code = "2"
+		}
+
+		if _, err := utils.RunCommand("nvmecontrol", "selftest", "-c", code, device); err != nil {
+			return fmt.Errorf("failed_to_start_self_test: %v", err)
+		}
+
+		return nil
+	}
+
+	var out struct{}
+	if err := runSmartctl(&out, "-t", testType, "/dev/"+device); err != nil {
+		return fmt.Errorf("failed_to_start_self_test: %v", err)
+	}
+
+	return nil
+}
+
+func selfTestLog(disk diskServiceInterfaces.DiskInfo) (diskServiceInterfaces.SelfTestLog, error) {
+	log := diskServiceInterfaces.SelfTestLog{Entries: []diskServiceInterfaces.SelfTestEntry{}}
+
+	device, err := smartDevice(disk)
+	if err != nil {
+		return log, err
+	}
+
+	var raw smartctlSelfTestLog
+	if err := runSmartctl(&raw, "-c", "-l", "selftest", "/dev/"+device); err != nil {
+		return log, err
+	}
+
+	if disk.Type == "NVMe" {
+		log.InProgress = raw.NVMe.Current.Value != 0
+		for _, entry := range raw.NVMe.Table {
+			log.Entries = append(log.Entries, diskServiceInterfaces.SelfTestEntry{
+				Type:   entry.Code.String,
+				Status: entry.Result.String,
+				// 5-7 are failed segments or a fatal error, the rest
+				// are aborts or success
+				Failed:        entry.Result.Value >= 5 && entry.Result.Value <= 7,
+				LifetimeHours: entry.PowerOnHours,
+			})
+		}
+
+		return log, nil
+	}
+
+	// ATA self-test execution status 15 (high nibble) means a test is
+	// running right now
+	log.InProgress = raw.ATAData.SelfTest.Status.Value>>4 == 15
+	for _, entry := range raw.ATA.Standard.Table {
+		log.Entries = append(log.Entries, diskServiceInterfaces.SelfTestEntry{
+			Type:          entry.Type.String,
+			Status:        entry.Status.String,
+			Failed:        entry.Status.Passed != nil && !*entry.Status.Passed,
+			LifetimeHours: entry.LifetimeHours,
+		})
+	}
+
+	return log, nil
+}
+
+func (s *Service) GetSelfTestLog(uuid string) (diskServiceInterfaces.SelfTestLog, error) {
+	disk, err := s.findDisk(uuid)
+	if err != nil {
+		return diskServiceInterfaces.SelfTestLog{}, err
+	}
+
+	return selfTestLog(disk)
+}
+
+func (s *Service) RunSelfTest(uuid string, testType string) error {
+	disk, err := s.findDisk(uuid)
+	if err != nil {
+		return err
+	}
+
+	return runSelfTest(disk, testType)
+}
+
+func (s *Service) GetSmartTestSchedules() ([]diskModels.SmartTestSchedule, error) {
+	var schedules []diskModels.SmartTestSchedule
+	if err := s.DB.Order("disk_uuid ASC, test_type ASC").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (s *Service) SetSmartTestSchedule(uuid string, req diskServiceInterfaces.SmartTestSchedule) error {
+	if req.TestType != diskModels.SelfTestShort && req.TestType != diskModels.SelfTestLong {
+		return fmt.Errorf("invalid_test_type: %s", req.TestType)
+	}
+
+	if _, err := cron.ParseStandard(req.CronExpr); err != nil {
+		return fmt.Errorf("invalid_cron_expr: %v", err)
+	}
+
+	disk, err := s.findDisk(uuid)
+	if err != nil {
+		return err
+	}
+
+	if _, err := smartDevice(disk); err != nil {
+		return err
+	}
+
+	var schedule diskModels.SmartTestSchedule
+	err = s.DB.Where("disk_uuid = ? AND test_type = ?", uuid, req.TestType).
+		Attrs(diskModels.SmartTestSchedule{DiskUUID: uuid, TestType: req.TestType}).
+		FirstOrInit(&schedule).Error
+	if err != nil {
+		return err
+	}
+
+	schedule.Serial = disk.Serial
+	schedule.CronExpr = req.CronExpr
+	schedule.Enabled = req.Enabled
+
+	return s.DB.Save(&schedule).Error
+}
+
+func (s *Service) DeleteSmartTestSchedule(uuid string, testType string) error {
+	result := s.DB.Where("disk_uuid = ? AND test_type = ?", uuid, testType).Delete(&diskModels.SmartTestSchedule{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("schedule_not_found")
+	}
+
+	return nil
+}
+
+func (s *Service) runDueSelfTests(now time.Time) {
+	var schedules []diskModels.SmartTestSchedule
+	if err := s.DB.Where("enabled = ?", true).Find(&schedules).Error; err != nil {
+		logger.L.Debug().Err(err).Msg("Failed to load SMART test schedules")
+		return
+	}
+
+	for _, schedule := range schedules {
+		due, err := utils.CronDue(schedule.CronExpr, schedule.LastRunAt, schedule.CreatedAt, now)
+		if err != nil {
+			logger.L.Debug().Err(err).Msgf("Invalid cron expression for SMART schedule %d", schedule.ID)
+			continue
+		}
+
+		if !due {
+			continue
+		}
+
+		lastError := ""
+		if err := s.runScheduledSelfTest(schedule); err != nil {
+			lastError = err.Error()
+			logger.L.Warn().Err(err).Msgf("Scheduled %s self-test of disk %s failed", schedule.TestType, schedule.Serial)
+		}
+
+		if err := s.DB.Model(&schedule).Updates(map[string]any{
+			"last_run_at": now,
+			"last_error":  lastError,
+		}).Error; err != nil {
+			logger.L.Debug().Err(err).Msgf("Failed to update LastRunAt for %d", schedule.ID)
+		}
+	}
+}
+
+func (s *Service) runScheduledSelfTest(schedule diskModels.SmartTestSchedule) error {
+	disk, err := s.findDisk(schedule.DiskUUID)
+	if err != nil {
+		return err
+	}
+
+	log, err := selfTestLog(disk)
+	if err == nil && log.InProgress {
+		return fmt.Errorf("self_test_in_progress")
+	}
+
+	return runSelfTest(disk, schedule.TestType)
+}
+
+func (s *Service) StartSmartMonitor(ctx context.Context) {
+	schedules := time.NewTicker(30 * time.Second)
+	readings := time.NewTicker(time.Hour)
+	defer schedules.Stop()
+	defer readings.Stop()
+
+	s.CollectSmartReadings()
+
+	for {
+		select {
+		case now := <-schedules.C:
+			s.runDueSelfTests(now)
+		case <-readings.C:
+			s.CollectSmartReadings()
+			s.trimSmartReadings()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -21,31 +21,47 @@ import (
 	"github.com/alchemillahq/sylve/pkg/utils"
 )
 
-func getSmartCtlData(device string) (diskServiceInterfaces.SmartData, error) {
-	output, err := utils.RunCommand("smartctl",
-		"-A",
-		"-H",
-		"-j",
-		fmt.Sprintf("/dev/%s", device))
+// runSmartctl runs smartctl in JSON mode and decodes its output into v.
+// smartctl's exit status is a bit mask: only the two low bits mean the
+// command itself failed, the others report problems with the disk and
+// those are exactly the readings we must not throw away.
+func runSmartctl(v any, args ...string) error {
+	output, err := utils.RunCommand("smartctl", append([]string{"-j"}, args...)...)
+
+	var status struct {
+		Smartctl diskServiceInterfaces.SmartctlInfo `json:"smartctl"`
+	}
 
-	if err != nil {
-		return diskServiceInterfaces.SmartData{}, err
+	if jsonErr := json.Unmarshal([]byte(output), &status); jsonErr != nil {
+		if err != nil {
+			return err
+		}
+
+		return jsonErr
 	}
 
-	var parsed diskServiceInterfaces.SmartData
-	err = json.Unmarshal([]byte(output), &parsed)
+	if err != nil && status.Smartctl.ExitStatus&0x3 != 0 {
+		return err
+	}
 
-	if err != nil {
+	return json.Unmarshal([]byte(output), v)
+}
+
+func getSmartCtlData(device string) (diskServiceInterfaces.SmartData, error) {
+	var parsed diskServiceInterfaces.SmartData
+	if err := runSmartctl(&parsed, "-A", "-H", "-l", "devstat", fmt.Sprintf("/dev/%s", device)); err != nil {
 		return diskServiceInterfaces.SmartData{}, err
 	}
 
 	return parsed, nil
 }
 
-func getNVMeControlData(serial string) (diskServiceInterfaces.SMARTNvme, error) {
+// nvmeController finds the nvmeX controller that owns the namespace with
+// serial, since nvmecontrol works on controllers rather than nda/nvd disks.
+func nvmeController(serial string) (string, error) {
 	output, err := utils.RunCommand("nvmecontrol", "devlist")
 	if err != nil {
-		return diskServiceInterfaces.SMARTNvme{}, fmt.Errorf("failed to get NVMe device list: %v", err)
+		return "", fmt.Errorf("failed to get NVMe device list: %v", err)
 	}
 
 	var nvmeDevices []string
@@ -59,30 +75,38 @@ func getNVMeControlData(serial string) (diskServiceInterfaces.SMARTNvme, error)
 		}
 	}
 
+	serialRegex := regexp.MustCompile(`Serial Number:\s*(\S+)`)
+
 	for _, nvmeDevice := range nvmeDevices {
 		output, err := utils.RunCommand("nvmecontrol", "identify", fmt.Sprintf("/dev/%s", nvmeDevice))
 		if err != nil {
-			return diskServiceInterfaces.SMARTNvme{}, fmt.Errorf("failed to get NVMe device info: %v", err)
+			return "", fmt.Errorf("failed to get NVMe device info: %v", err)
 		}
 
-		serialRegex := regexp.MustCompile(`Serial Number:\s*(\S+)`)
-		if matches := serialRegex.FindStringSubmatch(output); matches != nil {
-			if matches[1] == serial {
-				output, err := utils.RunCommand("nvmecontrol", "logpage", "-p", "2", nvmeDevice)
-				if err != nil {
-					return diskServiceInterfaces.SMARTNvme{}, fmt.Errorf("failed to get NVMe device logpage: %v", err)
-				}
+		if matches := serialRegex.FindStringSubmatch(output); matches != nil && matches[1] == serial {
+			return nvmeDevice, nil
+		}
+	}
 
-				output = utils.RemoveEmptyLines(output)
-				parsedSMART := parseNVMeSMART(output)
-				parsedSMART.Device = nvmeDevice
+	return "", fmt.Errorf("NVMe device with serial %s not found", serial)
+}
 
-				return parsedSMART, nil
-			}
-		}
+func getNVMeControlData(serial string) (diskServiceInterfaces.SMARTNvme, error) {
+	nvmeDevice, err := nvmeController(serial)
+	if err != nil {
+		return diskServiceInterfaces.SMARTNvme{}, err
 	}
 
-	return diskServiceInterfaces.SMARTNvme{}, fmt.Errorf("NVMe device with serial %s not found", serial)
+	output, err := utils.RunCommand("nvmecontrol", "logpage", "-p", "2", nvmeDevice)
+	if err != nil {
+		return diskServiceInterfaces.SMARTNvme{}, fmt.Errorf("failed to get NVMe device logpage: %v", err)
+	}
+
+	output = utils.RemoveEmptyLines(output)
+	parsedSMART := parseNVMeSMART(output)
+	parsedSMART.Device = nvmeDevice
+
+	return parsedSMART, nil
 }
 
 func parseNVMeSMART(output string) diskServiceInterfaces.SMARTNvme {
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package iscsi
+
+import (
+	"fmt"
+
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+	"github.com/alchemillahq/sylve/pkg/system"
+	"github.com/alchemillahq/sylve/pkg/system/ctl"
+	"github.com/alchemillahq/sylve/pkg/utils"
+)
+
+func (s *Service) GetSettings() (iscsiModels.ISCSISettings, error) {
+	var settings iscsiModels.ISCSISettings
+	if err := s.DB.First(&settings).Error; err != nil {
+		return iscsiModels.ISCSISettings{}, fmt.Errorf("failed_to_get_iscsi_settings: %w", err)
+	}
+	return settings, nil
+}
+
+func (s *Service) SetSettings(enabled bool) error {
+	settings, err := s.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	settings.Enabled = enabled
+
+	if err := s.DB.Save(&settings).Error; err != nil {
+		return fmt.Errorf("failed_to_update_iscsi_settings: %w", err)
+	}
+
+	if err := s.WriteConfig(false); err != nil {
+		return err
+	}
+
+	return s.ApplyService(true)
+}
+
+// ApplyService writes ctld_enable and starts or stops ctld to match. ctld
+// is never restarted, a reload keeps the sessions of unchanged targets.
+func (s *Service) ApplyService(restart bool) error {
+	settings, err := s.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	if !settings.Enabled && !restart {
+		return nil
+	}
+
+	value := "NO"
+	if settings.Enabled {
+		value = "YES"
+	}
+
+	if _, err := utils.RunCommand("sysrc", "ctld_enable="+value); err != nil {
+		return fmt.Errorf("failed_to_set_rc_conf_ctld_enable: %w", err)
+	}
+
+	running := ctldRunning()
+
+	switch {
+	case !settings.Enabled && running:
+		if err := system.ServiceAction("ctld", "onestop"); err != nil {
+			return fmt.Errorf("failed_to_stop_ctld: %w", err)
+		}
+	case settings.Enabled && !running:
+		if err := system.ServiceAction("ctld", "start"); err != nil {
+			return fmt.Errorf("failed_to_start_ctld: %w", err)
+		}
+	case settings.Enabled && restart:
+		if err := system.ServiceAction("ctld", "reload"); err != nil {
+			return fmt.Errorf("ctld_reload_failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func ctldRunning() bool {
+	_, err := utils.RunCommand("service", "ctld", "onestatus")
+	return err == nil
+}
+
+func (s *Service) GetSessions() ([]ctl.Session, error) {
+	if !ctldRunning() {
+		return []ctl.Session{}, nil
+	}
+
+	sessions, err := ctl.Sessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_list_sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (s *Service) LogoutSession(id int) error {
+	if err := ctl.Logout(id); err != nil {
+		return fmt.Errorf("failed_to_logout_session: %w", err)
+	}
+	return nil
+}
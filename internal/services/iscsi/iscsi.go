@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package iscsi
+
+import (
+	iscsiServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/iscsi"
+
+	"gorm.io/gorm"
+)
+
+var _ iscsiServiceInterfaces.IscsiServiceInterface = (*Service)(nil)
+
+type Service struct {
+	DB *gorm.DB
+}
+
+func NewIscsiService(db *gorm.DB) iscsiServiceInterfaces.IscsiServiceInterface {
+	return &Service{
+		DB: db,
+	}
+}
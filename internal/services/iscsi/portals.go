@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package iscsi
+
+import (
+	"fmt"
+	"regexp"
+
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+	iscsiServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/iscsi"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	iface "github.com/alchemillahq/sylve/pkg/network/iface"
+)
+
+var portalGroupNameRe = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+func (s *Service) GetPortalGroups() ([]iscsiModels.ISCSIPortalGroup, error) {
+	var groups []iscsiModels.ISCSIPortalGroup
+	if err := s.DB.Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_portal_groups: %w", err)
+	}
+	return groups, nil
+}
+
+func validatePortalGroup(req *iscsiServiceInterfaces.PortalGroup) error {
+	if !portalGroupNameRe.MatchString(req.Name) {
+		return fmt.Errorf("invalid_portal_group_name")
+	}
+
+	if req.Port == 0 {
+		req.Port = 3260
+	}
+
+	req.Interfaces = utils.RemoveDuplicates(req.Interfaces)
+	if len(req.Interfaces) == 0 {
+		return fmt.Errorf("no_interfaces_selected")
+	}
+
+	for _, name := range req.Interfaces {
+		if _, err := iface.Get(name); err != nil {
+			return fmt.Errorf("invalid_interface: %s", name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) CreatePortalGroup(req iscsiServiceInterfaces.PortalGroup) error {
+	if err := validatePortalGroup(&req); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := s.DB.Model(&iscsiModels.ISCSIPortalGroup{}).Where("name = ?", req.Name).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_name_conflict: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("portal_group_with_name_exists")
+	}
+
+	group := iscsiModels.ISCSIPortalGroup{
+		Name:       req.Name,
+		Interfaces: req.Interfaces,
+		Port:       req.Port,
+		Comment:    req.Comment,
+	}
+
+	if err := s.DB.Create(&group).Error; err != nil {
+		return fmt.Errorf("failed_to_create_portal_group: %w", err)
+	}
+
+	return s.WriteConfig(true)
+}
+
+func (s *Service) UpdatePortalGroup(id uint, req iscsiServiceInterfaces.PortalGroup) error {
+	var group iscsiModels.ISCSIPortalGroup
+	if err := s.DB.First(&group, id).Error; err != nil {
+		return fmt.Errorf("portal_group_not_found: %w", err)
+	}
+
+	if err := validatePortalGroup(&req); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := s.DB.Model(&iscsiModels.ISCSIPortalGroup{}).
+		Where("name = ? AND id != ?", req.Name, id).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_name_conflict: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("portal_group_with_name_exists")
+	}
+
+	group.Name = req.Name
+	group.Interfaces = req.Interfaces
+	group.Port = req.Port
+	group.Comment = req.Comment
+
+	if err := s.DB.Save(&group).Error; err != nil {
+		return fmt.Errorf("failed_to_update_portal_group: %w", err)
+	}
+
+	return s.WriteConfig(true)
+}
+
+func (s *Service) DeletePortalGroup(id uint) error {
+	var group iscsiModels.ISCSIPortalGroup
+	if err := s.DB.First(&group, id).Error; err != nil {
+		return fmt.Errorf("portal_group_not_found: %w", err)
+	}
+
+	var count int64
+	if err := s.DB.Model(&iscsiModels.ISCSITarget{}).Where("portal_group_id = ?", id).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_portal_group_usage: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("portal_group_in_use")
+	}
+
+	if err := s.DB.Delete(&group).Error; err != nil {
+		return fmt.Errorf("failed_to_delete_portal_group: %w", err)
+	}
+
+	return s.WriteConfig(true)
+}
@@ -0,0 +1,394 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package iscsi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/system"
+	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+
+	iface "github.com/alchemillahq/sylve/pkg/network/iface"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ConfigPath   = "/etc/ctl.conf"
+	ConfigHeader = "# === This file is automatically generated by Sylve, don't edit! ==="
+)
+
+// portalAddresses returns the listen addresses of a portal group. Link
+// local IPv6 addresses are skipped since ctld cannot bind them without a
+// zone.
+func portalAddresses(group iscsiModels.ISCSIPortalGroup) []string {
+	var listen []string
+	port := strconv.Itoa(group.Port)
+
+	for _, name := range group.Interfaces {
+		ifc, err := iface.Get(name)
+		if err != nil {
+			logger.L.Warn().Err(err).Msgf("Portal group %s: interface %s not found", group.Name, name)
+			continue
+		}
+
+		for _, addr := range ifc.IPv4 {
+			listen = append(listen, net.JoinHostPort(addr.IP.String(), port))
+		}
+
+		for _, addr := range ifc.IPv6 {
+			if addr.IP.IsLinkLocalUnicast() || addr.Detached {
+				continue
+			}
+			listen = append(listen, net.JoinHostPort(addr.IP.String(), port))
+		}
+	}
+
+	return listen
+}
+
+// initiatorPortals turns network objects into initiator-portal values,
+// bracketing IPv6 the way ctl.conf(5) wants it. FQDNs use the addresses
+// the resolver found, ctld does not resolve names itself.
+func initiatorPortals(objects []networkModels.Object) []string {
+	var portals []string
+	seen := map[string]bool{}
+
+	add := func(value string) {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return
+		}
+
+		addr, prefix, hasPrefix := strings.Cut(value, "/")
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return
+		}
+
+		portal := ip.String()
+		if ip.To4() == nil {
+			portal = "[" + portal + "]"
+		}
+
+		if hasPrefix {
+			portal += "/" + prefix
+		}
+
+		if !seen[portal] {
+			seen[portal] = true
+			portals = append(portals, portal)
+		}
+	}
+
+	for _, obj := range objects {
+		switch obj.Type {
+		case "Host", "Network":
+			for _, entry := range obj.Entries {
+				add(entry.Value)
+			}
+		case "FQDN":
+			for _, res := range obj.Resolutions {
+				add(res.ResolvedIP)
+			}
+		}
+	}
+
+	return portals
+}
+
+// lunSerial ties the serial number to the zvol rather than to the LUN's
+// position, so initiators (and multipath) keep recognising the disk.
+func lunSerial(guid string) string {
+	n, err := strconv.ParseUint(guid, 10, 64)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%016x", n)
+}
+
+func authGroup(name string, target iscsiModels.ISCSITarget, portals []string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "auth-group %s {\n", name)
+
+	switch target.AuthType {
+	case iscsiModels.AuthTypeCHAP:
+		fmt.Fprintf(&b, "\tchap \"%s\" \"%s\"\n", target.CHAPUser, target.CHAPSecret)
+	case iscsiModels.AuthTypeCHAPMutual:
+		fmt.Fprintf(&b, "\tchap-mutual \"%s\" \"%s\" \"%s\" \"%s\"\n",
+			target.CHAPUser, target.CHAPSecret, target.MutualUser, target.MutualSecret)
+	default:
+		b.WriteString("\tauth-type none\n")
+	}
+
+	for _, name := range target.InitiatorNames {
+		fmt.Fprintf(&b, "\tinitiator-name \"%s\"\n", name)
+	}
+
+	for _, portal := range portals {
+		fmt.Fprintf(&b, "\tinitiator-portal %s\n", portal)
+	}
+
+	b.WriteString("}\n\n")
+
+	return b.String()
+}
+
+func (s *Service) Config() (string, error) {
+	var groups []iscsiModels.ISCSIPortalGroup
+	if err := s.DB.Order("id ASC").Find(&groups).Error; err != nil {
+		return "", fmt.Errorf("failed_to_get_portal_groups: %w", err)
+	}
+
+	var targets []iscsiModels.ISCSITarget
+	if err := s.DB.
+		Preload("Initiators").
+		Preload("Initiators.Entries").
+		Preload("Initiators.Resolutions").
+		Preload("LUNs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("number ASC")
+		}).
+		Order("id ASC").
+		Find(&targets).Error; err != nil {
+		return "", fmt.Errorf("failed_to_get_targets: %w", err)
+	}
+
+	volumes, err := zfs.Volumes("")
+	if err != nil {
+		return "", fmt.Errorf("failed_to_fetch_volumes: %v", err)
+	}
+
+	var config strings.Builder
+	config.WriteString(ConfigHeader + "\n\n")
+
+	// A portal group without a single address is rejected by ctld, which
+	// would take every other target down with it on reload
+	usable := map[uint]bool{}
+
+	for _, group := range groups {
+		listen := portalAddresses(group)
+		if len(listen) == 0 {
+			logger.L.Warn().Msgf("Skipping iSCSI portal group %s, none of its interfaces has an address", group.Name)
+			continue
+		}
+		usable[group.ID] = true
+
+		fmt.Fprintf(&config, "portal-group sylve-pg-%d {\n", group.ID)
+		config.WriteString("\tdiscovery-auth-group no-authentication\n")
+		config.WriteString("\tdiscovery-filter portal-name\n")
+		for _, addr := range listen {
+			fmt.Fprintf(&config, "\tlisten %s\n", addr)
+		}
+		config.WriteString("}\n\n")
+	}
+
+	for _, target := range targets {
+		if !usable[target.PortalGroupID] {
+			logger.L.Warn().Msgf("Skipping iSCSI target %s, its portal group is not available", target.Name)
+			continue
+		}
+
+		// An auth-group without initiator-portal lets everyone in, so a
+		// target whose ACL objects resolve to nothing stays off instead
+		portals := initiatorPortals(target.Initiators)
+		if len(target.Initiators) > 0 && len(portals) == 0 {
+			logger.L.Warn().Msgf("Skipping iSCSI target %s, none of its initiator objects resolve to an address", target.Name)
+			continue
+		}
+
+		ag := fmt.Sprintf("sylve-ag-%d", target.ID)
+		config.WriteString(authGroup(ag, target, portals))
+
+		fmt.Fprintf(&config, "target %s {\n", target.Name)
+		if target.Alias != "" {
+			fmt.Fprintf(&config, "\talias \"%s\"\n", target.Alias)
+		}
+		fmt.Fprintf(&config, "\tauth-group %s\n", ag)
+		fmt.Fprintf(&config, "\tportal-group sylve-pg-%d\n", target.PortalGroupID)
+
+		for _, lun := range target.LUNs {
+			var volume *zfs.Dataset
+			for _, v := range volumes {
+				if v.GUID == lun.Dataset {
+					volume = v
+					break
+				}
+			}
+
+			if volume == nil {
+				logger.L.Warn().Msgf("Skipping LUN %d of iSCSI target %s, volume %s not found", lun.Number, target.Name, lun.Dataset)
+				continue
+			}
+
+			fmt.Fprintf(&config, "\n\tlun %d {\n", lun.Number)
+			fmt.Fprintf(&config, "\t\tpath /dev/zvol/%s\n", volume.Name)
+			if lun.BlockSize > 0 {
+				fmt.Fprintf(&config, "\t\tblocksize %d\n", lun.BlockSize)
+			}
+			if serial := lunSerial(lun.Dataset); serial != "" {
+				fmt.Fprintf(&config, "\t\tserial \"%s\"\n", serial)
+				fmt.Fprintf(&config, "\t\tdevice-id \"SYLVE %s\"\n", serial)
+			}
+			config.WriteString("\t}\n")
+		}
+
+		config.WriteString("}\n\n")
+	}
+
+	return config.String(), nil
+}
+
+func (s *Service) WriteConfig(reload bool) error {
+	_, err := s.writeConfig(reload, false)
+	return err
+}
+
+// configManaged reports whether Sylve owns ctl.conf: once iSCSI is enabled,
+// a target exists or the file already carries Sylve's header. Until then a
+// ctld set up by hand keeps its configuration.
+func (s *Service) configManaged(current []byte) (bool, error) {
+	if bytes.Contains(current, []byte(ConfigHeader)) {
+		return true, nil
+	}
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		return false, err
+	}
+
+	if settings.Enabled {
+		return true, nil
+	}
+
+	var count int64
+	if err := s.DB.Model(&iscsiModels.ISCSITarget{}).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// writeConfig replaces ctl.conf atomically and asks ctld to reload it. If
+// the reload fails the previous file is put back, so a restart of ctld
+// later on comes up with the last configuration that worked. The ctl.conf
+// found before Sylve takes it over is kept next to it.
+func (s *Service) writeConfig(reload bool, onlyChanged bool) (bool, error) {
+	previous, readErr := os.ReadFile(ConfigPath)
+
+	managed, err := s.configManaged(previous)
+	if err != nil || !managed {
+		return false, err
+	}
+
+	content, err := s.Config()
+	if err != nil {
+		return false, err
+	}
+
+	if onlyChanged && readErr == nil && bytes.Equal(previous, []byte(content)) {
+		return false, nil
+	}
+
+	if readErr == nil && !bytes.Contains(previous, []byte(ConfigHeader)) {
+		backupPath := ConfigPath + ".pre-sylve"
+
+		exists, err := utils.FileExists(backupPath)
+		if err != nil {
+			return false, err
+		}
+
+		if !exists {
+			if err := utils.CopyFile(ConfigPath, backupPath); err != nil {
+				return false, fmt.Errorf("failed_to_backup_ctl_conf: %w", err)
+			}
+
+			if err := os.Chmod(backupPath, 0600); err != nil {
+				return false, fmt.Errorf("failed_to_backup_ctl_conf: %w", err)
+			}
+		}
+	}
+
+	if err := writeAtomic(ConfigPath, []byte(content)); err != nil {
+		return false, fmt.Errorf("failed_to_write_ctl_conf: %w", err)
+	}
+
+	if !reload {
+		return true, nil
+	}
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		return true, err
+	}
+
+	if !settings.Enabled || !ctldRunning() {
+		return true, nil
+	}
+
+	if err := system.ServiceAction("ctld", "reload"); err != nil {
+		if readErr == nil {
+			if rErr := writeAtomic(ConfigPath, previous); rErr != nil {
+				logger.L.Error().Err(rErr).Msg("Failed to restore previous ctl.conf")
+			}
+		}
+		return true, fmt.Errorf("ctld_reload_failed: %w", err)
+	}
+
+	return true, nil
+}
+
+func writeAtomic(path string, data []byte) error {
+	tmp := path + ".sylve-tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+// StartConfigWatcher keeps ctl.conf in step with interface addresses,
+// network objects used as initiator ACLs and zvol renames.
+func (s *Service) StartConfigWatcher(ctx context.Context) {
+	ticker := time.NewTicker(60 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			changed, err := s.writeConfig(true, true)
+			if err != nil {
+				logger.L.Warn().Err(err).Msg("Failed to sync iSCSI configuration")
+				continue
+			}
+
+			if changed {
+				logger.L.Info().Msg("iSCSI configuration changed, reloaded ctld")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
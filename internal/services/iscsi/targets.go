@@ -0,0 +1,366 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package iscsi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
+	iscsiServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/iscsi"
+	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+
+	"gorm.io/gorm"
+)
+
+// RFC 3720 names: iqn.yyyy-mm.reversed.domain[:anything], or the 64 and
+// 64/128 bit eui. and naa. forms.
+var (
+	iqnRe = regexp.MustCompile(`^iqn\.\d{4}-\d{2}\.[a-z0-9]([a-z0-9.-]*[a-z0-9])?(:[a-z0-9.:_-]+)?$`)
+	euiRe = regexp.MustCompile(`^eui\.[0-9A-F]{16}$`)
+	naaRe = regexp.MustCompile(`^naa\.([0-9A-F]{16}|[0-9A-F]{32})$`)
+
+	chapUserRe = regexp.MustCompile(`^[A-Za-z0-9_.:@-]{1,255}$`)
+)
+
+func validISCSIName(name string) bool {
+	return len(name) <= 223 && (iqnRe.MatchString(name) || euiRe.MatchString(name) || naaRe.MatchString(name))
+}
+
+// Secrets end up quoted in ctl.conf, and most initiators only accept
+// 12 to 16 characters.
+func validCHAPSecret(secret string) bool {
+	if len(secret) < 12 || len(secret) > 16 {
+		return false
+	}
+
+	return !strings.ContainsAny(secret, "\"\\ \t\n")
+}
+
+func (s *Service) GetTargets() ([]iscsiModels.ISCSITarget, error) {
+	var targets []iscsiModels.ISCSITarget
+	if err := s.DB.
+		Preload("PortalGroup").
+		Preload("Initiators").
+		Preload("LUNs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("number ASC")
+		}).
+		Find(&targets).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_targets: %w", err)
+	}
+	return targets, nil
+}
+
+func (s *Service) validateTarget(id uint, req *iscsiServiceInterfaces.Target, existing *iscsiModels.ISCSITarget) ([]networkModels.Object, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if strings.HasPrefix(strings.ToLower(req.Name), "iqn.") {
+		req.Name = strings.ToLower(req.Name)
+	}
+
+	if !validISCSIName(req.Name) {
+		return nil, fmt.Errorf("invalid_target_name")
+	}
+
+	var count int64
+	if err := s.DB.Model(&iscsiModels.ISCSITarget{}).
+		Where("name = ? AND id != ?", req.Name, id).
+		Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_check_name_conflict: %w", err)
+	}
+
+	if count > 0 {
+		return nil, fmt.Errorf("target_with_name_exists")
+	}
+
+	if strings.ContainsAny(req.Alias, "\"\\\n") {
+		return nil, fmt.Errorf("invalid_target_alias")
+	}
+
+	if err := s.DB.First(&iscsiModels.ISCSIPortalGroup{}, req.PortalGroupID).Error; err != nil {
+		return nil, fmt.Errorf("portal_group_not_found")
+	}
+
+	if req.AuthType == "" {
+		req.AuthType = iscsiModels.AuthTypeNone
+	}
+
+	if existing != nil {
+		if req.CHAPSecret == "" {
+			req.CHAPSecret = existing.CHAPSecret
+		}
+		if req.MutualSecret == "" {
+			req.MutualSecret = existing.MutualSecret
+		}
+	}
+
+	switch req.AuthType {
+	case iscsiModels.AuthTypeNone:
+		req.CHAPUser, req.CHAPSecret = "", ""
+		req.MutualUser, req.MutualSecret = "", ""
+	case iscsiModels.AuthTypeCHAP, iscsiModels.AuthTypeCHAPMutual:
+		if !chapUserRe.MatchString(req.CHAPUser) {
+			return nil, fmt.Errorf("invalid_chap_user")
+		}
+
+		if !validCHAPSecret(req.CHAPSecret) {
+			return nil, fmt.Errorf("invalid_chap_secret")
+		}
+
+		if req.AuthType == iscsiModels.AuthTypeCHAP {
+			req.MutualUser, req.MutualSecret = "", ""
+			break
+		}
+
+		if !chapUserRe.MatchString(req.MutualUser) {
+			return nil, fmt.Errorf("invalid_mutual_chap_user")
+		}
+
+		if !validCHAPSecret(req.MutualSecret) {
+			return nil, fmt.Errorf("invalid_mutual_chap_secret")
+		}
+
+		if req.MutualSecret == req.CHAPSecret {
+			return nil, fmt.Errorf("mutual_chap_secret_must_differ")
+		}
+	default:
+		return nil, fmt.Errorf("invalid_auth_type")
+	}
+
+	req.InitiatorNames = utils.RemoveDuplicates(req.InitiatorNames)
+	for i, name := range req.InitiatorNames {
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(strings.ToLower(name), "iqn.") {
+			name = strings.ToLower(name)
+		}
+
+		if !validISCSIName(name) {
+			return nil, fmt.Errorf("invalid_initiator_name: %s", name)
+		}
+		req.InitiatorNames[i] = name
+	}
+
+	var objects []networkModels.Object
+	if len(req.Initiators) > 0 {
+		if err := s.DB.Where("id IN ?", req.Initiators).Find(&objects).Error; err != nil {
+			return nil, fmt.Errorf("failed_to_get_network_objects: %w", err)
+		}
+
+		unique := map[uint]bool{}
+		for _, oid := range req.Initiators {
+			unique[oid] = true
+		}
+
+		if len(objects) != len(unique) {
+			return nil, fmt.Errorf("network_object_not_found")
+		}
+
+		for _, obj := range objects {
+			if obj.Type != "Host" && obj.Type != "Network" && obj.Type != "FQDN" {
+				return nil, fmt.Errorf("invalid_network_object_type: %s", obj.Name)
+			}
+		}
+	}
+
+	if err := s.validateLUNs(id, req.LUNs); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (s *Service) validateLUNs(targetID uint, luns []iscsiServiceInterfaces.LUN) error {
+	if len(luns) == 0 {
+		return nil
+	}
+
+	volumes, err := zfs.Volumes("")
+	if err != nil {
+		return fmt.Errorf("failed_to_fetch_volumes: %v", err)
+	}
+
+	numbers := map[int]bool{}
+	datasets := map[string]bool{}
+
+	for _, lun := range luns {
+		if numbers[lun.Number] {
+			return fmt.Errorf("duplicate_lun_number: %d", lun.Number)
+		}
+		numbers[lun.Number] = true
+
+		if datasets[lun.Dataset] {
+			return fmt.Errorf("duplicate_lun_dataset")
+		}
+		datasets[lun.Dataset] = true
+
+		var volume *zfs.Dataset
+		for _, v := range volumes {
+			if v.GUID == lun.Dataset {
+				volume = v
+				break
+			}
+		}
+
+		if volume == nil {
+			return fmt.Errorf("volume_not_found")
+		}
+
+		volmode, err := volume.GetProperty("volmode")
+		if err == nil && volmode == "none" {
+			return fmt.Errorf("volume_volmode_none: %s", volume.Name)
+		}
+
+		var count int64
+		if err := s.DB.Model(&vmModels.Storage{}).Where("dataset = ?", lun.Dataset).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed_to_check_volume_usage: %w", err)
+		}
+
+		if count > 0 {
+			return fmt.Errorf("volume_in_use_by_vm: %s", volume.Name)
+		}
+
+		if err := s.DB.Model(&iscsiModels.ISCSILUN{}).
+			Where("dataset = ? AND target_id != ?", lun.Dataset, targetID).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed_to_check_volume_usage: %w", err)
+		}
+
+		if count > 0 {
+			return fmt.Errorf("volume_in_use_by_other_target: %s", volume.Name)
+		}
+	}
+
+	return nil
+}
+
+func lunModels(luns []iscsiServiceInterfaces.LUN) []iscsiModels.ISCSILUN {
+	out := make([]iscsiModels.ISCSILUN, 0, len(luns))
+	for _, lun := range luns {
+		out = append(out, iscsiModels.ISCSILUN{
+			Number:    lun.Number,
+			Dataset:   lun.Dataset,
+			BlockSize: lun.BlockSize,
+		})
+	}
+	return out
+}
+
+func (s *Service) CreateTarget(req iscsiServiceInterfaces.Target) error {
+	objects, err := s.validateTarget(0, &req, nil)
+	if err != nil {
+		return err
+	}
+
+	target := iscsiModels.ISCSITarget{
+		Name:           req.Name,
+		Alias:          req.Alias,
+		PortalGroupID:  req.PortalGroupID,
+		AuthType:       req.AuthType,
+		CHAPUser:       req.CHAPUser,
+		CHAPSecret:     req.CHAPSecret,
+		MutualUser:     req.MutualUser,
+		MutualSecret:   req.MutualSecret,
+		InitiatorNames: req.InitiatorNames,
+		Initiators:     objects,
+		LUNs:           lunModels(req.LUNs),
+	}
+
+	if err := s.DB.Create(&target).Error; err != nil {
+		return fmt.Errorf("failed_to_create_target: %w", err)
+	}
+
+	return s.WriteConfig(true)
+}
+
+func (s *Service) UpdateTarget(id uint, req iscsiServiceInterfaces.Target) error {
+	var target iscsiModels.ISCSITarget
+	if err := s.DB.First(&target, id).Error; err != nil {
+		return fmt.Errorf("target_not_found: %w", err)
+	}
+
+	objects, err := s.validateTarget(id, &req, &target)
+	if err != nil {
+		return err
+	}
+
+	target.Name = req.Name
+	target.Alias = req.Alias
+	target.PortalGroupID = req.PortalGroupID
+	target.AuthType = req.AuthType
+	target.CHAPUser = req.CHAPUser
+	target.CHAPSecret = req.CHAPSecret
+	target.MutualUser = req.MutualUser
+	target.MutualSecret = req.MutualSecret
+	target.InitiatorNames = req.InitiatorNames
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("PortalGroup").Save(&target).Error; err != nil {
+			return fmt.Errorf("failed_to_update_target: %w", err)
+		}
+
+		if err := tx.Model(&target).Association("Initiators").Replace(objects); err != nil {
+			return fmt.Errorf("failed_to_update_target_initiators: %w", err)
+		}
+
+		if err := tx.Where("target_id = ?", id).Delete(&iscsiModels.ISCSILUN{}).Error; err != nil {
+			return fmt.Errorf("failed_to_clear_target_luns: %w", err)
+		}
+
+		luns := lunModels(req.LUNs)
+		for i := range luns {
+			luns[i].TargetID = id
+		}
+
+		if len(luns) > 0 {
+			if err := tx.Create(&luns).Error; err != nil {
+				return fmt.Errorf("failed_to_create_target_luns: %w", err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return s.WriteConfig(true)
+}
+
+func (s *Service) DeleteTarget(id uint) error {
+	var target iscsiModels.ISCSITarget
+	if err := s.DB.First(&target, id).Error; err != nil {
+		return fmt.Errorf("target_not_found: %w", err)
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&target).Association("Initiators").Clear(); err != nil {
+			return fmt.Errorf("failed_to_clear_target_initiators: %w", err)
+		}
+
+		if err := tx.Where("target_id = ?", id).Delete(&iscsiModels.ISCSILUN{}).Error; err != nil {
+			return fmt.Errorf("failed_to_delete_target_luns: %w", err)
+		}
+
+		if err := tx.Delete(&target).Error; err != nil {
+			return fmt.Errorf("failed_to_delete_target: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return s.WriteConfig(true)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/alchemillahq/sylve/internal/config"
 	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
 	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/alchemillahq/sylve/pkg/zfs"
 )
 
 func (s *Service) JailAction(ctId int, action string) error {
@@ -47,6 +48,27 @@ func (s *Service) JailAction(ctId int, action string) error {
 		return fmt.Errorf("failed to find jail with ct_id %d: %w", ctId, err)
 	}
 
+	if action != "stop" {
+		guids := []string{jail.Dataset}
+		var delegated []jailModels.DelegatedDataset
+		if err := s.DB.Where("jail_id = ?", jail.ID).Find(&delegated).Error; err != nil {
+			return fmt.Errorf("failed to get delegated datasets: %w", err)
+		}
+
+		for _, d := range delegated {
+			guids = append(guids, d.GUID)
+		}
+
+		locked, err := zfs.LockedDataset(guids)
+		if err != nil {
+			return fmt.Errorf("failed_to_check_dataset_encryption: %w", err)
+		}
+
+		if locked != nil {
+			return fmt.Errorf("dataset_locked: %s is encrypted and its key is not loaded, unlock %s first", locked.Name, locked.EncryptionRoot)
+		}
+	}
+
 	cmd := exec.Command("jail", "-f", jailConf, flag, ctidHash)
 
 	stdout, _ := cmd.StdoutPipe()
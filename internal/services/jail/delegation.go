@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package jail
+
+import (
+	"fmt"
+	"strings"
+
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+
+	"gorm.io/gorm"
+)
+
+func (s *Service) findDatasetByGUID(guid string) (*zfs.Dataset, error) {
+	datasets, err := zfs.Datasets("")
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_get_datasets: %w", err)
+	}
+
+	for _, d := range datasets {
+		if d.GUID == guid {
+			return d, nil
+		}
+	}
+
+	return nil, fmt.Errorf("dataset_not_found")
+}
+
+func (s *Service) GetDelegatedDatasets(ctId uint) ([]jailModels.DelegatedDataset, error) {
+	var jail jailModels.Jail
+	if err := s.DB.Preload("DelegatedDatasets").Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("jail_not_found")
+		}
+		return nil, fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	for i, d := range jail.DelegatedDatasets {
+		dataset, err := s.findDatasetByGUID(d.GUID)
+		if err != nil {
+			continue
+		}
+
+		if dataset.Name != d.Name {
+			jail.DelegatedDatasets[i].Name = dataset.Name
+			if err := s.DB.Model(&jail.DelegatedDatasets[i]).Update("name", dataset.Name).Error; err != nil {
+				logger.L.Warn().Err(err).Msgf("failed to update delegated dataset name for %s", d.GUID)
+			}
+		}
+	}
+
+	return jail.DelegatedDatasets, nil
+}
+
+func (s *Service) DelegateDataset(ctId uint, guid string) error {
+	s.crudMutex.Lock()
+	defer s.crudMutex.Unlock()
+
+	var jail jailModels.Jail
+	if err := s.DB.Preload("DelegatedDatasets").Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("jail_not_found")
+		}
+		return fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	if guid == jail.Dataset {
+		return fmt.Errorf("cannot_delegate_jail_root_dataset")
+	}
+
+	var count int64
+	if err := s.DB.Model(&jailModels.DelegatedDataset{}).Where("guid = ?", guid).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_delegated_datasets: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("dataset_already_delegated")
+	}
+
+	dataset, err := s.findDatasetByGUID(guid)
+	if err != nil {
+		return err
+	}
+
+	if dataset.Type != zfs.DatasetFilesystem {
+		return fmt.Errorf("only_filesystems_can_be_delegated")
+	}
+
+	root, err := s.findDatasetByGUID(jail.Dataset)
+	if err != nil {
+		return fmt.Errorf("failed_to_find_jail_dataset: %w", err)
+	}
+
+	if strings.HasPrefix(dataset.Name+"/", root.Name+"/") || strings.HasPrefix(root.Name+"/", dataset.Name+"/") {
+		return fmt.Errorf("dataset_overlaps_jail_root_dataset")
+	}
+
+	if err := dataset.SetProperty("jailed", "on"); err != nil {
+		return fmt.Errorf("failed_to_set_jailed_property: %w", err)
+	}
+
+	delegated := jailModels.DelegatedDataset{
+		JailID: jail.ID,
+		GUID:   dataset.GUID,
+		Name:   dataset.Name,
+	}
+
+	if err := s.DB.Create(&delegated).Error; err != nil {
+		return fmt.Errorf("failed_to_create_delegated_dataset: %w", err)
+	}
+
+	if err := s.SyncDelegations(ctId); err != nil {
+		return err
+	}
+
+	active, err := s.IsJailActive(ctId)
+	if err != nil {
+		return fmt.Errorf("failed_to_check_jail_state: %w", err)
+	}
+
+	if active {
+		ctidHash := utils.HashIntToNLetters(int(ctId), 5)
+		if _, err := utils.RunCommand("zfs", "jail", ctidHash, dataset.Name); err != nil {
+			return fmt.Errorf("failed_to_attach_dataset_to_jail: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) RemoveDelegatedDataset(ctId uint, id uint) error {
+	s.crudMutex.Lock()
+	defer s.crudMutex.Unlock()
+
+	var jail jailModels.Jail
+	if err := s.DB.Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("jail_not_found")
+		}
+		return fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	var delegated jailModels.DelegatedDataset
+	if err := s.DB.Where("id = ? AND jail_id = ?", id, jail.ID).First(&delegated).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("delegated_dataset_not_found")
+		}
+		return fmt.Errorf("failed_to_find_delegated_dataset: %w", err)
+	}
+
+	s.releaseDelegatedDataset(ctId, delegated)
+
+	if err := s.DB.Delete(&delegated).Error; err != nil {
+		return fmt.Errorf("failed_to_delete_delegated_dataset: %w", err)
+	}
+
+	return s.SyncDelegations(ctId)
+}
+
+func (s *Service) releaseDelegatedDataset(ctId uint, delegated jailModels.DelegatedDataset) {
+	dataset, err := s.findDatasetByGUID(delegated.GUID)
+	if err != nil {
+		logger.L.Warn().Err(err).Msgf("delegated dataset %s not found, skipping release", delegated.GUID)
+		return
+	}
+
+	active, err := s.IsJailActive(ctId)
+	if err == nil && active {
+		ctidHash := utils.HashIntToNLetters(int(ctId), 5)
+		if _, err := utils.RunCommand("zfs", "unjail", ctidHash, dataset.Name); err != nil {
+			logger.L.Warn().Err(err).Msgf("failed to unjail dataset %s from jail %s", dataset.Name, ctidHash)
+		}
+	}
+
+	if err := dataset.SetProperty("jailed", "off"); err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to reset jailed property on %s", dataset.Name)
+	}
+}
+
+func (s *Service) GetDelegationCleanedConfig(ctId uint) (string, error) {
+	cfg, err := s.GetJailConfig(ctId)
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(cfg, "\n")
+	for i := 0; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(t, `exec.created += "zfs jail `) {
+			lines = append(lines[:i], lines[i+1:]...)
+			i--
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (s *Service) SyncDelegations(ctId uint) error {
+	var jail jailModels.Jail
+	if err := s.DB.Preload("DelegatedDatasets").Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		return fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	cfg, err := s.GetDelegationCleanedConfig(ctId)
+	if err != nil {
+		return err
+	}
+
+	if len(jail.DelegatedDatasets) > 0 {
+		ctidHash := utils.HashIntToNLetters(int(ctId), 5)
+
+		var b strings.Builder
+		for _, d := range jail.DelegatedDatasets {
+			name := d.Name
+			if dataset, err := s.findDatasetByGUID(d.GUID); err == nil {
+				name = dataset.Name
+			}
+
+			b.WriteString(fmt.Sprintf("\texec.created += \"zfs jail %s %s\";\n", ctidHash, name))
+		}
+
+		cfg, err = s.AppendToConfig(ctId, cfg, b.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.SaveJailConfig(ctId, cfg); err != nil {
+		return err
+	}
+
+	return s.SyncParameters(ctId)
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package jail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	jailServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/jail"
+	"github.com/alchemillahq/sylve/pkg/utils"
+)
+
+const (
+	defaultExecTimeout = 60
+	maxExecTimeout     = 3600
+
+	// how long to wait for output copies after jexec exits, background
+	// processes in the jail may keep stdout and stderr open
+	execWaitDelay = 5 * time.Second
+)
+
+var (
+	execUserRe   = regexp.MustCompile(`^[a-z_][a-z0-9_-]*\$?$`)
+	execEnvKeyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+func (s *Service) buildExecArgs(ctId uint, req jailServiceInterfaces.ExecRequest) ([]string, time.Duration, error) {
+	if len(req.Command) == 0 || strings.TrimSpace(req.Command[0]) == "" {
+		return nil, 0, fmt.Errorf("command_required")
+	}
+
+	user := req.User
+	if user == "" {
+		user = "root"
+	}
+
+	if !execUserRe.MatchString(user) {
+		return nil, 0, fmt.Errorf("invalid_user")
+	}
+
+	timeout := req.Timeout
+	if timeout == 0 {
+		timeout = defaultExecTimeout
+	}
+
+	if timeout < 0 || timeout > maxExecTimeout {
+		return nil, 0, fmt.Errorf("invalid_timeout")
+	}
+
+	if s.GetJidByCtId(int(ctId)) < 0 {
+		return nil, 0, fmt.Errorf("jail_not_running")
+	}
+
+	args := []string{"-U", user, utils.HashIntToNLetters(int(ctId), 5)}
+
+	if len(req.Env) > 0 {
+		keys := make([]string, 0, len(req.Env))
+		for k := range req.Env {
+			if !execEnvKeyRe.MatchString(k) {
+				return nil, 0, fmt.Errorf("invalid_env_key: %s", k)
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		// env takes leading name=value words as assignments, so a command
+		// that looks like one cannot be run through it
+		if strings.Contains(req.Command[0], "=") {
+			return nil, 0, fmt.Errorf("invalid_command")
+		}
+
+		args = append(args, "/usr/bin/env", "--")
+		for _, k := range keys {
+			args = append(args, fmt.Sprintf("%s=%s", k, req.Env[k]))
+		}
+	}
+
+	args = append(args, req.Command...)
+
+	return args, time.Duration(timeout) * time.Second, nil
+}
+
+func (s *Service) ExecStream(ctId uint, req jailServiceInterfaces.ExecRequest, stdin io.Reader, stdout io.Writer, stderr io.Writer) (int, bool, error) {
+	args, timeout, err := s.buildExecArgs(ctId, req)
+	if err != nil {
+		return -1, false, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "jexec", args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = execWaitDelay
+
+	// with cmd.Stdin set, Wait blocks until stdin hits EOF even after the
+	// process is gone; a pipe is closed by Wait instead and the copy below
+	// ends once the caller closes stdin
+	var stdinPipe io.WriteCloser
+	if stdin != nil {
+		stdinPipe, err = cmd.StdinPipe()
+		if err != nil {
+			return -1, false, fmt.Errorf("failed_to_run_command: %w", err)
+		}
+	}
+
+	if err := cmd.Start(); err != nil {
+		return -1, false, fmt.Errorf("failed_to_run_command: %w", err)
+	}
+
+	if stdinPipe != nil {
+		go func() {
+			io.Copy(stdinPipe, stdin)
+			stdinPipe.Close()
+		}()
+	}
+
+	err = cmd.Wait()
+	if errors.Is(err, exec.ErrWaitDelay) {
+		err = nil
+	}
+
+	timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), timedOut, nil
+		}
+
+		return -1, timedOut, fmt.Errorf("failed_to_run_command: %w", err)
+	}
+
+	return 0, false, nil
+}
+
+func (s *Service) Exec(ctId uint, req jailServiceInterfaces.ExecRequest) (jailServiceInterfaces.ExecResult, error) {
+	var stdout, stderr bytes.Buffer
+
+	var stdin io.Reader
+	if req.Stdin != "" {
+		stdin = strings.NewReader(req.Stdin)
+	}
+
+	exitCode, timedOut, err := s.ExecStream(ctId, req, stdin, &stdout, &stderr)
+	if err != nil {
+		return jailServiceInterfaces.ExecResult{}, err
+	}
+
+	return jailServiceInterfaces.ExecResult{
+		ExitCode: exitCode,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		TimedOut: timedOut,
+	}, nil
+}
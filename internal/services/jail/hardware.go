@@ -254,6 +254,10 @@ func (s *Service) UpdateResourceLimits(ctId uint, enabled bool) error {
 		return fmt.Errorf("failed to save jail config: %w", err)
 	}
 
+	if err := s.clearRctlLimits(jail); err != nil {
+		return fmt.Errorf("failed to clear rctl limits: %w", err)
+	}
+
 	// Remove live rctl rule
 	if _, err := utils.RunCommand("rctl", "-r", fmt.Sprintf("jail:%s", ctIdHash)); err != nil {
 		logger.L.Warn().Err(err).Msgf("failed to remove rctl rules for jail %s", ctIdHash)
@@ -51,7 +51,7 @@ func NewJailService(db *gorm.DB, networkService networkServiceInterfaces.Network
 
 func (s *Service) GetJails() ([]jailModels.Jail, error) {
 	var jails []jailModels.Jail
-	if err := s.DB.Preload("Networks").Find(&jails).Error; err != nil {
+	if err := s.DB.Preload("Networks").Preload("DelegatedDatasets").Find(&jails).Error; err != nil {
 		logger.L.Error().Err(err).Msg("get_jails: failed to fetch jails")
 		return nil, fmt.Errorf("failed_to_fetch_jails: %w", err)
 	}
@@ -218,8 +218,11 @@ func (s *Service) ValidateCreate(data jailServiceInterfaces.CreateJailRequest) e
 		slaac = *data.SLAAC
 	}
 
+	autoIPv4 := data.AutoIPv4 != nil && *data.AutoIPv4
+	autoIPv6 := data.AutoIPv6 != nil && *data.AutoIPv6
+
 	if swAvailable {
-		if !dhcp {
+		if !dhcp && !autoIPv4 {
 			if data.IPv4 != nil {
 				ipv4Id := uint(*data.IPv4)
 				if ipv4Id != 0 && data.IPv4Gw != nil {
@@ -244,7 +247,7 @@ func (s *Service) ValidateCreate(data jailServiceInterfaces.CreateJailRequest) e
 			}
 		}
 
-		if !slaac {
+		if !slaac && !autoIPv6 {
 			if data.IPv6 != nil {
 				ipv6Id := uint(*data.IPv6)
 
@@ -275,6 +278,16 @@ func (s *Service) ValidateCreate(data jailServiceInterfaces.CreateJailRequest) e
 		return fmt.Errorf("start_order_must_be_greater_than_or_equal_to_0")
 	}
 
+	if data.Parameters != nil {
+		if err := ValidateParameters(*data.Parameters); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateDevfsRules(data.DevfsRules); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -293,20 +306,16 @@ func (s *Service) CreateJailConfig(data jailServiceInterfaces.CreateJailRequest,
 	config += fmt.Sprintf("\tpersist;\n")
 	config += fmt.Sprintf("\texec.clean;\n\n")
 
-	config += fmt.Sprintf("\tmount.devfs;\n")
-	config += fmt.Sprintf("\tdevfs_ruleset=\"8181\";\n\n")
-
-	config += fmt.Sprintf("\tallow.sysvipc;\n")
-	config += fmt.Sprintf("\tallow.reserved_ports;\n")
-	config += fmt.Sprintf("\tallow.raw_sockets;\n")
-	config += fmt.Sprintf("\tallow.socket_af;\n\n")
-
 	var jail jailModels.Jail
-	err := s.DB.Preload("Networks").First(&jail, "ct_id = ?", ctid).Error
+	err := s.DB.Preload("Networks").Preload("DelegatedDatasets").First(&jail, "ct_id = ?", ctid).Error
 	if err != nil {
 		return "", fmt.Errorf("failed to find jail with ct_id %d: %w", ctid, err)
 	}
 
+	config += fmt.Sprintf("\tmount.devfs;\n")
+	config += s.renderParameters(jail)
+	config += "\n"
+
 	config += fmt.Sprintf("\texec.start += \"/bin/sh /etc/rc\";\n")
 
 	if len(jail.Networks) == 1 {
@@ -490,6 +499,14 @@ func (s *Service) CreateJail(data jailServiceInterfaces.CreateJailRequest) error
 	jail.StartAtBoot = data.StartAtBoot
 	jail.StartOrder = data.StartOrder
 	jail.ResourceLimits = data.ResourceLimits
+	jail.DevfsRules = data.DevfsRules
+
+	if data.Parameters != nil {
+		jail.Parameters = data.Parameters
+	} else {
+		params := jailModels.DefaultParameters()
+		jail.Parameters = &params
+	}
 
 	if *jail.ResourceLimits {
 		jail.Cores = *data.Cores
@@ -602,6 +619,24 @@ func (s *Service) CreateJail(data jailServiceInterfaces.CreateJailRequest) error
 			slaac = *data.SLAAC
 		}
 
+		if !dhcp && data.AutoIPv4 != nil && *data.AutoIPv4 {
+			ip, gw, err := s.NetworkService.AllocatePoolAddress(swId, swType, 4, data.Name)
+			if err != nil {
+				return fmt.Errorf("failed_to_allocate_ipv4: %w", err)
+			}
+
+			ipv4Id, ipv4GwId = &ip, &gw
+		}
+
+		if !slaac && data.AutoIPv6 != nil && *data.AutoIPv6 {
+			ip, gw, err := s.NetworkService.AllocatePoolAddress(swId, swType, 6, data.Name)
+			if err != nil {
+				return fmt.Errorf("failed_to_allocate_ipv6: %w", err)
+			}
+
+			ipv6Id, ipv6GwId = &ip, &gw
+		}
+
 		jail.Networks = append(jail.Networks, jailModels.Network{
 			SwitchID:   swId,
 			SwitchType: swType,
@@ -694,6 +729,12 @@ func (s *Service) CreateJail(data jailServiceInterfaces.CreateJailRequest) error
 		return fmt.Errorf("failed_to_write_jail_config_file: %w", err)
 	}
 
+	if len(jail.DevfsRules) > 0 {
+		if err := s.SyncDevfsRules(); err != nil {
+			return fmt.Errorf("failed_to_sync_devfs_rules: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -703,7 +744,7 @@ func (s *Service) DeleteJail(ctId uint, deleteMacs bool) error {
 	}
 
 	var jail jailModels.Jail
-	if err := s.DB.Preload("Networks").Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+	if err := s.DB.Preload("Networks").Preload("DelegatedDatasets").Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return fmt.Errorf("jail_not_found")
 		}
@@ -742,6 +783,23 @@ func (s *Service) DeleteJail(ctId uint, deleteMacs bool) error {
 		}
 	}
 
+	for _, delegated := range jail.DelegatedDatasets {
+		s.releaseDelegatedDataset(ctId, delegated)
+
+		if err := s.DB.Delete(&delegated).Error; err != nil {
+			logger.L.Error().Err(err).Msg("delete_jail: failed to delete delegated dataset")
+			return fmt.Errorf("failed_to_delete_delegated_dataset: %w", err)
+		}
+	}
+
+	if err := s.DB.Where("jail_id = ?", jail.ID).Delete(&jailModels.ResourceLimit{}).Error; err != nil {
+		return fmt.Errorf("failed_to_delete_rctl_limits: %w", err)
+	}
+
+	if err := s.DB.Where("jail_id = ?", jail.ID).Delete(&jailModels.PackageVulnerability{}).Error; err != nil {
+		return fmt.Errorf("failed_to_delete_package_vulnerabilities: %w", err)
+	}
+
 	if err := s.DB.Delete(&jail).Error; err != nil {
 		return fmt.Errorf("failed_to_delete_jail: %w", err)
 	}
@@ -756,6 +814,12 @@ func (s *Service) DeleteJail(ctId uint, deleteMacs bool) error {
 		return fmt.Errorf("failed_to_remove_jail_directory: %w", err)
 	}
 
+	if len(jail.DevfsRules) > 0 {
+		if err := s.SyncDevfsRules(); err != nil {
+			logger.L.Error().Err(err).Msg("delete_jail: failed to sync devfs rules")
+		}
+	}
+
 	fsDestroyed := false
 
 	if err := dataset.Destroy(zfs.DestroyRecursive); err != nil {
@@ -773,6 +837,7 @@ func (s *Service) DeleteJail(ctId uint, deleteMacs bool) error {
 			"encryption":  {},
 			"aclinherit":  {},
 			"aclmode":     {},
+			"keyformat":   {},
 			"keylocation": {},
 			"quota":       {},
 		}
@@ -56,7 +56,9 @@ func (s *Service) AddNetwork(ctId uint,
 	ip6 uint,
 	ip6gw uint,
 	dhcp bool,
-	slaac bool) error {
+	slaac bool,
+	autoIP4 bool,
+	autoIP6 bool) error {
 	var jail jailModels.Jail
 	var network jailModels.Network
 
@@ -117,6 +119,20 @@ func (s *Service) AddNetwork(ctId uint,
 	network.SwitchID = switchId
 	network.SwitchType = switchType
 
+	if !dhcp && autoIP4 {
+		var err error
+		if ip4, ip4gw, err = s.NetworkService.AllocatePoolAddress(switchId, switchType, 4, jail.Name); err != nil {
+			return fmt.Errorf("failed_to_allocate_ip4: %w", err)
+		}
+	}
+
+	if !slaac && autoIP6 {
+		var err error
+		if ip6, ip6gw, err = s.NetworkService.AllocatePoolAddress(switchId, switchType, 6, jail.Name); err != nil {
+			return fmt.Errorf("failed_to_allocate_ip6: %w", err)
+		}
+	}
+
 	if !dhcp {
 		if ip4 == 0 || ip4gw == 0 {
 			return fmt.Errorf("ip4_and_ip4gw_must_be_specified_when_dhcp_is_disabled")
@@ -224,6 +240,10 @@ func (s *Service) AddNetwork(ctId uint,
 		return fmt.Errorf("failed_to_sync_epairs: %w", err)
 	}
 
+	if err := s.NetworkService.SyncDHCP(); err != nil {
+		logger.L.Warn().Err(err).Msg("failed to sync dhcp after adding jail network")
+	}
+
 	return s.SyncNetwork(ctId, jail, true)
 }
 
@@ -254,6 +274,10 @@ func (s *Service) DeleteNetwork(ctId uint, networkId uint) error {
 		return err
 	}
 
+	if err := s.NetworkService.SyncDHCP(); err != nil {
+		logger.L.Warn().Err(err).Msg("failed to sync dhcp after removing jail network")
+	}
+
 	return s.SyncNetwork(ctId, jail, true)
 }
 
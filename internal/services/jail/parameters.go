@@ -0,0 +1,309 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package jail
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	devfsRulesPath       = "/etc/devfs.rules"
+	defaultDevfsRuleset  = 8181
+	perJailRulesetOffset = 10000
+)
+
+var allowedJailAllows = []string{
+	"set_hostname", "sysvipc", "raw_sockets", "chflags", "mount", "mount.devfs",
+	"mount.fdescfs", "mount.linprocfs", "mount.linsysfs", "mount.nullfs",
+	"mount.procfs", "mount.tmpfs", "mount.zfs", "quotas", "socket_af", "mlock",
+	"reserved_ports", "read_msgbuf", "unprivileged_proc_debug", "suser", "vmm",
+	"nfsd", "extattr", "adjtime", "settime", "routing", "setaudit",
+}
+
+var (
+	osReleaseRe = regexp.MustCompile(`^[0-9]+\.[0-9]+-[A-Z0-9-]+$`)
+	devfsPathRe = regexp.MustCompile(`^[A-Za-z0-9_.*/\-\[\]]+$`)
+)
+
+func ValidateParameters(p jailModels.Parameters) error {
+	for _, a := range p.Allow {
+		if !slices.Contains(allowedJailAllows, a) {
+			return fmt.Errorf("invalid_allow_parameter: %s", a)
+		}
+	}
+
+	if p.Securelevel != nil && (*p.Securelevel < -1 || *p.Securelevel > 3) {
+		return fmt.Errorf("invalid_securelevel")
+	}
+
+	if p.EnforceStatfs < 0 || p.EnforceStatfs > 2 {
+		return fmt.Errorf("invalid_enforce_statfs")
+	}
+
+	if p.ChildrenMax < 0 {
+		return fmt.Errorf("invalid_children_max")
+	}
+
+	if p.OSRelease != "" && !osReleaseRe.MatchString(p.OSRelease) {
+		return fmt.Errorf("invalid_osrelease")
+	}
+
+	for _, v := range []string{p.SysVShm, p.SysVSem, p.SysVMsg} {
+		switch v {
+		case "", "disable", "inherit", "new":
+		default:
+			return fmt.Errorf("invalid_sysv_parameter: %s", v)
+		}
+
+		if v != "" && slices.Contains(p.Allow, "sysvipc") {
+			return fmt.Errorf("sysvipc_conflicts_with_sysv_parameters")
+		}
+	}
+
+	return nil
+}
+
+func ValidateDevfsRules(rules []string) error {
+	for _, r := range rules {
+		if r == "" || strings.HasPrefix(r, "/") || strings.Contains(r, "..") || !devfsPathRe.MatchString(r) {
+			return fmt.Errorf("invalid_devfs_path: %s", r)
+		}
+	}
+
+	return nil
+}
+
+func devfsRuleset(jail jailModels.Jail) int {
+	if len(jail.DevfsRules) > 0 {
+		return perJailRulesetOffset + jail.CTID
+	}
+
+	return defaultDevfsRuleset
+}
+
+func effectiveParameters(jail jailModels.Jail) jailModels.Parameters {
+	params := jailModels.DefaultParameters()
+	if jail.Parameters != nil {
+		params = *jail.Parameters
+	}
+
+	if len(jail.DelegatedDatasets) > 0 {
+		for _, a := range []string{"mount", "mount.zfs"} {
+			if !slices.Contains(params.Allow, a) {
+				params.Allow = append(params.Allow, a)
+			}
+		}
+
+		if params.EnforceStatfs > 1 {
+			params.EnforceStatfs = 1
+		}
+	}
+
+	return params
+}
+
+func (s *Service) renderParameters(jail jailModels.Jail) string {
+	params := effectiveParameters(jail)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\tdevfs_ruleset=\"%d\";\n", devfsRuleset(jail)))
+
+	for _, a := range params.Allow {
+		b.WriteString(fmt.Sprintf("\tallow.%s;\n", a))
+	}
+
+	b.WriteString(fmt.Sprintf("\tenforce_statfs=%d;\n", params.EnforceStatfs))
+
+	if params.Securelevel != nil {
+		b.WriteString(fmt.Sprintf("\tsecurelevel=%d;\n", *params.Securelevel))
+	}
+
+	if params.ChildrenMax > 0 {
+		b.WriteString(fmt.Sprintf("\tchildren.max=%d;\n", params.ChildrenMax))
+	}
+
+	if params.OSRelease != "" {
+		b.WriteString(fmt.Sprintf("\tosrelease=\"%s\";\n", params.OSRelease))
+	}
+
+	if params.SysVShm != "" {
+		b.WriteString(fmt.Sprintf("\tsysvshm=%s;\n", params.SysVShm))
+	}
+
+	if params.SysVSem != "" {
+		b.WriteString(fmt.Sprintf("\tsysvsem=%s;\n", params.SysVSem))
+	}
+
+	if params.SysVMsg != "" {
+		b.WriteString(fmt.Sprintf("\tsysvmsg=%s;\n", params.SysVMsg))
+	}
+
+	return b.String()
+}
+
+func (s *Service) GetParametersCleanedConfig(ctId uint) (string, error) {
+	cfg, err := s.GetJailConfig(ctId)
+	if err != nil {
+		return "", err
+	}
+
+	prefixes := []string{
+		"allow.", "devfs_ruleset", "enforce_statfs", "securelevel",
+		"children.max", "osrelease", "sysvshm", "sysvsem", "sysvmsg",
+	}
+
+	lines := strings.Split(cfg, "\n")
+	for i := 0; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		for _, p := range prefixes {
+			if strings.HasPrefix(t, p) {
+				lines = append(lines[:i], lines[i+1:]...)
+				i--
+				break
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (s *Service) SyncParameters(ctId uint) error {
+	var jail jailModels.Jail
+	if err := s.DB.Preload("DelegatedDatasets").Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		return fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	cfg, err := s.GetParametersCleanedConfig(ctId)
+	if err != nil {
+		return err
+	}
+
+	cfg, err = s.AppendToConfig(ctId, cfg, s.renderParameters(jail))
+	if err != nil {
+		return err
+	}
+
+	return s.SaveJailConfig(ctId, cfg)
+}
+
+func (s *Service) GetParameters(ctId uint) (jailModels.Parameters, []string, error) {
+	var jail jailModels.Jail
+	if err := s.DB.Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return jailModels.Parameters{}, nil, fmt.Errorf("jail_not_found")
+		}
+		return jailModels.Parameters{}, nil, fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	params := jailModels.DefaultParameters()
+	if jail.Parameters != nil {
+		params = *jail.Parameters
+	}
+
+	return params, jail.DevfsRules, nil
+}
+
+func (s *Service) UpdateParameters(ctId uint, params jailModels.Parameters, devfsRules []string) error {
+	if err := ValidateParameters(params); err != nil {
+		return err
+	}
+
+	if err := ValidateDevfsRules(devfsRules); err != nil {
+		return err
+	}
+
+	s.crudMutex.Lock()
+	defer s.crudMutex.Unlock()
+
+	var jail jailModels.Jail
+	if err := s.DB.Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("jail_not_found")
+		}
+		return fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	jail.Parameters = &params
+	jail.DevfsRules = devfsRules
+
+	if err := s.DB.Save(&jail).Error; err != nil {
+		return fmt.Errorf("failed_to_update_jail_parameters: %w", err)
+	}
+
+	if err := s.SyncDevfsRules(); err != nil {
+		return err
+	}
+
+	return s.SyncParameters(ctId)
+}
+
+func (s *Service) SyncDevfsRules() error {
+	var jails []jailModels.Jail
+	if err := s.DB.Select("id, ct_id, devfs_rules").Find(&jails).Error; err != nil {
+		return fmt.Errorf("failed_to_fetch_jails: %w", err)
+	}
+
+	existing := ""
+	if data, err := os.ReadFile(devfsRulesPath); err == nil {
+		existing = string(data)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed_to_read_devfs_rules: %w", err)
+	}
+
+	var kept []string
+	skip := false
+	for _, line := range strings.Split(existing, "\n") {
+		t := strings.TrimSpace(line)
+		if strings.HasPrefix(t, "[") {
+			skip = strings.HasPrefix(t, "[devfsrules_sylve_")
+		}
+
+		if !skip {
+			kept = append(kept, line)
+		}
+	}
+
+	content := strings.TrimRight(strings.Join(kept, "\n"), "\n") + "\n"
+
+	for _, jail := range jails {
+		if len(jail.DevfsRules) == 0 {
+			continue
+		}
+
+		content += fmt.Sprintf("\n[devfsrules_sylve_%d=%d]\n", jail.CTID, devfsRuleset(jail))
+		content += "add include $devfsrules_jails\n"
+		for _, r := range jail.DevfsRules {
+			content += fmt.Sprintf("add path '%s' unhide\n", r)
+		}
+	}
+
+	if content == existing {
+		return nil
+	}
+
+	if err := os.WriteFile(devfsRulesPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed_to_write_devfs_rules: %w", err)
+	}
+
+	if _, err := utils.RunCommand("service", "devfs", "restart"); err != nil {
+		logger.L.Warn().Err(err).Msg("failed to restart devfs service")
+	}
+
+	return nil
+}
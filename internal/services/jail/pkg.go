@@ -0,0 +1,429 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package jail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemillahq/sylve/internal/config"
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	jailServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/jail"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+var pkgNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.+@/-]*$`)
+
+func validatePackageNames(names []string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("no_packages_specified")
+	}
+
+	for _, n := range names {
+		if !pkgNameRe.MatchString(n) {
+			return fmt.Errorf("invalid_package_name: %s", n)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) pkgTarget(ctId uint) ([]string, error) {
+	if s.GetJidByCtId(int(ctId)) >= 0 {
+		return []string{"-j", utils.HashIntToNLetters(int(ctId), 5)}, nil
+	}
+
+	mountPoint, err := s.GetJailMountPoint(ctId)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{"-r", mountPoint}, nil
+}
+
+func (s *Service) runPkg(ctId uint, args ...string) (string, error) {
+	target, err := s.pkgTarget(ctId)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.RunCommand("pkg", append(target, args...)...)
+}
+
+func (s *Service) ListPackages(ctId uint) ([]jailServiceInterfaces.Package, error) {
+	output, err := s.runPkg(ctId, "query", "-a", "%n\t%v\t%sb\t%c")
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_list_packages: %w", err)
+	}
+
+	packages := []jailServiceInterfaces.Package{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) < 4 {
+			continue
+		}
+
+		size, _ := strconv.ParseUint(fields[2], 10, 64)
+		packages = append(packages, jailServiceInterfaces.Package{
+			Name:    fields[0],
+			Version: fields[1],
+			Size:    size,
+			Comment: fields[3],
+		})
+	}
+
+	return packages, nil
+}
+
+func (s *Service) InstallPackages(ctId uint, names []string) (string, error) {
+	if err := validatePackageNames(names); err != nil {
+		return "", err
+	}
+
+	output, err := s.runPkg(ctId, append([]string{"install", "-y"}, names...)...)
+	if err != nil {
+		return output, fmt.Errorf("failed_to_install_packages: %w", err)
+	}
+
+	return output, nil
+}
+
+func (s *Service) RemovePackages(ctId uint, names []string) (string, error) {
+	if err := validatePackageNames(names); err != nil {
+		return "", err
+	}
+
+	output, err := s.runPkg(ctId, append([]string{"delete", "-y"}, names...)...)
+	if err != nil {
+		return output, fmt.Errorf("failed_to_remove_packages: %w", err)
+	}
+
+	return output, nil
+}
+
+func parseUpgradePlan(output string) []jailServiceInterfaces.PackageChange {
+	sections := map[string]string{
+		"INSTALLED":   "install",
+		"UPGRADED":    "upgrade",
+		"DOWNGRADED":  "downgrade",
+		"REINSTALLED": "reinstall",
+		"REMOVED":     "remove",
+	}
+
+	changes := []jailServiceInterfaces.PackageChange{}
+	action := ""
+
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "\t") {
+			action = ""
+			for k, v := range sections {
+				if strings.HasSuffix(strings.TrimSpace(line), "to be "+k+":") {
+					action = v
+					break
+				}
+			}
+			continue
+		}
+
+		if action == "" {
+			continue
+		}
+
+		entry := strings.TrimSpace(line)
+		if i := strings.Index(entry, " ["); i > 0 {
+			entry = entry[:i]
+		}
+
+		change := jailServiceInterfaces.PackageChange{Action: action}
+
+		name, versions, found := strings.Cut(entry, ": ")
+		if found {
+			change.Name = name
+			if oldV, newV, isUpgrade := strings.Cut(versions, " -> "); isUpgrade {
+				change.OldVersion = oldV
+				change.NewVersion = newV
+			} else if action == "remove" {
+				change.OldVersion = versions
+			} else {
+				change.NewVersion = versions
+			}
+		} else {
+			change.Name = strings.Fields(entry)[0]
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func (s *Service) UpgradePackages(ctId uint, dryRun bool) (jailServiceInterfaces.UpgradePlan, error) {
+	if dryRun {
+		output, err := s.runPkg(ctId, "upgrade", "-n")
+		changes := parseUpgradePlan(output)
+		if err != nil && len(changes) == 0 {
+			return jailServiceInterfaces.UpgradePlan{}, fmt.Errorf("failed_to_plan_upgrade: %w", err)
+		}
+
+		return jailServiceInterfaces.UpgradePlan{Changes: changes, Output: output}, nil
+	}
+
+	output, err := s.runPkg(ctId, "upgrade", "-y")
+	if err != nil {
+		return jailServiceInterfaces.UpgradePlan{Output: output}, fmt.Errorf("failed_to_upgrade_packages: %w", err)
+	}
+
+	return jailServiceInterfaces.UpgradePlan{Changes: parseUpgradePlan(output), Output: output}, nil
+}
+
+func (s *Service) MirrorVuxml() (string, error) {
+	vuxmlPath, err := config.GetVuxmlPath()
+	if err != nil {
+		return "", err
+	}
+
+	before := time.Time{}
+	if st, err := os.Stat(vuxmlPath); err == nil {
+		before = st.ModTime()
+	}
+
+	// pkg audit exits non-zero when the host itself has vulnerable packages,
+	// so success is judged by whether the database file got refreshed
+	output, err := utils.RunCommand("pkg", "audit", "-F", "-q", "-f", vuxmlPath)
+
+	st, statErr := os.Stat(vuxmlPath)
+	if statErr != nil || !st.ModTime().After(before) {
+		if err != nil {
+			return "", fmt.Errorf("failed_to_fetch_vuxml: %w", err)
+		}
+
+		if statErr != nil {
+			return "", fmt.Errorf("vuxml_not_found: %w", statErr)
+		}
+
+		logger.L.Debug().Msgf("vuxml database unchanged: %s", strings.TrimSpace(output))
+	}
+
+	return vuxmlPath, nil
+}
+
+func parseAuditOutput(output string) []jailModels.PackageVulnerability {
+	var vulns []jailModels.PackageVulnerability
+	var current *jailModels.PackageVulnerability
+
+	for _, line := range strings.Split(output, "\n") {
+		t := strings.TrimSpace(line)
+
+		if strings.HasSuffix(t, " is vulnerable:") {
+			if current != nil {
+				vulns = append(vulns, *current)
+			}
+			current = &jailModels.PackageVulnerability{
+				Package: strings.TrimSuffix(t, " is vulnerable:"),
+				CVEs:    []string{},
+			}
+			continue
+		}
+
+		if current == nil || t == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(t, "CVE: "):
+			current.CVEs = append(current.CVEs, strings.TrimPrefix(t, "CVE: "))
+		case strings.HasPrefix(t, "WWW: "):
+			current.URL = strings.TrimPrefix(t, "WWW: ")
+		case strings.Contains(t, "problem(s) in"):
+		default:
+			if current.Topic == "" {
+				current.Topic = t
+			}
+		}
+	}
+
+	if current != nil {
+		vulns = append(vulns, *current)
+	}
+
+	return vulns
+}
+
+func (s *Service) auditJail(ctId uint, vuxmlPath string) ([]jailModels.PackageVulnerability, error) {
+	var jail jailModels.Jail
+	if err := s.DB.Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("jail_not_found")
+		}
+		return nil, fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	packages, err := s.ListPackages(ctId)
+	if err != nil {
+		return nil, err
+	}
+
+	vulns := []jailModels.PackageVulnerability{}
+
+	if len(packages) > 0 {
+		args := []string{"audit", "-f", vuxmlPath}
+		for _, p := range packages {
+			args = append(args, p.Name+"-"+p.Version)
+		}
+
+		output, err := utils.RunCommand("pkg", args...)
+		if err != nil && !strings.Contains(output, "is vulnerable") {
+			return nil, fmt.Errorf("failed_to_audit_packages: %w", err)
+		}
+
+		vulns = parseAuditOutput(output)
+	}
+
+	now := time.Now()
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("jail_id = ?", jail.ID).Delete(&jailModels.PackageVulnerability{}).Error; err != nil {
+			return err
+		}
+
+		for i := range vulns {
+			vulns[i].JailID = jail.ID
+			if err := tx.Create(&vulns[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&jail).Update("last_audit_at", now).Error
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_store_audit_results: %w", err)
+	}
+
+	return vulns, nil
+}
+
+func (s *Service) AuditPackages(ctId uint) ([]jailModels.PackageVulnerability, error) {
+	vuxmlPath, err := s.MirrorVuxml()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.auditJail(ctId, vuxmlPath)
+}
+
+func (s *Service) AuditAllJails() error {
+	vuxmlPath, err := s.MirrorVuxml()
+	if err != nil {
+		return err
+	}
+
+	var jails []jailModels.Jail
+	if err := s.DB.Select("id, ct_id").Find(&jails).Error; err != nil {
+		return fmt.Errorf("failed_to_fetch_jails: %w", err)
+	}
+
+	for _, jail := range jails {
+		if _, err := s.auditJail(uint(jail.CTID), vuxmlPath); err != nil {
+			logger.L.Warn().Err(err).Msgf("failed to audit packages for jail %d", jail.CTID)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) GetPackageVulnerabilities(ctId uint) ([]jailModels.PackageVulnerability, error) {
+	var jail jailModels.Jail
+	if err := s.DB.Preload("Vulnerabilities").Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("jail_not_found")
+		}
+		return nil, fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	return jail.Vulnerabilities, nil
+}
+
+func (s *Service) GetPackageAuditJob() (jailModels.PackageAuditJob, error) {
+	var job jailModels.PackageAuditJob
+	if err := s.DB.FirstOrCreate(&job, jailModels.PackageAuditJob{ID: 1}).Error; err != nil {
+		return job, fmt.Errorf("failed_to_get_package_audit_job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (s *Service) UpdatePackageAuditJob(enabled bool, cronExpr string) error {
+	if enabled {
+		if _, err := cron.ParseStandard(cronExpr); err != nil {
+			return fmt.Errorf("invalid_cron_expression: %w", err)
+		}
+	}
+
+	job, err := s.GetPackageAuditJob()
+	if err != nil {
+		return err
+	}
+
+	job.Enabled = enabled
+	job.CronExpr = cronExpr
+
+	if err := s.DB.Save(&job).Error; err != nil {
+		return fmt.Errorf("failed_to_update_package_audit_job: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) StartPackageAuditScheduler(ctx context.Context) {
+	ticker := time.NewTicker(60 * time.Second)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				var job jailModels.PackageAuditJob
+				if err := s.DB.First(&job).Error; err != nil || !job.Enabled || job.CronExpr == "" {
+					continue
+				}
+
+				sched, err := cron.ParseStandard(job.CronExpr)
+				if err != nil {
+					logger.L.Debug().Err(err).Msg("Invalid cron expression for package audit job")
+					continue
+				}
+
+				now := time.Now()
+				if !job.LastRunAt.IsZero() && !now.After(sched.Next(job.LastRunAt)) {
+					continue
+				}
+
+				if err := s.DB.Model(&job).Update("LastRunAt", now).Error; err != nil {
+					logger.L.Debug().Err(err).Msg("Failed to update LastRunAt for package audit job")
+				}
+
+				if err := s.AuditAllJails(); err != nil {
+					logger.L.Error().Err(err).Msg("Scheduled package audit failed")
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
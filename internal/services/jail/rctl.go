@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package jail
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	jailServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/jail"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	cpuid "github.com/klauspost/cpuid/v2"
+	"gorm.io/gorm"
+)
+
+var rctlResources = map[string]bool{
+	"pcpu":       false,
+	"maxproc":    false,
+	"openfiles":  false,
+	"vmemoryuse": false,
+	"swapuse":    false,
+	"readbps":    true,
+	"writebps":   true,
+	"readiops":   true,
+	"writeiops":  true,
+}
+
+func validateRctlRule(rule jailServiceInterfaces.RctlRule) error {
+	isIO, ok := rctlResources[rule.Resource]
+	if !ok {
+		return fmt.Errorf("invalid_rctl_resource: %s", rule.Resource)
+	}
+
+	switch rule.Action {
+	case "log", "devctl":
+	case "deny":
+		if isIO {
+			return fmt.Errorf("deny_not_supported_for_%s_use_throttle", rule.Resource)
+		}
+	case "throttle":
+		if !isIO {
+			return fmt.Errorf("throttle_only_supported_for_io_resources")
+		}
+	default:
+		return fmt.Errorf("invalid_rctl_action: %s", rule.Action)
+	}
+
+	if rule.Amount == 0 {
+		return fmt.Errorf("invalid_rctl_amount: %s", rule.Resource)
+	}
+
+	if rule.Resource == "pcpu" && rule.Amount > uint64(100*cpuid.CPU.LogicalCores) {
+		return fmt.Errorf("pcpu_exceeds_available_cores")
+	}
+
+	return nil
+}
+
+func rctlRuleString(ctidHash string, resource string, action string, amount uint64) string {
+	return fmt.Sprintf("jail:%s:%s:%s=%d", ctidHash, resource, action, amount)
+}
+
+func (s *Service) GetRctlCleanedConfig(ctId uint) (string, error) {
+	cfg, err := s.GetJailConfig(ctId)
+	if err != nil {
+		return "", err
+	}
+
+	ctidHash := utils.HashIntToNLetters(int(ctId), 5)
+
+	lines := strings.Split(cfg, "\n")
+	for i := 0; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		for resource := range rctlResources {
+			if strings.HasPrefix(t, fmt.Sprintf(`exec.poststart += "rctl -a jail:%s:%s:`, ctidHash, resource)) {
+				lines = append(lines[:i], lines[i+1:]...)
+				i--
+				break
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (s *Service) GetResourceLimits(ctId uint) ([]jailModels.ResourceLimit, error) {
+	var jail jailModels.Jail
+	if err := s.DB.Preload("Limits").Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("jail_not_found")
+		}
+		return nil, fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	return jail.Limits, nil
+}
+
+func (s *Service) SetRctlLimits(ctId uint, rules []jailServiceInterfaces.RctlRule) error {
+	s.crudMutex.Lock()
+	defer s.crudMutex.Unlock()
+
+	var jail jailModels.Jail
+	if err := s.DB.Preload("Limits").Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("jail_not_found")
+		}
+		return fmt.Errorf("failed_to_find_jail: %w", err)
+	}
+
+	if jail.ResourceLimits == nil || !*jail.ResourceLimits {
+		return fmt.Errorf("resource_limits_disabled")
+	}
+
+	seen := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		if err := validateRctlRule(rule); err != nil {
+			return err
+		}
+
+		key := rule.Resource + ":" + rule.Action
+		if _, dup := seen[key]; dup {
+			return fmt.Errorf("duplicate_rctl_rule: %s", key)
+		}
+		seen[key] = struct{}{}
+	}
+
+	old := jail.Limits
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("jail_id = ?", jail.ID).Delete(&jailModels.ResourceLimit{}).Error; err != nil {
+			return err
+		}
+
+		for _, rule := range rules {
+			limit := jailModels.ResourceLimit{
+				JailID:   jail.ID,
+				Resource: rule.Resource,
+				Action:   rule.Action,
+				Amount:   rule.Amount,
+			}
+
+			if err := tx.Create(&limit).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed_to_save_rctl_limits: %w", err)
+	}
+
+	cfg, err := s.GetRctlCleanedConfig(ctId)
+	if err != nil {
+		return err
+	}
+
+	ctidHash := utils.HashIntToNLetters(int(ctId), 5)
+
+	if len(rules) > 0 {
+		var b strings.Builder
+		for _, rule := range rules {
+			b.WriteString(fmt.Sprintf("\texec.poststart += \"rctl -a %s\";\n", rctlRuleString(ctidHash, rule.Resource, rule.Action, rule.Amount)))
+		}
+
+		poststop := fmt.Sprintf(`exec.poststop += "rctl -r jail:%s";`, ctidHash)
+		if !strings.Contains(cfg, poststop) {
+			b.WriteString("\t" + poststop + "\n")
+		}
+
+		cfg, err = s.AppendToConfig(ctId, cfg, b.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.SaveJailConfig(ctId, cfg); err != nil {
+		return fmt.Errorf("failed_to_save_jail_config: %w", err)
+	}
+
+	if s.GetJidByCtId(int(ctId)) < 0 {
+		return nil
+	}
+
+	for _, limit := range old {
+		filter := fmt.Sprintf("jail:%s:%s:%s", ctidHash, limit.Resource, limit.Action)
+		if _, err := utils.RunCommand("rctl", "-r", filter); err != nil {
+			logger.L.Warn().Err(err).Msgf("failed to remove rctl rule %s", filter)
+		}
+	}
+
+	for _, rule := range rules {
+		if _, err := utils.RunCommand("rctl", "-a", rctlRuleString(ctidHash, rule.Resource, rule.Action, rule.Amount)); err != nil {
+			return fmt.Errorf("failed_to_apply_rctl_rule: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) clearRctlLimits(jail jailModels.Jail) error {
+	if err := s.DB.Where("jail_id = ?", jail.ID).Delete(&jailModels.ResourceLimit{}).Error; err != nil {
+		return fmt.Errorf("failed_to_delete_rctl_limits: %w", err)
+	}
+
+	cfg, err := s.GetRctlCleanedConfig(uint(jail.CTID))
+	if err != nil {
+		return err
+	}
+
+	return s.SaveJailConfig(uint(jail.CTID), cfg)
+}
+
+func (s *Service) GetRctlUsage(ctId uint, limits []jailModels.ResourceLimit) ([]jailServiceInterfaces.RctlUsage, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	ctidHash := utils.HashIntToNLetters(int(ctId), 5)
+
+	output, err := utils.RunCommand("rctl", "-u", fmt.Sprintf("jail:%s", ctidHash))
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_get_rctl_usage: %w", err)
+	}
+
+	current := make(map[string]uint64)
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found {
+			continue
+		}
+
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		current[key] = v
+	}
+
+	usage := make([]jailServiceInterfaces.RctlUsage, 0, len(limits))
+	for _, limit := range limits {
+		used := current[limit.Resource]
+
+		var percent float64
+		if limit.Amount > 0 {
+			percent = math.Round((float64(used)/float64(limit.Amount))*10000) / 100
+		}
+
+		usage = append(usage, jailServiceInterfaces.RctlUsage{
+			Resource: limit.Resource,
+			Action:   limit.Action,
+			Amount:   limit.Amount,
+			Usage:    used,
+			Percent:  percent,
+		})
+	}
+
+	return usage, nil
+}
@@ -18,6 +18,7 @@ import (
 
 	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
 	jailServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/jail"
+	"github.com/alchemillahq/sylve/internal/logger"
 	"github.com/alchemillahq/sylve/pkg/utils"
 
 	cpuid "github.com/klauspost/cpuid/v2"
@@ -41,7 +42,7 @@ func (s *Service) GetJidByCtId(ctId int) int {
 
 func (s *Service) GetJailStats(ctId int) (jailServiceInterfaces.State, error) {
 	var jail jailModels.Jail
-	if err := s.DB.Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
+	if err := s.DB.Preload("Limits").Where("ct_id = ?", ctId).First(&jail).Error; err != nil {
 		return jailServiceInterfaces.State{}, err
 	}
 
@@ -108,6 +109,15 @@ func (s *Service) GetJailStats(ctId int) (jailServiceInterfaces.State, error) {
 	state.Memory = int64(totalRSS * 1024)
 	state.State = "ACTIVE"
 
+	if len(jail.Limits) > 0 {
+		limits, err := s.GetRctlUsage(uint(ctId), jail.Limits)
+		if err != nil {
+			logger.L.Debug().Err(err).Msgf("failed to get rctl usage for jail %d", ctId)
+		} else {
+			state.Limits = limits
+		}
+	}
+
 	return state, nil
 }
 
@@ -463,6 +463,17 @@ func (s *Service) ModifyVNC(vmId int, vncPort int, vncResolution string, vncPass
 		return fmt.Errorf("no_changes_detected: %d", vmId)
 	}
 
+	if vm.VNCPort != vncPort {
+		forwarded, err := isPortForwarded(s.DB, vncPort)
+		if err != nil {
+			return fmt.Errorf("failed_to_check_port_forwards: %w", err)
+		}
+
+		if forwarded {
+			return fmt.Errorf("vnc_port_used_by_port_forward")
+		}
+	}
+
 	domain, err := s.Conn.DomainLookupByName(strconv.Itoa(vmId))
 	if err != nil {
 		return fmt.Errorf("failed_to_lookup_domain_by_name: %w", err)
@@ -620,6 +620,34 @@ func (s *Service) CheckPCIDevicesInUse(vm vmModels.VM) error {
 	return nil
 }
 
+// checkStorageUnlocked refuses to boot a VM whose disks live on an
+// encrypted dataset that has not been unlocked yet, since bhyve would
+// otherwise fail with an unhelpful missing device error.
+func (s *Service) checkStorageUnlocked(vm vmModels.VM) error {
+	var storages []vmModels.Storage
+	if err := s.DB.Where("vm_id = ?", vm.ID).Find(&storages).Error; err != nil {
+		return fmt.Errorf("failed_to_get_vm_storages: %w", err)
+	}
+
+	var guids []string
+	for _, storage := range storages {
+		if storage.Dataset != "" {
+			guids = append(guids, storage.Dataset)
+		}
+	}
+
+	locked, err := zfs.LockedDataset(guids)
+	if err != nil {
+		return fmt.Errorf("failed_to_check_dataset_encryption: %w", err)
+	}
+
+	if locked != nil {
+		return fmt.Errorf("dataset_locked: %s is encrypted and its key is not loaded, unlock %s first", locked.Name, locked.EncryptionRoot)
+	}
+
+	return nil
+}
+
 func (s *Service) LvVMAction(vm vmModels.VM, action string) error {
 	s.actionMutex.Lock()
 	defer s.actionMutex.Unlock()
@@ -645,6 +673,10 @@ func (s *Service) LvVMAction(vm vmModels.VM, action string) error {
 			return nil
 		}
 
+		if err := s.checkStorageUnlocked(vm); err != nil {
+			return err
+		}
+
 		err = s.StartTPM()
 
 		if err != nil {
@@ -11,6 +11,7 @@ package libvirt
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/alchemillahq/sylve/internal/db/models"
@@ -83,6 +84,16 @@ func (s *Service) SimpleListVM() ([]libvirtServiceInterfaces.SimpleList, error)
 	return list, nil
 }
 
+func isPortForwarded(db *gorm.DB, port int) (bool, error) {
+	var count int64
+	err := db.Model(&networkModels.PortForward{}).
+		Joins("JOIN object_entries ON object_entries.object_id = port_forwards.host_port_object_id").
+		Where("object_entries.value = ?", strconv.Itoa(port)).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
 func validateCreate(data libvirtServiceInterfaces.CreateVMRequest, db *gorm.DB) error {
 	if data.Name == "" || !utils.IsValidVMName(data.Name) {
 		return fmt.Errorf("invalid_vm_name")
@@ -265,6 +276,15 @@ func validateCreate(data libvirtServiceInterfaces.CreateVMRequest, db *gorm.DB)
 			if utils.IsPortInUse(data.VNCPort) {
 				return fmt.Errorf("vnc_port_already_in_use_by_another_service")
 			}
+
+			forwarded, err := isPortForwarded(db, data.VNCPort)
+			if err != nil {
+				return fmt.Errorf("failed_to_check_port_forwards: %w", err)
+			}
+
+			if forwarded {
+				return fmt.Errorf("vnc_port_used_by_port_forward")
+			}
 		}
 	}
 
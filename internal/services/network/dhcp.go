@@ -0,0 +1,651 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemillahq/sylve/internal/config"
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
+	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	dnsmasqConfPath   = "/usr/local/etc/dnsmasq.conf"
+	dnsmasqLeasesPath = "/var/db/dnsmasq.leases"
+	dnsmasqFile       = "sylve.conf"
+)
+
+var (
+	dhcpHostnameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	dhcpDomainRe   = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+	dhcpOptionRe   = regexp.MustCompile(`^(option6?:[a-z0-9-]+|[0-9]{1,3})$`)
+	hostnameCharRe = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+func dhcpHostname(name string) string {
+	h := hostnameCharRe.ReplaceAllString(strings.ToLower(name), "-")
+	h = strings.Trim(h, "-")
+	if len(h) > 63 {
+		h = strings.Trim(h[:63], "-")
+	}
+
+	return h
+}
+
+func dhcpTag(sw networkModels.StandardSwitch) string {
+	return fmt.Sprintf("sylve%d", sw.ID)
+}
+
+func (s *Service) dhcpSwitch(id uint) (networkModels.StandardSwitch, *net.IPNet, error) {
+	var sw networkModels.StandardSwitch
+	if err := s.DB.
+		Preload("NetworkObj.Entries").
+		Preload("GatewayAddressObj.Entries").
+		First(&sw, id).Error; err != nil {
+		return sw, nil, fmt.Errorf("switch_not_found")
+	}
+
+	if sw.DHCP {
+		return sw, nil, fmt.Errorf("switch_is_dhcp_client")
+	}
+
+	_, subnet, err := net.ParseCIDR(sw.Network(4))
+	if err != nil {
+		return sw, nil, fmt.Errorf("switch_has_no_ipv4_network")
+	}
+
+	return sw, subnet, nil
+}
+
+func ipInSubnet(ip string, subnet *net.IPNet) error {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.To4() == nil {
+		return fmt.Errorf("invalid_ipv4_address: %s", ip)
+	}
+
+	if !subnet.Contains(parsed) {
+		return fmt.Errorf("ip_not_in_switch_network: %s", ip)
+	}
+
+	return nil
+}
+
+func (s *Service) GetDHCPConfig() (networkModels.DHCPConfig, error) {
+	var cfg networkModels.DHCPConfig
+	err := s.DB.Preload("StandardSwitches").First(&cfg).Error
+	if err == gorm.ErrRecordNotFound {
+		return networkModels.DHCPConfig{ExpandHosts: true}, nil
+	}
+
+	if err != nil {
+		return cfg, fmt.Errorf("failed_to_get_dhcp_config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func (s *Service) ModifyDHCPConfig(switchIDs []uint, dnsServers []string, domain string, expandHosts bool) error {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if domain != "" && !dhcpDomainRe.MatchString(domain) {
+		return fmt.Errorf("invalid_domain")
+	}
+
+	for _, d := range dnsServers {
+		if !utils.IsValidIPv4(d) && !utils.IsValidIPv6(d) {
+			return fmt.Errorf("invalid_dns_server: %s", d)
+		}
+	}
+
+	var switches []networkModels.StandardSwitch
+	for _, id := range switchIDs {
+		sw, _, err := s.dhcpSwitch(id)
+		if err != nil {
+			return fmt.Errorf("%w: %d", err, id)
+		}
+
+		switches = append(switches, sw)
+	}
+
+	cfg, err := s.GetDHCPConfig()
+	if err != nil {
+		return err
+	}
+
+	cfg.DNSServers = dnsServers
+	cfg.Domain = domain
+	cfg.ExpandHosts = expandHosts
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if cfg.ID == 0 {
+			if err := tx.Create(&cfg).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&cfg).Select("DNSServers", "Domain", "ExpandHosts").Updates(&cfg).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&cfg).Association("StandardSwitches").Replace(switches)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed_to_save_dhcp_config: %w", err)
+	}
+
+	return s.SyncDHCP()
+}
+
+func (s *Service) GetDHCPRanges() ([]networkModels.DHCPRanges, error) {
+	var ranges []networkModels.DHCPRanges
+	if err := s.DB.Preload("StandardSwitch").Order("id ASC").Find(&ranges).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_dhcp_ranges: %w", err)
+	}
+
+	return ranges, nil
+}
+
+func (s *Service) CreateDHCPRange(switchID uint, startIP string, endIP string) error {
+	_, subnet, err := s.dhcpSwitch(switchID)
+	if err != nil {
+		return err
+	}
+
+	for _, ip := range []string{startIP, endIP} {
+		if err := ipInSubnet(ip, subnet); err != nil {
+			return err
+		}
+	}
+
+	start := net.ParseIP(startIP).To4()
+	end := net.ParseIP(endIP).To4()
+	if bytes.Compare(start, end) > 0 {
+		return fmt.Errorf("start_ip_after_end_ip")
+	}
+
+	var existing []networkModels.DHCPRanges
+	if err := s.DB.Where("standard_switch_id = ?", switchID).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed_to_get_dhcp_ranges: %w", err)
+	}
+
+	for _, r := range existing {
+		rs := net.ParseIP(r.StartIP).To4()
+		re := net.ParseIP(r.EndIP).To4()
+		if bytes.Compare(start, re) <= 0 && bytes.Compare(rs, end) <= 0 {
+			return fmt.Errorf("dhcp_range_overlaps: %s-%s", r.StartIP, r.EndIP)
+		}
+	}
+
+	r := networkModels.DHCPRanges{
+		StartIP:          startIP,
+		EndIP:            endIP,
+		StandardSwitchID: &switchID,
+	}
+
+	if err := s.DB.Create(&r).Error; err != nil {
+		return fmt.Errorf("failed_to_create_dhcp_range: %w", err)
+	}
+
+	return s.SyncDHCP()
+}
+
+func (s *Service) DeleteDHCPRange(id uint) error {
+	res := s.DB.Delete(&networkModels.DHCPRanges{}, id)
+	if res.Error != nil {
+		return fmt.Errorf("failed_to_delete_dhcp_range: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("dhcp_range_not_found")
+	}
+
+	return s.SyncDHCP()
+}
+
+func (s *Service) GetDHCPStaticMappings() ([]networkModels.DHCPStaticMapping, error) {
+	var mappings []networkModels.DHCPStaticMapping
+	if err := s.DB.Preload("StandardSwitch").Order("id ASC").Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_dhcp_static_mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+func (s *Service) CreateDHCPStaticMapping(switchID uint, hostname string, mac string, ip string, comments string, expiry int) error {
+	_, subnet, err := s.dhcpSwitch(switchID)
+	if err != nil {
+		return err
+	}
+
+	hostname = strings.ToLower(hostname)
+	if !dhcpHostnameRe.MatchString(hostname) {
+		return fmt.Errorf("invalid_hostname")
+	}
+
+	if !utils.IsValidMAC(mac) {
+		return fmt.Errorf("invalid_mac_address")
+	}
+
+	if err := ipInSubnet(ip, subnet); err != nil {
+		return err
+	}
+
+	if expiry < 0 {
+		return fmt.Errorf("invalid_expiry")
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.DHCPStaticMapping{}).
+		Where("standard_switch_id = ? AND (mac = ? OR ip = ?)", switchID, strings.ToLower(mac), ip).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_dhcp_static_mappings: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("mac_or_ip_already_mapped")
+	}
+
+	mapping := networkModels.DHCPStaticMapping{
+		Hostname:         hostname,
+		MAC:              strings.ToLower(mac),
+		IP:               ip,
+		Comments:         comments,
+		Expiry:           expiry,
+		StandardSwitchID: &switchID,
+	}
+
+	if err := s.DB.Create(&mapping).Error; err != nil {
+		return fmt.Errorf("failed_to_create_dhcp_static_mapping: %w", err)
+	}
+
+	return s.SyncDHCP()
+}
+
+func (s *Service) DeleteDHCPStaticMapping(id uint) error {
+	res := s.DB.Delete(&networkModels.DHCPStaticMapping{}, id)
+	if res.Error != nil {
+		return fmt.Errorf("failed_to_delete_dhcp_static_mapping: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("dhcp_static_mapping_not_found")
+	}
+
+	return s.SyncDHCP()
+}
+
+func (s *Service) GetDHCPOptions() ([]networkModels.DHCPOption, error) {
+	var options []networkModels.DHCPOption
+	if err := s.DB.Preload("StandardSwitch").Order("id ASC").Find(&options).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_dhcp_options: %w", err)
+	}
+
+	return options, nil
+}
+
+func (s *Service) CreateDHCPOption(switchID uint, option string, value string, comments string) error {
+	if _, _, err := s.dhcpSwitch(switchID); err != nil {
+		return err
+	}
+
+	if !dhcpOptionRe.MatchString(option) {
+		return fmt.Errorf("invalid_dhcp_option")
+	}
+
+	if value == "" || strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("invalid_dhcp_option_value")
+	}
+
+	o := networkModels.DHCPOption{
+		Option:           option,
+		Value:            value,
+		Comments:         comments,
+		StandardSwitchID: &switchID,
+	}
+
+	if err := s.DB.Create(&o).Error; err != nil {
+		return fmt.Errorf("failed_to_create_dhcp_option: %w", err)
+	}
+
+	return s.SyncDHCP()
+}
+
+func (s *Service) DeleteDHCPOption(id uint) error {
+	res := s.DB.Delete(&networkModels.DHCPOption{}, id)
+	if res.Error != nil {
+		return fmt.Errorf("failed_to_delete_dhcp_option: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("dhcp_option_not_found")
+	}
+
+	return s.SyncDHCP()
+}
+
+// guestHosts maps the MAC of every VM and jail NIC on a standard switch to
+// the guest's name, so they resolve without a static mapping.
+func (s *Service) guestHosts() (map[uint]map[string]string, error) {
+	hosts := make(map[uint]map[string]string)
+	add := func(switchID uint, obj *networkModels.Object, name string) {
+		if obj == nil || len(obj.Entries) == 0 {
+			return
+		}
+
+		hostname := dhcpHostname(name)
+		if hostname == "" {
+			return
+		}
+
+		if hosts[switchID] == nil {
+			hosts[switchID] = make(map[string]string)
+		}
+
+		hosts[switchID][strings.ToLower(obj.Entries[0].Value)] = hostname
+	}
+
+	var vms []vmModels.VM
+	if err := s.DB.Preload("Networks.AddressObj.Entries").Find(&vms).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_vms: %w", err)
+	}
+
+	for _, vm := range vms {
+		for _, n := range vm.Networks {
+			if n.SwitchType == "standard" {
+				add(n.SwitchID, n.AddressObj, vm.Name)
+			}
+		}
+	}
+
+	var jails []jailModels.Jail
+	if err := s.DB.Preload("Networks.MacAddressObj.Entries").Find(&jails).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_jails: %w", err)
+	}
+
+	for _, jail := range jails {
+		for _, n := range jail.Networks {
+			if n.SwitchType == "standard" {
+				add(n.SwitchID, n.MacAddressObj, jail.Name)
+			}
+		}
+	}
+
+	return hosts, nil
+}
+
+func (s *Service) renderDHCP() (string, error) {
+	cfg, err := s.GetDHCPConfig()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("# Managed by Sylve, changes will be overwritten\n")
+
+	if len(cfg.StandardSwitches) == 0 {
+		return b.String(), nil
+	}
+
+	if cfg.Domain != "" {
+		b.WriteString(fmt.Sprintf("domain=%s\n", cfg.Domain))
+		b.WriteString(fmt.Sprintf("local=/%s/\n", cfg.Domain))
+	}
+
+	if cfg.ExpandHosts {
+		b.WriteString("expand-hosts\n")
+	}
+
+	if len(cfg.DNSServers) > 0 {
+		b.WriteString("no-resolv\n")
+		for _, d := range cfg.DNSServers {
+			b.WriteString(fmt.Sprintf("server=%s\n", d))
+		}
+	}
+
+	hosts, err := s.guestHosts()
+	if err != nil {
+		return "", err
+	}
+
+	for _, served := range cfg.StandardSwitches {
+		sw, subnet, err := s.dhcpSwitch(served.ID)
+		if err != nil {
+			logger.L.Warn().Err(err).Msgf("skipping dhcp for switch %s", served.Name)
+			continue
+		}
+
+		tag := dhcpTag(sw)
+		bridgeIP, _, _ := net.ParseCIDR(sw.Network(4))
+		router := bridgeIP.String()
+		if !sw.Private && sw.Gateway(4) != "" {
+			router = sw.Gateway(4)
+		}
+
+		b.WriteString(fmt.Sprintf("\n# %s\n", sw.Name))
+		b.WriteString(fmt.Sprintf("interface=%s\n", sw.BridgeName))
+
+		var ranges []networkModels.DHCPRanges
+		if err := s.DB.Where("standard_switch_id = ?", sw.ID).Order("id ASC").Find(&ranges).Error; err != nil {
+			return "", fmt.Errorf("failed_to_get_dhcp_ranges: %w", err)
+		}
+
+		netmask := net.IP(subnet.Mask).String()
+		for _, r := range ranges {
+			b.WriteString(fmt.Sprintf("dhcp-range=set:%s,%s,%s,%s,12h\n", tag, r.StartIP, r.EndIP, netmask))
+		}
+
+		b.WriteString(fmt.Sprintf("dhcp-option=tag:%s,option:router,%s\n", tag, router))
+		b.WriteString(fmt.Sprintf("dhcp-option=tag:%s,option:dns-server,%s\n", tag, bridgeIP.String()))
+
+		var options []networkModels.DHCPOption
+		if err := s.DB.Where("standard_switch_id = ?", sw.ID).Order("id ASC").Find(&options).Error; err != nil {
+			return "", fmt.Errorf("failed_to_get_dhcp_options: %w", err)
+		}
+
+		for _, o := range options {
+			b.WriteString(fmt.Sprintf("dhcp-option=tag:%s,%s,%s\n", tag, o.Option, o.Value))
+		}
+
+		var mappings []networkModels.DHCPStaticMapping
+		if err := s.DB.Where("standard_switch_id = ?", sw.ID).Order("id ASC").Find(&mappings).Error; err != nil {
+			return "", fmt.Errorf("failed_to_get_dhcp_static_mappings: %w", err)
+		}
+
+		mapped := make(map[string]bool)
+		for _, m := range mappings {
+			mapped[m.MAC] = true
+			line := fmt.Sprintf("dhcp-host=%s,%s,%s", m.MAC, m.IP, m.Hostname)
+			if m.Expiry > 0 {
+				line += fmt.Sprintf(",%d", m.Expiry)
+			}
+			b.WriteString(line + "\n")
+		}
+
+		macs := make([]string, 0, len(hosts[sw.ID]))
+		for mac := range hosts[sw.ID] {
+			macs = append(macs, mac)
+		}
+		slices.Sort(macs)
+
+		for _, mac := range macs {
+			if !mapped[mac] {
+				b.WriteString(fmt.Sprintf("dhcp-host=%s,%s\n", mac, hosts[sw.ID][mac]))
+			}
+		}
+	}
+
+	return b.String(), nil
+}
+
+func ensureDnsmasqInclude(path string) error {
+	existing := ""
+	if data, err := os.ReadFile(dnsmasqConfPath); err == nil {
+		existing = string(data)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed_to_read_dnsmasq_conf: %w", err)
+	}
+
+	include := "conf-file=" + path
+	for _, line := range strings.Split(existing, "\n") {
+		if strings.TrimSpace(line) == include {
+			return nil
+		}
+	}
+
+	if existing != "" && !strings.HasSuffix(existing, "\n") {
+		existing += "\n"
+	}
+
+	if err := os.WriteFile(dnsmasqConfPath, []byte(existing+include+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed_to_write_dnsmasq_conf: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) SyncDHCP() error {
+	s.dhcpMutex.Lock()
+	defer s.dhcpMutex.Unlock()
+
+	content, err := s.renderDHCP()
+	if err != nil {
+		return err
+	}
+
+	base, err := config.GetDnsmasqPath()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(base, dnsmasqFile)
+
+	previous, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed_to_read_dhcp_config: %w", err)
+	}
+
+	if err == nil && string(previous) == content {
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed_to_write_dhcp_config: %w", err)
+	}
+
+	if err := ensureDnsmasqInclude(path); err != nil {
+		return err
+	}
+
+	if _, err := utils.RunCommand("dnsmasq", "--test", "-C", dnsmasqConfPath); err != nil {
+		if previous != nil {
+			os.WriteFile(path, previous, 0644)
+		} else {
+			os.Remove(path)
+		}
+
+		return fmt.Errorf("dhcp_config_validation_failed: %w", err)
+	}
+
+	if _, err := utils.RunCommand("service", "dnsmasq", "restart"); err != nil {
+		return fmt.Errorf("failed_to_restart_dnsmasq: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) StartDHCPWatcher(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.SyncDHCP(); err != nil {
+					logger.L.Debug().Err(err).Msg("Failed to sync DHCP config")
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (s *Service) GetDHCPLeases() ([]networkServiceInterfaces.DHCPLease, error) {
+	data, err := os.ReadFile(dnsmasqLeasesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []networkServiceInterfaces.DHCPLease{}, nil
+		}
+		return nil, fmt.Errorf("failed_to_read_dhcp_leases: %w", err)
+	}
+
+	switches, err := s.GetStandardSwitches()
+	if err != nil {
+		return nil, err
+	}
+
+	leases := []networkServiceInterfaces.DHCPLease{}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] == "duid" {
+			continue
+		}
+
+		epoch, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		lease := networkServiceInterfaces.DHCPLease{
+			Expiry: time.Unix(epoch, 0),
+			MAC:    fields[1],
+			IP:     fields[2],
+		}
+
+		if fields[3] != "*" {
+			lease.Hostname = fields[3]
+		}
+
+		if len(fields) > 4 && fields[4] != "*" {
+			lease.ClientID = fields[4]
+		}
+
+		ip := net.ParseIP(lease.IP)
+		for _, sw := range switches {
+			_, subnet, err := net.ParseCIDR(sw.Network(4))
+			if err == nil && ip != nil && subnet.Contains(ip) {
+				id := sw.ID
+				lease.SwitchID = &id
+				break
+			}
+		}
+
+		leases = append(leases, lease)
+	}
+
+	return leases, nil
+}
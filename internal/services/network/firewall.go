@@ -0,0 +1,973 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alchemillahq/sylve/internal/config"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	pfConfPath          = "/etc/pf.conf"
+	firewallAnchor      = "sylve/filter"
+	maxConfirmTimeout   = 3600
+	firewallActiveFile  = "filter.conf"
+	firewallPendingFile = "filter.pending.conf"
+)
+
+var (
+	pfTranslationAnchors = []string{`nat-anchor "sylve/*"`, `rdr-anchor "sylve/*"`}
+	pfFilterAnchor       = `anchor "sylve/*"`
+)
+
+var firewallAddressTypes = []string{"Host", "Network", "List", "Country", "FQDN"}
+
+func firewallTableName(id uint) string {
+	return fmt.Sprintf("sylve_obj_%d", id)
+}
+
+func firewallFiles() (string, string, string, error) {
+	base, err := config.GetFirewallPath()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return filepath.Join(base, firewallActiveFile),
+		filepath.Join(base, firewallPendingFile),
+		filepath.Join(base, "tables"),
+		nil
+}
+
+func objectAddresses(object networkModels.Object) []string {
+	var addresses []string
+
+	switch object.Type {
+	case "Host", "Network":
+		for _, e := range object.Entries {
+			addresses = append(addresses, e.Value)
+		}
+	case "List":
+		for _, e := range object.Entries {
+			if utils.IsValidIPv4(e.Value) || utils.IsValidIPv6(e.Value) ||
+				utils.IsValidIPv4CIDR(e.Value) || utils.IsValidIPv6CIDR(e.Value) {
+				addresses = append(addresses, e.Value)
+			}
+		}
+	case "Country", "FQDN":
+		for _, r := range object.Resolutions {
+			addresses = append(addresses, r.ResolvedIP)
+		}
+	}
+
+	return addresses
+}
+
+func validateFirewallRule(rule networkModels.FirewallRule, objects map[uint]networkModels.Object) error {
+	switch rule.Action {
+	case "pass", "block", "reject":
+	default:
+		return fmt.Errorf("invalid_firewall_action: %s", rule.Action)
+	}
+
+	switch rule.Direction {
+	case "in", "out", "any":
+	default:
+		return fmt.Errorf("invalid_firewall_direction: %s", rule.Direction)
+	}
+
+	switch rule.Protocol {
+	case "any", "tcp", "udp", "tcp/udp":
+	case "icmp":
+		if rule.Family == "inet6" {
+			return fmt.Errorf("icmp_requires_inet")
+		}
+	case "icmp6":
+		if rule.Family == "inet" {
+			return fmt.Errorf("icmp6_requires_inet6")
+		}
+	default:
+		return fmt.Errorf("invalid_firewall_protocol: %s", rule.Protocol)
+	}
+
+	switch rule.Family {
+	case "", "inet", "inet6":
+	default:
+		return fmt.Errorf("invalid_firewall_family: %s", rule.Family)
+	}
+
+	for _, id := range []*uint{rule.SourceID, rule.DestinationID} {
+		if id == nil {
+			continue
+		}
+
+		object, ok := objects[*id]
+		if !ok {
+			return fmt.Errorf("firewall_object_not_found: %d", *id)
+		}
+
+		if !slices.Contains(firewallAddressTypes, object.Type) {
+			return fmt.Errorf("invalid_firewall_address_object: %s", object.Name)
+		}
+	}
+
+	for _, id := range []*uint{rule.SourcePortID, rule.DestinationPortID} {
+		if id == nil {
+			continue
+		}
+
+		object, ok := objects[*id]
+		if !ok {
+			return fmt.Errorf("firewall_object_not_found: %d", *id)
+		}
+
+		if object.Type != "Port" {
+			return fmt.Errorf("invalid_firewall_port_object: %s", object.Name)
+		}
+
+		if rule.Protocol != "tcp" && rule.Protocol != "udp" && rule.Protocol != "tcp/udp" {
+			return fmt.Errorf("ports_require_tcp_or_udp")
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) GetFirewallRuleSets() ([]networkModels.FirewallRuleSet, error) {
+	var sets []networkModels.FirewallRuleSet
+
+	err := s.DB.
+		Preload("Switch").
+		Preload("Rules", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, id ASC")
+		}).
+		Order("priority ASC, id ASC").
+		Find(&sets).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_get_firewall_rule_sets: %w", err)
+	}
+
+	return sets, nil
+}
+
+func (s *Service) validateRuleSetScope(switchID *uint) error {
+	if switchID == nil {
+		return nil
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.StandardSwitch{}).Where("id = ?", *switchID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_switch: %w", err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("switch_not_found")
+	}
+
+	return nil
+}
+
+func (s *Service) CreateFirewallRuleSet(name string, switchID *uint, priority int, enabled bool) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("name_required")
+	}
+
+	if err := s.validateRuleSetScope(switchID); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.FirewallRuleSet{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_rule_set_name: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("rule_set_with_name_already_exists: %s", name)
+	}
+
+	set := networkModels.FirewallRuleSet{
+		Name:     name,
+		SwitchID: switchID,
+		Priority: priority,
+		Enabled:  enabled,
+	}
+
+	if err := s.DB.Create(&set).Error; err != nil {
+		return fmt.Errorf("failed_to_create_rule_set: %w", err)
+	}
+
+	/* gorm skips zero values that have a default tag on create */
+	if !enabled {
+		if err := s.DB.Model(&set).Update("enabled", false).Error; err != nil {
+			return fmt.Errorf("failed_to_create_rule_set: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) EditFirewallRuleSet(id uint, name string, switchID *uint, priority int, enabled bool) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("name_required")
+	}
+
+	if err := s.validateRuleSetScope(switchID); err != nil {
+		return err
+	}
+
+	var set networkModels.FirewallRuleSet
+	if err := s.DB.First(&set, id).Error; err != nil {
+		return fmt.Errorf("rule_set_not_found: %w", err)
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.FirewallRuleSet{}).Where("name = ? AND id != ?", name, id).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_rule_set_name: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("rule_set_with_name_already_exists: %s", name)
+	}
+
+	err := s.DB.Model(&set).Select("name", "switch_id", "priority", "enabled").Updates(networkModels.FirewallRuleSet{
+		Name:     name,
+		SwitchID: switchID,
+		Priority: priority,
+		Enabled:  enabled,
+	}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed_to_update_rule_set: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteFirewallRuleSet(id uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_set_id = ?", id).Delete(&networkModels.FirewallRule{}).Error; err != nil {
+			return fmt.Errorf("failed_to_delete_rules: %w", err)
+		}
+
+		res := tx.Delete(&networkModels.FirewallRuleSet{}, id)
+		if res.Error != nil {
+			return fmt.Errorf("failed_to_delete_rule_set: %w", res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("rule_set_not_found")
+		}
+
+		return nil
+	})
+}
+
+func (s *Service) SetFirewallRules(ruleSetID uint, rules []networkModels.FirewallRule) error {
+	var set networkModels.FirewallRuleSet
+	if err := s.DB.First(&set, ruleSetID).Error; err != nil {
+		return fmt.Errorf("rule_set_not_found: %w", err)
+	}
+
+	var ids []uint
+	for i := range rules {
+		if rules[i].Protocol == "" {
+			rules[i].Protocol = "any"
+		}
+
+		ids = append(ids, rules[i].ObjectIDs()...)
+	}
+
+	objects := make(map[uint]networkModels.Object)
+	if len(ids) > 0 {
+		var found []networkModels.Object
+		if err := s.DB.Where("id IN ?", ids).Find(&found).Error; err != nil {
+			return fmt.Errorf("failed_to_find_objects: %w", err)
+		}
+
+		for _, o := range found {
+			objects[o.ID] = o
+		}
+	}
+
+	for _, rule := range rules {
+		if err := validateFirewallRule(rule, objects); err != nil {
+			return err
+		}
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_set_id = ?", ruleSetID).Delete(&networkModels.FirewallRule{}).Error; err != nil {
+			return fmt.Errorf("failed_to_delete_rules: %w", err)
+		}
+
+		for i, rule := range rules {
+			r := networkModels.FirewallRule{
+				RuleSetID:         ruleSetID,
+				Position:          i,
+				Enabled:           rule.Enabled,
+				Action:            rule.Action,
+				Direction:         rule.Direction,
+				Protocol:          rule.Protocol,
+				Family:            rule.Family,
+				Quick:             rule.Quick,
+				Log:               rule.Log,
+				Comment:           rule.Comment,
+				SourceID:          rule.SourceID,
+				SourcePortID:      rule.SourcePortID,
+				DestinationID:     rule.DestinationID,
+				DestinationPortID: rule.DestinationPortID,
+			}
+
+			if err := tx.Create(&r).Error; err != nil {
+				return fmt.Errorf("failed_to_create_rule: %w", err)
+			}
+
+			if err := tx.Model(&r).Select("enabled", "quick", "log").Updates(map[string]any{
+				"enabled": rule.Enabled,
+				"quick":   rule.Quick,
+				"log":     rule.Log,
+			}).Error; err != nil {
+				return fmt.Errorf("failed_to_create_rule: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func renderFirewallAddress(id *uint) string {
+	if id == nil {
+		return "any"
+	}
+
+	return fmt.Sprintf("<%s>", firewallTableName(*id))
+}
+
+func renderFirewallPorts(id *uint, objects map[uint]networkModels.Object) string {
+	if id == nil {
+		return ""
+	}
+
+	var ports []string
+	for _, e := range objects[*id].Entries {
+		ports = append(ports, e.Value)
+	}
+
+	return fmt.Sprintf(" port { %s }", strings.Join(ports, " "))
+}
+
+func renderFirewallRule(rule networkModels.FirewallRule, iface string, objects map[uint]networkModels.Object) string {
+	var b strings.Builder
+
+	switch rule.Action {
+	case "reject":
+		b.WriteString("block return")
+	default:
+		b.WriteString(rule.Action)
+	}
+
+	if rule.Direction != "any" {
+		b.WriteString(" " + rule.Direction)
+	}
+
+	if rule.Log {
+		b.WriteString(" log")
+	}
+
+	if rule.Quick {
+		b.WriteString(" quick")
+	}
+
+	if iface != "" {
+		b.WriteString(" on " + iface)
+	}
+
+	family := rule.Family
+	if family == "" && rule.Protocol == "icmp" {
+		family = "inet"
+	} else if family == "" && rule.Protocol == "icmp6" {
+		family = "inet6"
+	}
+
+	if family != "" {
+		b.WriteString(" " + family)
+	}
+
+	switch rule.Protocol {
+	case "any":
+	case "tcp/udp":
+		b.WriteString(" proto { tcp udp }")
+	default:
+		b.WriteString(" proto " + rule.Protocol)
+	}
+
+	b.WriteString(" from " + renderFirewallAddress(rule.SourceID))
+	b.WriteString(renderFirewallPorts(rule.SourcePortID, objects))
+	b.WriteString(" to " + renderFirewallAddress(rule.DestinationID))
+	b.WriteString(renderFirewallPorts(rule.DestinationPortID, objects))
+	b.WriteString(fmt.Sprintf(" label \"sylve_rule_%d\"", rule.ID))
+
+	return b.String()
+}
+
+func (s *Service) renderFirewall() (string, map[uint]networkModels.Object, error) {
+	sets, err := s.GetFirewallRuleSets()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var ids []uint
+	for _, set := range sets {
+		for _, rule := range set.Rules {
+			ids = append(ids, rule.ObjectIDs()...)
+		}
+	}
+
+	objects := make(map[uint]networkModels.Object)
+	if len(ids) > 0 {
+		var found []networkModels.Object
+		if err := s.DB.Preload("Entries").Preload("Resolutions").Where("id IN ?", ids).Find(&found).Error; err != nil {
+			return "", nil, fmt.Errorf("failed_to_find_objects: %w", err)
+		}
+
+		for _, o := range found {
+			objects[o.ID] = o
+		}
+	}
+
+	_, _, tablesDir, err := firewallFiles()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var tables, rules strings.Builder
+	tableObjects := make(map[uint]networkModels.Object)
+
+	for _, set := range sets {
+		if !set.Enabled {
+			continue
+		}
+
+		iface := ""
+		if set.Switch != nil {
+			iface = set.Switch.BridgeName
+		}
+
+		rules.WriteString(fmt.Sprintf("\n# %s\n", set.Name))
+
+		for _, rule := range set.Rules {
+			if !rule.Enabled {
+				continue
+			}
+
+			if err := validateFirewallRule(rule, objects); err != nil {
+				logger.L.Warn().Err(err).Msgf("skipping invalid firewall rule %d", rule.ID)
+				continue
+			}
+
+			for _, id := range []*uint{rule.SourceID, rule.DestinationID} {
+				if id != nil {
+					tableObjects[*id] = objects[*id]
+				}
+			}
+
+			rules.WriteString(renderFirewallRule(rule, iface, objects) + "\n")
+		}
+	}
+
+	tableIDs := make([]uint, 0, len(tableObjects))
+	for id := range tableObjects {
+		tableIDs = append(tableIDs, id)
+	}
+	slices.Sort(tableIDs)
+
+	for _, id := range tableIDs {
+		name := firewallTableName(id)
+		tables.WriteString(fmt.Sprintf("table <%s> persist file \"%s\"\n", name, filepath.Join(tablesDir, name)))
+	}
+
+	content := "# Managed by Sylve, changes will be overwritten\n" + tables.String() + rules.String()
+
+	return content, tableObjects, nil
+}
+
+func writeFirewallTable(object networkModels.Object) (string, error) {
+	_, _, tablesDir, err := firewallFiles()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(tablesDir, 0755); err != nil {
+		return "", fmt.Errorf("failed_to_create_tables_dir: %w", err)
+	}
+
+	path := filepath.Join(tablesDir, firewallTableName(object.ID))
+	data := strings.Join(objectAddresses(object), "\n")
+	if data != "" {
+		data += "\n"
+	}
+
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		return "", fmt.Errorf("failed_to_write_firewall_table: %w", err)
+	}
+
+	return path, nil
+}
+
+func (s *Service) ApplyFirewall(confirmTimeout int) error {
+	if confirmTimeout < 0 || confirmTimeout > maxConfirmTimeout {
+		return fmt.Errorf("invalid_confirm_timeout")
+	}
+
+	s.fwMutex.Lock()
+	defer s.fwMutex.Unlock()
+
+	if ready, err := s.preparePf(); err != nil || !ready {
+		return err
+	}
+
+	content, tables, err := s.renderFirewall()
+	if err != nil {
+		return err
+	}
+
+	for _, object := range tables {
+		if _, err := writeFirewallTable(object); err != nil {
+			return err
+		}
+	}
+
+	active, pending, _, err := firewallFiles()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(pending, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed_to_write_firewall_rules: %w", err)
+	}
+
+	if _, err := utils.RunCommand("pfctl", "-n", "-a", firewallAnchor, "-f", pending); err != nil {
+		os.Remove(pending)
+		return fmt.Errorf("firewall_validation_failed: %w", err)
+	}
+
+	/* pfctl loads an anchor in a single transaction, so a failure leaves the old rules in place */
+	if _, err := utils.RunCommand("pfctl", "-a", firewallAnchor, "-f", pending); err != nil {
+		os.Remove(pending)
+		return fmt.Errorf("failed_to_load_firewall_rules: %w", err)
+	}
+
+	if s.fwRollback != nil {
+		s.fwRollback.Stop()
+		s.fwRollback = nil
+	}
+
+	if confirmTimeout == 0 {
+		if err := os.Rename(pending, active); err != nil {
+			return fmt.Errorf("failed_to_commit_firewall_rules: %w", err)
+		}
+
+		return nil
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Duration(confirmTimeout)*time.Second, func() {
+		s.rollbackFirewall(timer)
+	})
+
+	s.fwRollback = timer
+	s.fwRollbackAt = time.Now().Add(time.Duration(confirmTimeout) * time.Second)
+
+	return nil
+}
+
+func (s *Service) ConfirmFirewall() error {
+	s.fwMutex.Lock()
+	defer s.fwMutex.Unlock()
+
+	if s.fwRollback == nil {
+		return fmt.Errorf("no_pending_firewall_changes")
+	}
+
+	s.fwRollback.Stop()
+	s.fwRollback = nil
+
+	active, pending, _, err := firewallFiles()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(pending, active); err != nil {
+		return fmt.Errorf("failed_to_commit_firewall_rules: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) RollbackFirewall() error {
+	s.fwMutex.Lock()
+	timer := s.fwRollback
+	s.fwMutex.Unlock()
+
+	if timer == nil {
+		return fmt.Errorf("no_pending_firewall_changes")
+	}
+
+	timer.Stop()
+	s.rollbackFirewall(timer)
+
+	return nil
+}
+
+func (s *Service) rollbackFirewall(timer *time.Timer) {
+	s.fwMutex.Lock()
+	defer s.fwMutex.Unlock()
+
+	/* Confirmed or superseded by a newer apply while we were waiting for the lock */
+	if s.fwRollback != timer {
+		return
+	}
+
+	s.fwRollback = nil
+
+	active, pending, _, err := firewallFiles()
+	if err != nil {
+		logger.L.Error().Err(err).Msg("failed to resolve firewall paths for rollback")
+		return
+	}
+
+	os.Remove(pending)
+
+	if err := loadFirewallRules(active); err != nil {
+		logger.L.Error().Err(err).Msg("failed to roll back firewall rules")
+		return
+	}
+
+	logger.L.Warn().Msg("firewall changes were not confirmed in time, rolled back")
+}
+
+func loadFirewallRules(path string) error {
+	exists, err := utils.FileExists(path)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if _, err := utils.RunCommand("pfctl", "-a", firewallAnchor, "-F", "all"); err != nil {
+			return fmt.Errorf("failed_to_flush_firewall_anchor: %w", err)
+		}
+
+		return nil
+	}
+
+	if _, err := utils.RunCommand("pfctl", "-a", firewallAnchor, "-f", path); err != nil {
+		return fmt.Errorf("failed_to_load_firewall_rules: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetFirewallStatus() (networkServiceInterfaces.FirewallStatus, error) {
+	var status networkServiceInterfaces.FirewallStatus
+
+	if out, err := utils.RunCommand("pfctl", "-s", "info"); err == nil {
+		status.Running = strings.Contains(out, "Status: Enabled")
+	}
+
+	staged, _, err := s.renderFirewall()
+	if err != nil {
+		return status, err
+	}
+
+	status.Staged = staged
+
+	s.fwMutex.Lock()
+	defer s.fwMutex.Unlock()
+
+	active, pending, _, err := firewallFiles()
+	if err != nil {
+		return status, err
+	}
+
+	path := active
+	if s.fwRollback != nil {
+		status.Pending = true
+		rollbackAt := s.fwRollbackAt
+		status.RollbackAt = &rollbackAt
+		path = pending
+	}
+
+	if data, err := os.ReadFile(path); err == nil {
+		status.Applied = string(data)
+	}
+
+	status.Dirty = status.Applied != status.Staged
+
+	return status, nil
+}
+
+func (s *Service) isObjectUsedByFirewall(id uint) (bool, error) {
+	var count int64
+	err := s.DB.Model(&networkModels.FirewallRule{}).
+		Where("source_object_id = ? OR source_port_object_id = ? OR destination_object_id = ? OR destination_port_object_id = ?", id, id, id, id).
+		Count(&count).Error
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check firewall rules using object %d: %w", id, err)
+	}
+
+	if count > 0 {
+		return true, nil
+	}
+
+	err = s.DB.Model(&networkModels.PortForward{}).
+		Where("host_port_object_id = ? OR destination_object_id = ? OR destination_port_object_id = ?", id, id, id).
+		Count(&count).Error
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check port forwards using object %d: %w", id, err)
+	}
+
+	return count > 0, nil
+}
+
+// ReloadFirewallTable refreshes the pf table backing an object in place. Port
+// objects are rendered inline, so their changes show up as staged until the
+// next apply.
+func (s *Service) ReloadFirewallTable(id uint) error {
+	used, err := s.isObjectUsedByFirewall(id)
+	if err != nil || !used {
+		return err
+	}
+
+	var object networkModels.Object
+	if err := s.DB.Preload("Entries").Preload("Resolutions").First(&object, id).Error; err != nil {
+		return fmt.Errorf("failed to find object with ID %d: %w", id, err)
+	}
+
+	if !slices.Contains(firewallAddressTypes, object.Type) {
+		return nil
+	}
+
+	s.fwMutex.Lock()
+	defer s.fwMutex.Unlock()
+
+	path, err := writeFirewallTable(object)
+	if err != nil {
+		return err
+	}
+
+	active, pending, _, err := firewallFiles()
+	if err != nil {
+		return err
+	}
+
+	loaded := active
+	if s.fwRollback != nil {
+		loaded = pending
+	}
+
+	data, err := os.ReadFile(loaded)
+	if err != nil || !strings.Contains(string(data), "<"+firewallTableName(id)+">") {
+		return nil
+	}
+
+	if _, err := utils.RunCommand("pfctl", "-a", firewallAnchor, "-t", firewallTableName(id), "-T", "replace", "-f", path); err != nil {
+		return fmt.Errorf("failed_to_reload_firewall_table: %w", err)
+	}
+
+	return nil
+}
+
+// ensurePfAnchors hooks the sylve anchors into the main ruleset. pf wants
+// translation rules ahead of filter rules, so the nat/rdr anchors go in front
+// of the first filtering line and the filter anchor at the end.
+func ensurePfAnchors() error {
+	existing := ""
+	if data, err := os.ReadFile(pfConfPath); err == nil {
+		existing = string(data)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed_to_read_pf_conf: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(existing, "\n"), "\n")
+	if existing == "" {
+		lines = nil
+	}
+
+	has := func(want string) bool {
+		for _, line := range lines {
+			if strings.TrimSpace(line) == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	changed := false
+
+	var missing []string
+	for _, a := range pfTranslationAnchors {
+		if !has(a) {
+			missing = append(missing, a)
+		}
+	}
+
+	if len(missing) > 0 {
+		at := len(lines)
+		for i, line := range lines {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+
+			switch fields[0] {
+			case "pass", "block", "match", "antispoof", "anchor":
+			default:
+				continue
+			}
+
+			at = i
+			break
+		}
+
+		lines = append(lines[:at], append(missing, lines[at:]...)...)
+		changed = true
+	}
+
+	if !has(pfFilterAnchor) {
+		lines = append(lines, pfFilterAnchor)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if err := os.WriteFile(pfConfPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed_to_write_pf_conf: %w", err)
+	}
+
+	return nil
+}
+
+// pfManaged reports whether there is anything for Sylve to load into pf:
+// filter rule sets, NAT on a switch or port forwards.
+func (s *Service) pfManaged() (bool, error) {
+	var ruleSets, natSwitches, forwards int64
+
+	if err := s.DB.Model(&networkModels.FirewallRuleSet{}).Count(&ruleSets).Error; err != nil {
+		return false, fmt.Errorf("failed_to_count_rule_sets: %w", err)
+	}
+
+	if err := s.DB.Model(&networkModels.StandardSwitch{}).Where("nat = ?", true).Count(&natSwitches).Error; err != nil {
+		return false, fmt.Errorf("failed_to_count_nat_switches: %w", err)
+	}
+
+	if err := s.DB.Model(&networkModels.PortForward{}).Count(&forwards).Error; err != nil {
+		return false, fmt.Errorf("failed_to_count_port_forwards: %w", err)
+	}
+
+	return ruleSets > 0 || natSwitches > 0 || forwards > 0, nil
+}
+
+// preparePf hooks the anchors into pf.conf, loads it and enables pf, but
+// only once Sylve has rules of its own. Hosts that never used the firewall
+// keep pf and their ruleset untouched. Returns whether pf is ready to have
+// the sylve anchors loaded.
+func (s *Service) preparePf() (bool, error) {
+	s.pfMutex.Lock()
+	defer s.pfMutex.Unlock()
+
+	if s.pfReady {
+		return true, nil
+	}
+
+	managed, err := s.pfManaged()
+	if err != nil || !managed {
+		return false, err
+	}
+
+	if err := ensurePfAnchors(); err != nil {
+		return false, err
+	}
+
+	if _, err := utils.RunCommand("pfctl", "-f", pfConfPath); err != nil {
+		return false, fmt.Errorf("failed_to_load_pf_conf: %w", err)
+	}
+
+	if out, err := utils.RunCommand("pfctl", "-e"); err != nil && !strings.Contains(out, "already enabled") {
+		return false, fmt.Errorf("failed_to_enable_pf: %w", err)
+	}
+
+	s.pfReady = true
+	return true, nil
+}
+
+func (s *Service) InitFirewall() error {
+	active, pending, _, err := firewallFiles()
+	if err != nil {
+		return err
+	}
+
+	/* An apply that was never confirmed before we went down counts as rolled back */
+	os.Remove(pending)
+
+	ready, err := s.preparePf()
+	if err != nil || !ready {
+		return err
+	}
+
+	_, tables, err := s.renderFirewall()
+	if err != nil {
+		return err
+	}
+
+	for _, object := range tables {
+		if _, err := writeFirewallTable(object); err != nil {
+			return err
+		}
+	}
+
+	if err := s.SyncNAT(); err != nil {
+		logger.L.Error().Err(err).Msg("failed to load nat rules")
+	}
+
+	exists, err := utils.FileExists(active)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		var count int64
+		if err := s.DB.Model(&networkModels.FirewallRuleSet{}).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed_to_count_rule_sets: %w", err)
+		}
+
+		if count > 0 {
+			return s.ApplyFirewall(0)
+		}
+
+		return nil
+	}
+
+	return loadFirewallRules(active)
+}
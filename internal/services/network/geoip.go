@@ -0,0 +1,359 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemillahq/sylve/internal/config"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	utilitiesModels "github.com/alchemillahq/sylve/internal/db/models/utilities"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+func parseGeoIPAddr(value string) (netip.Addr, error) {
+	if n, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return netip.AddrFrom4([4]byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}), nil
+	}
+
+	return netip.ParseAddr(value)
+}
+
+// parseGeoIPRecord understands the two common CSV layouts: "cidr,country[,...]"
+// and "start,end,country[,...]" where addresses may be dotted or decimal.
+func parseGeoIPRecord(record []string) ([]string, string, bool) {
+	if len(record) < 2 {
+		return nil, "", false
+	}
+
+	first := strings.TrimSpace(record[0])
+
+	if prefix, err := netip.ParsePrefix(first); err == nil {
+		country := strings.ToUpper(strings.TrimSpace(record[1]))
+		if !utils.IsValidCountryCode(country) {
+			return nil, "", false
+		}
+
+		return []string{prefix.Masked().String()}, country, true
+	}
+
+	if len(record) < 3 {
+		return nil, "", false
+	}
+
+	start, err := parseGeoIPAddr(first)
+	if err != nil {
+		return nil, "", false
+	}
+
+	end, err := parseGeoIPAddr(strings.TrimSpace(record[1]))
+	if err != nil {
+		return nil, "", false
+	}
+
+	country := strings.ToUpper(strings.TrimSpace(record[2]))
+	if !utils.IsValidCountryCode(country) {
+		return nil, "", false
+	}
+
+	cidrs, err := utils.RangeToCIDRs(start.String(), end.String())
+	if err != nil {
+		return nil, "", false
+	}
+
+	return cidrs, country, true
+}
+
+func loadGeoIPNetworks(path string, countries map[string]bool) (map[string][]string, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed_to_open_geoip_database: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	networks := make(map[string][]string)
+	total := 0
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed_to_parse_geoip_database: %w", err)
+		}
+
+		cidrs, country, ok := parseGeoIPRecord(record)
+		if !ok {
+			continue
+		}
+
+		total += len(cidrs)
+
+		if countries[country] {
+			networks[country] = append(networks[country], cidrs...)
+		}
+	}
+
+	if total == 0 {
+		return nil, 0, fmt.Errorf("geoip_database_empty_or_unrecognized")
+	}
+
+	return networks, total, nil
+}
+
+func (s *Service) GetGeoIPDatabase() (networkModels.GeoIPDatabase, error) {
+	var database networkModels.GeoIPDatabase
+
+	err := s.DB.First(&database, 1).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return database, nil
+	}
+
+	return database, err
+}
+
+// SetGeoIPDatabase points Country objects at a local CSV file, either by
+// path or by the UUID of a finished HTTP download.
+func (s *Service) SetGeoIPDatabase(path string, downloadUUID string) error {
+	if downloadUUID != "" {
+		var download utilitiesModels.Downloads
+		if err := s.DB.Where("uuid = ?", downloadUUID).First(&download).Error; err != nil {
+			return fmt.Errorf("download_not_found: %w", err)
+		}
+
+		if download.Type != "http" {
+			return fmt.Errorf("geoip_download_must_be_http")
+		}
+
+		if download.Progress < 100 {
+			return fmt.Errorf("geoip_download_incomplete")
+		}
+
+		path = filepath.Join(config.GetDownloadsPath("http"), download.Name)
+	}
+
+	if path == "" {
+		return fmt.Errorf("geoip_path_required")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("geoip_database_not_found: %w", err)
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf("geoip_database_is_directory")
+	}
+
+	database := networkModels.GeoIPDatabase{
+		ID:           1,
+		Path:         path,
+		DownloadUUID: downloadUUID,
+	}
+
+	if err := s.DB.Save(&database).Error; err != nil {
+		return fmt.Errorf("failed_to_save_geoip_database: %w", err)
+	}
+
+	return s.expandCountryObjects()
+}
+
+func (s *Service) RemoveGeoIPDatabase() error {
+	if err := s.DB.Delete(&networkModels.GeoIPDatabase{}, 1).Error; err != nil {
+		return fmt.Errorf("failed_to_remove_geoip_database: %w", err)
+	}
+
+	var ids []uint
+	if err := s.DB.Model(&networkModels.Object{}).Where("type = ?", "Country").Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.storeCountryResolutions(id, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) storeCountryResolutions(id uint, fresh []networkModels.ObjectResolution) error {
+	var previous []networkModels.ObjectResolution
+	if err := s.DB.Where("object_id = ?", id).Find(&previous).Error; err != nil {
+		return err
+	}
+
+	if slices.Equal(resolvedSet(previous), resolvedSet(fresh)) {
+		return nil
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("object_id = ?", id).Delete(&networkModels.ObjectResolution{}).Error; err != nil {
+			return err
+		}
+
+		if len(fresh) == 0 {
+			return nil
+		}
+
+		return tx.CreateInBatches(&fresh, 500).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to store resolutions for object %d: %w", id, err)
+	}
+
+	if err := s.ReloadFirewallTable(id); err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to reload firewall table for object %d", id)
+	}
+
+	s.syncWireGuardUsingObject(id)
+
+	return nil
+}
+
+// expandCountryObjects rewrites the resolutions of the given Country objects
+// (all of them when no IDs are passed) from the configured GeoIP database.
+func (s *Service) expandCountryObjects(ids ...uint) error {
+	s.geoipMutex.Lock()
+	defer s.geoipMutex.Unlock()
+
+	database, err := s.GetGeoIPDatabase()
+	if err != nil {
+		return err
+	}
+
+	if database.Path == "" {
+		return nil
+	}
+
+	query := s.DB.Preload("Entries").Where("type = ?", "Country")
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	var objects []networkModels.Object
+	if err := query.Find(&objects).Error; err != nil {
+		return fmt.Errorf("failed to fetch country objects: %w", err)
+	}
+
+	info, err := os.Stat(database.Path)
+	if err != nil {
+		database.Error = err.Error()
+		s.DB.Save(&database)
+		return fmt.Errorf("geoip_database_not_found: %w", err)
+	}
+
+	countries := make(map[string]bool)
+	for _, object := range objects {
+		for _, entry := range object.Entries {
+			countries[strings.ToUpper(entry.Value)] = true
+		}
+	}
+
+	networks, total, err := loadGeoIPNetworks(database.Path, countries)
+	if err != nil {
+		database.Error = err.Error()
+		s.DB.Save(&database)
+		return err
+	}
+
+	for _, object := range objects {
+		var fresh []networkModels.ObjectResolution
+
+		for _, entry := range object.Entries {
+			code := strings.ToUpper(entry.Value)
+			for _, cidr := range networks[code] {
+				fresh = append(fresh, networkModels.ObjectResolution{
+					ObjectID:   object.ID,
+					ResolvedIP: cidr,
+					Entry:      code,
+				})
+			}
+		}
+
+		if err := s.storeCountryResolutions(object.ID, fresh); err != nil {
+			return err
+		}
+	}
+
+	database.Size = info.Size()
+	database.ModTime = info.ModTime()
+	database.Networks = total
+	database.LoadedAt = time.Now()
+	database.Error = ""
+
+	return s.DB.Save(&database).Error
+}
+
+func (s *Service) expandCountryInBackground(id uint) {
+	if err := s.expandCountryObjects(id); err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to expand country object %d", id)
+	}
+}
+
+func (s *Service) checkGeoIPDatabase() {
+	database, err := s.GetGeoIPDatabase()
+	if err != nil || database.Path == "" {
+		return
+	}
+
+	info, err := os.Stat(database.Path)
+	if err != nil {
+		return
+	}
+
+	if info.Size() == database.Size && info.ModTime().Equal(database.ModTime) {
+		return
+	}
+
+	if err := s.expandCountryObjects(); err != nil {
+		logger.L.Warn().Err(err).Msg("Failed to refresh country objects from GeoIP database")
+	}
+}
+
+func (s *Service) StartGeoIPWatcher(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Minute)
+
+	go func() {
+		s.checkGeoIPDatabase()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.checkGeoIPDatabase()
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,916 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
+	libvirtServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/libvirt"
+	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+)
+
+// Allocations younger than this are never reclaimed, so a jail or VM that
+// is still being created does not lose its address to a concurrent lookup.
+const ipamReclaimGrace = 10 * time.Minute
+
+type addrRange struct {
+	start netip.Addr
+	end   netip.Addr
+}
+
+func (r addrRange) contains(a netip.Addr) bool {
+	return !a.Less(r.start) && !r.end.Less(a)
+}
+
+// ipPlan is a parsed pool: its usable host range and the ranges the
+// allocator must never hand out (gateway, exclusions, DHCP dynamic ranges).
+type ipPlan struct {
+	prefix   netip.Prefix
+	gateway  netip.Addr
+	first    netip.Addr
+	last     netip.Addr
+	reserved []addrRange
+}
+
+func (p ipPlan) isReserved(a netip.Addr) (addrRange, bool) {
+	for _, r := range p.reserved {
+		if r.contains(a) {
+			return r, true
+		}
+	}
+
+	return addrRange{}, false
+}
+
+type ipClaim struct {
+	source   string
+	assigned bool
+}
+
+func poolFamily(subnet string) int {
+	prefix, err := netip.ParsePrefix(subnet)
+	if err == nil && prefix.Addr().Is6() {
+		return 6
+	}
+
+	return 4
+}
+
+func mergeRanges(ranges []addrRange) []addrRange {
+	slices.SortFunc(ranges, func(a, b addrRange) int {
+		return a.start.Compare(b.start)
+	})
+
+	var merged []addrRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 {
+			next := merged[n-1].end.Next()
+			if !next.IsValid() || !next.Less(r.start) {
+				if merged[n-1].end.Less(r.end) {
+					merged[n-1].end = r.end
+				}
+				continue
+			}
+		}
+
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+func parseIPPool(pool networkModels.IPPool) (ipPlan, error) {
+	var plan ipPlan
+
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(pool.Subnet))
+	if err != nil {
+		return plan, fmt.Errorf("invalid_subnet: %s", pool.Subnet)
+	}
+
+	plan.prefix = prefix.Masked()
+	plan.first = plan.prefix.Addr()
+	plan.last = utils.LastAddrInPrefix(plan.prefix)
+
+	// Skip the network and broadcast addresses on IPv4 and the subnet-router
+	// anycast address on IPv6, except on point-to-point sized prefixes.
+	if plan.prefix.Addr().Is4() && plan.prefix.Bits() <= 30 {
+		plan.first = plan.first.Next()
+		plan.last = plan.last.Prev()
+	} else if plan.prefix.Addr().Is6() && plan.prefix.Bits() <= 126 {
+		plan.first = plan.first.Next()
+	}
+
+	hosts := addrRange{start: plan.first, end: plan.last}
+
+	gateway, err := netip.ParseAddr(strings.TrimSpace(pool.Gateway))
+	if err != nil {
+		return plan, fmt.Errorf("invalid_gateway: %s", pool.Gateway)
+	}
+
+	plan.gateway = gateway.Unmap()
+	if !hosts.contains(plan.gateway) {
+		return plan, fmt.Errorf("gateway_not_in_subnet: %s", pool.Gateway)
+	}
+
+	ranges := []addrRange{{start: plan.gateway, end: plan.gateway}}
+	for _, exclusion := range pool.Exclusions {
+		start, end, err := utils.ParseAddrRange(exclusion)
+		if err != nil {
+			return plan, fmt.Errorf("invalid_exclusion: %s: %w", exclusion, err)
+		}
+
+		if !plan.prefix.Contains(start) || !plan.prefix.Contains(end) {
+			return plan, fmt.Errorf("exclusion_not_in_subnet: %s", exclusion)
+		}
+
+		ranges = append(ranges, addrRange{start: start, end: end})
+	}
+
+	plan.reserved = mergeRanges(ranges)
+
+	return plan, nil
+}
+
+// planIPPool parses the pool and adds the DHCP dynamic ranges served on its
+// switch, which dnsmasq hands out on its own.
+func (s *Service) planIPPool(pool networkModels.IPPool) (ipPlan, error) {
+	plan, err := parseIPPool(pool)
+	if err != nil {
+		return plan, err
+	}
+
+	if pool.SwitchType != "standard" {
+		return plan, nil
+	}
+
+	var dhcpRanges []networkModels.DHCPRanges
+	if err := s.DB.Where("standard_switch_id = ?", pool.SwitchID).Find(&dhcpRanges).Error; err != nil {
+		return plan, fmt.Errorf("failed_to_get_dhcp_ranges: %w", err)
+	}
+
+	ranges := plan.reserved
+	for _, r := range dhcpRanges {
+		start, end, err := utils.ParseAddrRange(r.StartIP + "-" + r.EndIP)
+		if err != nil || !plan.prefix.Contains(start) {
+			continue
+		}
+
+		if plan.last.Less(end) {
+			end = plan.last
+		}
+
+		ranges = append(ranges, addrRange{start: start, end: end})
+	}
+
+	plan.reserved = mergeRanges(ranges)
+
+	return plan, nil
+}
+
+// ipClaims collects every address inside the prefix that is already taken
+// by a Host object, an interface-style Network object (jail addresses) or a
+// DHCP static mapping.
+func (s *Service) ipClaims(prefix netip.Prefix) (map[netip.Addr][]ipClaim, error) {
+	claims := make(map[netip.Addr][]ipClaim)
+
+	var objects []networkModels.Object
+	if err := s.DB.
+		Preload("Entries").
+		Where("type IN ?", []string{"Host", "Network"}).
+		Find(&objects).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_objects: %w", err)
+	}
+
+	for _, obj := range objects {
+		for _, entry := range obj.Entries {
+			var addr netip.Addr
+			assigned := false
+
+			if obj.Type == "Host" {
+				a, err := netip.ParseAddr(entry.Value)
+				if err != nil {
+					continue
+				}
+				addr = a.Unmap()
+			} else {
+				p, err := netip.ParsePrefix(entry.Value)
+				if err != nil || p.Addr() == p.Masked().Addr() {
+					continue
+				}
+				addr = p.Addr().Unmap()
+				assigned = true
+			}
+
+			if prefix.Contains(addr) {
+				claims[addr] = append(claims[addr], ipClaim{source: "object:" + obj.Name, assigned: assigned})
+			}
+		}
+	}
+
+	var mappings []networkModels.DHCPStaticMapping
+	if err := s.DB.Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_dhcp_static_mappings: %w", err)
+	}
+
+	for _, m := range mappings {
+		addr, err := netip.ParseAddr(m.IP)
+		if err != nil {
+			continue
+		}
+
+		addr = addr.Unmap()
+		if prefix.Contains(addr) {
+			claims[addr] = append(claims[addr], ipClaim{source: "dhcp:" + m.Hostname, assigned: true})
+		}
+	}
+
+	return claims, nil
+}
+
+func ipConflicts(plan ipPlan, claims map[netip.Addr][]ipClaim) []networkServiceInterfaces.IPConflict {
+	hosts := addrRange{start: plan.first, end: plan.last}
+	conflicts := []networkServiceInterfaces.IPConflict{}
+
+	for addr, list := range claims {
+		var sources []string
+		assigned := 0
+
+		for _, c := range list {
+			if !slices.Contains(sources, c.source) {
+				sources = append(sources, c.source)
+				if c.assigned {
+					assigned++
+				}
+			}
+		}
+
+		if assigned == 0 {
+			continue
+		}
+
+		reason := ""
+		if !hosts.contains(addr) {
+			reason = "reserved_address_assigned"
+		} else if addr == plan.gateway {
+			reason = "gateway_assigned"
+		} else if assigned > 1 {
+			reason = "assigned_more_than_once"
+		} else if _, ok := plan.isReserved(addr); ok {
+			reason = "excluded_address_assigned"
+		}
+
+		if reason == "" {
+			continue
+		}
+
+		slices.Sort(sources)
+		conflicts = append(conflicts, networkServiceInterfaces.IPConflict{
+			Address: addr.String(),
+			Reason:  reason,
+			Sources: sources,
+		})
+	}
+
+	slices.SortFunc(conflicts, func(a, b networkServiceInterfaces.IPConflict) int {
+		return netip.MustParseAddr(a.Address).Compare(netip.MustParseAddr(b.Address))
+	})
+
+	return conflicts
+}
+
+func ipPoolStatus(pool networkModels.IPPool, plan ipPlan, claims map[netip.Addr][]ipClaim) networkServiceInterfaces.IPPoolStatus {
+	status := networkServiceInterfaces.IPPoolStatus{
+		IPPool:    pool,
+		Total:     utils.AddrRangeSize(plan.first, plan.last),
+		Conflicts: ipConflicts(plan, claims),
+	}
+
+	for _, r := range plan.reserved {
+		start, end := r.start, r.end
+		if start.Less(plan.first) {
+			start = plan.first
+		}
+
+		if plan.last.Less(end) {
+			end = plan.last
+		}
+
+		status.Reserved += utils.AddrRangeSize(start, end)
+	}
+
+	hosts := addrRange{start: plan.first, end: plan.last}
+	for addr := range claims {
+		if _, reserved := plan.isReserved(addr); hosts.contains(addr) && !reserved {
+			status.Used++
+		}
+	}
+
+	if status.Total > status.Reserved+status.Used {
+		status.Free = status.Total - status.Reserved - status.Used
+	}
+
+	return status
+}
+
+func nextFreeAddress(plan ipPlan, claims map[netip.Addr][]ipClaim) (netip.Addr, error) {
+	for addr := plan.first; addr.IsValid() && !plan.last.Less(addr); {
+		if r, ok := plan.isReserved(addr); ok {
+			addr = r.end.Next()
+			continue
+		}
+
+		if _, taken := claims[addr]; !taken {
+			return addr, nil
+		}
+
+		addr = addr.Next()
+	}
+
+	return netip.Addr{}, fmt.Errorf("ip_pool_exhausted")
+}
+
+func (s *Service) GetIPPools() ([]networkServiceInterfaces.IPPoolStatus, error) {
+	s.ipamMutex.Lock()
+	defer s.ipamMutex.Unlock()
+
+	var pools []networkModels.IPPool
+	if err := s.DB.Order("id ASC").Find(&pools).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_ip_pools: %w", err)
+	}
+
+	statuses := make([]networkServiceInterfaces.IPPoolStatus, 0, len(pools))
+	/* Reclaiming happens on allocation and pool edits, listing never changes anything */
+	for _, pool := range pools {
+		if err := s.DB.Where("pool_id = ?", pool.ID).Order("id ASC").Find(&pool.Allocations).Error; err != nil {
+			return nil, fmt.Errorf("failed_to_get_ip_pool_allocations: %w", err)
+		}
+
+		plan, err := s.planIPPool(pool)
+		if err != nil {
+			statuses = append(statuses, networkServiceInterfaces.IPPoolStatus{IPPool: pool})
+			continue
+		}
+
+		claims, err := s.ipClaims(plan.prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, ipPoolStatus(pool, plan, claims))
+	}
+
+	return statuses, nil
+}
+
+func (s *Service) validateIPPool(pool *networkModels.IPPool, id uint) error {
+	pool.Name = strings.TrimSpace(pool.Name)
+	if pool.Name == "" {
+		return fmt.Errorf("invalid_name")
+	}
+
+	plan, err := parseIPPool(*pool)
+	if err != nil {
+		return err
+	}
+
+	pool.Subnet = plan.prefix.String()
+	pool.Gateway = plan.gateway.String()
+
+	family := poolFamily(pool.Subnet)
+
+	switch pool.SwitchType {
+	case "standard":
+		var sw networkModels.StandardSwitch
+		if err := s.DB.
+			Preload("NetworkObj.Entries").
+			Preload("Network6Obj.Entries").
+			First(&sw, pool.SwitchID).Error; err != nil {
+			return fmt.Errorf("switch_not_found")
+		}
+
+		if network := sw.Network(family); network != "" {
+			swPrefix, err := netip.ParsePrefix(network)
+			if err == nil && (!swPrefix.Masked().Contains(plan.prefix.Addr()) || plan.prefix.Bits() < swPrefix.Bits()) {
+				return fmt.Errorf("subnet_outside_switch_network: %s", network)
+			}
+		}
+	case "manual":
+		var sw networkModels.ManualSwitch
+		if err := s.DB.First(&sw, pool.SwitchID).Error; err != nil {
+			return fmt.Errorf("switch_not_found")
+		}
+	default:
+		return fmt.Errorf("invalid_switch_type: %s", pool.SwitchType)
+	}
+
+	var others []networkModels.IPPool
+	if err := s.DB.Where("id != ?", id).Find(&others).Error; err != nil {
+		return fmt.Errorf("failed_to_get_ip_pools: %w", err)
+	}
+
+	for _, other := range others {
+		if strings.EqualFold(other.Name, pool.Name) {
+			return fmt.Errorf("ip_pool_name_already_exists")
+		}
+
+		otherPrefix, err := netip.ParsePrefix(other.Subnet)
+		if err != nil {
+			continue
+		}
+
+		if other.SwitchID == pool.SwitchID && other.SwitchType == pool.SwitchType && poolFamily(other.Subnet) == family {
+			return fmt.Errorf("switch_already_has_ipv%d_pool: %s", family, other.Name)
+		}
+
+		if otherPrefix.Overlaps(plan.prefix) {
+			return fmt.Errorf("subnet_overlaps_pool: %s", other.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) CreateIPPool(pool networkModels.IPPool) error {
+	s.ipamMutex.Lock()
+	defer s.ipamMutex.Unlock()
+
+	if err := s.validateIPPool(&pool, 0); err != nil {
+		return err
+	}
+
+	pool.Allocations = nil
+	if err := s.DB.Create(&pool).Error; err != nil {
+		return fmt.Errorf("failed_to_create_ip_pool: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) EditIPPool(id uint, pool networkModels.IPPool) error {
+	s.ipamMutex.Lock()
+	defer s.ipamMutex.Unlock()
+
+	var existing networkModels.IPPool
+	if err := s.DB.First(&existing, id).Error; err != nil {
+		return fmt.Errorf("ip_pool_not_found")
+	}
+
+	if err := s.validateIPPool(&pool, id); err != nil {
+		return err
+	}
+
+	if err := s.reclaimIPPoolAllocations(id); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.IPPoolAllocation{}).Where("pool_id = ?", id).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_count_ip_pool_allocations: %w", err)
+	}
+
+	moved := pool.Subnet != existing.Subnet || pool.SwitchID != existing.SwitchID || pool.SwitchType != existing.SwitchType
+	if count > 0 && moved {
+		return fmt.Errorf("cannot_move_ip_pool_with_allocations")
+	}
+
+	if err := s.DB.Model(&existing).
+		Select("Name", "SwitchID", "SwitchType", "Subnet", "Gateway", "Exclusions", "Comment").
+		Updates(pool).Error; err != nil {
+		return fmt.Errorf("failed_to_update_ip_pool: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteIPPool(id uint) error {
+	s.ipamMutex.Lock()
+	defer s.ipamMutex.Unlock()
+
+	var pool networkModels.IPPool
+	if err := s.DB.First(&pool, id).Error; err != nil {
+		return fmt.Errorf("ip_pool_not_found")
+	}
+
+	if err := s.reclaimIPPoolAllocations(id); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.IPPoolAllocation{}).Where("pool_id = ?", id).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_count_ip_pool_allocations: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("ip_pool_has_allocations")
+	}
+
+	if err := s.DB.Delete(&pool).Error; err != nil {
+		return fmt.Errorf("failed_to_delete_ip_pool: %w", err)
+	}
+
+	return nil
+}
+
+// vmOwnsMAC reports whether any VM network still uses the MAC address.
+func (s *Service) vmOwnsMAC(mac string) (bool, error) {
+	var networks []vmModels.Network
+	if err := s.DB.Preload("AddressObj.Entries").Find(&networks).Error; err != nil {
+		return false, fmt.Errorf("failed_to_get_vm_networks: %w", err)
+	}
+
+	for _, n := range networks {
+		if strings.EqualFold(n.MAC, mac) {
+			return true, nil
+		}
+
+		if n.AddressObj != nil && len(n.AddressObj.Entries) > 0 && strings.EqualFold(n.AddressObj.Entries[0].Value, mac) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// reclaimIPPoolAllocations releases allocations whose jail network or VM is
+// gone, deleting the backing object or static mapping. Objects that were
+// since reused elsewhere (firewall, routes) are left alone.
+func (s *Service) reclaimIPPoolAllocations(poolID uint) error {
+	var allocations []networkModels.IPPoolAllocation
+	if err := s.DB.
+		Where("pool_id = ? AND created_at < ?", poolID, time.Now().Add(-ipamReclaimGrace)).
+		Find(&allocations).Error; err != nil {
+		return fmt.Errorf("failed_to_get_ip_pool_allocations: %w", err)
+	}
+
+	for _, alloc := range allocations {
+		if alloc.ObjectID != nil {
+			var count int64
+			if err := s.DB.Model(&networkModels.Object{}).Where("id = ?", *alloc.ObjectID).Count(&count).Error; err != nil {
+				return err
+			}
+
+			if count > 0 {
+				used, _, err := s.IsObjectUsedByJail(*alloc.ObjectID)
+				if err != nil || used {
+					continue
+				}
+
+				if err := s.DeleteObject(*alloc.ObjectID); err != nil {
+					logger.L.Debug().Err(err).Uint("object", *alloc.ObjectID).Msg("keeping ip pool allocation")
+					continue
+				}
+			}
+		} else if alloc.DHCPStaticMappingID != nil {
+			var mapping networkModels.DHCPStaticMapping
+			if err := s.DB.Where("id = ?", *alloc.DHCPStaticMappingID).Limit(1).Find(&mapping).Error; err != nil {
+				return err
+			}
+
+			if mapping.ID != 0 {
+				owned, err := s.vmOwnsMAC(mapping.MAC)
+				if err != nil || owned {
+					continue
+				}
+
+				if err := s.DeleteDHCPStaticMapping(mapping.ID); err != nil {
+					logger.L.Debug().Err(err).Uint("mapping", mapping.ID).Msg("keeping ip pool allocation")
+					continue
+				}
+			}
+		}
+
+		if err := s.DB.Delete(&alloc).Error; err != nil {
+			return fmt.Errorf("failed_to_delete_ip_pool_allocation: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) poolForSwitch(switchID uint, switchType string, family int) (networkModels.IPPool, error) {
+	var pools []networkModels.IPPool
+	if err := s.DB.Where("switch_id = ? AND switch_type = ?", switchID, switchType).Find(&pools).Error; err != nil {
+		return networkModels.IPPool{}, fmt.Errorf("failed_to_get_ip_pools: %w", err)
+	}
+
+	for _, pool := range pools {
+		if poolFamily(pool.Subnet) == family {
+			return pool, nil
+		}
+	}
+
+	return networkModels.IPPool{}, fmt.Errorf("no_ipv%d_pool_for_switch", family)
+}
+
+// nextPoolAddress picks the next free address of the switch's pool. The
+// caller must hold ipamMutex until the address is recorded.
+func (s *Service) nextPoolAddress(switchID uint, switchType string, family int) (networkModels.IPPool, ipPlan, netip.Addr, error) {
+	pool, err := s.poolForSwitch(switchID, switchType, family)
+	if err != nil {
+		return pool, ipPlan{}, netip.Addr{}, err
+	}
+
+	if err := s.reclaimIPPoolAllocations(pool.ID); err != nil {
+		logger.L.Warn().Err(err).Str("pool", pool.Name).Msg("failed to reclaim ip pool allocations")
+	}
+
+	plan, err := s.planIPPool(pool)
+	if err != nil {
+		return pool, plan, netip.Addr{}, err
+	}
+
+	claims, err := s.ipClaims(plan.prefix)
+	if err != nil {
+		return pool, plan, netip.Addr{}, err
+	}
+
+	addr, err := nextFreeAddress(plan, claims)
+	if err != nil {
+		return pool, plan, netip.Addr{}, fmt.Errorf("%w: %s", err, pool.Name)
+	}
+
+	return pool, plan, addr, nil
+}
+
+func (s *Service) uniqueObjectName(base string) (string, error) {
+	name := base
+
+	for i := 0; ; i++ {
+		if i > 0 {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		var exists int64
+		if err := s.DB.
+			Model(&networkModels.Object{}).
+			Where("name = ?", name).
+			Limit(1).
+			Count(&exists).Error; err != nil {
+			return "", fmt.Errorf("failed_to_check_object_exists: %w", err)
+		}
+
+		if exists == 0 {
+			return name, nil
+		}
+	}
+}
+
+// poolGatewayObject returns a Host object for the pool gateway, reusing an
+// existing single-address object when there is one.
+func (s *Service) poolGatewayObject(pool networkModels.IPPool, gateway netip.Addr) (uint, error) {
+	var hosts []networkModels.Object
+	if err := s.DB.Preload("Entries").Where("type = ?", "Host").Find(&hosts).Error; err != nil {
+		return 0, fmt.Errorf("failed_to_get_objects: %w", err)
+	}
+
+	for _, host := range hosts {
+		if len(host.Entries) != 1 {
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(host.Entries[0].Value); err == nil && addr.Unmap() == gateway {
+			return host.ID, nil
+		}
+	}
+
+	name, err := s.uniqueObjectName(pool.Name + "-gw")
+	if err != nil {
+		return 0, err
+	}
+
+	obj := networkModels.Object{
+		Name:    name,
+		Type:    "Host",
+		Comment: "Gateway of IP pool " + pool.Name,
+		Entries: []networkModels.ObjectEntry{{Value: gateway.String()}},
+	}
+
+	if err := s.DB.Create(&obj).Error; err != nil {
+		return 0, fmt.Errorf("failed_to_create_gateway_object: %w", err)
+	}
+
+	return obj.ID, nil
+}
+
+// AllocatePoolAddress takes the next free address from the switch's pool and
+// returns a Network object holding it (address/prefix, as jails expect) and
+// a Host object for the pool gateway.
+func (s *Service) AllocatePoolAddress(switchID uint, switchType string, family int, owner string) (uint, uint, error) {
+	s.ipamMutex.Lock()
+	defer s.ipamMutex.Unlock()
+
+	pool, plan, addr, err := s.nextPoolAddress(switchID, switchType, family)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	gatewayID, err := s.poolGatewayObject(pool, plan.gateway)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	name, err := s.uniqueObjectName(fmt.Sprintf("%s-ipv%d", owner, family))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	obj := networkModels.Object{
+		Name:    name,
+		Type:    "Network",
+		Comment: "Allocated from IP pool " + pool.Name,
+		Entries: []networkModels.ObjectEntry{{Value: netip.PrefixFrom(addr, plan.prefix.Bits()).String()}},
+	}
+
+	if err := s.DB.Create(&obj).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed_to_create_address_object: %w", err)
+	}
+
+	alloc := networkModels.IPPoolAllocation{
+		PoolID:   pool.ID,
+		Address:  addr.String(),
+		Owner:    owner,
+		ObjectID: &obj.ID,
+	}
+
+	if err := s.DB.Create(&alloc).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed_to_record_ip_pool_allocation: %w", err)
+	}
+
+	return obj.ID, gatewayID, nil
+}
+
+// vmPoolSwitch checks up front that a VM on this switch can get an address:
+// VMs configure their own interfaces, so the address is handed out through a
+// DHCP static mapping and the switch must be served by DHCP.
+func (s *Service) vmPoolSwitch(switchName string) (networkModels.StandardSwitch, error) {
+	var sw networkModels.StandardSwitch
+	if err := s.DB.Where("name = ?", switchName).First(&sw).Error; err != nil {
+		return sw, fmt.Errorf("automatic_ip_requires_standard_switch")
+	}
+
+	if _, err := s.poolForSwitch(sw.ID, "standard", 4); err != nil {
+		return sw, err
+	}
+
+	if _, _, err := s.dhcpSwitch(sw.ID); err != nil {
+		return sw, err
+	}
+
+	cfg, err := s.GetDHCPConfig()
+	if err != nil {
+		return sw, err
+	}
+
+	for _, served := range cfg.StandardSwitches {
+		if served.ID == sw.ID {
+			return sw, nil
+		}
+	}
+
+	return sw, fmt.Errorf("dhcp_not_enabled_on_switch")
+}
+
+// vmMACObject returns the MAC object a new VM on the switch will use,
+// creating one when the request does not name it. created tells the caller
+// to delete it again if the VM is never created.
+func (s *Service) vmMACObject(req libvirtServiceInterfaces.CreateVMRequest, sw networkModels.StandardSwitch) (obj networkModels.Object, created bool, err error) {
+	if req.MacId != nil && *req.MacId != 0 {
+		if err := s.DB.Preload("Entries").First(&obj, *req.MacId).Error; err != nil {
+			return obj, false, fmt.Errorf("mac_object_not_found")
+		}
+
+		if obj.Type != "Mac" || len(obj.Entries) == 0 {
+			return obj, false, fmt.Errorf("invalid_mac_object")
+		}
+
+		return obj, false, nil
+	}
+
+	name, err := s.uniqueObjectName(fmt.Sprintf("%s-%s", req.Name, sw.Name))
+	if err != nil {
+		return obj, false, err
+	}
+
+	obj = networkModels.Object{
+		Name:    name,
+		Type:    "Mac",
+		Entries: []networkModels.ObjectEntry{{Value: utils.GenerateRandomMAC()}},
+	}
+
+	if err := s.DB.Create(&obj).Error; err != nil {
+		return obj, false, fmt.Errorf("failed_to_create_mac_object: %w", err)
+	}
+
+	return obj, true, nil
+}
+
+// reserveVMAddress maps the next pool address to the MAC through a DHCP
+// static mapping and records the allocation.
+func (s *Service) reserveVMAddress(sw networkModels.StandardSwitch, vmName string, mac string) (networkModels.IPPoolAllocation, error) {
+	s.ipamMutex.Lock()
+	defer s.ipamMutex.Unlock()
+
+	var alloc networkModels.IPPoolAllocation
+
+	pool, _, addr, err := s.nextPoolAddress(sw.ID, "standard", 4)
+	if err != nil {
+		return alloc, err
+	}
+
+	mac = strings.ToLower(mac)
+	if err := s.CreateDHCPStaticMapping(sw.ID, dhcpHostname(vmName), mac, addr.String(), "Allocated from IP pool "+pool.Name, 0); err != nil {
+		return alloc, err
+	}
+
+	var mapping networkModels.DHCPStaticMapping
+	if err := s.DB.Where("standard_switch_id = ? AND mac = ?", sw.ID, mac).First(&mapping).Error; err != nil {
+		return alloc, fmt.Errorf("failed_to_find_dhcp_static_mapping: %w", err)
+	}
+
+	alloc = networkModels.IPPoolAllocation{
+		PoolID:              pool.ID,
+		Address:             addr.String(),
+		Owner:               vmName,
+		DHCPStaticMappingID: &mapping.ID,
+	}
+
+	if err := s.DB.Create(&alloc).Error; err != nil {
+		if err := s.DeleteDHCPStaticMapping(mapping.ID); err != nil {
+			logger.L.Warn().Err(err).Uint("mapping", mapping.ID).Msg("failed to remove dhcp static mapping")
+		}
+
+		return alloc, fmt.Errorf("failed_to_record_ip_pool_allocation: %w", err)
+	}
+
+	return alloc, nil
+}
+
+// releaseVMAddress undoes reserveVMAddress for a VM that was never created.
+func (s *Service) releaseVMAddress(alloc networkModels.IPPoolAllocation) {
+	s.ipamMutex.Lock()
+	defer s.ipamMutex.Unlock()
+
+	if alloc.DHCPStaticMappingID != nil {
+		if err := s.DeleteDHCPStaticMapping(*alloc.DHCPStaticMappingID); err != nil {
+			logger.L.Warn().Err(err).Uint("mapping", *alloc.DHCPStaticMappingID).Msg("failed to remove dhcp static mapping")
+		}
+	}
+
+	if err := s.DB.Delete(&alloc).Error; err != nil {
+		logger.L.Warn().Err(err).Str("address", alloc.Address).Msg("failed to delete ip pool allocation")
+	}
+}
+
+// CreateVMWithPoolAddress creates a VM that gets its address from the IP
+// pool of its switch. The address is reserved for the VM's MAC before the
+// VM is created and released again when creating the VM fails.
+func (s *Service) CreateVMWithPoolAddress(req libvirtServiceInterfaces.CreateVMRequest) error {
+	sw, err := s.vmPoolSwitch(req.SwitchName)
+	if err != nil {
+		return fmt.Errorf("automatic_ip_unavailable: %w", err)
+	}
+
+	macObj, createdMAC, err := s.vmMACObject(req, sw)
+	if err != nil {
+		return err
+	}
+
+	cleanupMAC := func() {
+		if !createdMAC {
+			return
+		}
+
+		if err := s.DeleteObject(macObj.ID); err != nil {
+			logger.L.Warn().Err(err).Uint("object", macObj.ID).Msg("failed to remove mac object")
+		}
+	}
+
+	alloc, err := s.reserveVMAddress(sw, req.Name, macObj.Entries[0].Value)
+	if err != nil {
+		cleanupMAC()
+		return fmt.Errorf("failed_to_allocate_ip: %w", err)
+	}
+
+	req.MacId = &macObj.ID
+	if err := s.LibVirt.CreateVM(req); err != nil {
+		s.releaseVMAddress(alloc)
+		cleanupMAC()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"testing"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+)
+
+func testPlan(t *testing.T, subnet, gateway string, exclusions ...string) ipPlan {
+	t.Helper()
+
+	plan, err := parseIPPool(networkModels.IPPool{Subnet: subnet, Gateway: gateway, Exclusions: exclusions})
+	if err != nil {
+		t.Fatalf("parseIPPool(%q, %q, %v) failed: %v", subnet, gateway, exclusions, err)
+	}
+
+	return plan
+}
+
+func claimsOf(claims map[string][]ipClaim) map[netip.Addr][]ipClaim {
+	out := make(map[netip.Addr][]ipClaim, len(claims))
+	for addr, list := range claims {
+		out[netip.MustParseAddr(addr)] = list
+	}
+
+	return out
+}
+
+func TestParseIPPool(t *testing.T) {
+	tests := []struct {
+		subnet     string
+		gateway    string
+		exclusions []string
+		first      string
+		last       string
+		reserved   []string
+		err        string
+	}{
+		{"192.168.1.0/24", "192.168.1.1", nil, "192.168.1.1", "192.168.1.254", []string{"192.168.1.1-192.168.1.1"}, ""},
+		{"192.168.1.77/24", "192.168.1.1", nil, "192.168.1.1", "192.168.1.254", []string{"192.168.1.1-192.168.1.1"}, ""},
+		{"10.0.0.0/24", "10.0.0.1", []string{"10.0.0.2-10.0.0.10", "10.0.0.5-10.0.0.20", "10.0.0.100"}, "10.0.0.1", "10.0.0.254", []string{"10.0.0.1-10.0.0.20", "10.0.0.100-10.0.0.100"}, ""},
+		{"10.0.0.0/31", "10.0.0.0", nil, "10.0.0.0", "10.0.0.1", []string{"10.0.0.0-10.0.0.0"}, ""},
+		{"fd00::/64", "fd00::1", []string{"fd00::10-fd00::1f"}, "fd00::1", "fd00::ffff:ffff:ffff:ffff", []string{"fd00::1-fd00::1", "fd00::10-fd00::1f"}, ""},
+		{"fd00::/127", "fd00::", nil, "fd00::", "fd00::1", []string{"fd00::-fd00::"}, ""},
+		{"not-a-subnet", "10.0.0.1", nil, "", "", nil, "invalid_subnet: not-a-subnet"},
+		{"10.0.0.0/24", "", nil, "", "", nil, "invalid_gateway: "},
+		{"10.0.0.0/24", "10.0.1.1", nil, "", "", nil, "gateway_not_in_subnet: 10.0.1.1"},
+		{"10.0.0.0/24", "10.0.0.0", nil, "", "", nil, "gateway_not_in_subnet: 10.0.0.0"},
+		{"10.0.0.0/24", "10.0.0.255", nil, "", "", nil, "gateway_not_in_subnet: 10.0.0.255"},
+		{"10.0.0.0/24", "10.0.0.1", []string{"10.0.0.250-10.0.1.5"}, "", "", nil, "exclusion_not_in_subnet: 10.0.0.250-10.0.1.5"},
+		{"10.0.0.0/24", "10.0.0.1", []string{"10.0.0.9-10.0.0.2"}, "", "", nil, "invalid_exclusion: 10.0.0.9-10.0.0.2: end_before_start"},
+	}
+
+	for _, tt := range tests {
+		plan, err := parseIPPool(networkModels.IPPool{Subnet: tt.subnet, Gateway: tt.gateway, Exclusions: tt.exclusions})
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("parseIPPool(%q, %q, %v) error = %v, want %q", tt.subnet, tt.gateway, tt.exclusions, err, tt.err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseIPPool(%q, %q, %v) unexpected error: %v", tt.subnet, tt.gateway, tt.exclusions, err)
+			continue
+		}
+
+		if plan.first.String() != tt.first || plan.last.String() != tt.last {
+			t.Errorf("parseIPPool(%q) hosts = %s-%s, want %s-%s", tt.subnet, plan.first, plan.last, tt.first, tt.last)
+		}
+
+		var reserved []string
+		for _, r := range plan.reserved {
+			reserved = append(reserved, r.start.String()+"-"+r.end.String())
+		}
+
+		if !slices.Equal(reserved, tt.reserved) {
+			t.Errorf("parseIPPool(%q, %v) reserved = %v, want %v", tt.subnet, tt.exclusions, reserved, tt.reserved)
+		}
+	}
+}
+
+func TestMergeRanges(t *testing.T) {
+	r := func(start, end string) addrRange {
+		return addrRange{start: netip.MustParseAddr(start), end: netip.MustParseAddr(end)}
+	}
+
+	tests := []struct {
+		input    []addrRange
+		expected []addrRange
+	}{
+		{nil, nil},
+		{[]addrRange{r("10.0.0.5", "10.0.0.9")}, []addrRange{r("10.0.0.5", "10.0.0.9")}},
+		{[]addrRange{r("10.0.0.20", "10.0.0.30"), r("10.0.0.1", "10.0.0.5")}, []addrRange{r("10.0.0.1", "10.0.0.5"), r("10.0.0.20", "10.0.0.30")}},
+		{[]addrRange{r("10.0.0.1", "10.0.0.5"), r("10.0.0.6", "10.0.0.9")}, []addrRange{r("10.0.0.1", "10.0.0.9")}},
+		{[]addrRange{r("10.0.0.1", "10.0.0.50"), r("10.0.0.10", "10.0.0.20")}, []addrRange{r("10.0.0.1", "10.0.0.50")}},
+		{[]addrRange{r("10.0.0.10", "10.0.0.20"), r("10.0.0.1", "10.0.0.12"), r("10.0.0.22", "10.0.0.22")}, []addrRange{r("10.0.0.1", "10.0.0.20"), r("10.0.0.22", "10.0.0.22")}},
+		{[]addrRange{r("255.255.255.250", "255.255.255.255"), r("255.255.255.255", "255.255.255.255")}, []addrRange{r("255.255.255.250", "255.255.255.255")}},
+	}
+
+	for _, tt := range tests {
+		if got := mergeRanges(slices.Clone(tt.input)); !slices.Equal(got, tt.expected) {
+			t.Errorf("mergeRanges(%v) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestNextFreeAddress(t *testing.T) {
+	tests := []struct {
+		plan     ipPlan
+		claims   map[string][]ipClaim
+		expected string
+	}{
+		{testPlan(t, "192.168.1.0/24", "192.168.1.1"), nil, "192.168.1.2"},
+		{testPlan(t, "192.168.1.0/24", "192.168.1.254"), nil, "192.168.1.1"},
+		{testPlan(t, "192.168.1.0/24", "192.168.1.1", "192.168.1.2-192.168.1.99"), nil, "192.168.1.100"},
+		{testPlan(t, "192.168.1.0/24", "192.168.1.1", "192.168.1.2-192.168.1.9"), map[string][]ipClaim{
+			"192.168.1.10": {{source: "object:web"}},
+			"192.168.1.11": {{source: "dhcp:db", assigned: true}},
+		}, "192.168.1.12"},
+		{testPlan(t, "10.0.0.0/30", "10.0.0.1"), map[string][]ipClaim{
+			"10.0.0.2": {{source: "object:a", assigned: true}},
+		}, ""},
+		{testPlan(t, "10.0.0.0/29", "10.0.0.1", "10.0.0.2-10.0.0.6"), nil, ""},
+		{testPlan(t, "fd00::/64", "fd00::1", "fd00::2-fd00::ff"), nil, "fd00::100"},
+	}
+
+	for _, tt := range tests {
+		got, err := nextFreeAddress(tt.plan, claimsOf(tt.claims))
+		if tt.expected == "" {
+			if err == nil || err.Error() != "ip_pool_exhausted" {
+				t.Errorf("nextFreeAddress(%s) = %v, %v, want ip_pool_exhausted", tt.plan.prefix, got, err)
+			}
+			continue
+		}
+
+		if err != nil || got.String() != tt.expected {
+			t.Errorf("nextFreeAddress(%s) = %v, %v, want %s", tt.plan.prefix, got, err, tt.expected)
+		}
+	}
+}
+
+func TestIPConflicts(t *testing.T) {
+	plan := testPlan(t, "10.0.0.0/24", "10.0.0.1", "10.0.0.200-10.0.0.210")
+
+	tests := []struct {
+		name     string
+		claims   map[string][]ipClaim
+		expected []string
+	}{
+		{"none", nil, nil},
+		{"host object only", map[string][]ipClaim{
+			"10.0.0.1":   {{source: "object:gw"}},
+			"10.0.0.205": {{source: "object:printer"}},
+		}, nil},
+		{"single assignment", map[string][]ipClaim{
+			"10.0.0.50": {{source: "object:jail", assigned: true}, {source: "object:host"}},
+		}, nil},
+		{"same source twice", map[string][]ipClaim{
+			"10.0.0.50": {{source: "object:jail", assigned: true}, {source: "object:jail", assigned: true}},
+		}, nil},
+		{"network address", map[string][]ipClaim{
+			"10.0.0.0": {{source: "object:jail", assigned: true}},
+		}, []string{"10.0.0.0 reserved_address_assigned [object:jail]"}},
+		{"broadcast address", map[string][]ipClaim{
+			"10.0.0.255": {{source: "dhcp:vm", assigned: true}},
+		}, []string{"10.0.0.255 reserved_address_assigned [dhcp:vm]"}},
+		{"gateway", map[string][]ipClaim{
+			"10.0.0.1": {{source: "object:jail", assigned: true}, {source: "object:gw"}},
+		}, []string{"10.0.0.1 gateway_assigned [object:gw object:jail]"}},
+		{"duplicate", map[string][]ipClaim{
+			"10.0.0.60": {{source: "object:b", assigned: true}, {source: "dhcp:a", assigned: true}},
+			"10.0.0.9":  {{source: "object:c", assigned: true}, {source: "object:d", assigned: true}},
+		}, []string{"10.0.0.9 assigned_more_than_once [object:c object:d]", "10.0.0.60 assigned_more_than_once [dhcp:a object:b]"}},
+		{"excluded", map[string][]ipClaim{
+			"10.0.0.201": {{source: "object:jail", assigned: true}},
+		}, []string{"10.0.0.201 excluded_address_assigned [object:jail]"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, c := range ipConflicts(plan, claimsOf(tt.claims)) {
+			got = append(got, c.Address+" "+c.Reason+" "+fmt.Sprint(c.Sources))
+		}
+
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("ipConflicts(%s) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestIPPoolStatus(t *testing.T) {
+	tests := []struct {
+		plan     ipPlan
+		claims   map[string][]ipClaim
+		total    uint64
+		reserved uint64
+		used     uint64
+		free     uint64
+	}{
+		{testPlan(t, "192.168.1.0/24", "192.168.1.1"), nil, 254, 1, 0, 253},
+		{testPlan(t, "192.168.1.0/24", "192.168.1.1", "192.168.1.0-192.168.1.10"), map[string][]ipClaim{
+			"192.168.1.5":   {{source: "object:excluded"}},
+			"192.168.1.20":  {{source: "object:a", assigned: true}},
+			"192.168.1.21":  {{source: "dhcp:b", assigned: true}},
+			"192.168.1.255": {{source: "object:broadcast", assigned: true}},
+		}, 254, 10, 2, 242},
+		{testPlan(t, "10.0.0.0/30", "10.0.0.1"), map[string][]ipClaim{
+			"10.0.0.2": {{source: "object:a", assigned: true}},
+		}, 2, 1, 1, 0},
+		{testPlan(t, "10.0.0.0/31", "10.0.0.0"), nil, 2, 1, 0, 1},
+	}
+
+	for _, tt := range tests {
+		status := ipPoolStatus(networkModels.IPPool{}, tt.plan, claimsOf(tt.claims))
+		if status.Total != tt.total || status.Reserved != tt.reserved || status.Used != tt.used || status.Free != tt.free {
+			t.Errorf("ipPoolStatus(%s) = total %d reserved %d used %d free %d, want %d %d %d %d",
+				tt.plan.prefix, status.Total, status.Reserved, status.Used, status.Free,
+				tt.total, tt.reserved, tt.used, tt.free)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/network/iface"
+	"github.com/alchemillahq/sylve/pkg/utils"
+)
+
+var laggProtocols = []string{"lacp", "failover", "loadbalance", "roundrobin"}
+
+// defaultLaggMTU is what Ethernet ports come up with. An MTU of 0 on a lagg
+// means this default, and member ports follow whatever the lagg is set to.
+const defaultLaggMTU = 1500
+
+func (s *Service) GetLaggs() ([]networkServiceInterfaces.LaggStatus, error) {
+	var laggs []networkModels.Lagg
+	if err := s.DB.Order("id ASC").Find(&laggs).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_laggs: %w", err)
+	}
+
+	statuses := make([]networkServiceInterfaces.LaggStatus, 0, len(laggs))
+	for _, lagg := range laggs {
+		status := networkServiceInterfaces.LaggStatus{Lagg: lagg}
+		if live, err := iface.Get(lagg.LaggName); err == nil {
+			status.Live = live.Lagg
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// laggMemberOf returns the Sylve managed lagg a NIC belongs to, if any.
+func (s *Service) laggMemberOf(port string, excludeID uint) (*networkModels.Lagg, error) {
+	var laggs []networkModels.Lagg
+	if err := s.DB.Where("id != ?", excludeID).Find(&laggs).Error; err != nil {
+		return nil, fmt.Errorf("db_error_checking_laggs: %w", err)
+	}
+
+	for _, lagg := range laggs {
+		if slices.Contains(lagg.Members, port) {
+			return &lagg, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// rejectLaggMembers stops a NIC that is aggregated into a lagg from also
+// being used directly as a switch port.
+func (s *Service) rejectLaggMembers(ports []string) error {
+	for _, port := range ports {
+		lagg, err := s.laggMemberOf(port, 0)
+		if err != nil {
+			return err
+		}
+
+		if lagg != nil {
+			return fmt.Errorf("port_is_lagg_member: %s (lagg %q)", port, lagg.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) validateLagg(lagg networkModels.Lagg, id uint) error {
+	if lagg.Name == "" {
+		return fmt.Errorf("lagg_name_required")
+	}
+
+	if !slices.Contains(laggProtocols, lagg.Protocol) {
+		return fmt.Errorf("invalid_lagg_protocol: %s", lagg.Protocol)
+	}
+
+	if lagg.MTU != 0 && !utils.IsValidMTU(lagg.MTU) {
+		return fmt.Errorf("invalid_mtu")
+	}
+
+	if len(lagg.Members) == 0 {
+		return fmt.Errorf("lagg_requires_members")
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.Lagg{}).
+		Where("name = ? AND id != ?", lagg.Name, id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return fmt.Errorf("lagg_name_already_exists")
+	}
+
+	seen := make(map[string]bool)
+	for _, member := range lagg.Members {
+		if seen[member] {
+			return fmt.Errorf("duplicate_lagg_member: %s", member)
+		}
+		seen[member] = true
+
+		nic, err := iface.Get(member)
+		if err != nil {
+			return fmt.Errorf("lagg_member_not_found: %s", member)
+		}
+
+		if nic.Model == "" || nic.Lagg != nil || len(nic.BridgeMembers) > 0 {
+			return fmt.Errorf("lagg_member_not_physical: %s", member)
+		}
+
+		var ports []networkModels.NetworkPort
+		if err := s.DB.Preload("Switch").Where("name = ?", member).Find(&ports).Error; err != nil {
+			return fmt.Errorf("db_error_checking_ports: %w", err)
+		}
+
+		if len(ports) > 0 {
+			return fmt.Errorf("lagg_member_used_by_switch: %s (switch %q)", member, ports[0].Switch.Name)
+		}
+
+		other, err := s.laggMemberOf(member, id)
+		if err != nil {
+			return err
+		}
+
+		if other != nil {
+			return fmt.Errorf("lagg_member_used_by_lagg: %s (lagg %q)", member, other.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) isLaggInUse(lagg networkModels.Lagg) error {
+	var ports []networkModels.NetworkPort
+	if err := s.DB.Preload("Switch").Where("name = ?", lagg.LaggName).Find(&ports).Error; err != nil {
+		return fmt.Errorf("db_error_checking_ports: %w", err)
+	}
+
+	if len(ports) > 0 {
+		return fmt.Errorf("lagg_in_use_by_switch: %s", ports[0].Switch.Name)
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.StaticRoute{}).Where("interface = ?", lagg.LaggName).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return fmt.Errorf("lagg_in_use_by_route")
+	}
+
+	return nil
+}
+
+func (s *Service) CreateLagg(lagg networkModels.Lagg) error {
+	if err := s.validateLagg(lagg, 0); err != nil {
+		return err
+	}
+
+	lagg.ID = 0
+	lagg.LaggName = utils.ShortHash("lagg-" + lagg.Name)
+
+	if err := s.DB.Create(&lagg).Error; err != nil {
+		return fmt.Errorf("failed_to_create_lagg: %w", err)
+	}
+
+	if err := syncLagg(lagg); err != nil {
+		s.DB.Delete(&lagg)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) EditLagg(id uint, lagg networkModels.Lagg) error {
+	var existing networkModels.Lagg
+	if err := s.DB.First(&existing, id).Error; err != nil {
+		return fmt.Errorf("lagg_not_found")
+	}
+
+	if err := s.validateLagg(lagg, id); err != nil {
+		return err
+	}
+
+	resetMTU := existing.MTU != 0 && lagg.MTU == 0
+
+	existing.Name = lagg.Name
+	existing.Protocol = lagg.Protocol
+	existing.Members = lagg.Members
+	existing.MTU = lagg.MTU
+
+	if err := s.DB.Save(&existing).Error; err != nil {
+		return fmt.Errorf("failed_to_update_lagg: %w", err)
+	}
+
+	if err := syncLagg(existing); err != nil {
+		return err
+	}
+
+	/* syncLagg leaves the MTU alone when none is set, so going back to the default has to be done here */
+	if resetMTU {
+		if _, err := utils.RunCommand("ifconfig", existing.LaggName, "mtu", strconv.Itoa(defaultLaggMTU)); err != nil {
+			return fmt.Errorf("failed_to_reset_lagg_mtu: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteLagg(id uint) error {
+	var existing networkModels.Lagg
+	if err := s.DB.First(&existing, id).Error; err != nil {
+		return fmt.Errorf("lagg_not_found")
+	}
+
+	if err := s.isLaggInUse(existing); err != nil {
+		return err
+	}
+
+	if _, err := iface.Get(existing.LaggName); err == nil {
+		if _, err := utils.RunCommand("ifconfig", existing.LaggName, "destroy"); err != nil {
+			return fmt.Errorf("failed_to_destroy_lagg: %w", err)
+		}
+	}
+
+	if err := s.DB.Delete(&existing).Error; err != nil {
+		return fmt.Errorf("failed_to_delete_lagg: %w", err)
+	}
+
+	return nil
+}
+
+// syncLagg creates the lagg interface if needed and brings its protocol,
+// member ports and MTU in line with the database.
+func syncLagg(lagg networkModels.Lagg) error {
+	current, err := iface.Get(lagg.LaggName)
+	if err != nil {
+		raw, err := utils.RunCommand("ifconfig", "lagg", "create")
+		if err != nil {
+			return fmt.Errorf("failed_to_create_lagg_interface: %w", err)
+		}
+
+		raw = strings.TrimSpace(raw)
+		if _, err := utils.RunCommand("ifconfig", raw, "name", lagg.LaggName); err != nil {
+			utils.RunCommand("ifconfig", raw, "destroy")
+			return fmt.Errorf("failed_to_rename_lagg_interface: %w", err)
+		}
+	}
+
+	if _, err := utils.RunCommand("ifconfig", lagg.LaggName, "descr", lagg.Name); err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to set description on lagg %s", lagg.LaggName)
+	}
+
+	if current == nil || current.Lagg == nil || current.Lagg.Protocol != lagg.Protocol {
+		if _, err := utils.RunCommand("ifconfig", lagg.LaggName, "laggproto", lagg.Protocol); err != nil {
+			return fmt.Errorf("failed_to_set_lagg_protocol: %w", err)
+		}
+	}
+
+	var existing []string
+	if current != nil && current.Lagg != nil {
+		for _, port := range current.Lagg.Ports {
+			existing = append(existing, port.Name)
+		}
+	}
+
+	for _, port := range existing {
+		if !slices.Contains(lagg.Members, port) {
+			if _, err := utils.RunCommand("ifconfig", lagg.LaggName, "-laggport", port); err != nil {
+				return fmt.Errorf("failed_to_remove_lagg_port %s: %w", port, err)
+			}
+		}
+	}
+
+	for _, member := range lagg.Members {
+		if slices.Contains(existing, member) {
+			continue
+		}
+
+		if _, err := utils.RunCommand("ifconfig", member, "up"); err != nil {
+			return fmt.Errorf("failed_to_bring_up_lagg_port %s: %w", member, err)
+		}
+
+		if _, err := utils.RunCommand("ifconfig", lagg.LaggName, "laggport", member); err != nil {
+			return fmt.Errorf("failed_to_add_lagg_port %s: %w", member, err)
+		}
+	}
+
+	if lagg.MTU != 0 {
+		if _, err := utils.RunCommand("ifconfig", lagg.LaggName, "mtu", strconv.Itoa(lagg.MTU)); err != nil {
+			return fmt.Errorf("failed_to_set_lagg_mtu: %w", err)
+		}
+	}
+
+	if _, err := utils.RunCommand("ifconfig", lagg.LaggName, "up"); err != nil {
+		return fmt.Errorf("failed_to_bring_up_lagg: %w", err)
+	}
+
+	return nil
+}
+
+// SyncLaggs recreates every lagg from the database. It runs before switches
+// are synced so laggs can be used as switch ports and VLAN parents.
+func (s *Service) SyncLaggs() error {
+	var laggs []networkModels.Lagg
+	if err := s.DB.Find(&laggs).Error; err != nil {
+		return fmt.Errorf("failed_to_get_laggs: %w", err)
+	}
+
+	for _, lagg := range laggs {
+		if err := syncLagg(lagg); err != nil {
+			logger.L.Error().Err(err).Msgf("failed to sync lagg %s", lagg.Name)
+		}
+	}
+
+	return nil
+}
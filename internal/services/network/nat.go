@@ -0,0 +1,364 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/alchemillahq/sylve/internal/config"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+)
+
+const (
+	natAnchor = "sylve/nat"
+	natFile   = "nat.conf"
+)
+
+func wanInterfaces() []string {
+	if config.ParsedConfig == nil {
+		return nil
+	}
+
+	return config.ParsedConfig.WANInterfaces
+}
+
+func (s *Service) singleEntryObject(id uint, oType string) (string, error) {
+	var object networkModels.Object
+	if err := s.DB.Preload("Entries").First(&object, id).Error; err != nil {
+		return "", fmt.Errorf("object_not_found: %d", id)
+	}
+
+	if object.Type != oType {
+		return "", fmt.Errorf("object_%s_must_be_type_%s", object.Name, strings.ToLower(oType))
+	}
+
+	if len(object.Entries) != 1 {
+		return "", fmt.Errorf("object_%s_must_have_exactly_one_entry", object.Name)
+	}
+
+	return object.Entries[0].Value, nil
+}
+
+func (s *Service) SetSwitchNAT(id uint, enabled bool) error {
+	var sw networkModels.StandardSwitch
+	if err := s.DB.Preload("NetworkObj.Entries").First(&sw, id).Error; err != nil {
+		return fmt.Errorf("switch_not_found")
+	}
+
+	if enabled {
+		if !sw.Private {
+			return fmt.Errorf("nat_requires_private_switch")
+		}
+
+		if sw.Network(4) == "" {
+			return fmt.Errorf("nat_requires_ipv4_network")
+		}
+
+		if len(wanInterfaces()) == 0 {
+			return fmt.Errorf("no_wan_interfaces_configured")
+		}
+	}
+
+	if err := s.DB.Model(&sw).Update("nat", enabled).Error; err != nil {
+		return fmt.Errorf("failed_to_update_switch_nat: %w", err)
+	}
+
+	return s.SyncNAT()
+}
+
+func (s *Service) GetPortForwards() ([]networkModels.PortForward, error) {
+	var forwards []networkModels.PortForward
+
+	err := s.DB.
+		Preload("Switch").
+		Preload("HostPortObj.Entries").
+		Preload("DestinationObj.Entries").
+		Preload("DestinationPortObj.Entries").
+		Order("id ASC").
+		Find(&forwards).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_get_port_forwards: %w", err)
+	}
+
+	return forwards, nil
+}
+
+// New VMs get their VNC port picked from this range, so forwarding a host
+// port out of it would break the next VM that lands on it.
+const (
+	vncPortRangeStart = 5900
+	vncPortRangeEnd   = 5999
+)
+
+func protocolsOverlap(a, b string) bool {
+	return a == b || a == "tcp/udp" || b == "tcp/udp"
+}
+
+func (s *Service) validatePortForward(forward networkModels.PortForward, excludeID uint) error {
+	if strings.TrimSpace(forward.Name) == "" {
+		return fmt.Errorf("name_required")
+	}
+
+	switch forward.Protocol {
+	case "tcp", "udp", "tcp/udp":
+	default:
+		return fmt.Errorf("invalid_port_forward_protocol: %s", forward.Protocol)
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.PortForward{}).Where("name = ? AND id != ?", forward.Name, excludeID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_port_forward_name: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("port_forward_with_name_already_exists: %s", forward.Name)
+	}
+
+	var sw networkModels.StandardSwitch
+	if err := s.DB.Preload("NetworkObj.Entries").First(&sw, forward.SwitchID).Error; err != nil {
+		return fmt.Errorf("switch_not_found")
+	}
+
+	if !sw.Private {
+		return fmt.Errorf("port_forward_requires_private_switch")
+	}
+
+	destination, err := s.singleEntryObject(forward.DestinationID, "Host")
+	if err != nil {
+		return err
+	}
+
+	if !utils.IsValidIPv4(destination) {
+		return fmt.Errorf("port_forward_destination_must_be_ipv4")
+	}
+
+	_, subnet, err := net.ParseCIDR(sw.Network(4))
+	if err != nil || !subnet.Contains(net.ParseIP(destination)) {
+		return fmt.Errorf("destination_not_in_switch_network")
+	}
+
+	if _, err := s.singleEntryObject(forward.DestinationPortID, "Port"); err != nil {
+		return err
+	}
+
+	hostPortValue, err := s.singleEntryObject(forward.HostPortID, "Port")
+	if err != nil {
+		return err
+	}
+
+	hostPort, _ := strconv.Atoi(hostPortValue)
+
+	forwards, err := s.GetPortForwards()
+	if err != nil {
+		return err
+	}
+
+	for _, other := range forwards {
+		if other.ID == excludeID || other.HostPortObj == nil || len(other.HostPortObj.Entries) == 0 {
+			continue
+		}
+
+		if other.HostPortObj.Entries[0].Value == hostPortValue && protocolsOverlap(other.Protocol, forward.Protocol) {
+			return fmt.Errorf("host_port_already_forwarded: %s", other.Name)
+		}
+	}
+
+	if err := s.DB.Model(&vmModels.VM{}).Where("vnc_port = ?", hostPort).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_vnc_ports: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("host_port_conflicts_with_vnc")
+	}
+
+	if config.ParsedConfig != nil && hostPort == config.ParsedConfig.Port {
+		return fmt.Errorf("host_port_conflicts_with_sylve")
+	}
+
+	if hostPort >= vncPortRangeStart && hostPort <= vncPortRangeEnd {
+		return fmt.Errorf("host_port_in_vnc_port_range")
+	}
+
+	if utils.IsPortBound(hostPort) {
+		return fmt.Errorf("host_port_in_use")
+	}
+
+	return nil
+}
+
+func (s *Service) CreatePortForward(forward networkModels.PortForward) error {
+	if err := s.validatePortForward(forward, 0); err != nil {
+		return err
+	}
+
+	enabled := forward.Enabled
+	forward.ID = 0
+
+	if err := s.DB.Create(&forward).Error; err != nil {
+		return fmt.Errorf("failed_to_create_port_forward: %w", err)
+	}
+
+	if !enabled {
+		if err := s.DB.Model(&forward).Update("enabled", false).Error; err != nil {
+			return fmt.Errorf("failed_to_create_port_forward: %w", err)
+		}
+	}
+
+	return s.SyncNAT()
+}
+
+func (s *Service) EditPortForward(id uint, forward networkModels.PortForward) error {
+	var existing networkModels.PortForward
+	if err := s.DB.First(&existing, id).Error; err != nil {
+		return fmt.Errorf("port_forward_not_found")
+	}
+
+	if err := s.validatePortForward(forward, id); err != nil {
+		return err
+	}
+
+	err := s.DB.Model(&existing).
+		Select("Name", "Enabled", "Protocol", "Comment", "SwitchID", "HostPortID", "DestinationID", "DestinationPortID").
+		Updates(networkModels.PortForward{
+			Name:              forward.Name,
+			Enabled:           forward.Enabled,
+			Protocol:          forward.Protocol,
+			Comment:           forward.Comment,
+			SwitchID:          forward.SwitchID,
+			HostPortID:        forward.HostPortID,
+			DestinationID:     forward.DestinationID,
+			DestinationPortID: forward.DestinationPortID,
+		}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed_to_update_port_forward: %w", err)
+	}
+
+	return s.SyncNAT()
+}
+
+func (s *Service) DeletePortForward(id uint) error {
+	res := s.DB.Delete(&networkModels.PortForward{}, id)
+	if res.Error != nil {
+		return fmt.Errorf("failed_to_delete_port_forward: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("port_forward_not_found")
+	}
+
+	return s.SyncNAT()
+}
+
+func renderNATProtocol(protocol string) string {
+	if protocol == "tcp/udp" {
+		return "{ tcp udp }"
+	}
+
+	return protocol
+}
+
+func (s *Service) renderNAT() (string, error) {
+	var b strings.Builder
+	b.WriteString("# Managed by Sylve, changes will be overwritten\n")
+
+	wans := wanInterfaces()
+	if len(wans) == 0 {
+		return b.String(), nil
+	}
+
+	var switches []networkModels.StandardSwitch
+	if err := s.DB.Preload("NetworkObj.Entries").Where("nat = ? AND private = ?", true, true).Order("id ASC").Find(&switches).Error; err != nil {
+		return "", fmt.Errorf("failed_to_get_nat_switches: %w", err)
+	}
+
+	for _, sw := range switches {
+		network := sw.Network(4)
+		if network == "" {
+			continue
+		}
+
+		for _, wan := range wans {
+			b.WriteString(fmt.Sprintf("nat on %s inet from %s to any -> (%s)\n", wan, network, wan))
+		}
+	}
+
+	forwards, err := s.GetPortForwards()
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range forwards {
+		if !f.Enabled || f.Switch == nil || !f.Switch.Private {
+			continue
+		}
+
+		if f.HostPortObj == nil || f.DestinationObj == nil || f.DestinationPortObj == nil ||
+			len(f.HostPortObj.Entries) != 1 || len(f.DestinationObj.Entries) != 1 || len(f.DestinationPortObj.Entries) != 1 {
+			logger.L.Warn().Msgf("skipping port forward %s with incomplete objects", f.Name)
+			continue
+		}
+
+		for _, wan := range wans {
+			b.WriteString(fmt.Sprintf("rdr pass on %s inet proto %s from any to (%s) port %s -> %s port %s\n",
+				wan,
+				renderNATProtocol(f.Protocol),
+				wan,
+				f.HostPortObj.Entries[0].Value,
+				f.DestinationObj.Entries[0].Value,
+				f.DestinationPortObj.Entries[0].Value,
+			))
+		}
+	}
+
+	return b.String(), nil
+}
+
+func (s *Service) SyncNAT() error {
+	s.natMutex.Lock()
+	defer s.natMutex.Unlock()
+
+	if ready, err := s.preparePf(); err != nil || !ready {
+		return err
+	}
+
+	content, err := s.renderNAT()
+	if err != nil {
+		return err
+	}
+
+	base, err := config.GetFirewallPath()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(base, natFile)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed_to_write_nat_rules: %w", err)
+	}
+
+	if _, err := utils.RunCommand("pfctl", "-n", "-a", natAnchor, "-f", path); err != nil {
+		return fmt.Errorf("nat_validation_failed: %w", err)
+	}
+
+	if _, err := utils.RunCommand("pfctl", "-a", natAnchor, "-f", path); err != nil {
+		return fmt.Errorf("failed_to_load_nat_rules: %w", err)
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ package network
 
 import (
 	"sync"
+	"time"
 
 	libvirtServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/libvirt"
 	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
@@ -23,6 +24,20 @@ type Service struct {
 	DB        *gorm.DB
 	syncMutex sync.Mutex
 
+	fwMutex      sync.Mutex
+	fwRollback   *time.Timer
+	fwRollbackAt time.Time
+
+	pfMutex sync.Mutex
+	pfReady bool
+
+	natMutex   sync.Mutex
+	dhcpMutex  sync.Mutex
+	fqdnMutex  sync.Mutex
+	geoipMutex sync.Mutex
+	routeMutex sync.Mutex
+	ipamMutex  sync.Mutex
+
 	LibVirt libvirtServiceInterfaces.LibvirtServiceInterface
 }
 
@@ -10,13 +10,19 @@ package network
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 
 	"github.com/alchemillahq/sylve/internal/db/models"
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
 	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
 	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	nfsModels "github.com/alchemillahq/sylve/internal/db/models/nfs"
 	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
+	"github.com/alchemillahq/sylve/internal/logger"
 	utils "github.com/alchemillahq/sylve/pkg/utils"
+
+	"gorm.io/gorm"
 )
 
 func (s *Service) GetObjects() ([]networkModels.Object, error) {
@@ -37,12 +43,55 @@ func (s *Service) GetObjects() ([]networkModels.Object, error) {
 			return nil, err
 		}
 
+		if !used {
+			users, err := s.objectUsers(objects[i].ID)
+			if err != nil {
+				return nil, err
+			}
+
+			used = len(users) > 0
+		}
+
 		objects[i].IsUsed = used
 	}
 
 	return objects, nil
 }
 
+func (s *Service) GetObject(id uint) (networkModels.Object, error) {
+	var object networkModels.Object
+
+	err := s.DB.
+		Preload("Entries").
+		Preload("Resolutions").
+		Preload("ResolutionLogs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id DESC").Limit(maxResolutionLogs)
+		}).
+		First(&object, id).Error
+
+	if err != nil {
+		return object, fmt.Errorf("failed to find object with ID %d: %w", id, err)
+	}
+
+	used, err := s.IsObjectUsed(id)
+	if err != nil {
+		return object, err
+	}
+
+	if !used {
+		users, err := s.objectUsers(id)
+		if err != nil {
+			return object, err
+		}
+
+		used = len(users) > 0
+	}
+
+	object.IsUsed = used
+
+	return object, nil
+}
+
 func validateType(oType string) error {
 	validTypes := map[string]bool{
 		"Host":    true,
@@ -61,7 +110,16 @@ func validateType(oType string) error {
 	return nil
 }
 
-func validateValues(oType string, values []string) error {
+// resolvedObjectType reports whether an object's addresses come from its
+// resolutions (DNS or GeoIP) rather than straight from its entries.
+func resolvedObjectType(oType string) bool {
+	return oType == "FQDN" || oType == "Country"
+}
+
+// validateValues checks values for oType. Values in stored are already saved
+// on the object and are accepted as they are, so objects created before a
+// check was tightened can still be edited.
+func validateValues(oType string, values []string, stored []string) error {
 	if len(values) == 0 {
 		return fmt.Errorf("values cannot be empty for type: %s", oType)
 	}
@@ -131,6 +189,12 @@ func validateValues(oType string, values []string) error {
 				return fmt.Errorf("invalid MAC address: %s", value)
 			}
 		}
+
+		if oType == "FQDN" {
+			if !utils.IsValidFQDN(value) && !slices.Contains(stored, value) {
+				return fmt.Errorf("invalid FQDN: %s", value)
+			}
+		}
 	}
 
 	return nil
@@ -246,7 +310,7 @@ func (s *Service) CreateObject(name string, oType string, values []string) error
 		return err
 	}
 
-	if err := validateValues(oType, values); err != nil {
+	if err := validateValues(oType, values, nil); err != nil {
 		return err
 	}
 
@@ -279,6 +343,14 @@ func (s *Service) CreateObject(name string, oType string, values []string) error
 		return err
 	}
 
+	if oType == "FQDN" {
+		go s.resolveInBackground(object.ID)
+	}
+
+	if oType == "Country" {
+		go s.expandCountryInBackground(object.ID)
+	}
+
 	return nil
 }
 
@@ -292,10 +364,23 @@ func (s *Service) DeleteObject(id uint) error {
 		return fmt.Errorf("object %d is currently in use and cannot be deleted", id)
 	}
 
+	users, err := s.objectUsers(id)
+	if err != nil {
+		return err
+	}
+
+	if len(users) > 0 {
+		return fmt.Errorf("object_in_use_by_%s", users[0])
+	}
+
 	if err := s.DB.Where("object_id = ?", id).Delete(&networkModels.ObjectResolution{}).Error; err != nil {
 		return fmt.Errorf("failed to delete resolutions for object %d: %w", id, err)
 	}
 
+	if err := s.DB.Where("object_id = ?", id).Delete(&networkModels.ObjectResolutionLog{}).Error; err != nil {
+		return fmt.Errorf("failed to delete resolution logs for object %d: %w", id, err)
+	}
+
 	if err := s.DB.Where("object_id = ?", id).Delete(&networkModels.ObjectEntry{}).Error; err != nil {
 		return fmt.Errorf("failed to delete entries for object %d: %w", id, err)
 	}
@@ -331,7 +416,15 @@ func (s *Service) EditObject(id uint, name string, oType string, values []string
 		return err
 	}
 
-	if err := validateValues(oType, values); err != nil {
+	var stored []string
+	if err := s.DB.
+		Model(&networkModels.ObjectEntry{}).
+		Where("object_id IN (?)", s.DB.Model(&networkModels.Object{}).Select("id").Where("id = ? AND type = ?", id, oType)).
+		Pluck("value", &stored).Error; err != nil {
+		return fmt.Errorf("failed to get entries of object %d: %w", id, err)
+	}
+
+	if err := validateValues(oType, values, stored); err != nil {
 		return err
 	}
 
@@ -359,6 +452,25 @@ func (s *Service) EditObject(id uint, name string, oType string, values []string
 		return fmt.Errorf("failed to find object with ID %d: %w", id, err)
 	}
 
+	if object.Type != oType {
+		users, err := s.objectUsers(id)
+		if err != nil {
+			return err
+		}
+
+		if len(users) > 0 {
+			return fmt.Errorf("cannot_change_object_type_%s", users[0])
+		}
+	}
+
+	/* Only withdraw routes built from this object once the edit went through, a rejected edit must leave them alone */
+	routesBefore := s.staticRoutesUsingObject(id)
+	defer func() {
+		if err := s.refreshStaticRoutes(routesBefore); err != nil {
+			logger.L.Warn().Err(err).Msgf("failed to sync static routes after editing object %d", id)
+		}
+	}()
+
 	/* This object isn't used anywhere, yay! It's going to be an easy edit */
 	if !used {
 		object.Name = name
@@ -372,8 +484,11 @@ func (s *Service) EditObject(id uint, name string, oType string, values []string
 			return fmt.Errorf("failed to delete existing entries for object %d: %w", id, err)
 		}
 
-		if err := s.DB.Where("object_id = ?", id).Delete(&networkModels.ObjectResolution{}).Error; err != nil {
-			return fmt.Errorf("failed to delete resolutions for object %d: %w", id, err)
+		/* FQDN and Country resolutions stay until the background refresh replaces them, so pf tables never go empty meanwhile */
+		if !resolvedObjectType(oType) {
+			if err := s.DB.Where("object_id = ?", id).Delete(&networkModels.ObjectResolution{}).Error; err != nil {
+				return fmt.Errorf("failed to delete resolutions for object %d: %w", id, err)
+			}
 		}
 
 		for _, value := range values {
@@ -544,7 +659,7 @@ func (s *Service) EditObject(id uint, name string, oType string, values []string
 					}
 				}
 
-				err := s.SyncStandardSwitches(nil, "sync")
+				err := s.SyncStandardSwitches(nil, "reconcile")
 				if err != nil {
 					return fmt.Errorf("failed to sync standard switches after editing object %d: %w", id, err)
 				}
@@ -626,7 +741,7 @@ func (s *Service) EditObject(id uint, name string, oType string, values []string
 					}
 				}
 
-				err := s.SyncStandardSwitches(nil, "sync")
+				err := s.SyncStandardSwitches(nil, "reconcile")
 				if err != nil {
 					return fmt.Errorf("failed to sync standard switches after editing object %d: %w", id, err)
 				}
@@ -651,6 +766,31 @@ func (s *Service) EditObject(id uint, name string, oType string, values []string
 		}
 	}
 
+	/* The resolver and the GeoIP expansion reload the table themselves once the new addresses are in */
+	if !resolvedObjectType(oType) {
+		if err := s.ReloadFirewallTable(id); err != nil {
+			logger.L.Warn().Err(err).Msgf("failed to reload firewall table for object %d", id)
+		}
+	}
+
+	if err := s.SyncNAT(); err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to sync nat rules after editing object %d", id)
+	}
+
+	if err := s.SyncDHCP(); err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to sync dhcp config after editing object %d", id)
+	}
+
+	s.syncWireGuardUsingObject(id)
+
+	if oType == "FQDN" {
+		go s.resolveInBackground(id)
+	}
+
+	if oType == "Country" {
+		go s.expandCountryInBackground(id)
+	}
+
 	return nil
 }
 
@@ -674,6 +814,10 @@ func (s *Service) AddNetworkObjectEditJailTrigger(id uint, values []string) erro
 		}
 	}
 
+	return s.triggerJailObjectUpdate(id)
+}
+
+func (s *Service) triggerJailObjectUpdate(id uint) error {
 	used, jailIds, err := s.IsObjectUsedByJail(id)
 
 	if err != nil {
@@ -719,3 +863,51 @@ func (s *Service) GetObjectEntryByID(id uint) (string, error) {
 
 	return object.Entries[0].Value, nil
 }
+
+func (s *Service) isObjectUsedByNFS(id uint) (bool, error) {
+	var exports []nfsModels.NFSExport
+	if err := s.DB.Select("id").Preload("Networks", "id = ?", id).Find(&exports).Error; err != nil {
+		return false, fmt.Errorf("failed to check nfs exports using object %d: %w", id, err)
+	}
+
+	return slices.ContainsFunc(exports, func(e nfsModels.NFSExport) bool { return len(e.Networks) > 0 }), nil
+}
+
+func (s *Service) isObjectUsedByISCSI(id uint) (bool, error) {
+	var targets []iscsiModels.ISCSITarget
+	if err := s.DB.Select("id").Preload("Initiators", "id = ?", id).Find(&targets).Error; err != nil {
+		return false, fmt.Errorf("failed to check iscsi targets using object %d: %w", id, err)
+	}
+
+	return slices.ContainsFunc(targets, func(t iscsiModels.ISCSITarget) bool { return len(t.Initiators) > 0 }), nil
+}
+
+// objectUsers names the firewall, routing and service consumers that hold on
+// to an object, on top of the switches, jails and VMs IsObjectUsed checks.
+// Such an object can neither be deleted nor change its type.
+func (s *Service) objectUsers(id uint) ([]string, error) {
+	checks := []struct {
+		user  string
+		check func(uint) (bool, error)
+	}{
+		{"firewall", s.isObjectUsedByFirewall},
+		{"route", s.isObjectUsedByRoute},
+		{"wireguard", s.isObjectUsedByWireGuard},
+		{"nfs_export", s.isObjectUsedByNFS},
+		{"iscsi_target", s.isObjectUsedByISCSI},
+	}
+
+	var users []string
+	for _, c := range checks {
+		used, err := c.check(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if used {
+			users = append(users, c.user)
+		}
+	}
+
+	return users, nil
+}
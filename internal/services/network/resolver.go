@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	minResolutionTTL  = 30
+	maxResolutionTTL  = 86400
+	resolutionRetry   = 60 * time.Second
+	maxResolutionLogs = 100
+)
+
+type dnsAnswer struct {
+	Address string
+	TTL     uint32
+}
+
+func parseDrillAnswers(output string, rrType string) ([]dnsAnswer, error) {
+	var answers []dnsAnswer
+	inAnswer := false
+
+	for _, line := range strings.Split(output, "\n") {
+		t := strings.TrimSpace(line)
+
+		if idx := strings.Index(t, "rcode: "); idx >= 0 {
+			rcode := t[idx+len("rcode: "):]
+			if end := strings.Index(rcode, ","); end >= 0 {
+				rcode = rcode[:end]
+			}
+
+			if rcode != "NOERROR" {
+				return nil, fmt.Errorf("dns_query_failed: %s", strings.ToLower(rcode))
+			}
+			continue
+		}
+
+		if strings.HasPrefix(t, ";; ANSWER SECTION") {
+			inAnswer = true
+			continue
+		}
+
+		if !inAnswer {
+			continue
+		}
+
+		if t == "" || strings.HasPrefix(t, ";;") {
+			inAnswer = false
+			continue
+		}
+
+		fields := strings.Fields(t)
+		if len(fields) < 5 || fields[3] != rrType {
+			continue
+		}
+
+		ttl, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			continue
+		}
+
+		answers = append(answers, dnsAnswer{Address: fields[4], TTL: uint32(ttl)})
+	}
+
+	return answers, nil
+}
+
+func lookupFQDN(fqdn string) ([]dnsAnswer, error) {
+	var answers []dnsAnswer
+	var lastErr error
+
+	for _, rrType := range []string{"A", "AAAA"} {
+		output, err := utils.RunCommand("drill", fqdn, rrType)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		found, err := parseDrillAnswers(output, rrType)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		for _, a := range found {
+			if !slices.ContainsFunc(answers, func(e dnsAnswer) bool { return e.Address == a.Address }) {
+				answers = append(answers, a)
+			}
+		}
+	}
+
+	if len(answers) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("no_addresses_found")
+	}
+
+	return answers, nil
+}
+
+func clampTTL(ttl uint32) uint32 {
+	if ttl < minResolutionTTL {
+		return minResolutionTTL
+	}
+
+	if ttl > maxResolutionTTL {
+		return maxResolutionTTL
+	}
+
+	return ttl
+}
+
+func resolvedSet(resolutions []networkModels.ObjectResolution) []string {
+	var ips []string
+	for _, r := range resolutions {
+		if !slices.Contains(ips, r.ResolvedIP) {
+			ips = append(ips, r.ResolvedIP)
+		}
+	}
+
+	slices.Sort(ips)
+	return ips
+}
+
+// ResolveFQDNObject refreshes the resolutions of an FQDN object. Entries that
+// fail to resolve keep their previous addresses until the next retry, so a
+// flaky resolver does not empty firewall tables.
+func (s *Service) ResolveFQDNObject(id uint) error {
+	s.fqdnMutex.Lock()
+	defer s.fqdnMutex.Unlock()
+
+	var object networkModels.Object
+	if err := s.DB.Preload("Entries").Preload("Resolutions").First(&object, id).Error; err != nil {
+		return fmt.Errorf("failed to find object with ID %d: %w", id, err)
+	}
+
+	if object.Type != "FQDN" {
+		return nil
+	}
+
+	previous := make(map[string][]networkModels.ObjectResolution)
+	for _, r := range object.Resolutions {
+		previous[r.Entry] = append(previous[r.Entry], r)
+	}
+
+	now := time.Now()
+
+	var fresh []networkModels.ObjectResolution
+	var logs []networkModels.ObjectResolutionLog
+
+	for _, entry := range object.Entries {
+		log := networkModels.ObjectResolutionLog{
+			ObjectID: id,
+			Entry:    entry.Value,
+		}
+
+		var current []networkModels.ObjectResolution
+
+		answers, err := lookupFQDN(entry.Value)
+		if err != nil {
+			log.Error = err.Error()
+
+			for _, r := range previous[entry.Value] {
+				current = append(current, networkModels.ObjectResolution{
+					ObjectID:   id,
+					ResolvedIP: r.ResolvedIP,
+					Entry:      r.Entry,
+					TTL:        r.TTL,
+					ExpiresAt:  now.Add(resolutionRetry),
+				})
+			}
+		} else {
+			log.Success = true
+
+			for _, a := range answers {
+				ttl := clampTTL(a.TTL)
+				current = append(current, networkModels.ObjectResolution{
+					ObjectID:   id,
+					ResolvedIP: a.Address,
+					Entry:      entry.Value,
+					TTL:        ttl,
+					ExpiresAt:  now.Add(time.Duration(ttl) * time.Second),
+				})
+				log.Addresses = append(log.Addresses, a.Address)
+			}
+		}
+
+		log.Changed = !slices.Equal(resolvedSet(previous[entry.Value]), resolvedSet(current))
+		fresh = append(fresh, current...)
+		logs = append(logs, log)
+	}
+
+	changed := !slices.Equal(resolvedSet(object.Resolutions), resolvedSet(fresh))
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("object_id = ?", id).Delete(&networkModels.ObjectResolution{}).Error; err != nil {
+			return err
+		}
+
+		if len(fresh) > 0 {
+			if err := tx.Create(&fresh).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(logs) > 0 {
+			if err := tx.Create(&logs).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&networkModels.Object{}).Where("id = ?", id).UpdateColumn("resolved_at", now).Error; err != nil {
+			return err
+		}
+
+		keep := tx.Model(&networkModels.ObjectResolutionLog{}).
+			Select("id").
+			Where("object_id = ?", id).
+			Order("id DESC").
+			Limit(maxResolutionLogs)
+
+		return tx.Where("object_id = ? AND id NOT IN (?)", id, keep).Delete(&networkModels.ObjectResolutionLog{}).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to store resolutions for object %d: %w", id, err)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if err := s.ReloadFirewallTable(id); err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to reload firewall table for object %d", id)
+	}
+
+	if err := s.triggerJailObjectUpdate(id); err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to trigger jail update for object %d", id)
+	}
+
+	s.syncWireGuardUsingObject(id)
+
+	return nil
+}
+
+func (s *Service) resolveInBackground(id uint) {
+	if err := s.ResolveFQDNObject(id); err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to resolve FQDN object %d", id)
+	}
+}
+
+// resolutionDue reports whether an FQDN object needs resolving: it never
+// was, a resolution expired, or an entry without any addresses is due for
+// a retry.
+func resolutionDue(object networkModels.Object, now time.Time) bool {
+	if object.ResolvedAt == nil {
+		return true
+	}
+
+	resolved := make(map[string]bool)
+	for _, r := range object.Resolutions {
+		if !now.Before(r.ExpiresAt) {
+			return true
+		}
+		resolved[r.Entry] = true
+	}
+
+	if now.Sub(*object.ResolvedAt) < resolutionRetry {
+		return false
+	}
+
+	for _, entry := range object.Entries {
+		if !resolved[entry.Value] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Service) resolveDueFQDNs() {
+	var objects []networkModels.Object
+	if err := s.DB.Preload("Entries").Preload("Resolutions").Where("type = ?", "FQDN").Find(&objects).Error; err != nil {
+		logger.L.Debug().Err(err).Msg("Failed to fetch FQDN objects")
+		return
+	}
+
+	now := time.Now()
+	for _, object := range objects {
+		if !resolutionDue(object, now) {
+			continue
+		}
+
+		if err := s.ResolveFQDNObject(object.ID); err != nil {
+			logger.L.Debug().Err(err).Msgf("Failed to resolve FQDN object %s", object.Name)
+		}
+	}
+}
+
+func (s *Service) StartFQDNResolver(ctx context.Context) {
+	ticker := time.NewTicker(15 * time.Second)
+
+	go func() {
+		s.resolveDueFQDNs()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.resolveDueFQDNs()
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+)
+
+func TestResolutionDue(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) *time.Time {
+		ts := now.Add(offset)
+		return &ts
+	}
+
+	entries := []networkModels.ObjectEntry{{Value: "example.com"}, {Value: "mirror.example.org"}}
+	resolution := func(entry string, expires time.Duration) networkModels.ObjectResolution {
+		return networkModels.ObjectResolution{Entry: entry, ResolvedIP: "192.0.2.10", ExpiresAt: now.Add(expires)}
+	}
+
+	tests := []struct {
+		name     string
+		object   networkModels.Object
+		expected bool
+	}{
+		{"never resolved", networkModels.Object{Entries: entries}, true},
+		{"all entries fresh", networkModels.Object{
+			Entries:     entries,
+			ResolvedAt:  at(-10 * time.Minute),
+			Resolutions: []networkModels.ObjectResolution{resolution("example.com", time.Hour), resolution("mirror.example.org", time.Hour)},
+		}, false},
+		{"one resolution expired", networkModels.Object{
+			Entries:     entries,
+			ResolvedAt:  at(-10 * time.Minute),
+			Resolutions: []networkModels.ObjectResolution{resolution("example.com", time.Hour), resolution("mirror.example.org", 0)},
+		}, true},
+		{"first resolution failed, retry pending", networkModels.Object{
+			Entries:    entries,
+			ResolvedAt: at(-30 * time.Second),
+		}, false},
+		{"first resolution failed, retry due", networkModels.Object{
+			Entries:    entries,
+			ResolvedAt: at(-resolutionRetry),
+		}, true},
+		{"one entry failed, retry pending", networkModels.Object{
+			Entries:     entries,
+			ResolvedAt:  at(-30 * time.Second),
+			Resolutions: []networkModels.ObjectResolution{resolution("example.com", time.Hour)},
+		}, false},
+		{"one entry failed, retry due", networkModels.Object{
+			Entries:     entries,
+			ResolvedAt:  at(-2 * resolutionRetry),
+			Resolutions: []networkModels.ObjectResolution{resolution("example.com", time.Hour)},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		if got := resolutionDue(tt.object, now); got != tt.expected {
+			t.Errorf("resolutionDue(%s) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,611 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/network/iface"
+	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/alchemillahq/sylve/pkg/utils/sysctl"
+)
+
+type routeSpec struct {
+	IPv6        bool
+	Destination netip.Prefix
+	Gateway     string
+	Interface   string
+	Weight      uint32
+	FIB         uint
+}
+
+type kernelRoute struct {
+	Destination netip.Prefix
+	Gateway     string
+	Interface   string
+	// Weight is only filled in by kernelRouteWeight, netstat does not show it
+	Weight uint32
+}
+
+type netstatRoutes struct {
+	Statistics struct {
+		RouteInformation struct {
+			RouteTable struct {
+				RtFamily []struct {
+					AddressFamily string `json:"address-family"`
+					RtEntry       []struct {
+						Destination   string `json:"destination"`
+						Gateway       string `json:"gateway"`
+						Flags         string `json:"flags"`
+						InterfaceName string `json:"interface-name"`
+					} `json:"rt-entry"`
+				} `json:"rt-family"`
+			} `json:"route-table"`
+		} `json:"route-information"`
+	} `json:"statistics"`
+}
+
+func parseKernelDestination(dest string, ipv6 bool) (netip.Prefix, error) {
+	if dest == "default" {
+		if ipv6 {
+			return netip.MustParsePrefix("::/0"), nil
+		}
+		return netip.MustParsePrefix("0.0.0.0/0"), nil
+	}
+
+	if idx := strings.Index(dest, "%"); idx >= 0 {
+		rest := dest[idx:]
+		dest = dest[:idx]
+		if slash := strings.Index(rest, "/"); slash >= 0 {
+			dest += rest[slash:]
+		}
+	}
+
+	if strings.Contains(dest, "/") {
+		prefix, err := netip.ParsePrefix(dest)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(dest)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func kernelRoutes(fib uint) ([]kernelRoute, error) {
+	output, err := utils.RunCommand("netstat", "-rn", "-F", strconv.FormatUint(uint64(fib), 10), "--libxo", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_read_routing_table: %w", err)
+	}
+
+	return parseKernelRoutes(output)
+}
+
+// parseKernelRoutes reads the IPv4 and IPv6 entries of netstat -rn --libxo
+// json output.
+func parseKernelRoutes(output string) ([]kernelRoute, error) {
+	var parsed netstatRoutes
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		return nil, fmt.Errorf("failed_to_parse_routing_table: %w", err)
+	}
+
+	var routes []kernelRoute
+	for _, family := range parsed.Statistics.RouteInformation.RouteTable.RtFamily {
+		ipv6 := family.AddressFamily == "Internet6"
+		if !ipv6 && family.AddressFamily != "Internet" {
+			continue
+		}
+
+		for _, entry := range family.RtEntry {
+			dest, err := parseKernelDestination(entry.Destination, ipv6)
+			if err != nil {
+				continue
+			}
+
+			routes = append(routes, kernelRoute{
+				Destination: dest,
+				Gateway:     entry.Gateway,
+				Interface:   entry.InterfaceName,
+			})
+		}
+	}
+
+	return routes, nil
+}
+
+func (s *Service) routeSpec(route networkModels.StaticRoute) (routeSpec, error) {
+	spec := routeSpec{Weight: route.Weight, FIB: route.FIB}
+
+	destination, err := s.singleEntryObject(route.DestinationID, "Network")
+	if err != nil {
+		return spec, err
+	}
+
+	prefix, err := netip.ParsePrefix(destination)
+	if err != nil {
+		return spec, fmt.Errorf("invalid_route_destination: %s", destination)
+	}
+
+	spec.Destination = prefix.Masked()
+	spec.IPv6 = spec.Destination.Addr().Is6()
+
+	if (route.GatewayID == nil) == (route.Interface == "") {
+		return spec, fmt.Errorf("route_requires_gateway_or_interface")
+	}
+
+	if route.GatewayID != nil {
+		gateway, err := s.singleEntryObject(*route.GatewayID, "Host")
+		if err != nil {
+			return spec, err
+		}
+
+		addr, err := netip.ParseAddr(gateway)
+		if err != nil {
+			return spec, fmt.Errorf("invalid_route_gateway: %s", gateway)
+		}
+
+		if addr.Is6() != spec.IPv6 {
+			return spec, fmt.Errorf("route_gateway_family_mismatch")
+		}
+
+		spec.Gateway = addr.String()
+	} else {
+		spec.Interface = route.Interface
+	}
+
+	return spec, nil
+}
+
+func (r routeSpec) args(action string) []string {
+	args := []string{"-n"}
+	if r.IPv6 {
+		args = append(args, "-6")
+	}
+
+	args = append(args, action)
+
+	if r.Destination.Bits() == 0 {
+		args = append(args, "default")
+	} else {
+		args = append(args, "-net", r.Destination.String())
+	}
+
+	if action != "delete" {
+		if r.Interface != "" {
+			args = append(args, "-interface", r.Interface)
+		} else {
+			args = append(args, r.Gateway)
+		}
+
+		if r.Weight > 0 {
+			args = append(args, "-weight", strconv.FormatUint(uint64(r.Weight), 10))
+		}
+	}
+
+	args = append(args, "-fib", strconv.FormatUint(uint64(r.FIB), 10))
+
+	return args
+}
+
+func (r routeSpec) matches(k kernelRoute) bool {
+	if r.Weight > 0 && k.Weight != r.Weight {
+		return false
+	}
+
+	if r.Interface != "" {
+		return k.Interface == r.Interface
+	}
+
+	return k.Gateway == r.Gateway
+}
+
+// parseRouteWeight reads the weight column from the metrics table at the
+// end of `route get` output.
+func parseRouteWeight(output string) (uint32, error) {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		header := strings.Fields(line)
+		col := slices.Index(header, "weight")
+		if col < 0 || i+1 >= len(lines) {
+			continue
+		}
+
+		values := strings.Fields(lines[i+1])
+		if col >= len(values) {
+			break
+		}
+
+		weight, err := strconv.ParseUint(values[col], 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid_route_weight: %s", values[col])
+		}
+
+		return uint32(weight), nil
+	}
+
+	return 0, fmt.Errorf("route_weight_not_found")
+}
+
+// kernelRouteWeight asks the kernel for the weight of the route to spec's
+// destination.
+func kernelRouteWeight(spec routeSpec) (uint32, error) {
+	args := []string{"-n"}
+	if spec.IPv6 {
+		args = append(args, "-6")
+	}
+
+	args = append(args, "get")
+	if spec.Destination.Bits() == 0 {
+		args = append(args, "default")
+	} else {
+		args = append(args, "-net", spec.Destination.String())
+	}
+
+	args = append(args, "-fib", strconv.FormatUint(uint64(spec.FIB), 10))
+
+	output, err := utils.RunCommand("route", args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed_to_get_route: %w", err)
+	}
+
+	return parseRouteWeight(output)
+}
+
+// findSpecRoute looks up the kernel route for spec, with its weight when
+// spec asks for one.
+func findSpecRoute(table []kernelRoute, spec routeSpec) *kernelRoute {
+	k := findKernelRoute(table, spec.Destination)
+	if k == nil || spec.Weight == 0 {
+		return k
+	}
+
+	if weight, err := kernelRouteWeight(spec); err == nil {
+		k.Weight = weight
+	} else {
+		logger.L.Debug().Err(err).Msgf("failed to read weight of route to %s", spec.Destination)
+	}
+
+	return k
+}
+
+func findKernelRoute(routes []kernelRoute, dest netip.Prefix) *kernelRoute {
+	for i := range routes {
+		if routes[i].Destination == dest {
+			return &routes[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) validateStaticRoute(route networkModels.StaticRoute, id uint) error {
+	if route.Name == "" {
+		return fmt.Errorf("route_name_required")
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.StaticRoute{}).
+		Where("name = ? AND id != ?", route.Name, id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return fmt.Errorf("route_name_already_exists")
+	}
+
+	if route.FIB > 0 {
+		fibs, err := sysctl.GetInt64("net.fibs")
+		if err != nil {
+			return fmt.Errorf("failed_to_read_fib_count: %w", err)
+		}
+
+		if int64(route.FIB) >= fibs {
+			return fmt.Errorf("invalid_fib: %d", route.FIB)
+		}
+	}
+
+	if route.Interface != "" {
+		if _, err := iface.Get(route.Interface); err != nil {
+			return fmt.Errorf("route_interface_not_found: %s", route.Interface)
+		}
+	}
+
+	spec, err := s.routeSpec(route)
+	if err != nil {
+		return err
+	}
+
+	var others []networkModels.StaticRoute
+	if err := s.DB.Where("id != ? AND fib = ?", id, route.FIB).Find(&others).Error; err != nil {
+		return err
+	}
+
+	for _, other := range others {
+		otherSpec, err := s.routeSpec(other)
+		if err != nil {
+			continue
+		}
+
+		if otherSpec.Destination == spec.Destination {
+			return fmt.Errorf("route_destination_already_used_by_%s", other.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) isObjectUsedByRoute(id uint) (bool, error) {
+	var count int64
+	err := s.DB.Model(&networkModels.StaticRoute{}).
+		Where("destination_object_id = ? OR gateway_object_id = ?", id, id).
+		Count(&count).Error
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check static routes using object %d: %w", id, err)
+	}
+
+	return count > 0, nil
+}
+
+// GetStaticRoutes lists all static routes along with their state in the
+// kernel routing table of their FIB.
+func (s *Service) GetStaticRoutes() ([]networkModels.StaticRoute, error) {
+	var routes []networkModels.StaticRoute
+
+	err := s.DB.
+		Preload("DestinationObj.Entries").
+		Preload("GatewayObj.Entries").
+		Order("id ASC").
+		Find(&routes).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_get_static_routes: %w", err)
+	}
+
+	tables := make(map[uint][]kernelRoute)
+
+	for i := range routes {
+		if !routes[i].Enabled {
+			routes[i].Status = "disabled"
+			continue
+		}
+
+		spec, err := s.routeSpec(routes[i])
+		if err != nil {
+			routes[i].Status = "invalid"
+			continue
+		}
+
+		table, ok := tables[spec.FIB]
+		if !ok {
+			table, err = kernelRoutes(spec.FIB)
+			if err != nil {
+				return nil, err
+			}
+			tables[spec.FIB] = table
+		}
+
+		k := findSpecRoute(table, spec)
+		switch {
+		case k == nil:
+			routes[i].Status = "missing"
+		case spec.matches(*k):
+			routes[i].Status = "active"
+			routes[i].KernelGateway = k.Gateway
+		default:
+			routes[i].Status = "mismatch"
+			routes[i].KernelGateway = k.Gateway
+		}
+	}
+
+	return routes, nil
+}
+
+func (s *Service) CreateStaticRoute(route networkModels.StaticRoute) error {
+	if err := s.validateStaticRoute(route, 0); err != nil {
+		return err
+	}
+
+	enabled := route.Enabled
+	route.ID = 0
+
+	if err := s.DB.Create(&route).Error; err != nil {
+		return fmt.Errorf("failed_to_create_static_route: %w", err)
+	}
+
+	if !enabled {
+		if err := s.DB.Model(&route).Update("enabled", false).Error; err != nil {
+			return fmt.Errorf("failed_to_create_static_route: %w", err)
+		}
+	}
+
+	return s.SyncStaticRoutes()
+}
+
+func (s *Service) EditStaticRoute(id uint, route networkModels.StaticRoute) error {
+	var existing networkModels.StaticRoute
+	if err := s.DB.First(&existing, id).Error; err != nil {
+		return fmt.Errorf("static_route_not_found")
+	}
+
+	if err := s.validateStaticRoute(route, id); err != nil {
+		return err
+	}
+
+	before := make(map[uint]routeSpec)
+	if existing.Enabled {
+		if spec, err := s.routeSpec(existing); err == nil {
+			before[existing.ID] = spec
+		}
+	}
+
+	err := s.DB.Model(&existing).
+		Select("Name", "Enabled", "Comment", "DestinationID", "GatewayID", "Interface", "Weight", "FIB").
+		Updates(networkModels.StaticRoute{
+			Name:          route.Name,
+			Enabled:       route.Enabled,
+			Comment:       route.Comment,
+			DestinationID: route.DestinationID,
+			GatewayID:     route.GatewayID,
+			Interface:     route.Interface,
+			Weight:        route.Weight,
+			FIB:           route.FIB,
+		}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed_to_update_static_route: %w", err)
+	}
+
+	return s.refreshStaticRoutes(before)
+}
+
+func (s *Service) DeleteStaticRoute(id uint) error {
+	var existing networkModels.StaticRoute
+	if err := s.DB.First(&existing, id).Error; err != nil {
+		return fmt.Errorf("static_route_not_found")
+	}
+
+	s.routeMutex.Lock()
+	defer s.routeMutex.Unlock()
+
+	if existing.Enabled {
+		s.removeStaticRoute(existing)
+	}
+
+	if err := s.DB.Delete(&existing).Error; err != nil {
+		return fmt.Errorf("failed_to_delete_static_route: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) removeStaticRoute(route networkModels.StaticRoute) {
+	spec, err := s.routeSpec(route)
+	if err != nil {
+		return
+	}
+
+	deleteKernelRoute(route.Name, spec)
+}
+
+func deleteKernelRoute(name string, spec routeSpec) {
+	if out, err := utils.RunCommand("route", spec.args("delete")...); err != nil {
+		if !strings.Contains(out, "not in table") {
+			logger.L.Warn().Err(err).Msgf("failed to remove static route %s", name)
+		}
+	}
+}
+
+// staticRoutesUsingObject snapshots the kernel routes currently built from
+// an object, so an edit to it can withdraw the ones it ends up changing.
+func (s *Service) staticRoutesUsingObject(id uint) map[uint]routeSpec {
+	specs := make(map[uint]routeSpec)
+
+	var routes []networkModels.StaticRoute
+	if err := s.DB.Where("enabled = ? AND (destination_object_id = ? OR gateway_object_id = ?)", true, id, id).
+		Find(&routes).Error; err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to find static routes using object %d", id)
+		return specs
+	}
+
+	for _, route := range routes {
+		if spec, err := s.routeSpec(route); err == nil {
+			specs[route.ID] = spec
+		}
+	}
+
+	return specs
+}
+
+// refreshStaticRoutes withdraws the routes in before that are gone,
+// disabled or no longer render the same, then lets SyncStaticRoutes add
+// their replacements. Routes that did not change are left in place.
+func (s *Service) refreshStaticRoutes(before map[uint]routeSpec) error {
+	s.routeMutex.Lock()
+	for id, old := range before {
+		var route networkModels.StaticRoute
+		if err := s.DB.First(&route, id).Error; err == nil && route.Enabled {
+			if spec, err := s.routeSpec(route); err == nil && spec == old {
+				continue
+			}
+		}
+
+		deleteKernelRoute(route.Name, old)
+	}
+	s.routeMutex.Unlock()
+
+	return s.SyncStaticRoutes()
+}
+
+// SyncStaticRoutes reconciles the enabled static routes against the kernel
+// routing tables, adding missing routes and correcting ones whose gateway or
+// interface has drifted.
+func (s *Service) SyncStaticRoutes() error {
+	s.routeMutex.Lock()
+	defer s.routeMutex.Unlock()
+
+	var routes []networkModels.StaticRoute
+	if err := s.DB.Where("enabled = ?", true).Order("id ASC").Find(&routes).Error; err != nil {
+		return fmt.Errorf("failed_to_get_static_routes: %w", err)
+	}
+
+	tables := make(map[uint][]kernelRoute)
+	var errs []error
+
+	for _, route := range routes {
+		spec, err := s.routeSpec(route)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("route_%s: %w", route.Name, err))
+			continue
+		}
+
+		table, ok := tables[spec.FIB]
+		if !ok {
+			table, err = kernelRoutes(spec.FIB)
+			if err != nil {
+				return err
+			}
+			tables[spec.FIB] = table
+		}
+
+		k := findSpecRoute(table, spec)
+		if k != nil && spec.matches(*k) {
+			continue
+		}
+
+		action := "add"
+		if k != nil {
+			action = "change"
+		}
+
+		if out, err := utils.RunCommand("route", spec.args(action)...); err != nil {
+			if strings.Contains(out, "route already in table") {
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("route_%s: failed_to_%s_route: %w", route.Name, action, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
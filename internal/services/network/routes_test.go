@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Captured from netstat -rn -F 0 --libxo json on a host with a static route
+// and a link-local IPv6 default gateway; flags_pretty trimmed for brevity.
+const netstatRoutesOutput = `{"__version": "1", "statistics": {"route-information": {"route-table": {"rt-family": [
+{"address-family": "Internet", "rt-entry": [
+{"destination": "default", "gateway": "192.168.1.1", "flags": "UGS", "interface-name": "em0"},
+{"destination": "10.1.0.0/16", "gateway": "192.168.1.254", "flags": "UGS", "interface-name": "em0"},
+{"destination": "127.0.0.1", "gateway": "lo0", "flags": "UHS", "interface-name": "lo0"},
+{"destination": "172.16.5.0/24", "gateway": "link#4", "flags": "US", "interface-name": "wg0"},
+{"destination": "192.168.1.0/24", "gateway": "link#1", "flags": "U", "interface-name": "em0"},
+{"destination": "192.168.1.10", "gateway": "link#1", "flags": "UHS", "interface-name": "lo0"}
+]},
+{"address-family": "Internet6", "rt-entry": [
+{"destination": "::/96", "gateway": "::1", "flags": "URS", "interface-name": "lo0"},
+{"destination": "default", "gateway": "fe80::1%em0", "flags": "UGS", "interface-name": "em0"},
+{"destination": "::1", "gateway": "link#2", "flags": "UHS", "interface-name": "lo0"},
+{"destination": "2001:db8::/64", "gateway": "link#1", "flags": "U", "interface-name": "em0"},
+{"destination": "fe80::%lo0/64", "gateway": "link#2", "flags": "U", "interface-name": "lo0"},
+{"destination": "fe80::1%lo0", "gateway": "link#2", "flags": "UHS", "interface-name": "lo0"},
+{"destination": "ff02::%lo0/16", "gateway": "::1", "flags": "UR", "interface-name": "lo0"}
+]}
+]}}}}
+`
+
+// Captured from route -n get -net 10.1.0.0/16 -fib 0.
+const routeGetOutput = `   route to: 10.1.0.0
+destination: 10.1.0.0
+       mask: 255.255.0.0
+    gateway: 192.168.1.254
+        fib: 0
+  interface: em0
+      flags: <UP,GATEWAY,DONE,STATIC>
+ recvpipe  sendpipe  ssthresh  rtt,msec    mtu        weight    expire
+       0         0         0         0      1500        20         0 
+`
+
+func TestParseKernelDestination(t *testing.T) {
+	tests := []struct {
+		input    string
+		ipv6     bool
+		expected string
+	}{
+		{"default", false, "0.0.0.0/0"},
+		{"default", true, "::/0"},
+		{"10.1.0.0/16", false, "10.1.0.0/16"},
+		{"10.1.2.3/16", false, "10.1.0.0/16"},
+		{"192.168.1.10", false, "192.168.1.10/32"},
+		{"2001:db8::/64", true, "2001:db8::/64"},
+		{"::1", true, "::1/128"},
+		{"fe80::%lo0/64", true, "fe80::/64"},
+		{"fe80::1%lo0", true, "fe80::1/128"},
+		{"link#1", false, ""},
+		{"", false, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := parseKernelDestination(tt.input, tt.ipv6)
+		if tt.expected == "" {
+			if err == nil {
+				t.Errorf("parseKernelDestination(%q) = %v, want an error", tt.input, got)
+			}
+			continue
+		}
+
+		if err != nil || got.String() != tt.expected {
+			t.Errorf("parseKernelDestination(%q, %v) = %v, %v, want %s", tt.input, tt.ipv6, got, err, tt.expected)
+		}
+	}
+}
+
+func TestParseKernelRoutes(t *testing.T) {
+	routes, err := parseKernelRoutes(netstatRoutesOutput)
+	if err != nil {
+		t.Fatalf("parseKernelRoutes failed: %v", err)
+	}
+
+	var got []string
+	for _, r := range routes {
+		got = append(got, r.Destination.String()+" "+r.Gateway+" "+r.Interface)
+	}
+
+	expected := []string{
+		"0.0.0.0/0 192.168.1.1 em0",
+		"10.1.0.0/16 192.168.1.254 em0",
+		"127.0.0.1/32 lo0 lo0",
+		"172.16.5.0/24 link#4 wg0",
+		"192.168.1.0/24 link#1 em0",
+		"192.168.1.10/32 link#1 lo0",
+		"::/96 ::1 lo0",
+		"::/0 fe80::1%em0 em0",
+		"::1/128 link#2 lo0",
+		"2001:db8::/64 link#1 em0",
+		"fe80::/64 link#2 lo0",
+		"fe80::1/128 link#2 lo0",
+		"ff02::/16 ::1 lo0",
+	}
+
+	if !slices.Equal(got, expected) {
+		t.Errorf("parseKernelRoutes = %v, want %v", got, expected)
+	}
+
+	for _, input := range []string{"", "netstat: invalid option", `{"statistics": []}`} {
+		if _, err := parseKernelRoutes(input); err == nil || !strings.HasPrefix(err.Error(), "failed_to_parse_routing_table") {
+			t.Errorf("parseKernelRoutes(%q) error = %v, want failed_to_parse_routing_table", input, err)
+		}
+	}
+}
+
+func TestParseRouteWeight(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected uint32
+		err      string
+	}{
+		{routeGetOutput, 20, ""},
+		{strings.Replace(routeGetOutput, "1500        20", "1500         1", 1), 1, ""},
+		{strings.Replace(routeGetOutput, "1500        20", "1500       abc", 1), 0, "invalid_route_weight: abc"},
+		{strings.Replace(routeGetOutput, "weight", "hops", 1), 0, "route_weight_not_found"},
+		{strings.SplitAfter(routeGetOutput, "expire\n")[0], 0, "route_weight_not_found"},
+		{strings.SplitAfter(routeGetOutput, "expire")[0], 0, "route_weight_not_found"},
+		{"route: route has not been found\n", 0, "route_weight_not_found"},
+		{"", 0, "route_weight_not_found"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRouteWeight(tt.input)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("parseRouteWeight(%q) = %v, %v, want error %q", tt.input, got, err, tt.err)
+			}
+			continue
+		}
+
+		if err != nil || got != tt.expected {
+			t.Errorf("parseRouteWeight(%q) = %v, %v, want %v", tt.input, got, err, tt.expected)
+		}
+	}
+}
+
+func TestRouteSpecArgs(t *testing.T) {
+	tests := []struct {
+		spec     routeSpec
+		action   string
+		expected string
+	}{
+		{routeSpec{Destination: netip.MustParsePrefix("10.1.0.0/16"), Gateway: "192.168.1.254"}, "add",
+			"-n add -net 10.1.0.0/16 192.168.1.254 -fib 0"},
+		{routeSpec{Destination: netip.MustParsePrefix("0.0.0.0/0"), Gateway: "192.168.1.1", Weight: 5, FIB: 2}, "add",
+			"-n add default 192.168.1.1 -weight 5 -fib 2"},
+		{routeSpec{Destination: netip.MustParsePrefix("172.16.5.0/24"), Interface: "wg0"}, "change",
+			"-n change -net 172.16.5.0/24 -interface wg0 -fib 0"},
+		{routeSpec{IPv6: true, Destination: netip.MustParsePrefix("2001:db8:1::/48"), Gateway: "2001:db8::1", Weight: 10}, "add",
+			"-n -6 add -net 2001:db8:1::/48 2001:db8::1 -weight 10 -fib 0"},
+		{routeSpec{IPv6: true, Destination: netip.MustParsePrefix("::/0"), Gateway: "2001:db8::1", FIB: 1}, "delete",
+			"-n -6 delete default -fib 1"},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(tt.spec.args(tt.action), " "); got != tt.expected {
+			t.Errorf("routeSpec.args(%q) = %q, want %q", tt.action, got, tt.expected)
+		}
+	}
+}
+
+func TestRouteSpecMatches(t *testing.T) {
+	routes, err := parseKernelRoutes(netstatRoutesOutput)
+	if err != nil {
+		t.Fatalf("parseKernelRoutes failed: %v", err)
+	}
+
+	tests := []struct {
+		spec     routeSpec
+		weight   uint32
+		expected bool
+	}{
+		{routeSpec{Destination: netip.MustParsePrefix("10.1.0.0/16"), Gateway: "192.168.1.254"}, 0, true},
+		{routeSpec{Destination: netip.MustParsePrefix("10.1.0.0/16"), Gateway: "192.168.1.253"}, 0, false},
+		{routeSpec{Destination: netip.MustParsePrefix("10.1.0.0/16"), Gateway: "192.168.1.254", Weight: 20}, 20, true},
+		{routeSpec{Destination: netip.MustParsePrefix("10.1.0.0/16"), Gateway: "192.168.1.254", Weight: 20}, 1, false},
+		{routeSpec{Destination: netip.MustParsePrefix("10.1.0.0/16"), Gateway: "192.168.1.254", Weight: 20}, 0, false},
+		{routeSpec{Destination: netip.MustParsePrefix("172.16.5.0/24"), Interface: "wg0"}, 0, true},
+		{routeSpec{Destination: netip.MustParsePrefix("172.16.5.0/24"), Interface: "wg1"}, 0, false},
+		{routeSpec{Destination: netip.MustParsePrefix("0.0.0.0/0"), Gateway: "192.168.1.1"}, 0, true},
+		{routeSpec{IPv6: true, Destination: netip.MustParsePrefix("::/0"), Interface: "em0"}, 0, true},
+		{routeSpec{Destination: netip.MustParsePrefix("10.2.0.0/16"), Gateway: "192.168.1.254"}, 0, false},
+	}
+
+	for _, tt := range tests {
+		k := findKernelRoute(routes, tt.spec.Destination)
+		if k == nil {
+			if tt.expected {
+				t.Errorf("findKernelRoute(%s) found no route", tt.spec.Destination)
+			}
+			continue
+		}
+
+		route := *k
+		route.Weight = tt.weight
+		if got := tt.spec.matches(route); got != tt.expected {
+			t.Errorf("routeSpec{%s via %q%q weight %d}.matches(weight %d) = %v, want %v",
+				tt.spec.Destination, tt.spec.Gateway, tt.spec.Interface, tt.spec.Weight, tt.weight, got, tt.expected)
+		}
+	}
+}
@@ -74,6 +74,17 @@ func (s *Service) DeleteManualSwitch(id uint) error {
 		return err
 	}
 
+	var poolCount int64
+	if err := s.DB.Model(&networkModels.IPPool{}).
+		Where("switch_id = ? AND switch_type = ?", id, "manual").
+		Count(&poolCount).Error; err != nil {
+		return fmt.Errorf("db_error_checking_ip_pool_switch: %v", err)
+	}
+
+	if poolCount > 0 {
+		return fmt.Errorf("switch_in_use_by_ip_pool")
+	}
+
 	if err := s.DB.Delete(&sw).Error; err != nil {
 		return err
 	}
@@ -0,0 +1,495 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/network/iface"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+type switchStep struct {
+	change networkServiceInterfaces.SwitchChange
+	apply  func() error
+}
+
+func commandStep(sw string, action string, command string, args ...string) switchStep {
+	return switchStep{
+		change: networkServiceInterfaces.SwitchChange{
+			Switch:  sw,
+			Action:  action,
+			Command: command + " " + strings.Join(args, " "),
+		},
+		apply: func() error {
+			out, err := utils.RunCommand(command, args...)
+			if err != nil && strings.Contains(out, "route already in table") {
+				return nil
+			}
+			return err
+		},
+	}
+}
+
+func funcStep(sw string, action string, command string, apply func() error) switchStep {
+	return switchStep{
+		change: networkServiceInterfaces.SwitchChange{
+			Switch:  sw,
+			Action:  action,
+			Command: command,
+		},
+		apply: apply,
+	}
+}
+
+// switchRoute is a route a standard switch installs, dest is invalid when
+// the switch has no such route.
+type switchRoute struct {
+	dest    netip.Prefix
+	args    []string
+	gateway string
+}
+
+func (r switchRoute) command(action string) []string {
+	var args []string
+	if r.dest.Addr().Is6() {
+		args = append(args, "-6")
+	}
+
+	args = append(args, action)
+	args = append(args, r.args...)
+
+	return append(args, r.gateway)
+}
+
+// switchRoutes is what a switch adds to the routing table on top of its
+// connected routes. The kernel cannot tell these apart from routes an admin
+// added, so the set each sync applied is stored and only routes that still
+// match it are ever changed or removed.
+type switchRoutes struct {
+	network4 switchRoute
+	default4 switchRoute
+	network6 switchRoute
+}
+
+func (r *switchRoutes) byKind() map[string]*switchRoute {
+	return map[string]*switchRoute{
+		"network4": &r.network4,
+		"default4": &r.default4,
+		"network6": &r.network6,
+	}
+}
+
+func standardSwitchRoutes(sw networkModels.StandardSwitch) switchRoutes {
+	var r switchRoutes
+
+	network4, gateway4 := sw.Network(4), sw.Gateway(4)
+	if network4 != "" && gateway4 != "" {
+		if p, err := netip.ParsePrefix(network4); err == nil {
+			r.network4 = switchRoute{dest: p.Masked(), args: []string{"-net", network4}, gateway: gateway4}
+
+			if sw.DefaultRoute {
+				r.default4 = switchRoute{dest: netip.MustParsePrefix("0.0.0.0/0"), args: []string{"default"}, gateway: gateway4}
+			}
+		}
+	}
+
+	network6, gateway6 := sw.Network(6), sw.Gateway(6)
+	if network6 != "" && gateway6 != "" && !sw.DisableIPv6 && !strings.HasPrefix(gateway6, "fe80::") {
+		if p, err := netip.ParsePrefix(network6); err == nil {
+			r.network6 = switchRoute{dest: p.Masked(), args: []string{"-net", network6}, gateway: gateway6}
+		}
+	}
+
+	return r
+}
+
+// planSwitchRoute converges one route of a switch. A kernel route is only
+// changed or deleted when it is the one the previous sync installed.
+func planSwitchRoute(sw string, name string, routes []kernelRoute, want switchRoute, prev switchRoute) []switchStep {
+	var steps []switchStep
+
+	prevOwned := false
+	if prev.dest.IsValid() {
+		if k := findKernelRoute(routes, prev.dest); k != nil && k.Gateway == prev.gateway {
+			prevOwned = true
+		}
+	}
+
+	if want.dest.IsValid() {
+		k := findKernelRoute(routes, want.dest)
+		switch {
+		case k == nil:
+			steps = append(steps, commandStep(sw, "add_"+name, "route", want.command("add")...))
+		case k.Gateway != want.gateway && prevOwned && prev.dest == want.dest:
+			steps = append(steps, commandStep(sw, "change_"+name, "route", want.command("change")...))
+		}
+	}
+
+	if prevOwned && prev.dest != want.dest {
+		steps = append(steps, commandStep(sw, "remove_"+name, "route", prev.command("delete")...))
+	}
+
+	return steps
+}
+
+func ipv4PrefixLen(a iface.IPv4) int {
+	mask := net.ParseIP(a.Netmask).To4()
+	if mask == nil {
+		return -1
+	}
+
+	ones, _ := net.IPMask(mask).Size()
+	return ones
+}
+
+// isGuestMember reports whether a bridge member belongs to a VM or jail
+// rather than to the switch definition.
+func isGuestMember(name string) bool {
+	member, err := iface.Get(name)
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(member.Driver, "tap") ||
+		utils.Contains(member.Groups, "tap") ||
+		utils.Contains(member.Groups, "vnet") ||
+		utils.Contains(member.Groups, "epair")
+}
+
+// planStandardSwitch diffs a switch against the live bridge and returns only
+// the steps needed to converge it, leaving guest taps and epairs attached.
+// prev holds the routes the last sync applied, nil if there was none yet.
+func planStandardSwitch(sw networkModels.StandardSwitch, routes []kernelRoute, prev *switchRoutes) []switchStep {
+	br := sw.BridgeName
+
+	current, err := iface.Get(br)
+	if err != nil {
+		return []switchStep{funcStep(sw.Name, "create_bridge", "ifconfig bridge create name "+br, func() error {
+			return createStandardBridge(sw)
+		})}
+	}
+
+	var steps []switchStep
+
+	if current.Description != sw.Name {
+		steps = append(steps, commandStep(sw.Name, "set_description", "ifconfig", br, "descr", sw.Name))
+	}
+
+	if sw.MTU != 0 && current.MTU != sw.MTU {
+		steps = append(steps, commandStep(sw.Name, "set_mtu", "ifconfig", br, "mtu", strconv.Itoa(sw.MTU)))
+	}
+
+	members := make(map[string]bool)
+	for _, m := range current.BridgeMembers {
+		members[m.Name] = true
+	}
+
+	desired := make(map[string]bool)
+	for _, port := range sw.Ports {
+		target := port.Name
+		if sw.VLAN > 0 {
+			target = fmt.Sprintf("%s.%d", port.Name, sw.VLAN)
+		}
+		desired[target] = true
+
+		if !members[target] {
+			steps = append(steps, funcStep(sw.Name, "add_member", fmt.Sprintf("ifconfig %s addm %s up", br, target), func() error {
+				return addBridgeMember(br, port.Name, sw.MTU, sw.VLAN)
+			}))
+			continue
+		}
+
+		if sw.MTU > 0 {
+			if p, err := iface.Get(port.Name); err == nil && p.MTU != sw.MTU {
+				steps = append(steps, commandStep(sw.Name, "set_port_mtu", "ifconfig", port.Name, "mtu", strconv.Itoa(sw.MTU)))
+			}
+		}
+	}
+
+	for _, m := range current.BridgeMembers {
+		if desired[m.Name] {
+			continue
+		}
+
+		member, err := iface.Get(m.Name)
+		if err != nil {
+			continue
+		}
+
+		name := m.Name
+		if strings.HasPrefix(member.Description, "svm-vlan/"+br+"/") {
+			steps = append(steps, funcStep(sw.Name, "remove_vlan_member", fmt.Sprintf("ifconfig %s deletem %s; ifconfig %s destroy", br, name, name), func() error {
+				if _, err := utils.RunCommand("ifconfig", br, "deletem", name); err != nil {
+					return err
+				}
+				_, err := utils.RunCommand("ifconfig", name, "destroy")
+				return err
+			}))
+			continue
+		}
+
+		if isGuestMember(name) {
+			continue
+		}
+
+		steps = append(steps, commandStep(sw.Name, "remove_member", "ifconfig", br, "deletem", name))
+	}
+
+	network4, gateway4 := sw.Network(4), sw.Gateway(4)
+	var want4 netip.Prefix
+
+	if network4 != "" && gateway4 != "" {
+		if p, err := netip.ParsePrefix(network4); err == nil {
+			want4 = p
+		}
+	}
+
+	/* Addresses are matched with their prefix so a netmask change is applied, leases are dhclient's business */
+	has4 := false
+	for _, a := range current.IPv4 {
+		ip, err := netip.ParseAddr(a.IP.String())
+		if err == nil && want4.IsValid() && ip.Unmap() == want4.Addr() && ipv4PrefixLen(a) == want4.Bits() {
+			has4 = true
+			continue
+		}
+
+		if !sw.DHCP {
+			steps = append(steps, commandStep(sw.Name, "remove_address", "ifconfig", br, "inet", a.IP.String(), "delete"))
+		}
+	}
+
+	if want4.IsValid() && !has4 {
+		steps = append(steps, commandStep(sw.Name, "add_address", "ifconfig", br, "inet", network4, "alias"))
+	}
+
+	/* A bare address gets ifconfig's default prefix, so only its address is compared */
+	var want6 netip.Addr
+	want6Bits := -1
+	if sw.Network(6) != "" && sw.Gateway(6) != "" && !sw.DisableIPv6 {
+		if p, err := netip.ParsePrefix(sw.IPv6()); err == nil {
+			want6, want6Bits = p.Addr(), p.Bits()
+		} else if a, err := netip.ParseAddr(sw.IPv6()); err == nil {
+			want6 = a
+		}
+	}
+
+	has6 := false
+	for _, a := range current.IPv6 {
+		ip, err := netip.ParseAddr(a.IP.String())
+		if err != nil || ip.IsLinkLocalUnicast() || a.AutoConf {
+			continue
+		}
+
+		if want6.IsValid() && ip == want6 && (want6Bits < 0 || a.PrefixLength == want6Bits) {
+			has6 = true
+			continue
+		}
+
+		steps = append(steps, commandStep(sw.Name, "remove_address6", "ifconfig", br, "inet6", ip.String(), "delete"))
+	}
+
+	if want6.IsValid() && !has6 {
+		steps = append(steps, commandStep(sw.Name, "add_address6", "ifconfig", br, "inet6", sw.IPv6(), "alias"))
+	}
+
+	if routes != nil {
+		want := standardSwitchRoutes(sw)
+		var last switchRoutes
+		if prev != nil {
+			last = *prev
+		}
+
+		steps = append(steps, planSwitchRoute(sw.Name, "route", routes, want.network4, last.network4)...)
+		steps = append(steps, planSwitchRoute(sw.Name, "default_route", routes, want.default4, last.default4)...)
+		steps = append(steps, planSwitchRoute(sw.Name, "route6", routes, want.network6, last.network6)...)
+	}
+
+	disabled := utils.Contains(current.ND6.Desc, "IFDISABLED")
+	rtadv := utils.Contains(current.ND6.Desc, "ACCEPT_RTADV")
+
+	if sw.DisableIPv6 && (!disabled || rtadv) {
+		steps = append(steps, commandStep(sw.Name, "disable_ipv6", "ifconfig", br, "inet6", "-accept_rtadv", "ifdisabled"))
+	} else if !sw.DisableIPv6 && sw.SLAAC && (disabled || !rtadv) {
+		steps = append(steps, commandStep(sw.Name, "enable_slaac", "ifconfig", br, "inet6", "auto_linklocal", "-ifdisabled", "accept_rtadv"))
+	}
+
+	if !utils.Contains(current.Flags.Desc, "UP") {
+		steps = append(steps, commandStep(sw.Name, "bring_up", "ifconfig", br, "up"))
+	}
+
+	if sw.DHCP && len(current.IPv4) == 0 {
+		steps = append(steps, funcStep(sw.Name, "start_dhclient", "dhclient -b "+br, func() error {
+			return runDhclient(br, 10)
+		}))
+	}
+
+	return steps
+}
+
+func (s *Service) loadStandardSwitches() ([]networkModels.StandardSwitch, error) {
+	var switches []networkModels.StandardSwitch
+	if err := s.DB.Preload("Ports").
+		Preload("NetworkObj.Entries").
+		Preload("Network6Obj.Entries").
+		Preload("Address6Obj.Entries").
+		Preload("GatewayAddressObj.Entries").
+		Preload("Gateway6AddressObj.Entries").
+		Find(&switches).Error; err != nil {
+		return nil, fmt.Errorf("db_error_checking_switches: %v", err)
+	}
+
+	return switches, nil
+}
+
+func (s *Service) planStandardSwitches() ([]switchStep, error) {
+	switches, err := s.loadStandardSwitches()
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := kernelRoutes(0)
+	if err != nil {
+		logger.L.Warn().Err(err).Msg("reconcile_standard_switches: skipping route checks")
+		routes = nil
+	}
+
+	applied, err := s.appliedSwitchRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []switchStep
+	for _, sw := range switches {
+		var prev *switchRoutes
+		if r, ok := applied[sw.ID]; ok {
+			prev = &r
+		}
+
+		steps = append(steps, planStandardSwitch(sw, routes, prev)...)
+	}
+
+	return steps, nil
+}
+
+// appliedSwitchRoutes loads the routes the last successful sync installed,
+// keyed by switch.
+func (s *Service) appliedSwitchRoutes() (map[uint]switchRoutes, error) {
+	var rows []networkModels.SwitchRoute
+	if err := s.DB.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_switch_routes: %w", err)
+	}
+
+	applied := make(map[uint]switchRoutes)
+	for _, row := range rows {
+		dest, err := netip.ParsePrefix(row.Destination)
+		if err != nil {
+			continue
+		}
+
+		r := applied[row.SwitchID]
+		if route, ok := r.byKind()[row.Kind]; ok {
+			*route = switchRoute{dest: dest, args: row.Args, gateway: row.Gateway}
+		}
+
+		applied[row.SwitchID] = r
+	}
+
+	return applied, nil
+}
+
+// recordSwitchRoutes stores the routes of every switch once a sync went
+// through, so later reconciles, also after a restart, can tell which kernel
+// routes Sylve owns.
+func (s *Service) recordSwitchRoutes() {
+	switches, err := s.loadStandardSwitches()
+	if err != nil {
+		logger.L.Warn().Err(err).Msg("reconcile_standard_switches: failed to record switch routes")
+		return
+	}
+
+	var rows []networkModels.SwitchRoute
+	for _, sw := range switches {
+		want := standardSwitchRoutes(sw)
+		for kind, route := range want.byKind() {
+			if !route.dest.IsValid() {
+				continue
+			}
+
+			rows = append(rows, networkModels.SwitchRoute{
+				SwitchID:    sw.ID,
+				Kind:        kind,
+				Destination: route.dest.String(),
+				Args:        route.args,
+				Gateway:     route.gateway,
+			})
+		}
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&networkModels.SwitchRoute{}).Error; err != nil {
+			return err
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+
+		return tx.Create(&rows).Error
+	})
+
+	if err != nil {
+		logger.L.Warn().Err(err).Msg("reconcile_standard_switches: failed to record switch routes")
+	}
+}
+
+// PlanStandardSwitches is the dry-run of a reconcile: it reports the changes
+// that would be applied without touching any interface.
+func (s *Service) PlanStandardSwitches() ([]networkServiceInterfaces.SwitchChange, error) {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	steps, err := s.planStandardSwitches()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]networkServiceInterfaces.SwitchChange, 0, len(steps))
+	for _, step := range steps {
+		changes = append(changes, step.change)
+	}
+
+	return changes, nil
+}
+
+func (s *Service) reconcileStandardSwitches() error {
+	steps, err := s.planStandardSwitches()
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		logger.L.Debug().Msgf("reconcile_standard_switches: %s: %s", step.change.Switch, step.change.Command)
+
+		if err := step.apply(); err != nil {
+			return fmt.Errorf("reconcile_standard_switches: %s: %s: %v", step.change.Switch, step.change.Action, err)
+		}
+	}
+
+	return nil
+}
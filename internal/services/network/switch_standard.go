@@ -93,6 +93,14 @@ func (s *Service) NewStandardSwitch(
 		return fmt.Errorf("invalid_vlan")
 	}
 
+	if err := s.rejectLaggMembers(ports); err != nil {
+		return err
+	}
+
+	if err := s.rejectWireGuardPorts(ports); err != nil {
+		return err
+	}
+
 	if conflicts, err := s.conflictingPortsForVLAN(ports, vlan, nil); err != nil {
 		return err
 	} else if len(conflicts) > 0 {
@@ -268,6 +276,50 @@ func (s *Service) DeleteStandardSwitch(id int) error {
 		return fmt.Errorf("switch_in_use_by_jail")
 	}
 
+	var fwCount int64
+	if err := s.DB.Model(&networkModels.FirewallRuleSet{}).
+		Where("switch_id = ?", id).
+		Count(&fwCount).Error; err != nil {
+		return fmt.Errorf("db_error_checking_firewall_switch: %v", err)
+	}
+
+	if fwCount > 0 {
+		return fmt.Errorf("switch_in_use_by_firewall")
+	}
+
+	var forwardCount int64
+	if err := s.DB.Model(&networkModels.PortForward{}).
+		Where("switch_id = ?", id).
+		Count(&forwardCount).Error; err != nil {
+		return fmt.Errorf("db_error_checking_port_forward_switch: %v", err)
+	}
+
+	if forwardCount > 0 {
+		return fmt.Errorf("switch_in_use_by_port_forward")
+	}
+
+	var dhcpCount int64
+	if err := s.DB.Table("dhcp_standard_switches").
+		Where("standard_switch_id = ?", id).
+		Count(&dhcpCount).Error; err != nil {
+		return fmt.Errorf("db_error_checking_dhcp_switch: %v", err)
+	}
+
+	if dhcpCount > 0 {
+		return fmt.Errorf("switch_in_use_by_dhcp")
+	}
+
+	var poolCount int64
+	if err := s.DB.Model(&networkModels.IPPool{}).
+		Where("switch_id = ? AND switch_type = ?", id, "standard").
+		Count(&poolCount).Error; err != nil {
+		return fmt.Errorf("db_error_checking_ip_pool_switch: %v", err)
+	}
+
+	if poolCount > 0 {
+		return fmt.Errorf("switch_in_use_by_ip_pool")
+	}
+
 	var oldSw networkModels.StandardSwitch
 
 	var sw networkModels.StandardSwitch
@@ -358,6 +410,14 @@ func (s *Service) EditStandardSwitch(
 		return fmt.Errorf("invalid_vlan")
 	}
 
+	if err := s.rejectLaggMembers(ports); err != nil {
+		return err
+	}
+
+	if err := s.rejectWireGuardPorts(ports); err != nil {
+		return err
+	}
+
 	if conflicts, err := s.conflictingPortsForVLAN(ports, vlan, &id); err != nil {
 		return err
 	} else if len(conflicts) > 0 {
@@ -457,6 +517,21 @@ func (s *Service) EditStandardSwitch(
 
 	before := loaded
 
+	if !private {
+		var forwardCount int64
+		if err := s.DB.Model(&networkModels.PortForward{}).
+			Where("switch_id = ?", id).
+			Count(&forwardCount).Error; err != nil {
+			return fmt.Errorf("db_error_checking_port_forward_switch: %v", err)
+		}
+
+		if forwardCount > 0 {
+			return fmt.Errorf("switch_has_port_forwards")
+		}
+
+		loaded.NAT = false
+	}
+
 	loaded.MTU = mtu
 	loaded.VLAN = vlan
 	loaded.Private = private
@@ -491,7 +566,7 @@ func (s *Service) EditStandardSwitch(
 	loaded.DefaultRoute = defaultRoute
 
 	if err := s.DB.Model(&loaded).
-		Select("MTU", "VLAN", "Private", "DHCP", "DisableIPv6", "SLAAC", "NetworkID", "GatewayAddressID", "Network6ID", "Gateway6AddressID", "DefaultRoute").
+		Select("MTU", "VLAN", "Private", "NAT", "DHCP", "DisableIPv6", "SLAAC", "NetworkID", "GatewayAddressID", "Network6ID", "Gateway6AddressID", "DefaultRoute").
 		Updates(loaded).Error; err != nil {
 		return fmt.Errorf("failed_to_update_switch: %v", err)
 	}
@@ -589,6 +664,11 @@ func (s *Service) SyncStandardSwitches(sw *networkModels.StandardSwitch, action
 			}
 		}
 
+	case "reconcile":
+		if err := s.reconcileStandardSwitches(); err != nil {
+			return err
+		}
+
 	case "create":
 		if err := createStandardBridge(*sw); err != nil {
 			return err
@@ -614,6 +694,20 @@ func (s *Service) SyncStandardSwitches(sw *networkModels.StandardSwitch, action
 		}
 	}
 
+	s.recordSwitchRoutes()
+
+	if err := s.SyncNAT(); err != nil {
+		logger.L.Warn().Err(err).Msg("sync_standard_switches: failed to sync nat rules")
+	}
+
+	if err := s.SyncDHCP(); err != nil {
+		logger.L.Warn().Err(err).Msg("sync_standard_switches: failed to sync dhcp config")
+	}
+
+	if err := s.SyncStaticRoutes(); err != nil {
+		logger.L.Warn().Err(err).Msg("sync_standard_switches: failed to sync static routes")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,793 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemillahq/sylve/internal/config"
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/crypto"
+	"github.com/alchemillahq/sylve/pkg/network/iface"
+	"github.com/alchemillahq/sylve/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// defaultWireGuardMTU is what if_wg creates interfaces with, an MTU of 0 on
+// an interface means this default.
+const defaultWireGuardMTU = 1420
+
+func (s *Service) GetWireGuardInterfaces() ([]networkModels.WireGuardInterface, error) {
+	var wgs []networkModels.WireGuardInterface
+
+	err := s.DB.
+		Preload("AddressObj.Entries").
+		Preload("Address6Obj.Entries").
+		Preload("Peers.AllowedIPs").
+		Order("id ASC").
+		Find(&wgs).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_get_wireguard_interfaces: %w", err)
+	}
+
+	for i := range wgs {
+		if !wgs[i].Enabled {
+			continue
+		}
+
+		stats, err := wireGuardPeerStats(wgs[i].IfName)
+		if err != nil {
+			logger.L.Debug().Err(err).Msgf("failed to read wireguard stats for %s", wgs[i].IfName)
+			continue
+		}
+
+		for j := range wgs[i].Peers {
+			if st, ok := stats[wgs[i].Peers[j].PublicKey]; ok {
+				wgs[i].Peers[j].LiveEndpoint = st.LiveEndpoint
+				wgs[i].Peers[j].LatestHandshake = st.LatestHandshake
+				wgs[i].Peers[j].RxBytes = st.RxBytes
+				wgs[i].Peers[j].TxBytes = st.TxBytes
+			}
+		}
+	}
+
+	return wgs, nil
+}
+
+// wireGuardPeerStats parses `wg show <if> dump`, keyed by peer public key.
+func wireGuardPeerStats(ifName string) (map[string]networkModels.WireGuardPeer, error) {
+	output, err := utils.RunCommand("wg", "show", ifName, "dump")
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]networkModels.WireGuardPeer)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+
+	// The first line describes the interface itself
+	for _, line := range lines[min(1, len(lines)):] {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 8 {
+			continue
+		}
+
+		peer := networkModels.WireGuardPeer{PublicKey: fields[0]}
+		if fields[2] != "(none)" {
+			peer.LiveEndpoint = fields[2]
+		}
+
+		if ts, err := strconv.ParseInt(fields[4], 10, 64); err == nil && ts > 0 {
+			t := time.Unix(ts, 0)
+			peer.LatestHandshake = &t
+		}
+
+		peer.RxBytes, _ = strconv.ParseUint(fields[5], 10, 64)
+		peer.TxBytes, _ = strconv.ParseUint(fields[6], 10, 64)
+
+		stats[peer.PublicKey] = peer
+	}
+
+	return stats, nil
+}
+
+func (s *Service) validateWireGuardInterface(wg networkModels.WireGuardInterface, id uint) error {
+	if wg.Name == "" {
+		return fmt.Errorf("wireguard_name_required")
+	}
+
+	if wg.ListenPort != 0 && !utils.IsValidPort(wg.ListenPort) {
+		return fmt.Errorf("invalid_listen_port")
+	}
+
+	if wg.MTU != 0 && !utils.IsValidMTU(wg.MTU) {
+		return fmt.Errorf("invalid_mtu")
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.WireGuardInterface{}).
+		Where("name = ? AND id != ?", wg.Name, id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return fmt.Errorf("wireguard_name_already_exists")
+	}
+
+	if wg.ListenPort != 0 {
+		if err := s.DB.Model(&networkModels.WireGuardInterface{}).
+			Where("listen_port = ? AND id != ?", wg.ListenPort, id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return fmt.Errorf("wireguard_listen_port_in_use")
+		}
+
+		/* An unchanged port is bound by the interface itself */
+		var current networkModels.WireGuardInterface
+		if id == 0 || s.DB.First(&current, id).Error != nil || current.ListenPort != wg.ListenPort {
+			if utils.IsPortBound(wg.ListenPort) {
+				return fmt.Errorf("wireguard_listen_port_bound")
+			}
+		}
+	}
+
+	if wg.AddressID != nil {
+		addr, err := s.singleEntryObject(*wg.AddressID, "Network")
+		if err != nil {
+			return err
+		}
+
+		if !utils.IsValidIPv4CIDR(addr) {
+			return fmt.Errorf("wireguard_address_must_be_ipv4")
+		}
+	}
+
+	if wg.Address6ID != nil {
+		addr, err := s.singleEntryObject(*wg.Address6ID, "Network")
+		if err != nil {
+			return err
+		}
+
+		if !utils.IsValidIPv6CIDR(addr) {
+			return fmt.Errorf("wireguard_address6_must_be_ipv6")
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) CreateWireGuardInterface(wg networkModels.WireGuardInterface) error {
+	if err := s.validateWireGuardInterface(wg, 0); err != nil {
+		return err
+	}
+
+	if wg.PrivateKey == "" {
+		priv, pub, err := crypto.GenerateWireGuardKey()
+		if err != nil {
+			return fmt.Errorf("failed_to_generate_wireguard_key: %w", err)
+		}
+		wg.PrivateKey, wg.PublicKey = priv, pub
+	} else {
+		pub, err := crypto.WireGuardPublicKey(wg.PrivateKey)
+		if err != nil {
+			return err
+		}
+		wg.PublicKey = pub
+	}
+
+	enabled := wg.Enabled
+	wg.ID = 0
+	wg.IfName = utils.ShortHash("wg-" + wg.Name)
+	wg.Peers = nil
+
+	if err := s.DB.Create(&wg).Error; err != nil {
+		return fmt.Errorf("failed_to_create_wireguard_interface: %w", err)
+	}
+
+	if !enabled {
+		if err := s.DB.Model(&wg).Update("enabled", false).Error; err != nil {
+			return fmt.Errorf("failed_to_create_wireguard_interface: %w", err)
+		}
+	}
+
+	return s.syncWireGuardInterface(wg.ID)
+}
+
+func (s *Service) EditWireGuardInterface(id uint, wg networkModels.WireGuardInterface) error {
+	var existing networkModels.WireGuardInterface
+	if err := s.DB.First(&existing, id).Error; err != nil {
+		return fmt.Errorf("wireguard_interface_not_found")
+	}
+
+	if err := s.validateWireGuardInterface(wg, id); err != nil {
+		return err
+	}
+
+	if !wg.Enabled {
+		if err := s.isWireGuardInUse(existing); err != nil {
+			return err
+		}
+	}
+
+	resetMTU := existing.MTU != 0 && wg.MTU == 0
+
+	err := s.DB.Model(&existing).
+		Select("Name", "Enabled", "ListenPort", "MTU", "AddressID", "Address6ID").
+		Updates(networkModels.WireGuardInterface{
+			Name:       wg.Name,
+			Enabled:    wg.Enabled,
+			ListenPort: wg.ListenPort,
+			MTU:        wg.MTU,
+			AddressID:  wg.AddressID,
+			Address6ID: wg.Address6ID,
+		}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed_to_update_wireguard_interface: %w", err)
+	}
+
+	if err := s.syncWireGuardInterface(id); err != nil {
+		return err
+	}
+
+	/* syncWireGuardInterface leaves the MTU alone when none is set, so going back to the default has to be done here */
+	if resetMTU && wg.Enabled {
+		if _, err := utils.RunCommand("ifconfig", existing.IfName, "mtu", strconv.Itoa(defaultWireGuardMTU)); err != nil {
+			return fmt.Errorf("failed_to_reset_wireguard_mtu: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) RotateWireGuardKey(id uint) error {
+	var existing networkModels.WireGuardInterface
+	if err := s.DB.First(&existing, id).Error; err != nil {
+		return fmt.Errorf("wireguard_interface_not_found")
+	}
+
+	priv, pub, err := crypto.GenerateWireGuardKey()
+	if err != nil {
+		return fmt.Errorf("failed_to_generate_wireguard_key: %w", err)
+	}
+
+	if err := s.DB.Model(&existing).Updates(map[string]any{
+		"private_key": priv,
+		"public_key":  pub,
+	}).Error; err != nil {
+		return fmt.Errorf("failed_to_rotate_wireguard_key: %w", err)
+	}
+
+	return s.syncWireGuardInterface(id)
+}
+
+func (s *Service) isWireGuardInUse(wg networkModels.WireGuardInterface) error {
+	var count int64
+	if err := s.DB.Model(&networkModels.StaticRoute{}).Where("interface = ?", wg.IfName).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return fmt.Errorf("wireguard_in_use_by_route")
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteWireGuardInterface(id uint) error {
+	var existing networkModels.WireGuardInterface
+	if err := s.DB.Preload("Peers").First(&existing, id).Error; err != nil {
+		return fmt.Errorf("wireguard_interface_not_found")
+	}
+
+	if err := s.isWireGuardInUse(existing); err != nil {
+		return err
+	}
+
+	if err := destroyWireGuardInterface(existing.IfName); err != nil {
+		return err
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		for _, peer := range existing.Peers {
+			if err := tx.Model(&peer).Association("AllowedIPs").Clear(); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("interface_id = ?", id).Delete(&networkModels.WireGuardPeer{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&existing).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed_to_delete_wireguard_interface: %w", err)
+	}
+
+	if path, err := wireGuardConfigPath(existing.IfName); err == nil {
+		os.Remove(path)
+	}
+
+	return nil
+}
+
+func (s *Service) validateWireGuardPeer(peer networkModels.WireGuardPeer, allowedIPs []uint, id uint) error {
+	if peer.Name == "" {
+		return fmt.Errorf("wireguard_peer_name_required")
+	}
+
+	if !crypto.IsValidWireGuardKey(peer.PublicKey) {
+		return fmt.Errorf("invalid_wireguard_public_key")
+	}
+
+	if peer.PresharedKey != "" && !crypto.IsValidWireGuardKey(peer.PresharedKey) {
+		return fmt.Errorf("invalid_wireguard_preshared_key")
+	}
+
+	if peer.PersistentKeepalive < 0 || peer.PersistentKeepalive > 65535 {
+		return fmt.Errorf("invalid_persistent_keepalive")
+	}
+
+	if peer.Endpoint != "" {
+		host, port, err := net.SplitHostPort(peer.Endpoint)
+		if err != nil || host == "" {
+			return fmt.Errorf("invalid_wireguard_endpoint")
+		}
+
+		p, err := strconv.Atoi(port)
+		if err != nil || !utils.IsValidPort(p) {
+			return fmt.Errorf("invalid_wireguard_endpoint_port")
+		}
+
+		if !utils.IsValidIP(host) && !utils.IsValidFQDN(host) {
+			return fmt.Errorf("invalid_wireguard_endpoint_host")
+		}
+	}
+
+	var wg networkModels.WireGuardInterface
+	if err := s.DB.First(&wg, peer.InterfaceID).Error; err != nil {
+		return fmt.Errorf("wireguard_interface_not_found")
+	}
+
+	if peer.PublicKey == wg.PublicKey {
+		return fmt.Errorf("wireguard_peer_key_matches_interface")
+	}
+
+	var count int64
+	if err := s.DB.Model(&networkModels.WireGuardPeer{}).
+		Where("interface_id = ? AND public_key = ? AND id != ?", peer.InterfaceID, peer.PublicKey, id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return fmt.Errorf("wireguard_peer_already_exists")
+	}
+
+	for _, objID := range allowedIPs {
+		var object networkModels.Object
+		if err := s.DB.First(&object, objID).Error; err != nil {
+			return fmt.Errorf("object_not_found: %d", objID)
+		}
+
+		if !slices.Contains(firewallAddressTypes, object.Type) {
+			return fmt.Errorf("object_%s_cannot_be_allowed_ips", object.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) CreateWireGuardPeer(peer networkModels.WireGuardPeer, allowedIPs []uint) error {
+	if err := s.validateWireGuardPeer(peer, allowedIPs, 0); err != nil {
+		return err
+	}
+
+	enabled := peer.Enabled
+	peer.ID = 0
+	peer.AllowedIPs = nil
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&peer).Error; err != nil {
+			return err
+		}
+
+		if !enabled {
+			if err := tx.Model(&peer).Update("enabled", false).Error; err != nil {
+				return err
+			}
+		}
+
+		return replacePeerAllowedIPs(tx, &peer, allowedIPs)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed_to_create_wireguard_peer: %w", err)
+	}
+
+	return s.syncWireGuardInterface(peer.InterfaceID)
+}
+
+// EditWireGuardPeer updates a peer, an empty preshared key keeps the stored
+// one unless clearPresharedKey is set.
+func (s *Service) EditWireGuardPeer(id uint, peer networkModels.WireGuardPeer, allowedIPs []uint, clearPresharedKey bool) error {
+	var existing networkModels.WireGuardPeer
+	if err := s.DB.First(&existing, id).Error; err != nil {
+		return fmt.Errorf("wireguard_peer_not_found")
+	}
+
+	peer.InterfaceID = existing.InterfaceID
+
+	if clearPresharedKey {
+		if peer.PresharedKey != "" {
+			return fmt.Errorf("preshared_key_set_while_clearing")
+		}
+	} else if peer.PresharedKey == "" {
+		peer.PresharedKey = existing.PresharedKey
+	}
+
+	if err := s.validateWireGuardPeer(peer, allowedIPs, id); err != nil {
+		return err
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&existing).
+			Select("Name", "Enabled", "PublicKey", "PresharedKey", "Endpoint", "PersistentKeepalive").
+			Updates(networkModels.WireGuardPeer{
+				Name:                peer.Name,
+				Enabled:             peer.Enabled,
+				PublicKey:           peer.PublicKey,
+				PresharedKey:        peer.PresharedKey,
+				Endpoint:            peer.Endpoint,
+				PersistentKeepalive: peer.PersistentKeepalive,
+			}).Error
+
+		if err != nil {
+			return err
+		}
+
+		return replacePeerAllowedIPs(tx, &existing, allowedIPs)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed_to_update_wireguard_peer: %w", err)
+	}
+
+	return s.syncWireGuardInterface(existing.InterfaceID)
+}
+
+func (s *Service) DeleteWireGuardPeer(id uint) error {
+	var existing networkModels.WireGuardPeer
+	if err := s.DB.First(&existing, id).Error; err != nil {
+		return fmt.Errorf("wireguard_peer_not_found")
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&existing).Association("AllowedIPs").Clear(); err != nil {
+			return err
+		}
+
+		return tx.Delete(&existing).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed_to_delete_wireguard_peer: %w", err)
+	}
+
+	return s.syncWireGuardInterface(existing.InterfaceID)
+}
+
+func replacePeerAllowedIPs(tx *gorm.DB, peer *networkModels.WireGuardPeer, ids []uint) error {
+	var objects []networkModels.Object
+	if len(ids) > 0 {
+		if err := tx.Where("id IN ?", ids).Find(&objects).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(peer).Association("AllowedIPs").Replace(objects)
+}
+
+func (s *Service) isObjectUsedByWireGuard(id uint) (bool, error) {
+	var peers []networkModels.WireGuardPeer
+	if err := s.DB.Select("id").Preload("AllowedIPs", "id = ?", id).Find(&peers).Error; err != nil {
+		return false, fmt.Errorf("failed to check wireguard peers using object %d: %w", id, err)
+	}
+
+	if slices.ContainsFunc(peers, func(p networkModels.WireGuardPeer) bool { return len(p.AllowedIPs) > 0 }) {
+		return true, nil
+	}
+
+	var count int64
+	err := s.DB.Model(&networkModels.WireGuardInterface{}).
+		Where("address_object_id = ? OR address6_object_id = ?", id, id).
+		Count(&count).Error
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check wireguard interfaces using object %d: %w", id, err)
+	}
+
+	return count > 0, nil
+}
+
+func wireGuardConfigPath(ifName string) (string, error) {
+	dir, err := config.GetWireGuardPath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, ifName+".conf"), nil
+}
+
+func renderWireGuard(wg networkModels.WireGuardInterface) string {
+	var sb strings.Builder
+
+	sb.WriteString("[Interface]\n")
+	sb.WriteString(fmt.Sprintf("PrivateKey = %s\n", wg.PrivateKey))
+	if wg.ListenPort != 0 {
+		sb.WriteString(fmt.Sprintf("ListenPort = %d\n", wg.ListenPort))
+	}
+
+	for _, peer := range wg.Peers {
+		if !peer.Enabled {
+			continue
+		}
+
+		var allowed []string
+		for _, object := range peer.AllowedIPs {
+			for _, addr := range objectAddresses(object) {
+				if prefix, err := netip.ParsePrefix(addr); err == nil {
+					addr = prefix.Masked().String()
+				}
+
+				if !slices.Contains(allowed, addr) {
+					allowed = append(allowed, addr)
+				}
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("\n# %s\n[Peer]\n", peer.Name))
+		sb.WriteString(fmt.Sprintf("PublicKey = %s\n", peer.PublicKey))
+		if peer.PresharedKey != "" {
+			sb.WriteString(fmt.Sprintf("PresharedKey = %s\n", peer.PresharedKey))
+		}
+		if len(allowed) > 0 {
+			sb.WriteString(fmt.Sprintf("AllowedIPs = %s\n", strings.Join(allowed, ", ")))
+		}
+		if peer.Endpoint != "" {
+			sb.WriteString(fmt.Sprintf("Endpoint = %s\n", peer.Endpoint))
+		}
+		if peer.PersistentKeepalive > 0 {
+			sb.WriteString(fmt.Sprintf("PersistentKeepalive = %d\n", peer.PersistentKeepalive))
+		}
+	}
+
+	return sb.String()
+}
+
+func destroyWireGuardInterface(ifName string) error {
+	if _, err := iface.Get(ifName); err != nil {
+		return nil
+	}
+
+	if _, err := utils.RunCommand("ifconfig", ifName, "destroy"); err != nil {
+		return fmt.Errorf("failed_to_destroy_wireguard_interface: %w", err)
+	}
+
+	return nil
+}
+
+// syncWireGuardInterface creates or updates the kernel interface and applies
+// the peer configuration with `wg syncconf`, which keeps existing sessions.
+func (s *Service) syncWireGuardInterface(id uint) error {
+	var wg networkModels.WireGuardInterface
+	err := s.DB.
+		Preload("AddressObj.Entries").
+		Preload("Address6Obj.Entries").
+		Preload("Peers.AllowedIPs.Entries").
+		Preload("Peers.AllowedIPs.Resolutions").
+		First(&wg, id).Error
+
+	if err != nil {
+		return fmt.Errorf("wireguard_interface_not_found")
+	}
+
+	if !wg.Enabled {
+		return destroyWireGuardInterface(wg.IfName)
+	}
+
+	current, err := iface.Get(wg.IfName)
+	if err != nil {
+		if _, err := utils.RunCommand("kldload", "-n", "if_wg"); err != nil {
+			return fmt.Errorf("failed_to_load_if_wg: %w", err)
+		}
+
+		raw, err := utils.RunCommand("ifconfig", "wg", "create")
+		if err != nil {
+			return fmt.Errorf("failed_to_create_wireguard_interface: %w", err)
+		}
+
+		raw = strings.TrimSpace(raw)
+		if _, err := utils.RunCommand("ifconfig", raw, "name", wg.IfName); err != nil {
+			utils.RunCommand("ifconfig", raw, "destroy")
+			return fmt.Errorf("failed_to_rename_wireguard_interface: %w", err)
+		}
+	}
+
+	if _, err := utils.RunCommand("ifconfig", wg.IfName, "descr", wg.Name); err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to set description on %s", wg.IfName)
+	}
+
+	var want []netip.Prefix
+	for _, obj := range []*networkModels.Object{wg.AddressObj, wg.Address6Obj} {
+		if obj == nil || len(obj.Entries) == 0 {
+			continue
+		}
+
+		if prefix, err := netip.ParsePrefix(obj.Entries[0].Value); err == nil {
+			want = append(want, prefix)
+		}
+	}
+
+	/* Addresses left over from a previous object or value are withdrawn before the new ones go on */
+	present := make(map[netip.Prefix]bool)
+	if current != nil {
+		for _, a := range current.IPv4 {
+			ip, err := netip.ParseAddr(a.IP.String())
+			if err != nil {
+				continue
+			}
+
+			prefix := netip.PrefixFrom(ip.Unmap(), ipv4PrefixLen(a))
+			if slices.Contains(want, prefix) {
+				present[prefix] = true
+				continue
+			}
+
+			if _, err := utils.RunCommand("ifconfig", wg.IfName, "inet", ip.Unmap().String(), "delete"); err != nil {
+				return fmt.Errorf("failed_to_remove_wireguard_address: %w", err)
+			}
+		}
+
+		for _, a := range current.IPv6 {
+			ip, err := netip.ParseAddr(a.IP.String())
+			if err != nil || ip.IsLinkLocalUnicast() || a.AutoConf {
+				continue
+			}
+
+			prefix := netip.PrefixFrom(ip.WithZone(""), a.PrefixLength)
+			if slices.Contains(want, prefix) {
+				present[prefix] = true
+				continue
+			}
+
+			if _, err := utils.RunCommand("ifconfig", wg.IfName, "inet6", prefix.Addr().String(), "delete"); err != nil {
+				return fmt.Errorf("failed_to_remove_wireguard_address: %w", err)
+			}
+		}
+	}
+
+	for _, prefix := range want {
+		if present[prefix] {
+			continue
+		}
+
+		family := "inet"
+		if prefix.Addr().Is6() {
+			family = "inet6"
+		}
+
+		if _, err := utils.RunCommand("ifconfig", wg.IfName, family, prefix.String(), "alias"); err != nil {
+			return fmt.Errorf("failed_to_set_wireguard_address: %w", err)
+		}
+	}
+
+	if wg.MTU != 0 {
+		if _, err := utils.RunCommand("ifconfig", wg.IfName, "mtu", strconv.Itoa(wg.MTU)); err != nil {
+			return fmt.Errorf("failed_to_set_wireguard_mtu: %w", err)
+		}
+	}
+
+	path, err := wireGuardConfigPath(wg.IfName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(renderWireGuard(wg)), 0600); err != nil {
+		return fmt.Errorf("failed_to_write_wireguard_config: %w", err)
+	}
+
+	if _, err := utils.RunCommand("wg", "syncconf", wg.IfName, path); err != nil {
+		return fmt.Errorf("failed_to_apply_wireguard_config: %w", err)
+	}
+
+	if _, err := utils.RunCommand("ifconfig", wg.IfName, "up"); err != nil {
+		return fmt.Errorf("failed_to_bring_up_wireguard_interface: %w", err)
+	}
+
+	return nil
+}
+
+// syncWireGuardUsingObject re-applies every WireGuard interface whose
+// addresses or peer allowed IPs come from the given object.
+func (s *Service) syncWireGuardUsingObject(id uint) {
+	var ids []uint
+	err := s.DB.Model(&networkModels.WireGuardPeer{}).
+		Distinct("interface_id").
+		Joins("JOIN wireguard_peer_allowed_ips ON wireguard_peer_allowed_ips.wire_guard_peer_id = wire_guard_peers.id").
+		Where("wireguard_peer_allowed_ips.object_id = ?", id).
+		Pluck("interface_id", &ids).Error
+
+	if err != nil {
+		logger.L.Warn().Err(err).Msgf("failed to find wireguard peers using object %d", id)
+		return
+	}
+
+	var direct []uint
+	if err := s.DB.Model(&networkModels.WireGuardInterface{}).
+		Where("address_object_id = ? OR address6_object_id = ?", id, id).
+		Pluck("id", &direct).Error; err == nil {
+		ids = append(ids, direct...)
+	}
+
+	slices.Sort(ids)
+	for _, wgID := range slices.Compact(ids) {
+		if err := s.syncWireGuardInterface(wgID); err != nil {
+			logger.L.Warn().Err(err).Msgf("failed to sync wireguard interface %d after object %d changed", wgID, id)
+		}
+	}
+}
+
+// SyncWireGuard brings up all WireGuard interfaces. It runs before switches
+// and static routes are synced so routes can use them as gateways.
+func (s *Service) SyncWireGuard() error {
+	var ids []uint
+	if err := s.DB.Model(&networkModels.WireGuardInterface{}).Pluck("id", &ids).Error; err != nil {
+		return fmt.Errorf("failed_to_get_wireguard_interfaces: %w", err)
+	}
+
+	for _, id := range ids {
+		if err := s.syncWireGuardInterface(id); err != nil {
+			logger.L.Error().Err(err).Msgf("failed to sync wireguard interface %d", id)
+		}
+	}
+
+	return nil
+}
+
+// rejectWireGuardPorts refuses WireGuard interfaces as bridge members; they
+// are layer 3 tunnels and if_bridge only accepts Ethernet interfaces.
+func (s *Service) rejectWireGuardPorts(ports []string) error {
+	var count int64
+	if err := s.DB.Model(&networkModels.WireGuardInterface{}).Where("if_name IN ?", ports).Count(&count).Error; err != nil {
+		return fmt.Errorf("db_error_checking_wireguard: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("wireguard_interface_cannot_be_bridged_use_static_route")
+	}
+
+	return nil
+}
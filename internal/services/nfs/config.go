@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package nfs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	nfsModels "github.com/alchemillahq/sylve/internal/db/models/nfs"
+	nfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/nfs"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/system"
+	"github.com/alchemillahq/sylve/pkg/utils"
+)
+
+func (s *Service) GetSettings() (nfsModels.NFSSettings, error) {
+	var settings nfsModels.NFSSettings
+	if err := s.DB.First(&settings).Error; err != nil {
+		return nfsModels.NFSSettings{}, fmt.Errorf("failed_to_get_nfs_settings: %w", err)
+	}
+	return settings, nil
+}
+
+func (s *Service) SetSettings(req nfsServiceInterfaces.Settings) error {
+	if req.V4Only && !req.V4Enabled {
+		return fmt.Errorf("v4_only_requires_v4_enabled")
+	}
+
+	if req.V4Root == "" {
+		req.V4Root = "/"
+	}
+
+	if !filepath.IsAbs(req.V4Root) || strings.ContainsAny(req.V4Root, " \t\n") {
+		return fmt.Errorf("invalid_v4_root")
+	}
+
+	security, err := normalizeSecurity(req.V4Security)
+	if err != nil {
+		return err
+	}
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	settings.Enabled = req.Enabled
+	settings.V4Enabled = req.V4Enabled
+	settings.V4Only = req.V4Only
+	settings.V4Root = filepath.Clean(req.V4Root)
+	settings.V4Security = security
+
+	if err := s.DB.Save(&settings).Error; err != nil {
+		return fmt.Errorf("failed_to_update_nfs_settings: %w", err)
+	}
+
+	if err := s.WriteExports(false); err != nil {
+		return err
+	}
+
+	return s.ApplyServices(true)
+}
+
+func normalizeSecurity(flavors []string) ([]string, error) {
+	if len(flavors) == 0 {
+		return []string{nfsModels.SecuritySys}, nil
+	}
+
+	valid := []string{
+		nfsModels.SecuritySys,
+		nfsModels.SecurityKrb5,
+		nfsModels.SecurityKrb5i,
+		nfsModels.SecurityKrb5p,
+	}
+
+	flavors = utils.RemoveDuplicates(flavors)
+	for _, flavor := range flavors {
+		if !utils.StringInSlice(flavor, valid) {
+			return nil, fmt.Errorf("invalid_security_flavor: %s", flavor)
+		}
+	}
+
+	return flavors, nil
+}
+
+// ApplyServices writes the NFS related rc.conf knobs and brings nfsd,
+// mountd and nfsuserd in line with the settings. Without restart, services
+// that are already running are left alone so clients are not disturbed.
+func (s *Service) ApplyServices(restart bool) error {
+	settings, err := s.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	// Left disabled, an NFS server someone set up by hand keeps running
+	// until it is explicitly turned off from here
+	if !settings.Enabled && !restart {
+		return nil
+	}
+
+	v4 := settings.Enabled && settings.V4Enabled
+
+	knobs := []struct {
+		key     string
+		enabled bool
+	}{
+		{"nfs_server_enable", settings.Enabled},
+		{"mountd_enable", settings.Enabled},
+		{"nfsv4_server_enable", v4},
+		{"nfsv4_server_only", v4 && settings.V4Only},
+		{"nfsuserd_enable", v4},
+	}
+
+	for _, knob := range knobs {
+		value := "NO"
+		if knob.enabled {
+			value = "YES"
+		}
+
+		if _, err := utils.RunCommand("sysrc", fmt.Sprintf("%s=%s", knob.key, value)); err != nil {
+			return fmt.Errorf("failed_to_set_rc_conf_%s: %w", knob.key, err)
+		}
+	}
+
+	if !settings.Enabled {
+		for _, service := range []string{"nfsd", "mountd", "nfsuserd"} {
+			if !serviceRunning(service) {
+				continue
+			}
+
+			if err := system.ServiceAction(service, "onestop"); err != nil {
+				logger.L.Warn().Err(err).Msgf("Failed to stop %s", service)
+			}
+		}
+
+		return nil
+	}
+
+	services := []string{"mountd", "nfsd"}
+	if v4 {
+		services = []string{"nfsuserd", "mountd", "nfsd"}
+	} else if serviceRunning("nfsuserd") {
+		if err := system.ServiceAction("nfsuserd", "onestop"); err != nil {
+			logger.L.Warn().Err(err).Msg("Failed to stop nfsuserd")
+		}
+	}
+
+	for _, service := range services {
+		action := "start"
+		if serviceRunning(service) {
+			if !restart {
+				continue
+			}
+			action = "restart"
+		}
+
+		if err := system.ServiceAction(service, action); err != nil {
+			return fmt.Errorf("failed_to_%s_%s: %w", action, service, err)
+		}
+	}
+
+	return nil
+}
+
+func serviceRunning(service string) bool {
+	_, err := utils.RunCommand("service", service, "onestatus")
+	return err == nil
+}
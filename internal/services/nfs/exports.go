@@ -0,0 +1,485 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package nfs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	networkModels "github.com/alchemillahq/sylve/internal/db/models/network"
+	nfsModels "github.com/alchemillahq/sylve/internal/db/models/nfs"
+	nfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/nfs"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/system"
+	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+const (
+	ExportsPath   = "/etc/exports"
+	ExportsHeader = "# === This file is automatically generated by Sylve, don't edit! ==="
+)
+
+// maproot and mapall take a user optionally followed by groups, each either
+// a name or a numeric id (-2 being the traditional nobody).
+var credentialRe = regexp.MustCompile(`^-?[A-Za-z0-9_][A-Za-z0-9_.-]*(:-?[A-Za-z0-9_][A-Za-z0-9_.-]*)*$`)
+
+func (s *Service) GetExports() ([]nfsModels.NFSExport, error) {
+	var exports []nfsModels.NFSExport
+	if err := s.DB.Preload("Networks").Preload("Networks.Entries").Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("failed_to_get_exports: %w", err)
+	}
+	return exports, nil
+}
+
+func (s *Service) validateExport(req nfsServiceInterfaces.Export) ([]networkModels.Object, []string, error) {
+	if req.MapRoot != "" && req.MapAll != "" {
+		return nil, nil, fmt.Errorf("maproot_and_mapall_are_exclusive")
+	}
+
+	if req.MapRoot != "" && !credentialRe.MatchString(req.MapRoot) {
+		return nil, nil, fmt.Errorf("invalid_maproot: %s", req.MapRoot)
+	}
+
+	if req.MapAll != "" && !credentialRe.MatchString(req.MapAll) {
+		return nil, nil, fmt.Errorf("invalid_mapall: %s", req.MapAll)
+	}
+
+	security, err := normalizeSecurity(req.Security)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataset, err := findFilesystem(req.Dataset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if dataset == nil {
+		return nil, nil, fmt.Errorf("dataset_not_found")
+	}
+
+	if !mounted(dataset) {
+		return nil, nil, fmt.Errorf("dataset_not_mounted")
+	}
+
+	if len(req.Networks) == 0 {
+		return nil, nil, fmt.Errorf("no_networks_selected")
+	}
+
+	var objects []networkModels.Object
+	if err := s.DB.Where("id IN ?", req.Networks).Find(&objects).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed_to_get_network_objects: %w", err)
+	}
+
+	if len(objects) != len(req.Networks) {
+		return nil, nil, fmt.Errorf("network_object_not_found")
+	}
+
+	for _, obj := range objects {
+		if obj.Type != "Host" && obj.Type != "Network" && obj.Type != "FQDN" {
+			return nil, nil, fmt.Errorf("invalid_network_object_type: %s", obj.Name)
+		}
+	}
+
+	return objects, security, nil
+}
+
+func (s *Service) CreateExport(req nfsServiceInterfaces.Export) error {
+	var count int64
+	if err := s.DB.Model(&nfsModels.NFSExport{}).Where("dataset = ?", req.Dataset).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed_to_check_dataset_conflict: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("export_with_dataset_exists")
+	}
+
+	objects, security, err := s.validateExport(req)
+	if err != nil {
+		return err
+	}
+
+	export := nfsModels.NFSExport{
+		Dataset:  req.Dataset,
+		Networks: objects,
+		ReadOnly: req.ReadOnly,
+		MapRoot:  req.MapRoot,
+		MapAll:   req.MapAll,
+		Security: security,
+		AllDirs:  req.AllDirs,
+		Comment:  req.Comment,
+	}
+
+	if err := s.DB.Create(&export).Error; err != nil {
+		return fmt.Errorf("failed_to_create_export: %w", err)
+	}
+
+	return s.WriteExports(true)
+}
+
+func (s *Service) UpdateExport(id uint, req nfsServiceInterfaces.Export) error {
+	var export nfsModels.NFSExport
+	if err := s.DB.First(&export, id).Error; err != nil {
+		return fmt.Errorf("export_not_found: %w", err)
+	}
+
+	if req.Dataset != export.Dataset {
+		var count int64
+		if err := s.DB.Model(&nfsModels.NFSExport{}).
+			Where("dataset = ? AND id != ?", req.Dataset, id).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed_to_check_dataset_conflict: %w", err)
+		}
+
+		if count > 0 {
+			return fmt.Errorf("export_with_dataset_exists")
+		}
+	}
+
+	objects, security, err := s.validateExport(req)
+	if err != nil {
+		return err
+	}
+
+	export.Dataset = req.Dataset
+	export.ReadOnly = req.ReadOnly
+	export.MapRoot = req.MapRoot
+	export.MapAll = req.MapAll
+	export.Security = security
+	export.AllDirs = req.AllDirs
+	export.Comment = req.Comment
+
+	tx := s.DB.Begin()
+
+	if err := tx.Save(&export).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed_to_update_export: %w", err)
+	}
+
+	if err := tx.Model(&export).Association("Networks").Replace(objects); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed_to_update_export_networks: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed_to_commit_transaction: %w", err)
+	}
+
+	return s.WriteExports(true)
+}
+
+func (s *Service) DeleteExport(id uint) error {
+	var export nfsModels.NFSExport
+	if err := s.DB.First(&export, id).Error; err != nil {
+		return fmt.Errorf("export_not_found: %w", err)
+	}
+
+	tx := s.DB.Begin()
+
+	if err := tx.Model(&export).Association("Networks").Clear(); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed_to_clear_export_networks: %w", err)
+	}
+
+	if err := tx.Delete(&export).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed_to_delete_export: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed_to_commit_transaction: %w", err)
+	}
+
+	return s.WriteExports(true)
+}
+
+func findFilesystem(guid string) (*zfs.Dataset, error) {
+	datasets, err := zfs.Filesystems("")
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_fetch_datasets: %v", err)
+	}
+
+	for _, ds := range datasets {
+		if ds.GUID == guid {
+			return ds, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func mounted(ds *zfs.Dataset) bool {
+	return ds.Mounted == "yes" && strings.HasPrefix(ds.Mountpoint, "/")
+}
+
+// exportHosts splits the entries of the export's objects into plain hosts
+// and networks, since exports(5) wants a separate line per -network. FQDNs
+// use the addresses the resolver found so that DNS changes are picked up by
+// the export watcher, and fall back to the name itself for mountd to
+// resolve.
+func exportHosts(objects []networkModels.Object) ([]string, []string) {
+	var hosts, networks []string
+	seen := map[string]bool{}
+
+	add := func(value string) {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			return
+		}
+		seen[value] = true
+
+		if _, ipNet, err := net.ParseCIDR(value); err == nil {
+			ones, bits := ipNet.Mask.Size()
+			if ones != bits {
+				networks = append(networks, ipNet.String())
+				return
+			}
+			value = ipNet.IP.String()
+		}
+
+		hosts = append(hosts, value)
+	}
+
+	for _, obj := range objects {
+		switch obj.Type {
+		case "Host", "Network":
+			for _, entry := range obj.Entries {
+				add(entry.Value)
+			}
+		case "FQDN":
+			if len(obj.Resolutions) == 0 {
+				for _, entry := range obj.Entries {
+					add(entry.Value)
+				}
+				continue
+			}
+
+			for _, res := range obj.Resolutions {
+				add(res.ResolvedIP)
+			}
+		}
+	}
+
+	return hosts, networks
+}
+
+func exportLines(path string, export nfsModels.NFSExport) []string {
+	fields := []string{strings.ReplaceAll(path, " ", `\ `)}
+
+	if export.ReadOnly {
+		fields = append(fields, "-ro")
+	}
+
+	if export.MapAll != "" {
+		fields = append(fields, "-mapall="+export.MapAll)
+	} else if export.MapRoot != "" {
+		fields = append(fields, "-maproot="+export.MapRoot)
+	}
+
+	if len(export.Security) > 0 {
+		fields = append(fields, "-sec="+strings.Join(export.Security, ":"))
+	}
+
+	if export.AllDirs {
+		fields = append(fields, "-alldirs")
+	}
+
+	base := strings.Join(fields, " ")
+	hosts, networks := exportHosts(export.Networks)
+
+	var lines []string
+	if len(hosts) > 0 {
+		lines = append(lines, base+" "+strings.Join(hosts, " "))
+	}
+
+	for _, network := range networks {
+		lines = append(lines, base+" -network "+network)
+	}
+
+	return lines
+}
+
+func (s *Service) Exports() (string, error) {
+	settings, err := s.GetSettings()
+	if err != nil {
+		return "", err
+	}
+
+	var exports []nfsModels.NFSExport
+	if err := s.DB.
+		Preload("Networks").
+		Preload("Networks.Entries").
+		Preload("Networks.Resolutions").
+		Order("id ASC").
+		Find(&exports).Error; err != nil {
+		return "", fmt.Errorf("failed_to_get_exports: %w", err)
+	}
+
+	datasets, err := zfs.Filesystems("")
+	if err != nil {
+		return "", fmt.Errorf("failed_to_fetch_datasets: %v", err)
+	}
+
+	var config strings.Builder
+	config.WriteString(ExportsHeader + "\n")
+
+	if settings.V4Enabled {
+		line := "V4: " + settings.V4Root
+		if len(settings.V4Security) > 0 {
+			line += " -sec=" + strings.Join(settings.V4Security, ":")
+		}
+		config.WriteString(line + "\n")
+	}
+
+	for _, export := range exports {
+		var dataset *zfs.Dataset
+		for _, ds := range datasets {
+			if ds.GUID == export.Dataset {
+				dataset = ds
+				break
+			}
+		}
+
+		// One missing or unmounted dataset should not take every other
+		// export down with it, mountd refuses lines it cannot resolve
+		if dataset == nil || !mounted(dataset) {
+			logger.L.Warn().Msgf("Skipping NFS export %d, dataset %s is not available", export.ID, export.Dataset)
+			continue
+		}
+
+		if export.Comment != "" {
+			config.WriteString("# " + strings.ReplaceAll(export.Comment, "\n", " ") + "\n")
+		}
+
+		for _, line := range exportLines(dataset.Mountpoint, export) {
+			config.WriteString(line + "\n")
+		}
+	}
+
+	return config.String(), nil
+}
+
+func (s *Service) WriteExports(reload bool) error {
+	_, err := s.writeExports(reload, false)
+	return err
+}
+
+// exportsManaged reports whether Sylve owns the exports file: once NFS is
+// enabled, an export exists or the file already carries Sylve's header.
+// Until then an exports file maintained by hand is left alone.
+func (s *Service) exportsManaged(current []byte) (bool, error) {
+	if bytes.Contains(current, []byte(ExportsHeader)) {
+		return true, nil
+	}
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		return false, err
+	}
+
+	if settings.Enabled {
+		return true, nil
+	}
+
+	var count int64
+	if err := s.DB.Model(&nfsModels.NFSExport{}).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// writeExports renders the exports file and writes it out. With onlyChanged
+// the file is left untouched, and mountd is not poked, when nothing differs.
+// The exports file found before Sylve takes it over is kept next to it.
+func (s *Service) writeExports(reload bool, onlyChanged bool) (bool, error) {
+	current, readErr := os.ReadFile(ExportsPath)
+
+	managed, err := s.exportsManaged(current)
+	if err != nil || !managed {
+		return false, err
+	}
+
+	content, err := s.Exports()
+	if err != nil {
+		return false, err
+	}
+
+	if onlyChanged && readErr == nil && bytes.Equal(current, []byte(content)) {
+		return false, nil
+	}
+
+	if readErr == nil && !bytes.Contains(current, []byte(ExportsHeader)) {
+		backupPath := ExportsPath + ".pre-sylve"
+
+		exists, err := utils.FileExists(backupPath)
+		if err != nil {
+			return false, err
+		}
+
+		if !exists {
+			if err := utils.CopyFile(ExportsPath, backupPath); err != nil {
+				return false, fmt.Errorf("failed_to_backup_exports: %w", err)
+			}
+		}
+	}
+
+	if err := os.WriteFile(ExportsPath, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed_to_write_exports: %w", err)
+	}
+
+	if !reload {
+		return true, nil
+	}
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		return true, err
+	}
+
+	if settings.Enabled && serviceRunning("mountd") {
+		if err := system.ServiceAction("mountd", "reload"); err != nil {
+			return true, fmt.Errorf("mountd_reload_failed: %w", err)
+		}
+	}
+
+	return true, nil
+}
+
+// StartExportWatcher keeps /etc/exports in step with things that change
+// behind the NFS service's back: edits to the network objects used by
+// exports, FQDN resolutions and dataset mountpoints.
+func (s *Service) StartExportWatcher(ctx context.Context) {
+	ticker := time.NewTicker(60 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			changed, err := s.writeExports(true, true)
+			if err != nil {
+				logger.L.Warn().Err(err).Msg("Failed to sync NFS exports")
+				continue
+			}
+
+			if changed {
+				logger.L.Info().Msg("NFS exports changed, reloaded mountd")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package nfs
+
+import (
+	nfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/nfs"
+
+	"gorm.io/gorm"
+)
+
+var _ nfsServiceInterfaces.NfsServiceInterface = (*Service)(nil)
+
+type Service struct {
+	DB *gorm.DB
+}
+
+func NewNfsService(db *gorm.DB) nfsServiceInterfaces.NfsServiceInterface {
+	return &Service{
+		DB: db,
+	}
+}
@@ -13,9 +13,11 @@ import (
 	clusterServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/cluster"
 	diskServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/disk"
 	infoServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/info"
+	iscsiServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/iscsi"
 	jailServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/jail"
 	libvirtServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/libvirt"
 	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	nfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/nfs"
 	sambaServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/samba"
 	systemServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/system"
 	utilitiesServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/utilities"
@@ -24,9 +26,11 @@ import (
 	"github.com/alchemillahq/sylve/internal/services/cluster"
 	"github.com/alchemillahq/sylve/internal/services/disk"
 	"github.com/alchemillahq/sylve/internal/services/info"
+	"github.com/alchemillahq/sylve/internal/services/iscsi"
 	"github.com/alchemillahq/sylve/internal/services/jail"
 	"github.com/alchemillahq/sylve/internal/services/libvirt"
 	"github.com/alchemillahq/sylve/internal/services/network"
+	"github.com/alchemillahq/sylve/internal/services/nfs"
 	"github.com/alchemillahq/sylve/internal/services/samba"
 	"github.com/alchemillahq/sylve/internal/services/startup"
 	"github.com/alchemillahq/sylve/internal/services/system"
@@ -47,6 +51,8 @@ type ServiceRegistry struct {
 	UtilitiesService utilitiesServiceInterfaces.UtilitiesServiceInterface
 	SystemService    systemServiceInterfaces.SystemServiceInterface
 	SambaService     sambaServiceInterfaces.SambaServiceInterface
+	NfsService       nfsServiceInterfaces.NfsServiceInterface
+	IscsiService     iscsiServiceInterfaces.IscsiServiceInterface
 	JailService      jailServiceInterfaces.JailServiceInterface
 	ClusterService   clusterServiceInterfaces.ClusterServiceInterface
 }
@@ -67,6 +73,9 @@ func NewService[T any](db *gorm.DB, dependencies ...interface{}) interface{} {
 		sambaService := dependencies[6].(sambaServiceInterfaces.SambaServiceInterface)
 		jailService := dependencies[7].(jailServiceInterfaces.JailServiceInterface)
 		clusterService := dependencies[8].(clusterServiceInterfaces.ClusterServiceInterface)
+		diskService := dependencies[9].(diskServiceInterfaces.DiskServiceInterface)
+		nfsService := dependencies[10].(nfsServiceInterfaces.NfsServiceInterface)
+		iscsiService := dependencies[11].(iscsiServiceInterfaces.IscsiServiceInterface)
 
 		return startup.NewStartupService(db,
 			infoService,
@@ -77,7 +86,10 @@ func NewService[T any](db *gorm.DB, dependencies ...interface{}) interface{} {
 			systemService,
 			sambaService,
 			jailService,
-			clusterService)
+			clusterService,
+			diskService,
+			nfsService,
+			iscsiService)
 	case *info.Service:
 		return info.NewInfoService(db)
 	case *zfs.Service:
@@ -93,6 +105,10 @@ func NewService[T any](db *gorm.DB, dependencies ...interface{}) interface{} {
 	case *samba.Service:
 		zfsService := dependencies[0].(zfsServiceInterfaces.ZfsServiceInterface)
 		return samba.NewSambaService(db, zfsService)
+	case *nfs.Service:
+		return nfs.NewNfsService(db)
+	case *iscsi.Service:
+		return iscsi.NewIscsiService(db)
 	case *jail.Service:
 		networkService := dependencies[0].(networkServiceInterfaces.NetworkServiceInterface)
 		return jail.NewJailService(db, networkService)
@@ -112,21 +128,26 @@ func NewServiceRegistry(db *gorm.DB) *ServiceRegistry {
 	utilitiesService := NewService[utilities.Service](db)
 	systemService := NewService[system.Service](db)
 	sambaService := NewService[samba.Service](db, zfsService)
+	nfsService := NewService[nfs.Service](db)
+	iscsiService := NewService[iscsi.Service](db)
 	networkService := NewService[network.Service](db, libvirtService)
 	jailService := NewService[jail.Service](db, networkService)
 	clusterService := NewService[cluster.Service](db, authService)
+	diskService := NewService[disk.Service](db, zfsService)
 
 	return &ServiceRegistry{
 		AuthService:      authService.(serviceInterfaces.AuthServiceInterface),
-		StartupService:   NewService[startup.Service](db, infoService, zfsService, networkService, libvirtService, utilitiesService, systemService, sambaService, jailService, clusterService).(*startup.Service),
+		StartupService:   NewService[startup.Service](db, infoService, zfsService, networkService, libvirtService, utilitiesService, systemService, sambaService, jailService, clusterService, diskService, nfsService, iscsiService).(*startup.Service),
 		InfoService:      infoService.(infoServiceInterfaces.InfoServiceInterface),
 		ZfsService:       zfsService.(*zfs.Service),
-		DiskService:      NewService[disk.Service](db, zfsService).(*disk.Service),
-		NetworkService:   NewService[network.Service](db, libvirtService).(*network.Service),
+		DiskService:      diskService.(*disk.Service),
+		NetworkService:   networkService.(*network.Service),
 		LibvirtService:   libvirtService.(libvirtServiceInterfaces.LibvirtServiceInterface),
 		UtilitiesService: utilitiesService.(utilitiesServiceInterfaces.UtilitiesServiceInterface),
 		SystemService:    systemService.(systemServiceInterfaces.SystemServiceInterface),
 		SambaService:     sambaService.(sambaServiceInterfaces.SambaServiceInterface),
+		NfsService:       nfsService.(nfsServiceInterfaces.NfsServiceInterface),
+		IscsiService:     iscsiService.(iscsiServiceInterfaces.IscsiServiceInterface),
 		JailService:      jailService.(jailServiceInterfaces.JailServiceInterface),
 		ClusterService:   clusterService.(clusterServiceInterfaces.ClusterServiceInterface),
 	}
@@ -46,7 +46,11 @@ func (s *Service) SysctlSync() error {
 }
 
 func (s *Service) InitFirewall() error {
-	return nil
+	if _, err := utils.RunCommand("kldload", "-n", "pf"); err != nil {
+		return fmt.Errorf("failed to load kernel module pf: %w", err)
+	}
+
+	return s.Network.InitFirewall()
 }
 
 func (s *Service) FreeBSDCheck() error {
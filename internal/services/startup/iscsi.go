@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package startup
+
+import (
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+)
+
+func (s *Service) InitISCSI() error {
+	var count int64
+	if err := s.DB.Model(&iscsiModels.ISCSISettings{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		if err := s.DB.Create(&iscsiModels.ISCSISettings{Enabled: false}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := s.ISCSI.WriteConfig(false); err != nil {
+		return err
+	}
+
+	return s.ISCSI.ApplyService(false)
+}
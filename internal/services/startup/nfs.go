@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package startup
+
+import (
+	nfsModels "github.com/alchemillahq/sylve/internal/db/models/nfs"
+)
+
+func (s *Service) InitNFS() error {
+	var count int64
+	if err := s.DB.Model(&nfsModels.NFSSettings{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		defaultSettings := nfsModels.NFSSettings{
+			Enabled:    false,
+			V4Enabled:  true,
+			V4Root:     "/",
+			V4Security: []string{nfsModels.SecuritySys},
+		}
+		if err := s.DB.Create(&defaultSettings).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := s.NFS.WriteExports(false); err != nil {
+		return err
+	}
+
+	return s.NFS.ApplyServices(false)
+}
@@ -17,10 +17,13 @@ import (
 
 	serviceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services"
 	clusterServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/cluster"
+	diskServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/disk"
 	infoServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/info"
+	iscsiServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/iscsi"
 	jailServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/jail"
 	libvirtServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/libvirt"
 	networkServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/network"
+	nfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/nfs"
 	sambaServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/samba"
 	systemServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/system"
 	utilitiesServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/utilities"
@@ -43,6 +46,9 @@ type Service struct {
 	Samba     sambaServiceInterfaces.SambaServiceInterface
 	Jail      jailServiceInterfaces.JailServiceInterface
 	Cluster   clusterServiceInterfaces.ClusterServiceInterface
+	Disk      diskServiceInterfaces.DiskServiceInterface
+	NFS       nfsServiceInterfaces.NfsServiceInterface
+	ISCSI     iscsiServiceInterfaces.IscsiServiceInterface
 }
 
 func NewStartupService(db *gorm.DB,
@@ -55,6 +61,9 @@ func NewStartupService(db *gorm.DB,
 	samba sambaServiceInterfaces.SambaServiceInterface,
 	jail jailServiceInterfaces.JailServiceInterface,
 	cluster clusterServiceInterfaces.ClusterServiceInterface,
+	disk diskServiceInterfaces.DiskServiceInterface,
+	nfs nfsServiceInterfaces.NfsServiceInterface,
+	iscsi iscsiServiceInterfaces.IscsiServiceInterface,
 ) serviceInterfaces.StartupServiceInterface {
 	return &Service{
 		DB:        db,
@@ -67,6 +76,9 @@ func NewStartupService(db *gorm.DB,
 		Samba:     samba,
 		Jail:      jail,
 		Cluster:   cluster,
+		Disk:      disk,
+		NFS:       nfs,
+		ISCSI:     iscsi,
 	}
 }
 
@@ -125,6 +137,10 @@ func (s *Service) Initialize(authService serviceInterfaces.AuthServiceInterface)
 		return err
 	}
 
+	if err := s.ZFS.UnlockEncryptedDatasets(); err != nil {
+		logger.L.Error().Msgf("error unlocking encrypted datasets: %v", err)
+	}
+
 	if err := s.ZFS.SyncLibvirtPools(); err != nil {
 		return err
 	}
@@ -136,11 +152,29 @@ func (s *Service) Initialize(authService serviceInterfaces.AuthServiceInterface)
 	go s.Info.Cron()
 	go s.ZFS.Cron()
 	go s.ZFS.StartSnapshotScheduler(context.Background())
+	go s.ZFS.StartPoolMaintenanceScheduler(context.Background())
+	go s.Disk.StartSmartMonitor(context.Background())
+	go s.Jail.StartPackageAuditScheduler(context.Background())
 	go s.Libvirt.StoreVMUsage()
 	go s.Jail.StoreJailUsage()
 	go s.Jail.WatchNetworkObjectChanges()
+	go s.Network.StartDHCPWatcher(context.Background())
+	go s.Network.StartFQDNResolver(context.Background())
+	go s.Network.StartGeoIPWatcher(context.Background())
+
+	if err := s.InitFirewall(); err != nil {
+		logger.L.Error().Msgf("error initializing firewall: %v", err)
+	}
+
+	if err := s.Network.SyncLaggs(); err != nil {
+		logger.L.Error().Msgf("error syncing laggs: %v", err)
+	}
+
+	if err := s.Network.SyncWireGuard(); err != nil {
+		logger.L.Error().Msgf("error syncing wireguard interfaces: %v", err)
+	}
 
-	err := s.Network.SyncStandardSwitches(nil, "sync")
+	err := s.Network.SyncStandardSwitches(nil, "reconcile")
 	if err != nil {
 		logger.L.Error().Msgf("error syncing standard switches: %v", err)
 	}
@@ -161,6 +195,18 @@ func (s *Service) Initialize(authService serviceInterfaces.AuthServiceInterface)
 		return fmt.Errorf("failed to initialize Samba admins: %w", err)
 	}
 
+	if err := s.InitNFS(); err != nil {
+		logger.L.Error().Msgf("error initializing NFS: %v", err)
+	}
+
+	go s.NFS.StartExportWatcher(context.Background())
+
+	if err := s.InitISCSI(); err != nil {
+		logger.L.Error().Msgf("error initializing iSCSI: %v", err)
+	}
+
+	go s.ISCSI.StartConfigWatcher(context.Background())
+
 	go func() {
 		for {
 			err := s.Utilities.SyncDownloadProgress()
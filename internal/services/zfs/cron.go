@@ -25,6 +25,9 @@ func (s *Service) StoreStats(interval int) {
 	}
 
 	if interval == 60 || interval == 0 {
+		s.storeArcStats()
+		s.storeDatasetIO()
+
 		pools, err := zfs.ListZpools()
 		if err != nil {
 			logger.L.Debug().Err(err).Msg("zfs_cron: Failed to list zpools")
@@ -41,6 +44,8 @@ func (s *Service) StoreStats(interval int) {
 		}
 
 		if time.Now().Minute()%10 == 0 {
+			s.trimHistorical(&infoModels.ARCHistorical{}, "")
+			s.trimHistorical(&infoModels.DatasetIOHistorical{}, "dataset")
 			s.trimZPoolHistoricalData()
 		}
 	}
@@ -159,6 +164,7 @@ func (s *Service) Cron() {
 
 	s.StoreStats(0)
 	s.RemoveNonExistentPools()
+	s.CheckPoolHealth()
 
 	for {
 		select {
@@ -167,6 +173,7 @@ func (s *Service) Cron() {
 		case <-tickerSlow.C:
 			s.StoreStats(60)
 			s.RemoveNonExistentPools()
+			s.CheckPoolHealth()
 		}
 	}
 }
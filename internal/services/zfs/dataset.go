@@ -11,6 +11,9 @@ package zfs
 import (
 	"fmt"
 
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	nfsModels "github.com/alchemillahq/sylve/internal/db/models/nfs"
 	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
 	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
 	"github.com/alchemillahq/sylve/pkg/zfs"
@@ -122,6 +125,21 @@ func (s *Service) BulkDeleteDataset(guids []string) error {
 
 func (s *Service) IsDatasetInUse(guid string, failEarly bool) bool {
 	var count int64
+	if err := s.DB.Model(&jailModels.DelegatedDataset{}).Where("guid = ?", guid).
+		Count(&count).Error; err == nil && count > 0 {
+		return true
+	}
+
+	if err := s.DB.Model(&nfsModels.NFSExport{}).Where("dataset = ?", guid).
+		Count(&count).Error; err == nil && count > 0 {
+		return true
+	}
+
+	if err := s.DB.Model(&iscsiModels.ISCSILUN{}).Where("dataset = ?", guid).
+		Count(&count).Error; err == nil && count > 0 {
+		return true
+	}
+
 	if err := s.DB.Model(&vmModels.Storage{}).Where("dataset = ?", guid).
 		Count(&count).Error; err != nil {
 		return false
@@ -10,10 +10,12 @@ package zfs
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/alchemillahq/sylve/pkg/zfs"
 
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	nfsModels "github.com/alchemillahq/sylve/internal/db/models/nfs"
 	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
 )
 
@@ -88,6 +90,14 @@ func (s *Service) DeleteFilesystem(guid string) error {
 		return fmt.Errorf("dataset_in_use_by_vm")
 	}
 
+	if err := s.DB.Model(&jailModels.DelegatedDataset{}).Where("guid = ?", guid).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check if dataset is delegated: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("dataset_delegated_to_jail")
+	}
+
 	filesystems, err := zfs.Filesystems("")
 	if err != nil {
 		return err
@@ -103,27 +113,43 @@ func (s *Service) DeleteFilesystem(guid string) error {
 			continue
 		}
 
-		keylocation, err := filesystem.GetProperty("keylocation")
+		// Destroy is recursive, so exports of children count as well
+		children, err := zfs.Datasets(filesystem.Name)
 		if err != nil {
 			return err
 		}
 
-		if err := filesystem.Destroy(zfs.DestroyRecursive); err != nil {
+		guids := make([]string, 0, len(children))
+		for _, child := range children {
+			guids = append(guids, child.GUID)
+		}
+
+		if err := s.DB.Model(&nfsModels.NFSExport{}).Where("dataset IN ?", guids).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check if dataset is exported: %w", err)
+		}
+
+		if count > 0 {
+			return fmt.Errorf("dataset_in_use_by_nfs_export")
+		}
+
+		if err := s.DB.Model(&iscsiModels.ISCSILUN{}).Where("dataset IN ?", guids).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check if dataset holds iscsi luns: %w", err)
+		}
+
+		if count > 0 {
+			return fmt.Errorf("dataset_in_use_by_iscsi_lun")
+		}
+
+		keylocation, err := filesystem.GetProperty("keylocation")
+		if err != nil {
 			return err
 		}
 
-		if keylocation != "" && keylocation != "none" {
-			keylocation = keylocation[7:]
-			if _, err := os.Stat(keylocation); err == nil {
-				if err := os.Remove(keylocation); err != nil {
-					return err
-				}
-			} else {
-				return fmt.Errorf("keylocation_file_not_found: %s", keylocation)
-			}
+		if err := filesystem.Destroy(zfs.DestroyRecursive); err != nil {
+			return err
 		}
 
-		return nil
+		return zfs.RemoveStoredKey(keylocation)
 	}
 
 	return fmt.Errorf("filesystem with guid %s not found", guid)
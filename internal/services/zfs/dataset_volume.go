@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"os"
 
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
 	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
 	"github.com/alchemillahq/sylve/pkg/utils"
 	"github.com/alchemillahq/sylve/pkg/zfs"
@@ -79,6 +80,14 @@ func (s *Service) DeleteVolume(guid string) error {
 		return fmt.Errorf("dataset_in_use_by_vm")
 	}
 
+	if err := s.DB.Model(&iscsiModels.ISCSILUN{}).Where("dataset = ?", guid).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check if dataset is an iscsi lun: %w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("dataset_in_use_by_iscsi_lun")
+	}
+
 	volumes, err := zfs.Volumes("")
 	if err != nil {
 		return err
@@ -91,11 +100,17 @@ func (s *Service) DeleteVolume(guid string) error {
 		}
 
 		if g == guid {
-			err := volume.Destroy(zfs.DestroyRecursive)
+			keylocation, err := volume.GetProperty("keylocation")
 			if err != nil {
 				return err
 			}
-			return nil
+
+			err = volume.Destroy(zfs.DestroyRecursive)
+			if err != nil {
+				return err
+			}
+
+			return zfs.RemoveStoredKey(keylocation)
 		}
 	}
 
@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+func (s *Service) GetEncryptionRoots() ([]zfs.EncryptionInfo, error) {
+	infos, err := zfs.EncryptionStatus("")
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_get_encryption_status: %v", err)
+	}
+
+	roots := []zfs.EncryptionInfo{}
+	for _, info := range infos {
+		if info.EncryptionRoot != "" && info.Name == info.EncryptionRoot {
+			roots = append(roots, info)
+		}
+	}
+
+	return roots, nil
+}
+
+// encryptionRoot resolves guid, which may be any encrypted dataset, to the
+// encryption root that owns its key along with every dataset sharing it.
+func (s *Service) encryptionRoot(guid string) (zfs.EncryptionInfo, []zfs.EncryptionInfo, error) {
+	infos, err := zfs.EncryptionStatus("")
+	if err != nil {
+		return zfs.EncryptionInfo{}, nil, fmt.Errorf("failed_to_get_encryption_status: %v", err)
+	}
+
+	var rootName string
+	for _, info := range infos {
+		if info.GUID == guid {
+			if info.Encryption == "" || info.Encryption == "off" {
+				return zfs.EncryptionInfo{}, nil, fmt.Errorf("dataset_not_encrypted")
+			}
+			rootName = info.EncryptionRoot
+			break
+		}
+	}
+
+	if rootName == "" {
+		return zfs.EncryptionInfo{}, nil, fmt.Errorf("dataset_not_found")
+	}
+
+	var root zfs.EncryptionInfo
+	var members []zfs.EncryptionInfo
+
+	for _, info := range infos {
+		if info.EncryptionRoot != rootName {
+			continue
+		}
+
+		if info.Name == rootName {
+			root = info
+		}
+		members = append(members, info)
+	}
+
+	return root, members, nil
+}
+
+func (s *Service) mountEncrypted(root string) error {
+	filesystems, err := zfs.Filesystems(root)
+	if err != nil {
+		return err
+	}
+
+	for _, fs := range filesystems {
+		if fs.Mounted == "yes" || fs.Mountpoint == "none" || fs.Mountpoint == "legacy" {
+			continue
+		}
+
+		canmount, err := fs.GetProperty("canmount")
+		if err != nil || canmount != "on" {
+			continue
+		}
+
+		if _, err := fs.Mount(false, nil); err != nil {
+			return fmt.Errorf("failed_to_mount %s: %v", fs.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) LoadKey(guid string, key string) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+	defer s.Libvirt.RescanStoragePools()
+
+	root, _, err := s.encryptionRoot(guid)
+	if err != nil {
+		return err
+	}
+
+	if !root.Locked() {
+		return fmt.Errorf("key_already_loaded")
+	}
+
+	var material []byte
+	if key != "" {
+		if root.KeyFormat == zfs.KeyFormatPassphrase {
+			material = []byte(key)
+		} else if material, err = zfs.EncodeKey(root.KeyFormat, key); err != nil {
+			return err
+		}
+	} else if root.KeyLocation == "prompt" {
+		return fmt.Errorf("key_required")
+	}
+
+	if err := zfs.LoadKey(root.Name, material); err != nil {
+		return fmt.Errorf("failed_to_load_key: %v", err)
+	}
+
+	return s.mountEncrypted(root.Name)
+}
+
+func (s *Service) UnloadKey(guid string) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+	defer s.Libvirt.RescanStoragePools()
+
+	root, members, err := s.encryptionRoot(guid)
+	if err != nil {
+		return err
+	}
+
+	if root.Locked() {
+		return fmt.Errorf("key_not_loaded")
+	}
+
+	for _, member := range members {
+		if s.IsDatasetInUse(member.GUID, false) {
+			return fmt.Errorf("dataset_in_use: %s", member.Name)
+		}
+	}
+
+	filesystems, err := zfs.Filesystems(root.Name)
+	if err != nil {
+		return err
+	}
+
+	// children have to be unmounted before their parents
+	sort.Slice(filesystems, func(i, j int) bool {
+		return strings.Count(filesystems[i].Name, "/") > strings.Count(filesystems[j].Name, "/")
+	})
+
+	for _, fs := range filesystems {
+		if fs.Mounted != "yes" {
+			continue
+		}
+
+		if _, err := fs.Unmount(false); err != nil {
+			return fmt.Errorf("failed_to_unmount %s: %v", fs.Name, err)
+		}
+	}
+
+	if err := zfs.UnloadKey(root.Name); err != nil {
+		return fmt.Errorf("failed_to_unload_key: %v", err)
+	}
+
+	return nil
+}
+
+func (s *Service) ChangeKey(guid string, req zfsServiceInterfaces.ChangeEncryptionKey) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	root, _, err := s.encryptionRoot(guid)
+	if err != nil {
+		return err
+	}
+
+	if root.Locked() {
+		return fmt.Errorf("key_not_loaded")
+	}
+
+	material, err := zfs.EncodeKey(req.KeyFormat, req.Key)
+	if err != nil {
+		return err
+	}
+
+	keylocation := "prompt"
+	if req.StoreKey {
+		keyFile, err := zfs.WriteKeyFile(root.Name, material)
+		if err != nil {
+			return err
+		}
+		keylocation = "file://" + keyFile
+	}
+
+	if err := zfs.ChangeKey(root.Name, req.KeyFormat, keylocation, material); err != nil {
+		if rerr := zfs.RemoveStoredKey(keylocation); rerr != nil {
+			logger.L.Warn().Err(rerr).Msg("failed to remove unused encryption key file")
+		}
+		return fmt.Errorf("failed_to_change_key: %v", err)
+	}
+
+	if root.KeyLocation != keylocation {
+		if err := zfs.RemoveStoredKey(root.KeyLocation); err != nil {
+			logger.L.Warn().Err(err).Msgf("failed to remove old key file for %s", root.Name)
+		}
+	}
+
+	return nil
+}
+
+// UnlockEncryptedDatasets loads keys that Sylve stores on disk so their
+// datasets are available before VMs and jails are started. Roots whose key
+// is not stored stay locked until unlocked on demand.
+func (s *Service) UnlockEncryptedDatasets() error {
+	roots, err := s.GetEncryptionRoots()
+	if err != nil {
+		return err
+	}
+
+	for _, root := range roots {
+		if !root.Locked() || !strings.HasPrefix(root.KeyLocation, "file://") {
+			continue
+		}
+
+		if err := zfs.LoadKey(root.Name, nil); err != nil {
+			logger.L.Warn().Err(err).Msgf("failed to load encryption key for %s", root.Name)
+			continue
+		}
+
+		if err := s.mountEncrypted(root.Name); err != nil {
+			logger.L.Warn().Err(err).Msgf("failed to mount datasets under %s", root.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,558 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/pkg/disk"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+var getDiskSize = disk.GetDiskSize
+var getPoolFeature = zfs.GetPoolFeature
+
+var minVdevDevices = map[string]int{
+	"mirror": 2,
+	"raidz":  3,
+	"raidz2": 4,
+	"raidz3": 5,
+}
+
+// vdevRef points at a vdev inside a PoolLayout. child is -1 when the
+// reference is to the top-level vdev itself.
+type vdevRef struct {
+	class string
+	top   int
+	child int
+}
+
+var layoutClasses = []string{
+	zfs.VdevClassData,
+	zfs.VdevClassSpecial,
+	zfs.VdevClassDedup,
+	zfs.VdevClassLog,
+	zfs.VdevClassCache,
+	zfs.VdevClassSpare,
+}
+
+func devicePath(dev string) string {
+	if strings.HasPrefix(dev, "/dev/") {
+		return dev
+	}
+	return "/dev/" + dev
+}
+
+func findVdev(layout zfs.PoolLayout, name string) (vdevRef, bool) {
+	candidates := []string{name}
+	if !strings.HasPrefix(name, "/") {
+		candidates = append(candidates, devicePath(name))
+	}
+
+	for _, class := range layoutClasses {
+		for i, top := range *layout.Class(class) {
+			if slices.Contains(candidates, top.Name) {
+				return vdevRef{class: class, top: i, child: -1}, true
+			}
+
+			for j, child := range top.Children {
+				if slices.Contains(candidates, child.Name) {
+					return vdevRef{class: class, top: i, child: j}, true
+				}
+			}
+		}
+	}
+
+	return vdevRef{}, false
+}
+
+func vdevUsable(v zfs.PoolVdev) uint64 {
+	if strings.HasPrefix(v.Type, "raidz") || strings.HasPrefix(v.Type, "draid") {
+		n := len(v.Children)
+		p := v.Redundancy()
+		if n <= p {
+			return 0
+		}
+		return v.Size / uint64(n) * uint64(n-p)
+	}
+
+	return v.Size
+}
+
+func classUsable(vdevs []zfs.PoolVdev) uint64 {
+	var total uint64
+	for _, v := range vdevs {
+		total += vdevUsable(v)
+	}
+	return total
+}
+
+// poolRedundancy is the number of device failures the pool as a whole
+// survives, which is bounded by its weakest data, special or dedup vdev.
+// Log and cache vdevs can be lost without losing the pool.
+func poolRedundancy(layout zfs.PoolLayout) int {
+	redundancy := -1
+	for _, class := range []string{zfs.VdevClassData, zfs.VdevClassSpecial, zfs.VdevClassDedup} {
+		for _, v := range *layout.Class(class) {
+			if r := v.Redundancy(); redundancy < 0 || r < redundancy {
+				redundancy = r
+			}
+		}
+	}
+
+	return max(redundancy, 0)
+}
+
+func layoutPreview(class string, before zfs.PoolLayout, after zfs.PoolLayout) zfsServiceInterfaces.PoolChangePreview {
+	return zfsServiceInterfaces.PoolChangePreview{
+		Class:             class,
+		CurrentUsable:     classUsable(*before.Class(class)),
+		ResultUsable:      classUsable(*after.Class(class)),
+		CurrentRedundancy: poolRedundancy(before),
+		ResultRedundancy:  poolRedundancy(after),
+		Warnings:          []string{},
+	}
+}
+
+func (s *Service) poolLayout(guid string) (*zfs.Zpool, zfs.PoolLayout, error) {
+	pool, err := zfs.GetZpoolByGUID(guid)
+	if err != nil {
+		return nil, zfs.PoolLayout{}, fmt.Errorf("pool_not_found")
+	}
+
+	layout, err := zfs.GetPoolLayout(pool.Name)
+	if err != nil {
+		return nil, zfs.PoolLayout{}, fmt.Errorf("failed_to_get_pool_layout: %v", err)
+	}
+
+	return pool, layout, nil
+}
+
+func (s *Service) GetPoolLayout(guid string) (zfs.PoolLayout, error) {
+	_, layout, err := s.poolLayout(guid)
+	return layout, err
+}
+
+// newDevices normalises device names, makes sure none of them already
+// belong to the pool and returns their sizes.
+func newDevices(layout zfs.PoolLayout, devices []string) ([]string, []uint64, error) {
+	paths := make([]string, 0, len(devices))
+	sizes := make([]uint64, 0, len(devices))
+
+	for _, dev := range devices {
+		path := devicePath(dev)
+		if slices.Contains(paths, path) {
+			return nil, nil, fmt.Errorf("duplicate_device: %s", path)
+		}
+
+		if _, found := findVdev(layout, path); found {
+			return nil, nil, fmt.Errorf("device_already_in_pool: %s", path)
+		}
+
+		size, err := getDiskSize(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid_device %s: %v", path, err)
+		}
+
+		if size == 0 {
+			return nil, nil, fmt.Errorf("invalid_device %s: size is zero", path)
+		}
+
+		paths = append(paths, path)
+		sizes = append(sizes, size)
+	}
+
+	return paths, sizes, nil
+}
+
+func planAddVdev(layout zfs.PoolLayout, req zfsServiceInterfaces.PoolVdevAdd) (zfsServiceInterfaces.PoolChangePreview, []string, error) {
+	var preview zfsServiceInterfaces.PoolChangePreview
+
+	switch req.Class {
+	case zfs.VdevClassCache:
+		if req.RaidType != "" {
+			return preview, nil, fmt.Errorf("cache_vdevs_cannot_be_redundant")
+		}
+	case zfs.VdevClassLog, zfs.VdevClassSpecial, zfs.VdevClassDedup:
+		if req.RaidType != "" && req.RaidType != "mirror" {
+			return preview, nil, fmt.Errorf("%s_vdevs_support_only_mirror", req.Class)
+		}
+	}
+
+	if minDevices, ok := minVdevDevices[req.RaidType]; ok && len(req.Devices) < minDevices {
+		return preview, nil, fmt.Errorf("insufficient_devices_for_%s (minimum %d)", req.RaidType, minDevices)
+	}
+
+	paths, sizes, err := newDevices(layout, req.Devices)
+	if err != nil {
+		return preview, nil, err
+	}
+
+	var added []zfs.PoolVdev
+	if req.RaidType == "" {
+		for i, path := range paths {
+			added = append(added, zfs.PoolVdev{Name: path, Type: "disk", Size: sizes[i]})
+		}
+	} else {
+		smallest := slices.Min(sizes)
+		vdev := zfs.PoolVdev{Name: req.RaidType, Type: zfs.VdevType(req.RaidType + "-0")}
+		for _, path := range paths {
+			vdev.Children = append(vdev.Children, zfs.PoolVdev{Name: path, Type: "disk", Size: smallest})
+		}
+
+		if vdev.Type == "mirror" {
+			vdev.Size = smallest
+		} else {
+			vdev.Size = smallest * uint64(len(paths))
+		}
+
+		added = append(added, vdev)
+	}
+
+	after := layout.Clone()
+	class := after.Class(req.Class)
+	*class = append(*class, added...)
+
+	preview = layoutPreview(req.Class, layout, after)
+
+	if req.RaidType != "" && slices.Min(sizes) != slices.Max(sizes) {
+		preview.Warnings = append(preview.Warnings, "mixed_device_sizes")
+	}
+
+	dataRedundancy := poolRedundancy(zfs.PoolLayout{Data: layout.Data})
+
+	switch req.Class {
+	case zfs.VdevClassData:
+		if len(layout.Data) > 0 {
+			existing := layout.Data[0]
+			width := len(existing.Children)
+			if existing.Type == "disk" {
+				width = 1
+			}
+
+			newWidth := len(added[0].Children)
+			if added[0].Type == "disk" {
+				newWidth = 1
+			}
+
+			if existing.Type != added[0].Type || width != newWidth {
+				if !req.Force {
+					return preview, nil, fmt.Errorf("mismatched_redundancy: pool uses %d-wide %s, new vdev is %d-wide %s",
+						width, existing.Type, newWidth, added[0].Type)
+				}
+				preview.Warnings = append(preview.Warnings, "mismatched_redundancy")
+			}
+		}
+	case zfs.VdevClassSpecial, zfs.VdevClassDedup:
+		if added[0].Redundancy() < dataRedundancy {
+			if !req.Force {
+				return preview, nil, fmt.Errorf("%s_vdev_less_redundant_than_data", req.Class)
+			}
+			preview.Warnings = append(preview.Warnings, "reduces_pool_redundancy")
+		}
+	case zfs.VdevClassLog:
+		if req.RaidType == "" && dataRedundancy > 0 {
+			preview.Warnings = append(preview.Warnings, "unmirrored_log")
+		}
+	}
+
+	return preview, paths, nil
+}
+
+func (s *Service) PreviewAddVdev(guid string, req zfsServiceInterfaces.PoolVdevAdd) (zfsServiceInterfaces.PoolChangePreview, error) {
+	pool, layout, err := s.poolLayout(guid)
+	if err != nil {
+		return zfsServiceInterfaces.PoolChangePreview{}, err
+	}
+
+	preview, paths, err := planAddVdev(layout, req)
+	if err != nil {
+		return preview, err
+	}
+
+	config, err := pool.AddVdev(req.Class, req.RaidType, paths, req.Force, true)
+	if err != nil {
+		return preview, fmt.Errorf("zpool_add_check_failed: %v", err)
+	}
+
+	preview.Config = config
+	return preview, nil
+}
+
+func (s *Service) AddVdev(guid string, req zfsServiceInterfaces.PoolVdevAdd) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	pool, layout, err := s.poolLayout(guid)
+	if err != nil {
+		return err
+	}
+
+	_, paths, err := planAddVdev(layout, req)
+	if err != nil {
+		return err
+	}
+
+	if _, err := pool.AddVdev(req.Class, req.RaidType, paths, req.Force, false); err != nil {
+		return fmt.Errorf("zpool_add_failed: %v", err)
+	}
+
+	return nil
+}
+
+func planAttach(pool *zfs.Zpool, layout zfs.PoolLayout, req zfsServiceInterfaces.PoolDeviceAttach) (zfsServiceInterfaces.PoolChangePreview, string, string, error) {
+	var preview zfsServiceInterfaces.PoolChangePreview
+
+	ref, found := findVdev(layout, req.Target)
+	if !found {
+		return preview, "", "", fmt.Errorf("target_not_found")
+	}
+
+	if ref.class == zfs.VdevClassCache || ref.class == zfs.VdevClassSpare {
+		return preview, "", "", fmt.Errorf("cannot_attach_to_%s_device", ref.class)
+	}
+
+	paths, sizes, err := newDevices(layout, []string{req.Device})
+	if err != nil {
+		return preview, "", "", err
+	}
+
+	after := layout.Clone()
+	top := &(*after.Class(ref.class))[ref.top]
+	device := zfs.PoolVdev{Name: paths[0], Type: "disk", Size: sizes[0]}
+
+	var target string
+	var warnings []string
+
+	switch {
+	case ref.child < 0 && strings.HasPrefix(top.Type, "raidz"):
+		feature := getPoolFeature(pool.Name, "raidz_expansion")
+		if feature != "enabled" && feature != "active" {
+			return preview, "", "", fmt.Errorf("raidz_expansion_unsupported")
+		}
+
+		smallest := top.Size / uint64(max(len(top.Children), 1))
+		if sizes[0] < smallest {
+			return preview, "", "", fmt.Errorf("device_too_small: needs at least %d bytes", smallest)
+		}
+
+		top.Size += smallest
+		top.Children = append(top.Children, device)
+		target = top.Name
+		warnings = append(warnings, "existing_data_keeps_old_parity_ratio")
+
+	case ref.child < 0 && top.Type == "disk":
+		if sizes[0] < top.Size {
+			return preview, "", "", fmt.Errorf("device_too_small: needs at least %d bytes", top.Size)
+		}
+
+		existing := *top
+		existing.Children = []zfs.PoolVdev{}
+		*top = zfs.PoolVdev{Name: "mirror", Type: "mirror", Size: top.Size, Children: []zfs.PoolVdev{existing, device}}
+		target = existing.Name
+
+	case ref.child >= 0 && top.Type == "mirror":
+		member := top.Children[ref.child]
+		if sizes[0] < member.Size {
+			return preview, "", "", fmt.Errorf("device_too_small: needs at least %d bytes", member.Size)
+		}
+
+		top.Children = append(top.Children, device)
+		target = member.Name
+
+	default:
+		return preview, "", "", fmt.Errorf("cannot_attach_to_%s", top.Type)
+	}
+
+	preview = layoutPreview(ref.class, layout, after)
+	preview.Warnings = append(preview.Warnings, warnings...)
+
+	return preview, target, paths[0], nil
+}
+
+func (s *Service) PreviewAttachDevice(guid string, req zfsServiceInterfaces.PoolDeviceAttach) (zfsServiceInterfaces.PoolChangePreview, error) {
+	pool, layout, err := s.poolLayout(guid)
+	if err != nil {
+		return zfsServiceInterfaces.PoolChangePreview{}, err
+	}
+
+	preview, _, _, err := planAttach(pool, layout, req)
+	return preview, err
+}
+
+func (s *Service) AttachDevice(guid string, req zfsServiceInterfaces.PoolDeviceAttach) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	pool, layout, err := s.poolLayout(guid)
+	if err != nil {
+		return err
+	}
+
+	_, target, device, err := planAttach(pool, layout, req)
+	if err != nil {
+		return err
+	}
+
+	if err := pool.Attach(target, device); err != nil {
+		return fmt.Errorf("zpool_attach_failed: %v", err)
+	}
+
+	return nil
+}
+
+func planDetach(layout zfs.PoolLayout, device string) (zfsServiceInterfaces.PoolChangePreview, string, error) {
+	var preview zfsServiceInterfaces.PoolChangePreview
+
+	ref, found := findVdev(layout, device)
+	if !found {
+		return preview, "", fmt.Errorf("device_not_found")
+	}
+
+	after := layout.Clone()
+	top := &(*after.Class(ref.class))[ref.top]
+
+	if ref.child < 0 || (top.Type != "mirror" && top.Type != "replacing" && top.Type != "spare") {
+		return preview, "", fmt.Errorf("device_not_detachable: only mirror, replacing or spare members can be detached")
+	}
+
+	name := top.Children[ref.child].Name
+	top.Children = slices.Delete(top.Children, ref.child, ref.child+1)
+
+	if len(top.Children) == 1 {
+		remaining := top.Children[0]
+		remaining.Size = max(remaining.Size, top.Size)
+		*top = remaining
+	}
+
+	preview = layoutPreview(ref.class, layout, after)
+
+	if ref.class != zfs.VdevClassLog && preview.ResultRedundancy == 0 && preview.CurrentRedundancy > 0 {
+		preview.Warnings = append(preview.Warnings, "pool_loses_redundancy")
+	}
+
+	return preview, name, nil
+}
+
+func (s *Service) PreviewDetachDevice(guid string, device string) (zfsServiceInterfaces.PoolChangePreview, error) {
+	_, layout, err := s.poolLayout(guid)
+	if err != nil {
+		return zfsServiceInterfaces.PoolChangePreview{}, err
+	}
+
+	preview, _, err := planDetach(layout, device)
+	return preview, err
+}
+
+func (s *Service) DetachDevice(guid string, device string) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	pool, layout, err := s.poolLayout(guid)
+	if err != nil {
+		return err
+	}
+
+	_, name, err := planDetach(layout, device)
+	if err != nil {
+		return err
+	}
+
+	if err := pool.Detach(name); err != nil {
+		return fmt.Errorf("zpool_detach_failed: %v", err)
+	}
+
+	return nil
+}
+
+// planRemove covers top-level vdev removal. Log, cache and spare devices can
+// always go; data, special and dedup vdevs need device removal, which ZFS
+// refuses on pools with raidz or draid data vdevs and which needs room on
+// the remaining vdevs for everything that gets evacuated.
+func planRemove(layout zfs.PoolLayout, vdev string) (zfsServiceInterfaces.PoolChangePreview, string, error) {
+	var preview zfsServiceInterfaces.PoolChangePreview
+
+	ref, found := findVdev(layout, vdev)
+	if !found {
+		return preview, "", fmt.Errorf("vdev_not_found")
+	}
+
+	if ref.child >= 0 {
+		return preview, "", fmt.Errorf("not_a_top_level_vdev: detach mirror members instead")
+	}
+
+	after := layout.Clone()
+	class := after.Class(ref.class)
+	removed := (*class)[ref.top]
+	*class = slices.Delete(*class, ref.top, ref.top+1)
+
+	preview = layoutPreview(ref.class, layout, after)
+
+	switch ref.class {
+	case zfs.VdevClassData, zfs.VdevClassSpecial, zfs.VdevClassDedup:
+		for _, v := range layout.Data {
+			if strings.HasPrefix(v.Type, "raidz") || strings.HasPrefix(v.Type, "draid") {
+				return preview, "", fmt.Errorf("device_removal_unsupported_with_raidz")
+			}
+		}
+
+		if len(after.Data) == 0 {
+			return preview, "", fmt.Errorf("cannot_remove_last_data_vdev")
+		}
+
+		var free uint64
+		for _, v := range after.Data {
+			if v.Size > v.Alloc {
+				free += v.Size - v.Alloc
+			}
+		}
+
+		if free < removed.Alloc {
+			return preview, "", fmt.Errorf("insufficient_space_for_removal: need %d bytes, %d available", removed.Alloc, free)
+		}
+
+		preview.Warnings = append(preview.Warnings, "removal_leaves_indirect_mappings")
+	}
+
+	return preview, removed.Name, nil
+}
+
+func (s *Service) PreviewRemoveVdev(guid string, vdev string) (zfsServiceInterfaces.PoolChangePreview, error) {
+	_, layout, err := s.poolLayout(guid)
+	if err != nil {
+		return zfsServiceInterfaces.PoolChangePreview{}, err
+	}
+
+	preview, _, err := planRemove(layout, vdev)
+	return preview, err
+}
+
+func (s *Service) RemoveVdev(guid string, vdev string) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	pool, layout, err := s.poolLayout(guid)
+	if err != nil {
+		return err
+	}
+
+	_, name, err := planRemove(layout, vdev)
+	if err != nil {
+		return err
+	}
+
+	if err := pool.RemoveVdev(name); err != nil {
+		return fmt.Errorf("zpool_remove_failed: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfs
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+const gib = uint64(1 << 30)
+
+func leaf(name string, size uint64) zfs.PoolVdev {
+	return zfs.PoolVdev{Name: name, Type: "disk", Size: size, Health: "ONLINE", Children: []zfs.PoolVdev{}}
+}
+
+func vdev(name string, size uint64, alloc uint64, children ...zfs.PoolVdev) zfs.PoolVdev {
+	if children == nil {
+		children = []zfs.PoolVdev{}
+	}
+
+	return zfs.PoolVdev{Name: name, Type: zfs.VdevType(name), Size: size, Alloc: alloc, Health: "ONLINE", Children: children}
+}
+
+// Layouts as parsePoolLayout returns them for zpool list -v -P -p.
+func mirrorLayout() zfs.PoolLayout {
+	return zfs.PoolLayout{
+		Data: []zfs.PoolVdev{
+			vdev("mirror-0", 1000*gib, 400*gib, leaf("/dev/ada0", 1000*gib), leaf("/dev/ada1", 1000*gib)),
+			vdev("mirror-1", 500*gib, 200*gib, leaf("/dev/ada2", 500*gib), leaf("/dev/ada3", 500*gib)),
+		},
+		Log:     []zfs.PoolVdev{leaf("/dev/nda0p1", 16*gib)},
+		Cache:   []zfs.PoolVdev{leaf("/dev/nda0p2", 200*gib)},
+		Spare:   []zfs.PoolVdev{leaf("/dev/ada9", 1000*gib)},
+		Special: []zfs.PoolVdev{},
+		Dedup:   []zfs.PoolVdev{},
+	}
+}
+
+func raidzLayout() zfs.PoolLayout {
+	return zfs.PoolLayout{
+		Data: []zfs.PoolVdev{
+			vdev("raidz1-0", 3000*gib, 900*gib, leaf("/dev/ada1", 1000*gib), leaf("/dev/ada2", 1000*gib), leaf("/dev/ada3", 1000*gib)),
+		},
+		Special: []zfs.PoolVdev{
+			vdev("mirror-1", 100*gib, 10*gib, leaf("/dev/nda0", 100*gib), leaf("/dev/nda1", 100*gib)),
+		},
+		Log:   []zfs.PoolVdev{},
+		Cache: []zfs.PoolVdev{},
+		Spare: []zfs.PoolVdev{},
+		Dedup: []zfs.PoolVdev{},
+	}
+}
+
+func singleLayout() zfs.PoolLayout {
+	return zfs.PoolLayout{
+		Data:    []zfs.PoolVdev{vdev("/dev/ada0", 1000*gib, 200*gib)},
+		Log:     []zfs.PoolVdev{},
+		Cache:   []zfs.PoolVdev{},
+		Spare:   []zfs.PoolVdev{},
+		Special: []zfs.PoolVdev{},
+		Dedup:   []zfs.PoolVdev{},
+	}
+}
+
+func replacingLayout() zfs.PoolLayout {
+	layout := singleLayout()
+	layout.Data = []zfs.PoolVdev{vdev("replacing-0", 1000*gib, 200*gib, leaf("/dev/ada0", 1000*gib), leaf("/dev/ada4", 1000*gib))}
+	return layout
+}
+
+// stubDisks makes newDevices see the sizes diskinfo reported for the spare
+// disks of the test machine.
+func stubDisks(t *testing.T) {
+	t.Helper()
+
+	sizes := map[string]uint64{
+		"/dev/ada4": 1000 * gib,
+		"/dev/ada5": 1000 * gib,
+		"/dev/ada6": 500 * gib,
+		"/dev/nda2": 100 * gib,
+		"/dev/nda3": 100 * gib,
+	}
+
+	original := getDiskSize
+	t.Cleanup(func() { getDiskSize = original })
+
+	getDiskSize = func(device string) (uint64, error) {
+		if size, ok := sizes[device]; ok {
+			return size, nil
+		}
+		return 0, fmt.Errorf("diskinfo: %s: No such file or directory", device)
+	}
+}
+
+type previewCase struct {
+	err        string
+	name       string
+	usable     [2]uint64
+	redundancy [2]int
+	warnings   []string
+}
+
+func checkPreview(t *testing.T, label string, preview zfsServiceInterfaces.PoolChangePreview, name string, err error, want previewCase) {
+	t.Helper()
+
+	if want.err != "" {
+		if err == nil || err.Error() != want.err {
+			t.Errorf("%s error = %v, want %q", label, err, want.err)
+		}
+		return
+	}
+
+	if err != nil {
+		t.Errorf("%s unexpected error: %v", label, err)
+		return
+	}
+
+	if name != want.name {
+		t.Errorf("%s = %q, want %q", label, name, want.name)
+	}
+
+	if usable := [2]uint64{preview.CurrentUsable, preview.ResultUsable}; usable != want.usable {
+		t.Errorf("%s usable = %v GiB, want %v GiB", label, [2]uint64{usable[0] / gib, usable[1] / gib}, [2]uint64{want.usable[0] / gib, want.usable[1] / gib})
+	}
+
+	if redundancy := [2]int{preview.CurrentRedundancy, preview.ResultRedundancy}; redundancy != want.redundancy {
+		t.Errorf("%s redundancy = %v, want %v", label, redundancy, want.redundancy)
+	}
+
+	if !slices.Equal(preview.Warnings, want.warnings) {
+		t.Errorf("%s warnings = %v, want %v", label, preview.Warnings, want.warnings)
+	}
+}
+
+func TestPlanAddVdev(t *testing.T) {
+	stubDisks(t)
+
+	tests := []struct {
+		layout zfs.PoolLayout
+		req    zfsServiceInterfaces.PoolVdevAdd
+		paths  []string
+		want   previewCase
+	}{
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", RaidType: "mirror", Devices: []string{"ada4", "ada5"}},
+			[]string{"/dev/ada4", "/dev/ada5"}, previewCase{usable: [2]uint64{1500 * gib, 2500 * gib}, redundancy: [2]int{1, 1}}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", RaidType: "mirror", Devices: []string{"ada4", "/dev/ada6"}},
+			[]string{"/dev/ada4", "/dev/ada6"}, previewCase{usable: [2]uint64{1500 * gib, 2000 * gib}, redundancy: [2]int{1, 1}, warnings: []string{"mixed_device_sizes"}}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", RaidType: "raidz", Devices: []string{"ada4", "ada5"}},
+			nil, previewCase{err: "insufficient_devices_for_raidz (minimum 3)"}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", Devices: []string{"ada4"}},
+			nil, previewCase{err: "mismatched_redundancy: pool uses 2-wide mirror, new vdev is 1-wide disk"}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", Devices: []string{"ada4"}, Force: true},
+			[]string{"/dev/ada4"}, previewCase{usable: [2]uint64{1500 * gib, 2500 * gib}, redundancy: [2]int{1, 0}, warnings: []string{"mismatched_redundancy"}}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", RaidType: "mirror", Devices: []string{"ada0", "ada4"}},
+			nil, previewCase{err: "device_already_in_pool: /dev/ada0"}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", RaidType: "mirror", Devices: []string{"ada9", "ada4"}},
+			nil, previewCase{err: "device_already_in_pool: /dev/ada9"}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", RaidType: "mirror", Devices: []string{"ada4", "/dev/ada4"}},
+			nil, previewCase{err: "duplicate_device: /dev/ada4"}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", RaidType: "mirror", Devices: []string{"ada4", "ada7"}},
+			nil, previewCase{err: "invalid_device /dev/ada7: diskinfo: /dev/ada7: No such file or directory"}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "cache", RaidType: "mirror", Devices: []string{"nda2", "nda3"}},
+			nil, previewCase{err: "cache_vdevs_cannot_be_redundant"}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "log", RaidType: "raidz", Devices: []string{"nda2", "nda3", "ada4"}},
+			nil, previewCase{err: "log_vdevs_support_only_mirror"}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "log", Devices: []string{"nda2"}},
+			[]string{"/dev/nda2"}, previewCase{usable: [2]uint64{16 * gib, 116 * gib}, redundancy: [2]int{1, 1}, warnings: []string{"unmirrored_log"}}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "special", Devices: []string{"nda2"}},
+			nil, previewCase{err: "special_vdev_less_redundant_than_data"}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "special", Devices: []string{"nda2"}, Force: true},
+			[]string{"/dev/nda2"}, previewCase{usable: [2]uint64{0, 100 * gib}, redundancy: [2]int{1, 0}, warnings: []string{"reduces_pool_redundancy"}}},
+		{mirrorLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "dedup", RaidType: "mirror", Devices: []string{"nda2", "nda3"}},
+			[]string{"/dev/nda2", "/dev/nda3"}, previewCase{usable: [2]uint64{0, 100 * gib}, redundancy: [2]int{1, 1}}},
+		{raidzLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", RaidType: "raidz", Devices: []string{"ada4", "ada5", "ada6"}},
+			[]string{"/dev/ada4", "/dev/ada5", "/dev/ada6"}, previewCase{usable: [2]uint64{2000 * gib, 3000 * gib}, redundancy: [2]int{1, 1}, warnings: []string{"mixed_device_sizes"}}},
+		{raidzLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", RaidType: "mirror", Devices: []string{"ada4", "ada5"}},
+			nil, previewCase{err: "mismatched_redundancy: pool uses 3-wide raidz1, new vdev is 2-wide mirror"}},
+		{singleLayout(), zfsServiceInterfaces.PoolVdevAdd{Class: "data", Devices: []string{"ada4", "ada5"}},
+			[]string{"/dev/ada4", "/dev/ada5"}, previewCase{usable: [2]uint64{1000 * gib, 3000 * gib}}},
+	}
+
+	for _, tt := range tests {
+		layout := tt.layout.Clone()
+		preview, paths, err := planAddVdev(tt.layout, tt.req)
+
+		label := fmt.Sprintf("planAddVdev(%+v)", tt.req)
+		checkPreview(t, label, preview, "", err, tt.want)
+
+		if !slices.Equal(paths, tt.paths) {
+			t.Errorf("%s paths = %v, want %v", label, paths, tt.paths)
+		}
+
+		if !reflect.DeepEqual(tt.layout, layout) {
+			t.Errorf("%s modified the current layout", label)
+		}
+	}
+}
+
+func TestPlanAttach(t *testing.T) {
+	stubDisks(t)
+
+	original := getPoolFeature
+	t.Cleanup(func() { getPoolFeature = original })
+
+	tests := []struct {
+		layout  zfs.PoolLayout
+		feature string
+		req     zfsServiceInterfaces.PoolDeviceAttach
+		device  string
+		want    previewCase
+	}{
+		{mirrorLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "ada0", Device: "ada4"}, "/dev/ada4",
+			previewCase{name: "/dev/ada0", usable: [2]uint64{1500 * gib, 1500 * gib}, redundancy: [2]int{1, 1}}},
+		{mirrorLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "/dev/ada2", Device: "ada4"}, "/dev/ada4",
+			previewCase{name: "/dev/ada2", usable: [2]uint64{1500 * gib, 1500 * gib}, redundancy: [2]int{1, 1}}},
+		{mirrorLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "ada0", Device: "ada6"}, "",
+			previewCase{err: "device_too_small: needs at least 1073741824000 bytes"}},
+		{mirrorLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "mirror-0", Device: "ada4"}, "",
+			previewCase{err: "cannot_attach_to_mirror"}},
+		{mirrorLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "nda0p2", Device: "nda2"}, "",
+			previewCase{err: "cannot_attach_to_cache_device"}},
+		{mirrorLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "ada9", Device: "ada4"}, "",
+			previewCase{err: "cannot_attach_to_spare_device"}},
+		{mirrorLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "ada0", Device: "ada1"}, "",
+			previewCase{err: "device_already_in_pool: /dev/ada1"}},
+		{mirrorLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "ada8", Device: "ada4"}, "",
+			previewCase{err: "target_not_found"}},
+		{mirrorLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "nda0p1", Device: "nda2"}, "/dev/nda2",
+			previewCase{name: "/dev/nda0p1", usable: [2]uint64{16 * gib, 16 * gib}, redundancy: [2]int{1, 1}}},
+		{singleLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "ada0", Device: "ada4"}, "/dev/ada4",
+			previewCase{name: "/dev/ada0", usable: [2]uint64{1000 * gib, 1000 * gib}, redundancy: [2]int{0, 1}}},
+		{singleLayout(), "", zfsServiceInterfaces.PoolDeviceAttach{Target: "ada0", Device: "ada6"}, "",
+			previewCase{err: "device_too_small: needs at least 1073741824000 bytes"}},
+		{raidzLayout(), "enabled", zfsServiceInterfaces.PoolDeviceAttach{Target: "raidz1-0", Device: "ada4"}, "/dev/ada4",
+			previewCase{name: "raidz1-0", usable: [2]uint64{2000 * gib, 3000 * gib}, redundancy: [2]int{1, 1}, warnings: []string{"existing_data_keeps_old_parity_ratio"}}},
+		{raidzLayout(), "active", zfsServiceInterfaces.PoolDeviceAttach{Target: "raidz1-0", Device: "ada6"}, "",
+			previewCase{err: "device_too_small: needs at least 1073741824000 bytes"}},
+		{raidzLayout(), "disabled", zfsServiceInterfaces.PoolDeviceAttach{Target: "raidz1-0", Device: "ada4"}, "",
+			previewCase{err: "raidz_expansion_unsupported"}},
+		{raidzLayout(), "enabled", zfsServiceInterfaces.PoolDeviceAttach{Target: "ada1", Device: "ada4"}, "",
+			previewCase{err: "cannot_attach_to_raidz1"}},
+	}
+
+	for _, tt := range tests {
+		getPoolFeature = func(pool string, feature string) string {
+			if pool != "tank" || feature != "raidz_expansion" {
+				t.Errorf("unexpected feature lookup %s@%s", pool, feature)
+			}
+			return tt.feature
+		}
+
+		preview, target, device, err := planAttach(&zfs.Zpool{Name: "tank"}, tt.layout, tt.req)
+
+		label := fmt.Sprintf("planAttach(%+v)", tt.req)
+		checkPreview(t, label, preview, target, err, tt.want)
+
+		if device != tt.device {
+			t.Errorf("%s device = %q, want %q", label, device, tt.device)
+		}
+	}
+}
+
+func TestPlanDetach(t *testing.T) {
+	logMirror := mirrorLayout()
+	logMirror.Log = []zfs.PoolVdev{vdev("mirror-2", 16*gib, 0, leaf("/dev/nda0p1", 16*gib), leaf("/dev/nda1p1", 16*gib))}
+
+	tests := []struct {
+		layout zfs.PoolLayout
+		device string
+		want   previewCase
+	}{
+		{mirrorLayout(), "ada0",
+			previewCase{name: "/dev/ada0", usable: [2]uint64{1500 * gib, 1500 * gib}, redundancy: [2]int{1, 0}, warnings: []string{"pool_loses_redundancy"}}},
+		{mirrorLayout(), "/dev/ada3",
+			previewCase{name: "/dev/ada3", usable: [2]uint64{1500 * gib, 1500 * gib}, redundancy: [2]int{1, 0}, warnings: []string{"pool_loses_redundancy"}}},
+		{logMirror, "nda1p1",
+			previewCase{name: "/dev/nda1p1", usable: [2]uint64{16 * gib, 16 * gib}, redundancy: [2]int{1, 1}}},
+		{replacingLayout(), "ada4",
+			previewCase{name: "/dev/ada4", usable: [2]uint64{1000 * gib, 1000 * gib}, redundancy: [2]int{0, 0}}},
+		{mirrorLayout(), "mirror-0", previewCase{err: "device_not_detachable: only mirror, replacing or spare members can be detached"}},
+		{mirrorLayout(), "nda0p1", previewCase{err: "device_not_detachable: only mirror, replacing or spare members can be detached"}},
+		{singleLayout(), "ada0", previewCase{err: "device_not_detachable: only mirror, replacing or spare members can be detached"}},
+		{raidzLayout(), "ada2", previewCase{err: "device_not_detachable: only mirror, replacing or spare members can be detached"}},
+		{mirrorLayout(), "ada8", previewCase{err: "device_not_found"}},
+	}
+
+	for _, tt := range tests {
+		preview, name, err := planDetach(tt.layout, tt.device)
+		checkPreview(t, fmt.Sprintf("planDetach(%s)", tt.device), preview, name, err, tt.want)
+	}
+}
+
+func TestPlanRemove(t *testing.T) {
+	tests := []struct {
+		layout zfs.PoolLayout
+		vdev   string
+		want   previewCase
+	}{
+		{mirrorLayout(), "mirror-1",
+			previewCase{name: "mirror-1", usable: [2]uint64{1500 * gib, 1000 * gib}, redundancy: [2]int{1, 1}, warnings: []string{"removal_leaves_indirect_mappings"}}},
+		{mirrorLayout(), "mirror-0",
+			previewCase{err: "insufficient_space_for_removal: need 429496729600 bytes, 322122547200 available"}},
+		{mirrorLayout(), "nda0p1",
+			previewCase{name: "/dev/nda0p1", usable: [2]uint64{16 * gib, 0}, redundancy: [2]int{1, 1}}},
+		{mirrorLayout(), "/dev/nda0p2",
+			previewCase{name: "/dev/nda0p2", usable: [2]uint64{200 * gib, 0}, redundancy: [2]int{1, 1}}},
+		{mirrorLayout(), "ada9",
+			previewCase{name: "/dev/ada9", usable: [2]uint64{1000 * gib, 0}, redundancy: [2]int{1, 1}}},
+		{mirrorLayout(), "ada0", previewCase{err: "not_a_top_level_vdev: detach mirror members instead"}},
+		{raidzLayout(), "mirror-1", previewCase{err: "device_removal_unsupported_with_raidz"}},
+		{raidzLayout(), "raidz1-0", previewCase{err: "device_removal_unsupported_with_raidz"}},
+		{singleLayout(), "ada0", previewCase{err: "cannot_remove_last_data_vdev"}},
+		{mirrorLayout(), "mirror-7", previewCase{err: "vdev_not_found"}},
+	}
+
+	for _, tt := range tests {
+		preview, name, err := planRemove(tt.layout, tt.vdev)
+		checkPreview(t, fmt.Sprintf("planRemove(%s)", tt.vdev), preview, name, err, tt.want)
+	}
+}
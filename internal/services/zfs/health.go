@@ -0,0 +1,310 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfs
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alchemillahq/sylve/internal/db"
+	zfsModels "github.com/alchemillahq/sylve/internal/db/models/zfs"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+const poolEventLimit = 5000
+
+func stateSeverity(state string) string {
+	switch state {
+	case "ONLINE", "AVAIL", "INUSE":
+		return zfsModels.PoolEventSeverityInfo
+	case "DEGRADED", "OFFLINE", "REMOVED":
+		return zfsModels.PoolEventSeverityWarning
+	}
+
+	return zfsModels.PoolEventSeverityCritical
+}
+
+// leafHealth flattens the device tree of a pool to its leaf devices. The
+// state of the vdevs above them follows from the leaves and the pool state,
+// so reporting those as well would only repeat the same failure.
+func leafHealth(devices []*zfs.ZpoolDevice, out map[string]zfsModels.DeviceHealth) {
+	for _, dev := range devices {
+		if len(dev.Children) > 0 {
+			leafHealth(dev.Children, out)
+			continue
+		}
+
+		if dev.State == "" {
+			continue
+		}
+
+		out[dev.Name] = zfsModels.DeviceHealth{
+			State: dev.State,
+			Read:  dev.Read,
+			Write: dev.Write,
+			Cksum: dev.Cksum,
+		}
+	}
+}
+
+func poolHealth(pool *zfs.Zpool, status zfs.ZpoolStatus) zfsModels.PoolHealthState {
+	health := zfsModels.PoolHealthState{
+		PoolGUID: pool.GUID,
+		Pool:     pool.Name,
+		State:    status.State,
+		Devices:  map[string]zfsModels.DeviceHealth{},
+	}
+
+	for _, root := range status.Devices {
+		leafHealth(root.Children, health.Devices)
+	}
+
+	return health
+}
+
+// healthEvents compares two observations of the same pool and describes
+// what changed between them.
+func healthEvents(previous, current zfsModels.PoolHealthState) []zfsModels.PoolEvent {
+	var events []zfsModels.PoolEvent
+
+	event := func(kind, severity, device, from, to, message string) {
+		events = append(events, zfsModels.PoolEvent{
+			PoolGUID: current.PoolGUID,
+			Pool:     current.Pool,
+			Type:     kind,
+			Severity: severity,
+			Device:   device,
+			Previous: from,
+			Current:  to,
+			Message:  message,
+		})
+	}
+
+	if previous.State != current.State {
+		event("pool_state_changed", stateSeverity(current.State), "", previous.State, current.State,
+			fmt.Sprintf("pool %s changed state from %s to %s", current.Pool, previous.State, current.State))
+	}
+
+	names := make([]string, 0, len(current.Devices))
+	for name := range current.Devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cur := current.Devices[name]
+		prev, known := previous.Devices[name]
+
+		if !known {
+			event("device_added", zfsModels.PoolEventSeverityInfo, name, "", cur.State,
+				fmt.Sprintf("device %s appeared in pool %s as %s", name, current.Pool, cur.State))
+			continue
+		}
+
+		if prev.State != cur.State {
+			kind := "device_state_changed"
+			if cur.State == "REMOVED" {
+				kind = "device_removed"
+			}
+
+			event(kind, stateSeverity(cur.State), name, prev.State, cur.State,
+				fmt.Sprintf("device %s in pool %s changed state from %s to %s", name, current.Pool, prev.State, cur.State))
+		}
+
+		if cur.Cksum > prev.Cksum {
+			event("checksum_errors", zfsModels.PoolEventSeverityWarning, name, fmt.Sprint(prev.Cksum), fmt.Sprint(cur.Cksum),
+				fmt.Sprintf("device %s in pool %s has %d checksum errors (was %d)", name, current.Pool, cur.Cksum, prev.Cksum))
+		}
+
+		if cur.Read > prev.Read || cur.Write > prev.Write {
+			event("io_errors", zfsModels.PoolEventSeverityWarning, name,
+				fmt.Sprintf("%d/%d", prev.Read, prev.Write), fmt.Sprintf("%d/%d", cur.Read, cur.Write),
+				fmt.Sprintf("device %s in pool %s has %d read and %d write errors (was %d and %d)",
+					name, current.Pool, cur.Read, cur.Write, prev.Read, prev.Write))
+		}
+	}
+
+	gone := make([]string, 0)
+	for name := range previous.Devices {
+		if _, ok := current.Devices[name]; !ok {
+			gone = append(gone, name)
+		}
+	}
+	sort.Strings(gone)
+
+	for _, name := range gone {
+		event("device_detached", zfsModels.PoolEventSeverityInfo, name, previous.Devices[name].State, "",
+			fmt.Sprintf("device %s is no longer part of pool %s", name, current.Pool))
+	}
+
+	return events
+}
+
+func (s *Service) raisePoolEvent(event zfsModels.PoolEvent) {
+	if event.Severity == zfsModels.PoolEventSeverityInfo {
+		logger.L.Info().Msgf("zfs_health: %s", event.Message)
+	} else {
+		logger.L.Warn().Str("severity", event.Severity).Msgf("zfs_health: %s", event.Message)
+	}
+
+	db.StoreAndTrimRecords(s.DB, &event, poolEventLimit)
+}
+
+func (s *Service) checkHealth(pool *zfs.Zpool, status zfs.ZpoolStatus) {
+	current := poolHealth(pool, status)
+
+	var previous zfsModels.PoolHealthState
+	if err := s.DB.Where("pool_guid = ?", pool.GUID).Limit(1).Find(&previous).Error; err != nil {
+		logger.L.Debug().Err(err).Msgf("zfs_health: failed to load health of %s", pool.Name)
+		return
+	}
+
+	if previous.PoolGUID == "" {
+		// First sighting, there is nothing to compare against yet, but a
+		// pool that is already unhealthy is still worth an alert
+		if current.State != "ONLINE" {
+			s.raisePoolEvent(zfsModels.PoolEvent{
+				PoolGUID: current.PoolGUID,
+				Pool:     current.Pool,
+				Type:     "pool_state_changed",
+				Severity: stateSeverity(current.State),
+				Current:  current.State,
+				Message:  fmt.Sprintf("pool %s is %s", current.Pool, current.State),
+			})
+		}
+	} else {
+		for _, event := range healthEvents(previous, current) {
+			s.raisePoolEvent(event)
+		}
+	}
+
+	if err := s.DB.Save(&current).Error; err != nil {
+		logger.L.Debug().Err(err).Msgf("zfs_health: failed to save health of %s", pool.Name)
+	}
+}
+
+// recordScan stores the outcome of the last scrub or resilver once, the
+// first time it is seen after completing.
+func (s *Service) recordScan(pool *zfs.Zpool, status zfs.ZpoolStatus) {
+	scan := zfs.ParseScan(status.Scan)
+	if scan.State != zfs.ScanStateFinished && scan.State != zfs.ScanStateCanceled {
+		return
+	}
+
+	if scan.EndedAt.IsZero() {
+		return
+	}
+
+	var last zfsModels.PoolScrubResult
+	if err := s.DB.Where("pool_guid = ? AND function = ?", pool.GUID, scan.Function).
+		Order("ended_at DESC").Limit(1).Find(&last).Error; err != nil {
+		logger.L.Debug().Err(err).Msgf("zfs_health: failed to load scrub history of %s", pool.Name)
+		return
+	}
+
+	if last.ID != 0 && !scan.EndedAt.After(last.EndedAt) {
+		return
+	}
+
+	result := zfsModels.PoolScrubResult{
+		PoolGUID:  pool.GUID,
+		Pool:      pool.Name,
+		Function:  scan.Function,
+		State:     scan.State,
+		StartedAt: scan.StartedAt,
+		EndedAt:   scan.EndedAt,
+		Duration:  scan.Duration,
+		Repaired:  scan.Repaired,
+		Errors:    scan.Errors,
+	}
+
+	if err := s.DB.Create(&result).Error; err != nil {
+		logger.L.Debug().Err(err).Msgf("zfs_health: failed to store scrub result of %s", pool.Name)
+		return
+	}
+
+	event := zfsModels.PoolEvent{
+		PoolGUID: pool.GUID,
+		Pool:     pool.Name,
+		Type:     scan.Function + "_" + scan.State,
+		Severity: zfsModels.PoolEventSeverityInfo,
+		Current:  scan.State,
+	}
+
+	switch {
+	case scan.State == zfs.ScanStateCanceled:
+		event.Message = fmt.Sprintf("%s of pool %s was canceled", scan.Function, pool.Name)
+	default:
+		if scan.Errors > 0 {
+			event.Severity = zfsModels.PoolEventSeverityCritical
+		} else if scan.Repaired > 0 {
+			event.Severity = zfsModels.PoolEventSeverityWarning
+		}
+
+		event.Message = fmt.Sprintf("%s of pool %s finished in %s, repaired %d bytes with %d errors",
+			scan.Function, pool.Name, time.Duration(scan.Duration)*time.Second, scan.Repaired, scan.Errors)
+	}
+
+	s.raisePoolEvent(event)
+}
+
+// CheckPoolHealth reads the status of every pool, records finished scrubs
+// and raises events for anything that changed since the last check.
+func (s *Service) CheckPoolHealth() {
+	pools, err := zfs.ListZpools()
+	if err != nil {
+		logger.L.Debug().Err(err).Msg("zfs_health: failed to list zpools")
+		return
+	}
+
+	for _, pool := range pools {
+		status, err := zfs.GetZpoolStatus(pool.Name)
+		if err != nil {
+			logger.L.Debug().Err(err).Msgf("zfs_health: failed to get status of %s", pool.Name)
+			continue
+		}
+
+		s.recordScan(pool, status)
+		s.checkHealth(pool, status)
+	}
+}
+
+func (s *Service) GetPoolEvents(guid string) ([]zfsModels.PoolEvent, error) {
+	var events []zfsModels.PoolEvent
+	if err := s.DB.Where("pool_guid = ?", guid).Order("id DESC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s *Service) GetPoolAlerts() ([]zfsModels.PoolEvent, error) {
+	var events []zfsModels.PoolEvent
+	if err := s.DB.Where("acknowledged = ? AND severity <> ?", false, zfsModels.PoolEventSeverityInfo).
+		Order("id DESC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s *Service) AcknowledgePoolEvent(id uint) error {
+	result := s.DB.Model(&zfsModels.PoolEvent{}).Where("id = ?", id).Update("acknowledged", true)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("pool_event_not_found")
+	}
+
+	return nil
+}
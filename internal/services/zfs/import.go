@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	iscsiModels "github.com/alchemillahq/sylve/internal/db/models/iscsi"
+	jailModels "github.com/alchemillahq/sylve/internal/db/models/jail"
+	nfsModels "github.com/alchemillahq/sylve/internal/db/models/nfs"
+	sambaModels "github.com/alchemillahq/sylve/internal/db/models/samba"
+	vmModels "github.com/alchemillahq/sylve/internal/db/models/vm"
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+func validateSearchDirs(dirs []string) error {
+	for _, dir := range dirs {
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("invalid_search_dir: %s", dir)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) ImportablePools(dirs []string) ([]zfs.ImportablePool, error) {
+	if err := validateSearchDirs(dirs); err != nil {
+		return nil, err
+	}
+
+	pools, err := zfs.ImportablePools(dirs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_scan_importable_pools: %v", err)
+	}
+
+	return pools, nil
+}
+
+func (s *Service) ImportPool(req zfsServiceInterfaces.ImportPool) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	if err := validateSearchDirs(req.Dirs); err != nil {
+		return err
+	}
+
+	if req.AltRoot != "" && !filepath.IsAbs(req.AltRoot) {
+		return fmt.Errorf("invalid_altroot")
+	}
+
+	importable, err := zfs.ImportablePools(req.Dirs...)
+	if err != nil {
+		return fmt.Errorf("failed_to_scan_importable_pools: %v", err)
+	}
+
+	var matches []zfs.ImportablePool
+	for _, p := range importable {
+		if p.GUID == req.Pool || p.Name == req.Pool {
+			matches = append(matches, p)
+		}
+	}
+
+	if len(matches) == 0 {
+		return fmt.Errorf("pool_not_importable")
+	}
+
+	if len(matches) > 1 {
+		return fmt.Errorf("ambiguous_pool_name_use_guid")
+	}
+
+	target := matches[0].Name
+	if req.NewName != "" {
+		if !zfs.IsValidPoolName(req.NewName) {
+			return fmt.Errorf("invalid_pool_name")
+		}
+		target = req.NewName
+	}
+
+	if _, err := zfs.GetZpool(target); err == nil {
+		return fmt.Errorf("pool_name_taken")
+	}
+
+	err = zfs.ImportPool(matches[0].GUID, zfs.ImportOptions{
+		NewName:  req.NewName,
+		ReadOnly: req.ReadOnly,
+		AltRoot:  req.AltRoot,
+		Force:    req.Force,
+		Dirs:     req.Dirs,
+	})
+
+	if err != nil {
+		return fmt.Errorf("zpool_import_failed: %v", err)
+	}
+
+	return s.SyncToLibvirt()
+}
+
+// poolUsage reports the first thing that still depends on a dataset inside
+// pool. Unlike IsDatasetInUse, a stopped VM still counts, since exporting
+// the pool would leave its disks dangling.
+func (s *Service) poolUsage(pool *zfs.Zpool) error {
+	datasets, err := pool.Datasets()
+	if err != nil {
+		return fmt.Errorf("failed_to_get_datasets: %v", err)
+	}
+
+	guids := make([]string, 0, len(datasets))
+	for _, ds := range datasets {
+		guids = append(guids, ds.GUID)
+	}
+
+	if len(guids) > 0 {
+		var storage vmModels.Storage
+		if err := s.DB.Where("dataset IN ?", guids).First(&storage).Error; err == nil {
+			var vm vmModels.VM
+			if err := s.DB.First(&vm, storage.VMID).Error; err == nil {
+				return fmt.Errorf("pool_in_use_by_vm: %s", vm.Name)
+			}
+			return fmt.Errorf("pool_in_use_by_vm: %d", storage.VMID)
+		}
+
+		var jail jailModels.Jail
+		if err := s.DB.Where("dataset IN ?", guids).First(&jail).Error; err == nil {
+			return fmt.Errorf("pool_in_use_by_jail: %s", jail.Name)
+		}
+
+		var delegated jailModels.DelegatedDataset
+		if err := s.DB.Where("guid IN ?", guids).First(&delegated).Error; err == nil {
+			if err := s.DB.First(&jail, delegated.JailID).Error; err == nil {
+				return fmt.Errorf("pool_in_use_by_jail: %s", jail.Name)
+			}
+			return fmt.Errorf("pool_in_use_by_jail: %d", delegated.JailID)
+		}
+
+		var share sambaModels.SambaShare
+		if err := s.DB.Where("dataset IN ?", guids).First(&share).Error; err == nil {
+			return fmt.Errorf("pool_in_use_by_samba_share: %s", share.Name)
+		}
+
+		var export nfsModels.NFSExport
+		if err := s.DB.Where("dataset IN ?", guids).First(&export).Error; err == nil {
+			return fmt.Errorf("pool_in_use_by_nfs_export: %d", export.ID)
+		}
+
+		var lun iscsiModels.ISCSILUN
+		if err := s.DB.Where("dataset IN ?", guids).First(&lun).Error; err == nil {
+			var target iscsiModels.ISCSITarget
+			if err := s.DB.First(&target, lun.TargetID).Error; err == nil {
+				return fmt.Errorf("pool_in_use_by_iscsi_target: %s", target.Name)
+			}
+			return fmt.Errorf("pool_in_use_by_iscsi_target: %d", lun.TargetID)
+		}
+	}
+
+	sPools, err := s.Libvirt.ListStoragePools()
+	if err != nil {
+		return fmt.Errorf("failed_to_list_libvirt_pools: %v", err)
+	}
+
+	for _, sp := range sPools {
+		if sp.Name == pool.Name {
+			continue
+		}
+
+		if sp.Source == pool.Name || strings.HasPrefix(sp.Source, pool.Name+"/") {
+			return fmt.Errorf("pool_in_use_by_libvirt_pool: %s", sp.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) ExportPool(guid string, force bool) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	pool, err := zfs.GetZpoolByGUID(guid)
+	if err != nil {
+		return fmt.Errorf("pool_not_found")
+	}
+
+	if err := s.poolUsage(pool); err != nil {
+		return err
+	}
+
+	if err := pool.Export(force); err != nil {
+		return fmt.Errorf("zpool_export_failed: %v", err)
+	}
+
+	if err := s.Libvirt.DeleteStoragePool(pool.Name); err != nil {
+		if !strings.Contains(err.Error(), "failed to lookup storage pool") &&
+			!strings.Contains(err.Error(), "Storage pool not found") {
+			return err
+		}
+	}
+
+	return s.SyncToLibvirt()
+}
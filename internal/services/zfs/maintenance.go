@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	zfsModels "github.com/alchemillahq/sylve/internal/db/models/zfs"
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+	"github.com/robfig/cron/v3"
+)
+
+func (s *Service) GetPoolMaintenance(guid string) ([]zfsModels.PoolMaintenance, error) {
+	var jobs []zfsModels.PoolMaintenance
+	if err := s.DB.Where("pool_guid = ?", guid).Order("task ASC").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (s *Service) SetPoolMaintenance(guid string, req zfsServiceInterfaces.PoolMaintenance) error {
+	if req.Task != zfsModels.PoolTaskScrub && req.Task != zfsModels.PoolTaskTrim {
+		return fmt.Errorf("invalid_task: %s", req.Task)
+	}
+
+	if _, err := cron.ParseStandard(req.CronExpr); err != nil {
+		return fmt.Errorf("invalid_cron_expr: %v", err)
+	}
+
+	if _, err := zfs.GetZpoolByGUID(guid); err != nil {
+		return fmt.Errorf("pool_not_found")
+	}
+
+	var job zfsModels.PoolMaintenance
+	err := s.DB.Where("pool_guid = ? AND task = ?", guid, req.Task).
+		Attrs(zfsModels.PoolMaintenance{PoolGUID: guid, Task: req.Task}).
+		FirstOrInit(&job).Error
+	if err != nil {
+		return err
+	}
+
+	job.CronExpr = req.CronExpr
+	job.Enabled = req.Enabled
+
+	return s.DB.Save(&job).Error
+}
+
+func (s *Service) DeletePoolMaintenance(guid string, task string) error {
+	result := s.DB.Where("pool_guid = ? AND task = ?", guid, task).Delete(&zfsModels.PoolMaintenance{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("maintenance_not_found")
+	}
+
+	return nil
+}
+
+func (s *Service) TrimPool(guid string) error {
+	pool, err := zfs.GetZpoolByGUID(guid)
+	if err != nil {
+		return fmt.Errorf("pool_not_found")
+	}
+
+	if err := pool.Trim(); err != nil {
+		return fmt.Errorf("pool_trim_failed: %v", err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetScrubHistory(guid string) ([]zfsModels.PoolScrubResult, error) {
+	var results []zfsModels.PoolScrubResult
+	if err := s.DB.Where("pool_guid = ?", guid).Order("ended_at DESC").Find(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (s *Service) runPoolMaintenance(job zfsModels.PoolMaintenance) error {
+	pool, err := zfs.GetZpoolByGUID(job.PoolGUID)
+	if err != nil {
+		return fmt.Errorf("pool_not_found")
+	}
+
+	switch job.Task {
+	case zfsModels.PoolTaskScrub:
+		status, err := zfs.GetZpoolStatus(pool.Name)
+		if err != nil {
+			return err
+		}
+
+		if scan := zfs.ParseScan(status.Scan); scan.State == zfs.ScanStateScanning {
+			return fmt.Errorf("scan_in_progress: %s", scan.Function)
+		}
+
+		return zfs.ScrubPool(job.PoolGUID)
+	case zfsModels.PoolTaskTrim:
+		return pool.Trim()
+	}
+
+	return fmt.Errorf("invalid_task: %s", job.Task)
+}
+
+func (s *Service) StartPoolMaintenanceScheduler(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			var jobs []zfsModels.PoolMaintenance
+			if err := s.DB.Where("enabled = ?", true).Find(&jobs).Error; err != nil {
+				logger.L.Debug().Err(err).Msg("Failed to load pool maintenance jobs")
+				continue
+			}
+
+			now := time.Now()
+
+			for _, job := range jobs {
+				due, err := utils.CronDue(job.CronExpr, job.LastRunAt, job.CreatedAt, now)
+				if err != nil {
+					logger.L.Debug().Err(err).Msgf("Invalid cron expression for pool maintenance %d", job.ID)
+					continue
+				}
+
+				if !due {
+					continue
+				}
+
+				lastError := ""
+				if err := s.runPoolMaintenance(job); err != nil {
+					lastError = err.Error()
+					logger.L.Warn().Err(err).Msgf("Scheduled %s of pool %s failed", job.Task, job.PoolGUID)
+				}
+
+				if err := s.DB.Model(&job).Updates(map[string]any{
+					"last_run_at": now,
+					"last_error":  lastError,
+				}).Error; err != nil {
+					logger.L.Debug().Err(err).Msgf("Failed to update LastRunAt for %d", job.ID)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/alchemillahq/sylve/internal/db"
 	infoModels "github.com/alchemillahq/sylve/internal/db/models/info"
+	zfsModels "github.com/alchemillahq/sylve/internal/db/models/zfs"
 	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
 	"github.com/alchemillahq/sylve/pkg/disk"
 	"github.com/alchemillahq/sylve/pkg/zfs"
@@ -43,13 +44,7 @@ func (s *Service) CreatePool(pool zfsServiceInterfaces.Zpool) error {
 	}
 
 	if pool.RaidType != "" {
-		validRaidTypes := map[string]int{
-			"mirror": 2,
-			"raidz":  3,
-			"raidz2": 4,
-			"raidz3": 5,
-		}
-		minDevices, ok := validRaidTypes[pool.RaidType]
+		minDevices, ok := minVdevDevices[pool.RaidType]
 		if !ok {
 			return fmt.Errorf("invalid_raidz_type")
 		}
@@ -136,6 +131,17 @@ func (s *Service) DeletePool(guid string) error {
 		return fmt.Errorf("failed_to_delete_historical_data: %v", result.Error)
 	}
 
+	for _, model := range []any{
+		&zfsModels.PoolMaintenance{},
+		&zfsModels.PoolScrubResult{},
+		&zfsModels.PoolEvent{},
+		&zfsModels.PoolHealthState{},
+	} {
+		if err := s.DB.Where("pool_guid = ?", guid).Delete(model).Error; err != nil {
+			return fmt.Errorf("failed_to_delete_pool_records: %v", err)
+		}
+	}
+
 	if err := s.Libvirt.DeleteStoragePool(pool.Name); err != nil {
 		if !strings.Contains(err.Error(), "failed to lookup storage pool") &&
 			!strings.Contains(err.Error(), "Storage pool not found") {
@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alchemillahq/sylve/internal/db"
+	zfsModels "github.com/alchemillahq/sylve/internal/db/models/zfs"
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+// periodicSnapshotLayout is the timestamp StartSnapshotScheduler appends to
+// the job prefix.
+const periodicSnapshotLayout = "2006-01-02-15-04"
+
+type jobSnapshot struct {
+	name    string
+	created time.Time
+}
+
+// jobSnapshotTime returns when a snapshot was taken if its name belongs to
+// the job, i.e. is exactly prefix-YYYY-MM-DD-HH-MM.
+func jobSnapshotTime(prefix string, snapName string) (time.Time, bool) {
+	stamp, ok := strings.CutPrefix(snapName, prefix+"-")
+	if !ok || len(stamp) != len(periodicSnapshotLayout) {
+		return time.Time{}, false
+	}
+
+	t, err := time.ParseInLocation(periodicSnapshotLayout, stamp, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// applyRetention decides, newest first, which snapshots a policy keeps and
+// why. Each count keeps the newest snapshot of that many distinct periods.
+func applyRetention(r zfsModels.SnapshotRetention, snaps []jobSnapshot, now time.Time) []zfsServiceInterfaces.SnapshotRetentionItem {
+	slices.SortFunc(snaps, func(a, b jobSnapshot) int {
+		return b.created.Compare(a.created)
+	})
+
+	reasons := make([][]string, len(snaps))
+
+	for i := range snaps {
+		if i < r.KeepLast {
+			reasons[i] = append(reasons[i], "last")
+		}
+	}
+
+	periods := []struct {
+		count  int
+		reason string
+		key    func(time.Time) string
+	}{
+		{r.KeepHourly, "hourly", func(t time.Time) string { return t.Format("2006-01-02-15") }},
+		{r.KeepDaily, "daily", func(t time.Time) string { return t.Format("2006-01-02") }},
+		{r.KeepWeekly, "weekly", func(t time.Time) string {
+			y, w := t.ISOWeek()
+			return fmt.Sprintf("%d-%02d", y, w)
+		}},
+		{r.KeepMonthly, "monthly", func(t time.Time) string { return t.Format("2006-01") }},
+	}
+
+	for _, p := range periods {
+		if p.count <= 0 {
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for i, snap := range snaps {
+			key := p.key(snap.created)
+			if seen[key] {
+				continue
+			}
+
+			if len(seen) >= p.count {
+				break
+			}
+
+			seen[key] = true
+			reasons[i] = append(reasons[i], p.reason)
+		}
+	}
+
+	items := make([]zfsServiceInterfaces.SnapshotRetentionItem, len(snaps))
+	for i, snap := range snaps {
+		item := zfsServiceInterfaces.SnapshotRetentionItem{
+			Snapshot:  snap.name,
+			CreatedAt: snap.created,
+			Reasons:   reasons[i],
+		}
+
+		if !r.HasCounts() {
+			item.Reasons = append(item.Reasons, "no_count_rules")
+		}
+
+		item.Keep = len(item.Reasons) > 0
+
+		if r.MaxAge > 0 && now.Sub(snap.created) > time.Duration(r.MaxAge)*time.Second {
+			item.Keep = false
+			item.Reasons = []string{"max_age"}
+		} else if !item.Keep {
+			item.Reasons = []string{"not_selected"}
+		}
+
+		items[i] = item
+	}
+
+	return items
+}
+
+// snapshotBlocker reports why a snapshot cannot be destroyed, if anything:
+// a user hold or a clone depending on it.
+func snapshotBlocker(snap *zfs.Dataset) (string, error) {
+	props, err := snap.GetProperties("userrefs", "clones")
+	if err != nil {
+		return "", err
+	}
+
+	if props[0] != "" && props[0] != "-" && props[0] != "0" {
+		return "held", nil
+	}
+
+	if props[1] != "" && props[1] != "-" {
+		return "has_clones", nil
+	}
+
+	return "", nil
+}
+
+// planRetention evaluates the job's policy against the snapshots the job
+// created. Only names on the job's own dataset are considered; for
+// recursive jobs a snapshot is blocked when any descendant copy is.
+func (s *Service) planRetention(job zfsModels.PeriodicSnapshot) ([]zfsServiceInterfaces.SnapshotRetentionItem, error) {
+	dataset, err := s.GetDatasetByGUID(job.GUID)
+	if err != nil {
+		return nil, fmt.Errorf("dataset_not_found: %w", err)
+	}
+
+	all, err := zfs.Snapshots(dataset.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var snaps []jobSnapshot
+	copies := make(map[string][]*zfs.Dataset)
+
+	for _, snap := range all {
+		dsName, snapName, ok := strings.Cut(snap.Name, "@")
+		if !ok {
+			continue
+		}
+
+		if dsName != dataset.Name && !(job.Recursive && strings.HasPrefix(dsName, dataset.Name+"/")) {
+			continue
+		}
+
+		created, ok := jobSnapshotTime(job.Prefix, snapName)
+		if !ok {
+			continue
+		}
+
+		copies[snapName] = append(copies[snapName], snap)
+		if dsName == dataset.Name {
+			snaps = append(snaps, jobSnapshot{name: snap.Name, created: created})
+		}
+	}
+
+	items := applyRetention(job.Retention, snaps, time.Now())
+
+	for i := range items {
+		if items[i].Keep {
+			continue
+		}
+
+		_, snapName, _ := strings.Cut(items[i].Snapshot, "@")
+		for _, snap := range copies[snapName] {
+			blocker, err := snapshotBlocker(snap)
+			if err != nil {
+				return nil, fmt.Errorf("failed_to_check_snapshot %s: %w", snap.Name, err)
+			}
+
+			if blocker != "" {
+				items[i].Blocked = fmt.Sprintf("%s: %s", blocker, snap.Name)
+				break
+			}
+		}
+	}
+
+	return items, nil
+}
+
+func validateRetention(r zfsModels.SnapshotRetention) error {
+	if r.KeepLast < 0 || r.KeepHourly < 0 || r.KeepDaily < 0 ||
+		r.KeepWeekly < 0 || r.KeepMonthly < 0 || r.MaxAge < 0 {
+		return fmt.Errorf("retention_values_must_not_be_negative")
+	}
+
+	return nil
+}
+
+func (s *Service) SetSnapshotRetention(id uint, retention zfsModels.SnapshotRetention) error {
+	if err := validateRetention(retention); err != nil {
+		return err
+	}
+
+	var job zfsModels.PeriodicSnapshot
+	if err := s.DB.First(&job, id).Error; err != nil {
+		return fmt.Errorf("periodic_snapshot_not_found")
+	}
+
+	return s.DB.Model(&job).
+		Updates(map[string]any{
+			"retention_keep_last":    retention.KeepLast,
+			"retention_keep_hourly":  retention.KeepHourly,
+			"retention_keep_daily":   retention.KeepDaily,
+			"retention_keep_weekly":  retention.KeepWeekly,
+			"retention_keep_monthly": retention.KeepMonthly,
+			"retention_max_age":      retention.MaxAge,
+		}).Error
+}
+
+func (s *Service) PreviewSnapshotRetention(id uint) ([]zfsServiceInterfaces.SnapshotRetentionItem, error) {
+	var job zfsModels.PeriodicSnapshot
+	if err := s.DB.First(&job, id).Error; err != nil {
+		return nil, fmt.Errorf("periodic_snapshot_not_found")
+	}
+
+	return s.planRetention(job)
+}
+
+// PruneSnapshots destroys the job's snapshots its retention policy no longer
+// keeps and returns the plan that was applied.
+func (s *Service) PruneSnapshots(id uint) ([]zfsServiceInterfaces.SnapshotRetentionItem, error) {
+	var job zfsModels.PeriodicSnapshot
+	if err := s.DB.First(&job, id).Error; err != nil {
+		return nil, fmt.Errorf("periodic_snapshot_not_found")
+	}
+
+	return s.pruneSnapshotJob(job)
+}
+
+func (s *Service) pruneSnapshotJob(job zfsModels.PeriodicSnapshot) ([]zfsServiceInterfaces.SnapshotRetentionItem, error) {
+	if !job.Retention.Enabled() {
+		return nil, nil
+	}
+
+	items, err := s.planRetention(job)
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	for _, item := range items {
+		if item.Keep || item.Blocked != "" {
+			continue
+		}
+
+		entry := &zfsModels.SnapshotPruneLog{
+			JobID:    job.ID,
+			Snapshot: item.Snapshot,
+			Reason:   strings.Join(item.Reasons, ","),
+		}
+
+		snaps, err := zfs.Snapshots(item.Snapshot)
+		if err == nil && len(snaps) == 0 {
+			err = fmt.Errorf("snapshot_not_found")
+		}
+
+		if err == nil {
+			if job.Recursive {
+				err = snaps[0].Destroy(zfs.DestroyRecursive)
+			} else {
+				err = snaps[0].Destroy(zfs.DestroyDefault)
+			}
+		}
+
+		if err != nil {
+			entry.Error = err.Error()
+			logger.L.Warn().Err(err).Str("snapshot", item.Snapshot).Msg("Failed to prune snapshot")
+		}
+
+		db.StoreAndTrimRecords(s.DB, entry, 1000)
+	}
+
+	if err := s.DB.Model(&job).Update("LastPrunedAt", time.Now()).Error; err != nil {
+		logger.L.Debug().Err(err).Msgf("Failed to update LastPrunedAt for %d", job.ID)
+	}
+
+	return items, nil
+}
+
+func (s *Service) GetSnapshotPruneLogs(id uint) ([]zfsModels.SnapshotPruneLog, error) {
+	var logs []zfsModels.SnapshotPruneLog
+	if err := s.DB.Where("job_id = ?", id).Order("id DESC").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	zfsModels "github.com/alchemillahq/sylve/internal/db/models/zfs"
+)
+
+func TestJobSnapshotTime(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		snapName string
+		expected string
+	}{
+		{"auto", "auto-2025-03-10-12-00", "2025-03-10 12:00"},
+		{"auto-daily", "auto-daily-2025-01-31-00-05", "2025-01-31 00:05"},
+		{"auto", "auto-daily-2025-03-10-12-00", ""},
+		{"auto", "autox-2025-03-10-12-00", ""},
+		{"auto", "auto-2025-13-10-12-00", ""},
+		{"auto", "auto-2025-03-10-12-00-1", ""},
+		{"auto", "auto-2025-3-10-12-000", ""},
+		{"auto", "manual", ""},
+		{"auto", "", ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := jobSnapshotTime(tt.prefix, tt.snapName)
+
+		want := tt.expected != ""
+		if ok != want {
+			t.Errorf("jobSnapshotTime(%q, %q) ok = %v, want %v", tt.prefix, tt.snapName, ok, want)
+			continue
+		}
+
+		if ok && got.Format("2006-01-02 15:04") != tt.expected {
+			t.Errorf("jobSnapshotTime(%q, %q) = %v, want %s", tt.prefix, tt.snapName, got, tt.expected)
+		}
+	}
+}
+
+func TestApplyRetention(t *testing.T) {
+	// zfs list -H -o name -t snapshot -s name tank/data, as created by a job
+	// with prefix "auto"; ISO weeks 11, 11, 11, 10, 9 and 8 respectively.
+	fixture := `tank/data@auto-2025-02-20-10-00
+tank/data@auto-2025-03-02-10-00
+tank/data@auto-2025-03-09-23-00
+tank/data@auto-2025-03-10-11-00
+tank/data@auto-2025-03-10-11-30
+tank/data@auto-2025-03-10-12-00`
+
+	var snaps []jobSnapshot
+	for _, name := range strings.Split(fixture, "\n") {
+		_, snapName, _ := strings.Cut(name, "@")
+		created, ok := jobSnapshotTime("auto", snapName)
+		if !ok {
+			t.Fatalf("fixture snapshot %q does not match the job", name)
+		}
+
+		snaps = append(snaps, jobSnapshot{name: name, created: created})
+	}
+
+	now := time.Date(2025, 3, 10, 13, 0, 0, 0, time.Local)
+	day := 24 * 60 * 60
+
+	tests := []struct {
+		retention zfsModels.SnapshotRetention
+		expected  []string
+	}{
+		{zfsModels.SnapshotRetention{KeepLast: 2}, []string{
+			"03-10-12-00 keep [last]", "03-10-11-30 keep [last]", "03-10-11-00 drop [not_selected]",
+			"03-09-23-00 drop [not_selected]", "03-02-10-00 drop [not_selected]", "02-20-10-00 drop [not_selected]",
+		}},
+		{zfsModels.SnapshotRetention{KeepHourly: 2}, []string{
+			"03-10-12-00 keep [hourly]", "03-10-11-30 keep [hourly]", "03-10-11-00 drop [not_selected]",
+			"03-09-23-00 drop [not_selected]", "03-02-10-00 drop [not_selected]", "02-20-10-00 drop [not_selected]",
+		}},
+		{zfsModels.SnapshotRetention{KeepDaily: 3}, []string{
+			"03-10-12-00 keep [daily]", "03-10-11-30 drop [not_selected]", "03-10-11-00 drop [not_selected]",
+			"03-09-23-00 keep [daily]", "03-02-10-00 keep [daily]", "02-20-10-00 drop [not_selected]",
+		}},
+		{zfsModels.SnapshotRetention{KeepWeekly: 3}, []string{
+			"03-10-12-00 keep [weekly]", "03-10-11-30 drop [not_selected]", "03-10-11-00 drop [not_selected]",
+			"03-09-23-00 keep [weekly]", "03-02-10-00 keep [weekly]", "02-20-10-00 drop [not_selected]",
+		}},
+		{zfsModels.SnapshotRetention{KeepMonthly: 2}, []string{
+			"03-10-12-00 keep [monthly]", "03-10-11-30 drop [not_selected]", "03-10-11-00 drop [not_selected]",
+			"03-09-23-00 drop [not_selected]", "03-02-10-00 drop [not_selected]", "02-20-10-00 keep [monthly]",
+		}},
+		{zfsModels.SnapshotRetention{KeepLast: 1, KeepDaily: 2, KeepMonthly: 12}, []string{
+			"03-10-12-00 keep [last daily monthly]", "03-10-11-30 drop [not_selected]", "03-10-11-00 drop [not_selected]",
+			"03-09-23-00 keep [daily]", "03-02-10-00 drop [not_selected]", "02-20-10-00 keep [monthly]",
+		}},
+		{zfsModels.SnapshotRetention{MaxAge: 2 * day}, []string{
+			"03-10-12-00 keep [no_count_rules]", "03-10-11-30 keep [no_count_rules]", "03-10-11-00 keep [no_count_rules]",
+			"03-09-23-00 keep [no_count_rules]", "03-02-10-00 drop [max_age]", "02-20-10-00 drop [max_age]",
+		}},
+		{zfsModels.SnapshotRetention{KeepLast: 10, MaxAge: 7 * day}, []string{
+			"03-10-12-00 keep [last]", "03-10-11-30 keep [last]", "03-10-11-00 keep [last]",
+			"03-09-23-00 keep [last]", "03-02-10-00 drop [max_age]", "02-20-10-00 drop [max_age]",
+		}},
+	}
+
+	for _, tt := range tests {
+		items := applyRetention(tt.retention, append([]jobSnapshot(nil), snaps...), now)
+		if len(items) != len(tt.expected) {
+			t.Errorf("applyRetention(%+v) returned %d items, want %d", tt.retention, len(items), len(tt.expected))
+			continue
+		}
+
+		for i, item := range items {
+			verdict := "drop"
+			if item.Keep {
+				verdict = "keep"
+			}
+
+			got := fmt.Sprintf("%s %s %v", item.Snapshot[len(item.Snapshot)-11:], verdict, item.Reasons)
+			if got != tt.expected[i] {
+				t.Errorf("applyRetention(%+v)[%d] = %q, want %q", tt.retention, i, got, tt.expected[i])
+			}
+		}
+	}
+}
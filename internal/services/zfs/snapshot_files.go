@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+var snapshotChangeNames = map[zfs.ChangeType]string{
+	zfs.Removed:  "removed",
+	zfs.Created:  "created",
+	zfs.Modified: "modified",
+	zfs.Renamed:  "renamed",
+}
+
+var snapshotInodeNames = map[zfs.InodeType]string{
+	zfs.BlockDevice:     "block_device",
+	zfs.CharacterDevice: "character_device",
+	zfs.Directory:       "directory",
+	zfs.Door:            "door",
+	zfs.NamedPipe:       "named_pipe",
+	zfs.SymbolicLink:    "symlink",
+	zfs.EventPort:       "event_port",
+	zfs.Socket:          "socket",
+	zfs.File:            "file",
+}
+
+// snapshotFilesystem resolves a snapshot GUID to the snapshot, its mounted
+// parent filesystem and the snapshot's directory under .zfs/snapshot.
+func (s *Service) snapshotFilesystem(guid string) (*zfs.Dataset, *zfs.Dataset, string, error) {
+	snap, err := s.GetDatasetByGUID(guid)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if snap.Type != zfs.DatasetSnapshot {
+		return nil, nil, "", fmt.Errorf("not_a_snapshot")
+	}
+
+	dsName, snapName, _ := strings.Cut(snap.Name, "@")
+
+	filesystems, err := zfs.Filesystems(dsName)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("snapshot_is_not_of_a_filesystem: %w", err)
+	}
+
+	var parent *zfs.Dataset
+	for _, fs := range filesystems {
+		if fs.Name == dsName {
+			parent = fs
+			break
+		}
+	}
+
+	if parent == nil {
+		return nil, nil, "", fmt.Errorf("snapshot_is_not_of_a_filesystem")
+	}
+
+	if parent.Mounted != "yes" || !filepath.IsAbs(parent.Mountpoint) {
+		return nil, nil, "", fmt.Errorf("dataset_not_mounted: %s", parent.Name)
+	}
+
+	root := filepath.Join(parent.Mountpoint, ".zfs", "snapshot", snapName)
+
+	return snap, parent, root, nil
+}
+
+// datasetRelPath turns a path inside the dataset, given either relative to
+// its root or as an absolute live path under the mountpoint, into a clean
+// relative path that cannot escape the dataset.
+func datasetRelPath(mountpoint string, path string) (string, error) {
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(mountpoint, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+			return "", fmt.Errorf("path_outside_dataset: %s", path)
+		}
+		path = rel
+	}
+
+	rel := strings.TrimPrefix(filepath.Clean("/"+path), "/")
+	if rel == ".zfs" || strings.HasPrefix(rel, ".zfs/") {
+		return "", fmt.Errorf("invalid_path: %s", path)
+	}
+
+	return rel, nil
+}
+
+// resolveExisting resolves symlinks in the part of path that exists and
+// appends the components that do not exist yet.
+func resolveExisting(path string) (string, error) {
+	path = filepath.Clean(path)
+	rest := ""
+
+	for {
+		if _, err := os.Lstat(path); err == nil {
+			resolved, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return "", err
+			}
+
+			return filepath.Join(resolved, rest), nil
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, rest), nil
+		}
+
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+// checkRestoreDest rejects a destination that a symlink inside base leads
+// out of base, since cp would follow it and write wherever it points.
+func checkRestoreDest(base string, dest string) error {
+	realBase, err := resolveExisting(base)
+	if err != nil {
+		return fmt.Errorf("failed_to_resolve_restore_base: %w", err)
+	}
+
+	realDest, err := resolveExisting(dest)
+	if err != nil {
+		return fmt.Errorf("destination_outside_restore_base: %s", dest)
+	}
+
+	rel, err := filepath.Rel(realBase, realDest)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return fmt.Errorf("destination_outside_restore_base: %s", dest)
+	}
+
+	return nil
+}
+
+// SnapshotPath returns the absolute path of a file or directory inside a
+// snapshot, suitable for the file explorer's listing and download endpoints.
+func (s *Service) SnapshotPath(guid string, path string) (string, error) {
+	_, parent, root, err := s.snapshotFilesystem(guid)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := datasetRelPath(parent.Mountpoint, path)
+	if err != nil {
+		return "", err
+	}
+
+	full := filepath.Join(root, rel)
+	if _, err := os.Lstat(full); err != nil {
+		return "", fmt.Errorf("path_not_found_in_snapshot: %s", path)
+	}
+
+	return full, nil
+}
+
+func (s *Service) DiffSnapshot(guid string) ([]zfsServiceInterfaces.SnapshotChange, error) {
+	snap, parent, _, err := s.snapshotFilesystem(guid)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := parent.Diff(snap.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_diff_snapshot: %w", err)
+	}
+
+	result := make([]zfsServiceInterfaces.SnapshotChange, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, zfsServiceInterfaces.SnapshotChange{
+			Change:  snapshotChangeNames[change.Change],
+			Type:    snapshotInodeNames[change.Type],
+			Path:    change.Path,
+			NewPath: change.NewPath,
+		})
+	}
+
+	return result, nil
+}
+
+// RestoreFromSnapshot copies files or directories out of a snapshot, either
+// back to where they were in the live dataset or into the target directory.
+// Existing files are only replaced when overwrite is set.
+func (s *Service) RestoreFromSnapshot(guid string, paths []string, target string, overwrite bool) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no_paths_provided")
+	}
+
+	if target != "" && !filepath.IsAbs(target) {
+		return fmt.Errorf("target_must_be_absolute_path")
+	}
+
+	_, parent, root, err := s.snapshotFilesystem(guid)
+	if err != nil {
+		return err
+	}
+
+	type restore struct {
+		source string
+		dest   string
+		isDir  bool
+	}
+
+	var restores []restore
+	for _, path := range paths {
+		rel, err := datasetRelPath(parent.Mountpoint, path)
+		if err != nil {
+			return err
+		}
+
+		if rel == "" && target == "" {
+			return fmt.Errorf("use_rollback_to_restore_whole_dataset")
+		}
+
+		source := filepath.Join(root, rel)
+		info, err := os.Lstat(source)
+		if err != nil {
+			return fmt.Errorf("path_not_found_in_snapshot: %s", path)
+		}
+
+		base := parent.Mountpoint
+		dest := filepath.Join(parent.Mountpoint, rel)
+		if target != "" {
+			base = target
+			dest = filepath.Join(target, filepath.Base(filepath.Join(parent.Mountpoint, rel)))
+		}
+
+		if err := checkRestoreDest(base, dest); err != nil {
+			return err
+		}
+
+		if existing, err := os.Lstat(dest); err == nil {
+			if !overwrite {
+				return fmt.Errorf("destination_exists: %s", dest)
+			}
+
+			if existing.IsDir() != info.IsDir() {
+				return fmt.Errorf("destination_type_mismatch: %s", dest)
+			}
+		}
+
+		restores = append(restores, restore{source: source, dest: dest, isDir: info.IsDir()})
+	}
+
+	for _, r := range restores {
+		if err := os.MkdirAll(filepath.Dir(r.dest), 0755); err != nil {
+			return fmt.Errorf("failed_to_create_parent_directory: %w", err)
+		}
+
+		source := r.source
+		if _, err := os.Lstat(r.dest); err == nil && r.isDir {
+			// Copy the contents into the existing directory instead of
+			// nesting a second copy inside it.
+			source = r.source + "/."
+		}
+
+		if _, err := utils.RunCommand("cp", "-a", source, r.dest); err != nil {
+			return fmt.Errorf("failed_to_restore %s: %w", r.dest, err)
+		}
+	}
+
+	return nil
+}
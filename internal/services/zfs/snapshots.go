@@ -106,7 +106,11 @@ func (s *Service) GetPeriodicSnapshots() ([]zfsModels.PeriodicSnapshot, error) {
 	return snapshots, nil
 }
 
-func (s *Service) AddPeriodicSnapshot(guid string, prefix string, recursive bool, interval int, cronExpr string) error {
+func (s *Service) AddPeriodicSnapshot(guid string, prefix string, recursive bool, interval int, cronExpr string, retention zfsModels.SnapshotRetention) error {
+	if err := validateRetention(retention); err != nil {
+		return err
+	}
+
 	dataset, err := s.GetDatasetByGUID(guid)
 	if err != nil {
 		return err
@@ -125,6 +129,7 @@ func (s *Service) AddPeriodicSnapshot(guid string, prefix string, recursive bool
 				Recursive: recursive,
 				Interval:  interval,
 				CronExpr:  cronExpr,
+				Retention: retention,
 			}
 
 			if err := s.DB.Create(&snapshot).Error; err != nil {
@@ -235,6 +240,10 @@ func (s *Service) StartSnapshotScheduler(ctx context.Context) {
 					}
 
 					logger.L.Debug().Msgf("Snapshot %s created successfully", name)
+
+					if _, err := s.pruneSnapshotJob(job); err != nil {
+						logger.L.Warn().Err(err).Msgf("Failed to prune snapshots for job %d", job.ID)
+					}
 				}
 			case <-ctx.Done():
 				ticker.Stop()
@@ -0,0 +1,360 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package zfs
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	infoModels "github.com/alchemillahq/sylve/internal/db/models/info"
+	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
+	"github.com/alchemillahq/sylve/internal/logger"
+	"github.com/alchemillahq/sylve/pkg/zfs"
+)
+
+// historyDefaultWindow bounds the ARC and dataset IO history read when the
+// caller does not ask for a number of points.
+const historyDefaultWindow = 24 * time.Hour
+
+// historySince returns the start of the oldest bucket that can be returned,
+// so only rows that end up in the result are read.
+func historySince(intervalMs int64, limit int) int64 {
+	if limit > 0 {
+		return (time.Now().UnixMilli()/intervalMs - int64(limit) + 1) * intervalMs
+	}
+
+	return time.Now().Add(-historyDefaultWindow).UnixMilli()
+}
+
+// counterDelta treats a counter that went backwards (module reload, pool
+// re-import) as restarted from zero.
+func counterDelta(cur uint64, prev uint64) uint64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+func (s *Service) storeArcStats() {
+	stats, err := zfs.GetArcStats()
+	if err != nil {
+		logger.L.Debug().Err(err).Msg("zfs_cron: Failed to read arcstats")
+		return
+	}
+
+	prev := s.arcPrev
+	s.arcPrev = &stats
+
+	if prev == nil {
+		return
+	}
+
+	record := infoModels.ARCHistorical{
+		Size:             stats.Size,
+		Target:           stats.Target,
+		MFUSize:          stats.MFUSize,
+		MRUSize:          stats.MRUSize,
+		CompressedSize:   stats.CompressedSize,
+		UncompressedSize: stats.UncompressedSize,
+		L2Size:           stats.L2Size,
+		Hits:             counterDelta(stats.Hits, prev.Hits),
+		Misses:           counterDelta(stats.Misses, prev.Misses),
+		MFUHits:          counterDelta(stats.MFUHits, prev.MFUHits),
+		MRUHits:          counterDelta(stats.MRUHits, prev.MRUHits),
+		L2Hits:           counterDelta(stats.L2Hits, prev.L2Hits),
+		L2Misses:         counterDelta(stats.L2Misses, prev.L2Misses),
+	}
+
+	if err := s.DB.Create(&record).Error; err != nil {
+		logger.L.Debug().Err(err).Msg("zfs_cron: Failed to insert arc data")
+	}
+}
+
+func (s *Service) storeDatasetIO() {
+	current, err := zfs.GetDatasetIO()
+	if err != nil {
+		logger.L.Debug().Err(err).Msg("zfs_cron: Failed to read dataset kstats")
+		return
+	}
+
+	now := time.Now()
+	prev, prevAt := s.ioPrev, s.ioPrevAt
+	s.ioPrev, s.ioPrevAt = current, now
+
+	if prev == nil {
+		return
+	}
+
+	seconds := now.Sub(prevAt).Seconds()
+	if seconds <= 0 {
+		return
+	}
+
+	records := make([]infoModels.DatasetIOHistorical, 0, len(current))
+	for name, cur := range current {
+		old, ok := prev[name]
+		if !ok {
+			continue
+		}
+
+		nread := counterDelta(cur.NRead, old.NRead)
+		nwritten := counterDelta(cur.NWritten, old.NWritten)
+		reads := counterDelta(cur.Reads, old.Reads)
+		writes := counterDelta(cur.Writes, old.Writes)
+
+		/* Idle datasets are the common case, a missing sample reads as zero */
+		if nread == 0 && nwritten == 0 && reads == 0 && writes == 0 {
+			continue
+		}
+
+		records = append(records, infoModels.DatasetIOHistorical{
+			Dataset:    name,
+			ReadBytes:  float64(nread) / seconds,
+			WriteBytes: float64(nwritten) / seconds,
+			ReadOps:    float64(reads) / seconds,
+			WriteOps:   float64(writes) / seconds,
+		})
+	}
+
+	if len(records) == 0 {
+		return
+	}
+
+	if err := s.DB.CreateInBatches(&records, 100).Error; err != nil {
+		logger.L.Debug().Err(err).Msg("zfs_cron: Failed to insert dataset io data")
+	}
+}
+
+// trimHistorical thins samples older than a day down to one per hour (per
+// value of partition, if set) and drops anything older than a year, the
+// same retention trimZPoolHistoricalData applies to pools.
+func (s *Service) trimHistorical(model any, partition string) {
+	now := time.Now()
+	cutoff24h := now.Add(-24 * time.Hour).UnixMilli()
+
+	columns := "id, created_at"
+	if partition != "" {
+		columns += ", " + partition + " AS part"
+	}
+
+	var rows []struct {
+		ID        int64
+		CreatedAt int64
+		Part      string
+	}
+
+	if err := s.DB.Model(model).
+		Select(columns).
+		Where("created_at < ?", cutoff24h).
+		Order("created_at ASC").
+		Scan(&rows).Error; err != nil {
+		logger.L.Debug().Err(err).Msg("zfs_cron: Failed to fetch old historical records")
+		return
+	}
+
+	type hourKey struct {
+		part string
+		hour int64
+	}
+
+	seen := make(map[hourKey]struct{})
+	var toDelete []int64
+
+	for _, row := range rows {
+		key := hourKey{
+			part: row.Part,
+			hour: time.UnixMilli(row.CreatedAt).Truncate(time.Hour).UnixMilli(),
+		}
+
+		if _, exists := seen[key]; exists {
+			toDelete = append(toDelete, row.ID)
+			continue
+		}
+		seen[key] = struct{}{}
+	}
+
+	for start := 0; start < len(toDelete); start += 500 {
+		end := min(start+500, len(toDelete))
+		if err := s.DB.Where("id IN ?", toDelete[start:end]).Delete(model).Error; err != nil {
+			logger.L.Debug().Err(err).Msg("zfs_cron: Failed to delete old historical records")
+			return
+		}
+	}
+
+	maxAge := now.Add(-365 * 24 * time.Hour).UnixMilli()
+	if err := s.DB.Where("created_at < ?", maxAge).Delete(model).Error; err != nil {
+		logger.L.Debug().Err(err).Msg("zfs_cron: Failed to delete very old historical records")
+	}
+}
+
+func (s *Service) GetArcHistoricalStats(intervalMinutes int, limit int) ([]zfsServiceInterfaces.ArcStatPoint, int, error) {
+	if intervalMinutes <= 0 {
+		return nil, 0, fmt.Errorf("invalid interval: must be > 0")
+	}
+
+	intervalMs := int64(intervalMinutes) * 60 * 1000
+
+	var records []infoModels.ARCHistorical
+	if err := s.DB.
+		Where("created_at >= ?", historySince(intervalMs, limit)).
+		Order("created_at ASC").
+		Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+
+	count := len(records)
+
+	// sizes are averaged over the bucket, ratios are computed from the
+	// summed hit and miss counts so busy samples weigh more
+	type bucket struct {
+		samples                    uint64
+		size, target, mfu, mru, l2 uint64
+		compressed, uncompressed   uint64
+		hits, misses, mfuHits      uint64
+		l2Hits, l2Misses           uint64
+	}
+
+	buckets := make(map[int64]*bucket)
+	for _, rec := range records {
+		bucketTime := (rec.CreatedAt / intervalMs) * intervalMs
+
+		b, ok := buckets[bucketTime]
+		if !ok {
+			b = &bucket{}
+			buckets[bucketTime] = b
+		}
+
+		b.samples++
+		b.size += rec.Size
+		b.target += rec.Target
+		b.mfu += rec.MFUSize
+		b.mru += rec.MRUSize
+		b.l2 += rec.L2Size
+		b.compressed += rec.CompressedSize
+		b.uncompressed += rec.UncompressedSize
+		b.hits += rec.Hits
+		b.misses += rec.Misses
+		b.mfuHits += rec.MFUHits
+		b.l2Hits += rec.L2Hits
+		b.l2Misses += rec.L2Misses
+	}
+
+	points := make([]zfsServiceInterfaces.ArcStatPoint, 0, len(buckets))
+	for t, b := range buckets {
+		agg := zfs.ArcStats{
+			CompressedSize:   b.compressed,
+			UncompressedSize: b.uncompressed,
+			Hits:             b.hits,
+			Misses:           b.misses,
+			L2Hits:           b.l2Hits,
+			L2Misses:         b.l2Misses,
+		}
+
+		point := zfsServiceInterfaces.ArcStatPoint{
+			Time:          t,
+			Size:          b.size / b.samples,
+			Target:        b.target / b.samples,
+			MFUSize:       b.mfu / b.samples,
+			MRUSize:       b.mru / b.samples,
+			L2Size:        b.l2 / b.samples,
+			HitRatio:      agg.HitRatio(),
+			L2HitRatio:    agg.L2HitRatio(),
+			CompressRatio: agg.CompressRatio(),
+		}
+
+		if b.hits > 0 {
+			point.MFUHitRatio = float64(b.mfuHits) / float64(b.hits) * 100
+		}
+
+		points = append(points, point)
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Time < points[j].Time
+	})
+
+	if limit > 0 && len(points) > limit {
+		points = points[len(points)-limit:]
+	}
+
+	return points, count, nil
+}
+
+func (s *Service) GetDatasetIOHistoricalStats(intervalMinutes int, limit int, dataset string) (map[string][]zfsServiceInterfaces.DatasetIOPoint, int, error) {
+	if intervalMinutes <= 0 {
+		return nil, 0, fmt.Errorf("invalid interval: must be > 0")
+	}
+
+	intervalMs := int64(intervalMinutes) * 60 * 1000
+
+	// every dataset has a row per minute, so an unbounded read would grow
+	// with the dataset count
+	query := s.DB.Where("created_at >= ?", historySince(intervalMs, limit))
+	if dataset != "" {
+		query = query.Where("dataset = ?", dataset)
+	}
+
+	var records []infoModels.DatasetIOHistorical
+	if err := query.
+		Order("created_at ASC").
+		Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+
+	type bucket struct {
+		samples int
+		point   zfsServiceInterfaces.DatasetIOPoint
+	}
+
+	buckets := make(map[string]map[int64]*bucket)
+	for _, rec := range records {
+		bucketTime := (rec.CreatedAt / intervalMs) * intervalMs
+
+		if buckets[rec.Dataset] == nil {
+			buckets[rec.Dataset] = make(map[int64]*bucket)
+		}
+
+		b, ok := buckets[rec.Dataset][bucketTime]
+		if !ok {
+			b = &bucket{point: zfsServiceInterfaces.DatasetIOPoint{Time: bucketTime}}
+			buckets[rec.Dataset][bucketTime] = b
+		}
+
+		b.samples++
+		b.point.ReadBytes += rec.ReadBytes
+		b.point.WriteBytes += rec.WriteBytes
+		b.point.ReadOps += rec.ReadOps
+		b.point.WriteOps += rec.WriteOps
+	}
+
+	result := make(map[string][]zfsServiceInterfaces.DatasetIOPoint, len(buckets))
+	for name, mp := range buckets {
+		pts := make([]zfsServiceInterfaces.DatasetIOPoint, 0, len(mp))
+		for _, b := range mp {
+			n := float64(b.samples)
+			b.point.ReadBytes /= n
+			b.point.WriteBytes /= n
+			b.point.ReadOps /= n
+			b.point.WriteOps /= n
+			pts = append(pts, b.point)
+		}
+		sort.Slice(pts, func(i, j int) bool {
+			return pts[i].Time < pts[j].Time
+		})
+
+		if limit > 0 && len(pts) > limit {
+			pts = pts[len(pts)-limit:]
+		}
+
+		result[name] = pts
+	}
+
+	return result, len(records), nil
+}
@@ -10,6 +10,7 @@ package zfs
 
 import (
 	"sync"
+	"time"
 
 	libvirtServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/libvirt"
 	zfsServiceInterfaces "github.com/alchemillahq/sylve/internal/interfaces/services/zfs"
@@ -25,6 +26,12 @@ type Service struct {
 	DB        *gorm.DB
 	Libvirt   libvirtServiceInterfaces.LibvirtServiceInterface
 	syncMutex *sync.Mutex
+
+	// previous samples of the cumulative ARC and dataset kstat counters,
+	// only touched by the stats cron
+	arcPrev  *zfs.ArcStats
+	ioPrev   map[string]zfs.DatasetIO
+	ioPrevAt time.Time
 }
 
 func NewZfsService(db *gorm.DB, libvirt libvirtServiceInterfaces.LibvirtServiceInterface) zfsServiceInterfaces.ZfsServiceInterface {
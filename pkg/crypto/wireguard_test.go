@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/alchemillahq/sylve/pkg/crypto"
+)
+
+func TestGenerateWireGuardKey(t *testing.T) {
+	priv, pub, err := crypto.GenerateWireGuardKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !crypto.IsValidWireGuardKey(priv) || !crypto.IsValidWireGuardKey(pub) {
+		t.Fatalf("generated keys are not valid: %q %q", priv, pub)
+	}
+
+	derived, err := crypto.WireGuardPublicKey(priv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if derived != pub {
+		t.Errorf("derived public key %q does not match %q", derived, pub)
+	}
+}
+
+func TestWireGuardPublicKeyKnownVector(t *testing.T) {
+	// RFC 7748 section 6.1, Alice's key pair
+	priv := "dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo="
+	want := "hSDwCYkwp1R0i33ctD73Wg2/Og0mOBr066SpjqqbTmo="
+
+	got, err := crypto.WireGuardPublicKey(priv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("WireGuardPublicKey() = %q, want %q", got, want)
+	}
+}
+
+func TestWireGuardPublicKeyInvalid(t *testing.T) {
+	for _, key := range []string{"", "not-base64!", "c2hvcnQ="} {
+		if _, err := crypto.WireGuardPublicKey(key); err == nil {
+			t.Errorf("expected error for key %q", key)
+		}
+
+		if crypto.IsValidWireGuardKey(key) {
+			t.Errorf("IsValidWireGuardKey(%q) = true, want false", key)
+		}
+	}
+}
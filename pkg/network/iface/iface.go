@@ -29,6 +29,50 @@ package iface
 #include <sys/sockio.h>
 #include <errno.h>
 #include <net/if_bridgevar.h>
+#include <net/ethernet.h>
+#include <net/if_lagg.h>
+
+struct lagg_port_info {
+    char     name[IFNAMSIZ];
+    uint32_t flags;
+    uint16_t actor_key;
+    uint8_t  actor_state;
+    uint16_t partner_key;
+    uint8_t  partner_state;
+    uint8_t  partner_mac[ETHER_ADDR_LEN];
+};
+
+static int
+get_lagg_info(int fd, const char *ifname, int *proto, struct lagg_port_info *out, int max)
+{
+    struct lagg_reqport rpbuf[LAGG_MAX_PORTS];
+    struct lagg_reqall ra;
+    int i, n;
+
+    memset(&ra, 0, sizeof(ra));
+    memset(rpbuf, 0, sizeof(rpbuf));
+    strlcpy(ra.ra_ifname, ifname, sizeof(ra.ra_ifname));
+    ra.ra_size = sizeof(rpbuf);
+    ra.ra_port = rpbuf;
+
+    if (ioctl(fd, SIOCGLAGG, &ra) < 0)
+        return -1;
+
+    *proto = ra.ra_proto;
+    n = ra.ra_ports < max ? ra.ra_ports : max;
+
+    for (i = 0; i < n; i++) {
+        strlcpy(out[i].name, rpbuf[i].rp_portname, IFNAMSIZ);
+        out[i].flags = rpbuf[i].rp_flags;
+        out[i].actor_key = rpbuf[i].rp_lacpreq.actor_key;
+        out[i].actor_state = rpbuf[i].rp_lacpreq.actor_state;
+        out[i].partner_key = rpbuf[i].rp_lacpreq.partner_key;
+        out[i].partner_state = rpbuf[i].rp_lacpreq.partner_state;
+        memcpy(out[i].partner_mac, rpbuf[i].rp_lacpreq.partner_mac, ETHER_ADDR_LEN);
+    }
+
+    return n;
+}
 
 static int
 get_stp_op_params(int fd, const char *ifname, struct ifbropreq *opr)
@@ -434,6 +478,86 @@ func parseBridgeFlags(f uint32) []string {
 	return desc
 }
 
+func parseLaggProto(p int) string {
+	switch p {
+	case C.LAGG_PROTO_ROUNDROBIN:
+		return "roundrobin"
+	case C.LAGG_PROTO_FAILOVER:
+		return "failover"
+	case C.LAGG_PROTO_LOADBALANCE:
+		return "loadbalance"
+	case C.LAGG_PROTO_LACP:
+		return "lacp"
+	case C.LAGG_PROTO_BROADCAST:
+		return "broadcast"
+	default:
+		return "none"
+	}
+}
+
+func parseLaggPortFlags(f uint32) []string {
+	var laggPortFlagDesc = []FlagDescriptor{
+		{Mask: C.LAGG_PORT_MASTER, Name: "MASTER"},
+		{Mask: C.LAGG_PORT_STACK, Name: "STACK"},
+		{Mask: C.LAGG_PORT_ACTIVE, Name: "ACTIVE"},
+		{Mask: C.LAGG_PORT_COLLECTING, Name: "COLLECTING"},
+		{Mask: C.LAGG_PORT_DISTRIBUTING, Name: "DISTRIBUTING"},
+	}
+	out, _ := parseFlags(f, laggPortFlagDesc)
+	return out
+}
+
+func parseLACPState(s uint32) []string {
+	var lacpStateDesc = []FlagDescriptor{
+		{Mask: 0x01, Name: "ACTIVITY"},
+		{Mask: 0x02, Name: "TIMEOUT"},
+		{Mask: 0x04, Name: "AGGREGATION"},
+		{Mask: 0x08, Name: "SYNC"},
+		{Mask: 0x10, Name: "COLLECTING"},
+		{Mask: 0x20, Name: "DISTRIBUTING"},
+		{Mask: 0x40, Name: "DEFAULTED"},
+		{Mask: 0x80, Name: "EXPIRED"},
+	}
+	out, _ := parseFlags(s, lacpStateDesc)
+	return out
+}
+
+func getLaggInfo(fd C.int, cname *C.char) *Lagg {
+	var ports [C.LAGG_MAX_PORTS]C.struct_lagg_port_info
+	var proto C.int
+
+	n := int(C.get_lagg_info(fd, cname, &proto, &ports[0], C.int(C.LAGG_MAX_PORTS)))
+	if n < 0 {
+		return nil
+	}
+
+	lagg := &Lagg{Protocol: parseLaggProto(int(proto))}
+	for i := 0; i < n; i++ {
+		p := ports[i]
+		flags := uint32(p.flags)
+		port := LaggPort{
+			Name:  C.GoString(&p.name[0]),
+			Flags: Flags{Raw: flags, Desc: parseLaggPortFlags(flags)},
+		}
+
+		if lagg.Protocol == "lacp" {
+			actor := uint32(p.actor_state)
+			partner := uint32(p.partner_state)
+			port.LACP = &LACPPortState{
+				ActorKey:     int(p.actor_key),
+				ActorState:   Flags{Raw: actor, Desc: parseLACPState(actor)},
+				PartnerKey:   int(p.partner_key),
+				PartnerState: Flags{Raw: partner, Desc: parseLACPState(partner)},
+				PartnerMAC:   net.HardwareAddr(C.GoBytes(unsafe.Pointer(&p.partner_mac[0]), C.ETHER_ADDR_LEN)).String(),
+			}
+		}
+
+		lagg.Ports = append(lagg.Ports, port)
+	}
+
+	return lagg
+}
+
 func getInterfaceInfo(name string) (*Interface, error) {
 	fd4 := C.socket(C.AF_INET, C.SOCK_DGRAM, 0)
 	if fd4 < 0 {
@@ -569,6 +693,8 @@ func getInterfaceInfo(name string) (*Interface, error) {
 		}
 	}
 
+	iface.Lagg = getLaggInfo(fd4, cname)
+
 	var grpPtr *C.struct_ifg_req
 	var grpCount C.int
 	if C.get_interface_groups(fd4, cname, &grpPtr, &grpCount) == 0 {
@@ -76,6 +76,25 @@ type BridgeMember struct {
 	PathCost  int    `json:"pathCost"`
 }
 
+type LACPPortState struct {
+	ActorKey     int    `json:"actorKey"`
+	ActorState   Flags  `json:"actorState"`
+	PartnerKey   int    `json:"partnerKey"`
+	PartnerState Flags  `json:"partnerState"`
+	PartnerMAC   string `json:"partnerMac"`
+}
+
+type LaggPort struct {
+	Name  string         `json:"name"`
+	Flags Flags          `json:"flags"`
+	LACP  *LACPPortState `json:"lacp"`
+}
+
+type Lagg struct {
+	Protocol string     `json:"protocol"`
+	Ports    []LaggPort `json:"ports"`
+}
+
 type Interface struct {
 	Name          string         `json:"name"`
 	Ether         string         `json:"ether"`
@@ -92,6 +111,7 @@ type Interface struct {
 	Timeout       int            `json:"timeout"`
 	BridgeMembers []BridgeMember `json:"bridgeMembers"`
 	Groups        []string       `json:"groups"`
+	Lagg          *Lagg          `json:"lagg"`
 
 	IPv4 []IPv4 `json:"ipv4"`
 	IPv6 []IPv6 `json:"ipv6"`
@@ -0,0 +1,105 @@
+package ctl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"github.com/alchemillahq/sylve/pkg/utils"
+)
+
+type Session struct {
+	ID               int    `json:"id"`
+	Initiator        string `json:"initiator"`
+	InitiatorAddr    string `json:"initiatorAddr"`
+	InitiatorAlias   string `json:"initiatorAlias"`
+	Target           string `json:"target"`
+	TargetAlias      string `json:"targetAlias"`
+	PortalGroupTag   int    `json:"portalGroupTag"`
+	HeaderDigest     string `json:"headerDigest"`
+	DataDigest       string `json:"dataDigest"`
+	MaxRecvSegment   int    `json:"maxRecvSegment"`
+	MaxSendSegment   int    `json:"maxSendSegment"`
+	MaxBurstLength   int    `json:"maxBurstLength"`
+	FirstBurstLength int    `json:"firstBurstLength"`
+	ImmediateData    bool   `json:"immediateData"`
+	ISER             bool   `json:"iser"`
+	Offload          string `json:"offload"`
+}
+
+type islist struct {
+	Connections []struct {
+		ID               string `xml:"id,attr"`
+		Initiator        string `xml:"initiator"`
+		InitiatorAddr    string `xml:"initiator_addr"`
+		InitiatorAlias   string `xml:"initiator_alias"`
+		Target           string `xml:"target"`
+		TargetAlias      string `xml:"target_alias"`
+		PortalGroupTag   string `xml:"target_portal_group_tag"`
+		HeaderDigest     string `xml:"header_digest"`
+		DataDigest       string `xml:"data_digest"`
+		MaxRecvSegment   string `xml:"max_recv_data_segment_length"`
+		MaxSendSegment   string `xml:"max_send_data_segment_length"`
+		MaxBurstLength   string `xml:"max_burst_length"`
+		FirstBurstLength string `xml:"first_burst_length"`
+		ImmediateData    string `xml:"immediate_data"`
+		ISER             string `xml:"iser"`
+		Offload          string `xml:"offload"`
+	} `xml:"connection"`
+}
+
+// Sessions lists the iSCSI connections the kernel target currently holds,
+// as reported by ctladm islist -x.
+func Sessions() ([]Session, error) {
+	out, err := utils.RunCommand("ctladm", "islist", "-x")
+	if err != nil {
+		return nil, fmt.Errorf("ctladm islist failed: %v: %s", err, out)
+	}
+
+	return ParseSessions([]byte(out))
+}
+
+func ParseSessions(data []byte) ([]Session, error) {
+	var list islist
+	if err := xml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse ctladm islist output: %w", err)
+	}
+
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+
+	sessions := make([]Session, 0, len(list.Connections))
+	for _, c := range list.Connections {
+		sessions = append(sessions, Session{
+			ID:               atoi(c.ID),
+			Initiator:        c.Initiator,
+			InitiatorAddr:    c.InitiatorAddr,
+			InitiatorAlias:   c.InitiatorAlias,
+			Target:           c.Target,
+			TargetAlias:      c.TargetAlias,
+			PortalGroupTag:   atoi(c.PortalGroupTag),
+			HeaderDigest:     c.HeaderDigest,
+			DataDigest:       c.DataDigest,
+			MaxRecvSegment:   atoi(c.MaxRecvSegment),
+			MaxSendSegment:   atoi(c.MaxSendSegment),
+			MaxBurstLength:   atoi(c.MaxBurstLength),
+			FirstBurstLength: atoi(c.FirstBurstLength),
+			ImmediateData:    c.ImmediateData == "1",
+			ISER:             c.ISER == "1",
+			Offload:          c.Offload,
+		})
+	}
+
+	return sessions, nil
+}
+
+// Logout drops a single connection, the initiator is free to log back in.
+func Logout(id int) error {
+	out, err := utils.RunCommand("ctladm", "islogout", "-c", strconv.Itoa(id))
+	if err != nil {
+		return fmt.Errorf("ctladm islogout failed: %v: %s", err, out)
+	}
+	return nil
+}
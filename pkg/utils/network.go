@@ -9,8 +9,11 @@
 package utils
 
 import (
+	"encoding/binary"
 	"fmt"
+	"math"
 	"net"
+	"net/netip"
 	"strconv"
 	"strings"
 )
@@ -108,6 +111,34 @@ func IsPortInUse(port int) bool {
 	return false
 }
 
+// IsPortBound reports whether something on the host already listens on
+// port over TCP or UDP, by trying to bind it.
+func IsPortBound(port int) bool {
+	if port < 1 || port > 65535 {
+		return false
+	}
+	addr := fmt.Sprintf(":%d", port)
+
+	tcpLn, tcpErr := net.Listen("tcp", addr)
+	if tcpErr != nil {
+		return true
+	}
+	tcpLn.Close()
+
+	udpAddr, udpResErr := net.ResolveUDPAddr("udp", addr)
+	if udpResErr != nil {
+		return false
+	}
+
+	udpConn, udpErr := net.ListenUDP("udp", udpAddr)
+	if udpErr != nil {
+		return true
+	}
+	udpConn.Close()
+
+	return false
+}
+
 func GetPortUserPID(proto string, port int) (int, error) {
 	if proto != "tcp" && proto != "udp" {
 		return 0, fmt.Errorf("invalid protocol: %s", proto)
@@ -158,3 +189,148 @@ func IsValidIPPort(ipPort string) bool {
 
 	return IsValidIP(ip) && IsValidPort(portInt)
 }
+
+func IsValidFQDN(fqdn string) bool {
+	fqdn = strings.TrimSuffix(fqdn, ".")
+	if fqdn == "" || len(fqdn) > 253 || IsValidIP(fqdn) {
+		return false
+	}
+
+	labels := strings.Split(fqdn, ".")
+	if len(labels) < 2 {
+		return false
+	}
+
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 {
+			return false
+		}
+
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '-' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// LastAddrInPrefix returns the highest address covered by the prefix.
+func LastAddrInPrefix(p netip.Prefix) netip.Addr {
+	a := p.Masked().Addr().AsSlice()
+	for i := p.Bits(); i < len(a)*8; i++ {
+		a[i/8] |= 1 << (7 - i%8)
+	}
+
+	addr, _ := netip.AddrFromSlice(a)
+	return addr
+}
+
+// RangeToCIDRs converts an inclusive start-end address range into the
+// smallest set of CIDR prefixes covering exactly that range.
+func RangeToCIDRs(start, end string) ([]string, error) {
+	s, err := netip.ParseAddr(start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_start_address: %w", err)
+	}
+
+	e, err := netip.ParseAddr(end)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_end_address: %w", err)
+	}
+
+	s, e = s.Unmap(), e.Unmap()
+
+	if s.Is4() != e.Is4() {
+		return nil, fmt.Errorf("address_family_mismatch")
+	}
+
+	if e.Less(s) {
+		return nil, fmt.Errorf("end_before_start")
+	}
+
+	var cidrs []string
+	for {
+		bits := s.BitLen()
+		for bits > 0 {
+			wider := netip.PrefixFrom(s, bits-1).Masked()
+			if wider.Addr() != s || e.Less(LastAddrInPrefix(wider)) {
+				break
+			}
+			bits--
+		}
+
+		prefix := netip.PrefixFrom(s, bits)
+		cidrs = append(cidrs, prefix.String())
+
+		last := LastAddrInPrefix(prefix)
+		if last == e {
+			break
+		}
+
+		s = last.Next()
+	}
+
+	return cidrs, nil
+}
+
+// ParseAddrRange parses either a single address or an inclusive
+// "start-end" range of the same family.
+func ParseAddrRange(value string) (netip.Addr, netip.Addr, error) {
+	first, last, isRange := strings.Cut(strings.TrimSpace(value), "-")
+
+	s, err := netip.ParseAddr(strings.TrimSpace(first))
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid_address: %s", first)
+	}
+
+	s = s.Unmap()
+	if !isRange {
+		return s, s, nil
+	}
+
+	e, err := netip.ParseAddr(strings.TrimSpace(last))
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid_address: %s", last)
+	}
+
+	e = e.Unmap()
+	if s.Is4() != e.Is4() {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("address_family_mismatch")
+	}
+
+	if e.Less(s) {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("end_before_start")
+	}
+
+	return s, e, nil
+}
+
+// AddrRangeSize returns the number of addresses in the inclusive range,
+// saturating at math.MaxUint64 for very large IPv6 ranges.
+func AddrRangeSize(start, end netip.Addr) uint64 {
+	if end.Less(start) {
+		return 0
+	}
+
+	s, e := start.As16(), end.As16()
+	sHi, sLo := binary.BigEndian.Uint64(s[:8]), binary.BigEndian.Uint64(s[8:])
+	eHi, eLo := binary.BigEndian.Uint64(e[:8]), binary.BigEndian.Uint64(e[8:])
+
+	hi := eHi - sHi
+	if eLo < sLo {
+		hi--
+	}
+
+	lo := eLo - sLo
+	if hi > 0 || lo == math.MaxUint64 {
+		return math.MaxUint64
+	}
+
+	return lo + 1
+}
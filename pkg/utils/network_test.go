@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: BSD-2-Clause
+//
+// Copyright (c) 2025 The FreeBSD Foundation.
+//
+// This software was developed by Hayzam Sherif <hayzam@alchemilla.io>
+// of Alchemilla Ventures Pvt. Ltd. <hello@alchemilla.io>,
+// under sponsorship from the FreeBSD Foundation.
+
+package utils
+
+import (
+	"math"
+	"net"
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+func TestIsPortBound(t *testing.T) {
+	ln, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer ln.Close()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	if !IsPortBound(port) {
+		t.Errorf("expected port %d to be reported as in use", port)
+	}
+
+	ln.Close()
+	if IsPortBound(port) {
+		t.Errorf("expected port %d to be free after closing listener", port)
+	}
+
+	for _, p := range []int{0, -1, 65536} {
+		if IsPortBound(p) {
+			t.Errorf("expected invalid port %d to be reported as free", p)
+		}
+	}
+}
+
+func TestIsValidFQDN(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"example.com", true},
+		{"www.example.com.", true},
+		{"a-b.c-d.example", true},
+		{"localhost", false},
+		{"", false},
+		{"-bad.example.com", false},
+		{"bad-.example.com", false},
+		{"under_score.example.com", false},
+		{"double..dot.com", false},
+		{"192.168.1.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidFQDN(tt.input); got != tt.expected {
+			t.Errorf("IsValidFQDN(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestRangeToCIDRs(t *testing.T) {
+	tests := []struct {
+		start    string
+		end      string
+		expected []string
+		wantErr  bool
+	}{
+		{"10.0.0.0", "10.0.0.255", []string{"10.0.0.0/24"}, false},
+		{"10.0.0.1", "10.0.0.1", []string{"10.0.0.1/32"}, false},
+		{"10.0.0.1", "10.0.0.6", []string{"10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/31", "10.0.0.6/32"}, false},
+		{"0.0.0.0", "255.255.255.255", []string{"0.0.0.0/0"}, false},
+		{"2001:db8::", "2001:db8::ffff", []string{"2001:db8::/112"}, false},
+		{"10.0.0.5", "10.0.0.1", nil, true},
+		{"10.0.0.1", "2001:db8::1", nil, true},
+		{"bogus", "10.0.0.1", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := RangeToCIDRs(tt.start, tt.end)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("RangeToCIDRs(%q, %q) error = %v, wantErr %v", tt.start, tt.end, err, tt.wantErr)
+			continue
+		}
+
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("RangeToCIDRs(%q, %q) = %v, want %v", tt.start, tt.end, got, tt.expected)
+		}
+	}
+}
+
+func TestParseAddrRange(t *testing.T) {
+	tests := []struct {
+		value   string
+		start   string
+		end     string
+		wantErr bool
+	}{
+		{"10.0.0.1", "10.0.0.1", "10.0.0.1", false},
+		{"10.0.0.10-10.0.0.20", "10.0.0.10", "10.0.0.20", false},
+		{" 10.0.0.10 - 10.0.0.20 ", "10.0.0.10", "10.0.0.20", false},
+		{"2001:db8::1-2001:db8::ff", "2001:db8::1", "2001:db8::ff", false},
+		{"10.0.0.20-10.0.0.10", "", "", true},
+		{"10.0.0.1-2001:db8::1", "", "", true},
+		{"10.0.0.0/24", "", "", true},
+	}
+
+	for _, tt := range tests {
+		s, e, err := ParseAddrRange(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAddrRange(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+
+		if tt.wantErr {
+			continue
+		}
+
+		if s.String() != tt.start || e.String() != tt.end {
+			t.Errorf("ParseAddrRange(%q) = %s-%s, want %s-%s", tt.value, s, e, tt.start, tt.end)
+		}
+	}
+}
+
+func TestAddrRangeSize(t *testing.T) {
+	tests := []struct {
+		start    string
+		end      string
+		expected uint64
+	}{
+		{"10.0.0.1", "10.0.0.1", 1},
+		{"10.0.0.0", "10.0.0.255", 256},
+		{"10.0.1.0", "10.0.0.0", 0},
+		{"2001:db8::", "2001:db8::ffff:ffff:ffff:ffff", math.MaxUint64},
+		{"2001:db8::", "2001:db8:0:1::", math.MaxUint64},
+		{"2001:db8::ffff:ffff:ffff:ffff", "2001:db8:0:1::", 2},
+	}
+
+	for _, tt := range tests {
+		got := AddrRangeSize(netip.MustParseAddr(tt.start), netip.MustParseAddr(tt.end))
+		if got != tt.expected {
+			t.Errorf("AddrRangeSize(%s, %s) = %d, want %d", tt.start, tt.end, got, tt.expected)
+		}
+	}
+}
@@ -11,6 +11,9 @@ package utils
 import (
 	"regexp"
 	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
 )
 
 func ParseZfsTimeUnit(value string) int64 {
@@ -38,3 +41,19 @@ func ParseZfsTimeUnit(value string) int64 {
 		return int64(num)
 	}
 }
+
+// CronDue reports whether a job on the standard cron schedule expr is due at
+// now. A job that never ran counts from created, so a new schedule waits for
+// its first slot instead of running the moment it is saved.
+func CronDue(expr string, last time.Time, created time.Time, now time.Time) (bool, error) {
+	sched, err := cron.ParseStandard(expr)
+	if err != nil {
+		return false, err
+	}
+
+	if last.IsZero() {
+		last = created
+	}
+
+	return !now.Before(sched.Next(last)), nil
+}
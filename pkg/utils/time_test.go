@@ -8,7 +8,10 @@
 
 package utils
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestParseZfsTimeUnit(t *testing.T) {
 	tests := []struct {
@@ -35,3 +38,49 @@ func TestParseZfsTimeUnit(t *testing.T) {
 		})
 	}
 }
+
+func TestCronDue(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+		if err != nil {
+			t.Fatalf("bad test time %q: %v", value, err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		expr     string
+		last     string
+		created  string
+		now      string
+		expected bool
+	}{
+		{"0 3 * * 0", "", "2025-03-05 12:00", "2025-03-05 12:01", false},
+		{"0 3 * * 0", "", "2025-03-05 12:00", "2025-03-09 03:00", true},
+		{"0 3 * * 0", "2025-03-09 03:00", "2025-03-01 12:00", "2025-03-09 03:00", false},
+		{"0 3 * * 0", "2025-03-09 03:00", "2025-03-01 12:00", "2025-03-15 23:59", false},
+		{"0 3 * * 0", "2025-03-09 03:00", "2025-03-01 12:00", "2025-03-16 03:00", true},
+		{"0 3 * * 0", "2025-02-09 03:00", "2025-01-01 12:00", "2025-03-10 09:00", true},
+		{"*/15 * * * *", "2025-03-10 10:00", "2025-03-01 12:00", "2025-03-10 10:14", false},
+		{"*/15 * * * *", "2025-03-10 10:00", "2025-03-01 12:00", "2025-03-10 10:15", true},
+		{"@daily", "", "2025-03-10 10:00", "2025-03-11 00:00", true},
+	}
+
+	for _, tt := range tests {
+		var last time.Time
+		if tt.last != "" {
+			last = at(tt.last)
+		}
+
+		got, err := CronDue(tt.expr, last, at(tt.created), at(tt.now))
+		if err != nil || got != tt.expected {
+			t.Errorf("CronDue(%q, %q, %q, %q) = %v, %v, want %v", tt.expr, tt.last, tt.created, tt.now, got, err, tt.expected)
+		}
+	}
+
+	for _, expr := range []string{"", "* * *", "61 * * * *", "every day"} {
+		if _, err := CronDue(expr, time.Time{}, time.Now(), time.Now()); err == nil {
+			t.Errorf("CronDue(%q) expected an error", expr)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package zfs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/alchemillahq/sylve/pkg/utils"
+	"github.com/alchemillahq/sylve/pkg/utils/sysctl"
+)
+
+const arcstatsPrefix = "kstat.zfs.misc.arcstats."
+
+// ArcStats is a snapshot of kstat.zfs.misc.arcstats. Sizes are gauges in
+// bytes, hits and misses are counters since boot.
+type ArcStats struct {
+	Size             uint64 `json:"size"`
+	Target           uint64 `json:"target"`
+	Min              uint64 `json:"min"`
+	Max              uint64 `json:"max"`
+	MFUSize          uint64 `json:"mfuSize"`
+	MRUSize          uint64 `json:"mruSize"`
+	DataSize         uint64 `json:"dataSize"`
+	MetadataSize     uint64 `json:"metadataSize"`
+	CompressedSize   uint64 `json:"compressedSize"`
+	UncompressedSize uint64 `json:"uncompressedSize"`
+	Hits             uint64 `json:"hits"`
+	Misses           uint64 `json:"misses"`
+	MFUHits          uint64 `json:"mfuHits"`
+	MRUHits          uint64 `json:"mruHits"`
+	MFUGhostHits     uint64 `json:"mfuGhostHits"`
+	MRUGhostHits     uint64 `json:"mruGhostHits"`
+	L2Size           uint64 `json:"l2Size"`
+	L2AllocatedSize  uint64 `json:"l2AllocatedSize"`
+	L2Hits           uint64 `json:"l2Hits"`
+	L2Misses         uint64 `json:"l2Misses"`
+}
+
+func (a ArcStats) HitRatio() float64 {
+	return ratio(a.Hits, a.Hits+a.Misses)
+}
+
+func (a ArcStats) L2HitRatio() float64 {
+	return ratio(a.L2Hits, a.L2Hits+a.L2Misses)
+}
+
+func (a ArcStats) CompressRatio() float64 {
+	if a.CompressedSize == 0 {
+		return 1
+	}
+	return float64(a.UncompressedSize) / float64(a.CompressedSize)
+}
+
+func ratio(part uint64, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+func GetArcStats() (ArcStats, error) {
+	var stats ArcStats
+
+	fields := map[string]*uint64{
+		"size":              &stats.Size,
+		"c":                 &stats.Target,
+		"c_min":             &stats.Min,
+		"c_max":             &stats.Max,
+		"mfu_size":          &stats.MFUSize,
+		"mru_size":          &stats.MRUSize,
+		"data_size":         &stats.DataSize,
+		"metadata_size":     &stats.MetadataSize,
+		"compressed_size":   &stats.CompressedSize,
+		"uncompressed_size": &stats.UncompressedSize,
+		"hits":              &stats.Hits,
+		"misses":            &stats.Misses,
+		"mfu_hits":          &stats.MFUHits,
+		"mru_hits":          &stats.MRUHits,
+		"mfu_ghost_hits":    &stats.MFUGhostHits,
+		"mru_ghost_hits":    &stats.MRUGhostHits,
+		"l2_size":           &stats.L2Size,
+		"l2_asize":          &stats.L2AllocatedSize,
+		"l2_hits":           &stats.L2Hits,
+		"l2_misses":         &stats.L2Misses,
+	}
+
+	for name, field := range fields {
+		value, err := sysctl.GetInt64(arcstatsPrefix + name)
+		if err != nil {
+			if name == "size" {
+				return ArcStats{}, fmt.Errorf("failed to read %s%s: %w", arcstatsPrefix, name, err)
+			}
+			continue
+		}
+		*field = uint64(value)
+	}
+
+	return stats, nil
+}
+
+// DatasetIO holds the objset kstat counters of a dataset, cumulative
+// since the pool was imported.
+type DatasetIO struct {
+	Name     string `json:"name"`
+	Reads    uint64 `json:"reads"`
+	Writes   uint64 `json:"writes"`
+	NRead    uint64 `json:"nread"`
+	NWritten uint64 `json:"nwritten"`
+}
+
+// GetDatasetIO reads kstat.zfs.<pool>.dataset.objset-* for every imported
+// pool. Those sysctls are named after object set ids, so they have to be
+// listed rather than looked up.
+func (z *zfs) GetDatasetIO() (map[string]DatasetIO, error) {
+	pools, err := z.ListZpools()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]DatasetIO)
+
+	for _, pool := range pools {
+		prefix := "kstat.zfs." + pool.Name + ".dataset"
+		var stdout, stderr bytes.Buffer
+		if err := z.exec.Run(nil, &stdout, &stderr, "sysctl", "-e", prefix); err != nil {
+			continue
+		}
+
+		for name, io := range parseDatasetKstats(prefix+".", stdout.String()) {
+			result[name] = io
+		}
+	}
+
+	return result, nil
+}
+
+func parseDatasetKstats(prefix string, out string) map[string]DatasetIO {
+	objsets := make(map[string]*DatasetIO)
+
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		objset, field, ok := strings.Cut(strings.TrimPrefix(key, prefix), ".")
+		if !ok {
+			continue
+		}
+
+		io, exists := objsets[objset]
+		if !exists {
+			io = &DatasetIO{}
+			objsets[objset] = io
+		}
+
+		switch field {
+		case "dataset_name":
+			io.Name = value
+		case "reads":
+			io.Reads = utils.StringToUint64(value)
+		case "writes":
+			io.Writes = utils.StringToUint64(value)
+		case "nread":
+			io.NRead = utils.StringToUint64(value)
+		case "nwritten":
+			io.NWritten = utils.StringToUint64(value)
+		}
+	}
+
+	result := make(map[string]DatasetIO, len(objsets))
+	for _, io := range objsets {
+		if io.Name != "" {
+			result[io.Name] = *io
+		}
+	}
+
+	return result
+}
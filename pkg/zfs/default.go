@@ -46,6 +46,22 @@ func ListZpools() ([]*Zpool, error) {
 	return z.ListZpools()
 }
 
+func ImportablePools(dirs ...string) ([]ImportablePool, error) {
+	return z.ImportablePools(dirs...)
+}
+
+func ImportPool(pool string, opts ImportOptions) error {
+	return z.ImportPool(pool, opts)
+}
+
+func GetPoolLayout(name string) (PoolLayout, error) {
+	return z.GetPoolLayout(name)
+}
+
+func GetPoolFeature(pool string, feature string) string {
+	return z.GetPoolFeature(pool, feature)
+}
+
 func GetPoolIODelay(poolName string) (float64, error) {
 	return z.GetPoolIODelay(poolName)
 }
@@ -54,6 +70,10 @@ func GetTotalIODelay() float64 {
 	return z.GetTotalIODelay()
 }
 
+func GetDatasetIO() (map[string]DatasetIO, error) {
+	return z.GetDatasetIO()
+}
+
 func DestroyPool(guid string) error {
 	var pools []*Zpool
 	pools, err := ListZpools()
@@ -168,3 +188,19 @@ func EditFilesystem(guid string, props map[string]string) error {
 func SetZpoolProperty(pool string, property string, value string) error {
 	return z.SetZpoolProperty(pool, property, value)
 }
+
+func EncryptionStatus(filter string) ([]EncryptionInfo, error) {
+	return z.EncryptionStatus(filter)
+}
+
+func LoadKey(name string, key []byte) error {
+	return z.LoadKey(name, key)
+}
+
+func UnloadKey(name string) error {
+	return z.UnloadKey(name)
+}
+
+func ChangeKey(name string, format string, keylocation string, key []byte) error {
+	return z.ChangeKey(name, format, keylocation, key)
+}
@@ -0,0 +1,241 @@
+package zfs
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alchemillahq/sylve/pkg/utils"
+)
+
+const KeysDir = "/etc/zfs/keys"
+
+const (
+	KeyFormatPassphrase = "passphrase"
+	KeyFormatHex        = "hex"
+	KeyFormatRaw        = "raw"
+)
+
+type EncryptionInfo struct {
+	Name           string `json:"name"`
+	GUID           string `json:"guid"`
+	Type           string `json:"type"`
+	Encryption     string `json:"encryption"`
+	EncryptionRoot string `json:"encryptionRoot"`
+	KeyStatus      string `json:"keyStatus"`
+	KeyFormat      string `json:"keyFormat"`
+	KeyLocation    string `json:"keyLocation"`
+}
+
+func (e EncryptionInfo) Locked() bool {
+	return e.Encryption != "" && e.Encryption != "off" && e.KeyStatus == "unavailable"
+}
+
+// EncodeKey turns user supplied key material into the bytes ZFS expects
+// for format. Raw keys are 32 bytes of binary data, so they are accepted
+// base64 encoded.
+func EncodeKey(format string, key string) ([]byte, error) {
+	switch format {
+	case "", KeyFormatPassphrase:
+		if len([]byte(key)) < 32 || len([]byte(key)) > 512 {
+			return nil, fmt.Errorf("invalid_encryption_key_length")
+		}
+		return []byte(key), nil
+	case KeyFormatHex:
+		decoded, err := hex.DecodeString(key)
+		if err != nil || len(decoded) != 32 {
+			return nil, fmt.Errorf("invalid_hex_key: expected 64 hex characters")
+		}
+		return []byte(strings.ToLower(key)), nil
+	case KeyFormatRaw:
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil || len(decoded) != 32 {
+			return nil, fmt.Errorf("invalid_raw_key: expected 32 base64 encoded bytes")
+		}
+		return decoded, nil
+	}
+
+	return nil, fmt.Errorf("invalid_key_format: %s", format)
+}
+
+// prepareEncryption consumes the encryptionKey and storeKey pseudo
+// properties. Stored keys are written to KeysDir and referenced through
+// keylocation; keys that are not stored are returned so they can be fed to
+// zfs on stdin, leaving keylocation=prompt.
+func prepareEncryption(name string, properties map[string]string) (io.Reader, error) {
+	key, ok := properties["encryptionKey"]
+	store := properties["storeKey"] != "off"
+
+	delete(properties, "encryptionKey")
+	delete(properties, "storeKey")
+
+	if !ok || key == "" || properties["encryption"] == "off" {
+		return nil, nil
+	}
+
+	format := properties["keyformat"]
+	if format == "" {
+		format = KeyFormatPassphrase
+	}
+
+	material, err := EncodeKey(format, key)
+	if err != nil {
+		return nil, err
+	}
+
+	properties["keyformat"] = format
+
+	if !store {
+		properties["keylocation"] = "prompt"
+		return bytes.NewReader(material), nil
+	}
+
+	keyFile, err := WriteKeyFile(name, material)
+	if err != nil {
+		return nil, err
+	}
+
+	properties["keylocation"] = "file://" + keyFile
+	return nil, nil
+}
+
+func WriteKeyFile(name string, material []byte) (string, error) {
+	seed := fmt.Sprintf("%s-%s", name, material)
+	keyFile := filepath.Join(KeysDir, utils.GenerateDeterministicUUID(seed))
+
+	if _, err := os.Stat(keyFile); err == nil {
+		return "", fmt.Errorf("dont_reuse_encryption_keys")
+	}
+
+	if err := os.WriteFile(keyFile, material, 0600); err != nil {
+		return "", fmt.Errorf("failed_to_write_encryption_key")
+	}
+
+	return keyFile, nil
+}
+
+// RemoveStoredKey deletes a key file written by Sylve. Key locations that
+// point anywhere else are left alone.
+func RemoveStoredKey(keylocation string) error {
+	path, ok := strings.CutPrefix(keylocation, "file://")
+	if !ok || filepath.Dir(path) != KeysDir {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (z *zfs) EncryptionStatus(filter string) ([]EncryptionInfo, error) {
+	props := []string{"type", "guid", "encryption", "encryptionroot", "keystatus", "keyformat", "keylocation"}
+	args := []string{"get", "-H", "-p", "-r", "-t", "filesystem,volume", "-o", "name,property,value", strings.Join(props, ",")}
+	if filter != "" {
+		args = append(args, filter)
+	}
+
+	out, err := z.doOutput(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []EncryptionInfo
+	index := make(map[string]int)
+
+	for _, line := range out {
+		if len(line) < 3 {
+			continue
+		}
+
+		i, ok := index[line[0]]
+		if !ok {
+			infos = append(infos, EncryptionInfo{Name: line[0]})
+			i = len(infos) - 1
+			index[line[0]] = i
+		}
+
+		value := line[2]
+		if value == "-" {
+			value = ""
+		}
+
+		info := &infos[i]
+		switch line[1] {
+		case "type":
+			info.Type = value
+		case "guid":
+			info.GUID = value
+		case "encryption":
+			info.Encryption = value
+		case "encryptionroot":
+			info.EncryptionRoot = value
+		case "keystatus":
+			info.KeyStatus = value
+		case "keyformat":
+			info.KeyFormat = value
+		case "keylocation":
+			info.KeyLocation = value
+		}
+	}
+
+	return infos, nil
+}
+
+// LoadKey loads the wrapping key for an encryption root. A nil key uses
+// the dataset's own keylocation, otherwise the key is read from stdin.
+func (z *zfs) LoadKey(name string, key []byte) error {
+	if key == nil {
+		return z.do("load-key", name)
+	}
+
+	_, err := z.run(bytes.NewReader(key), nil, "zfs", "load-key", "-L", "prompt", name)
+	return err
+}
+
+func (z *zfs) UnloadKey(name string) error {
+	return z.do("unload-key", name)
+}
+
+// ChangeKey rewraps the encryption root with a new key. keylocation is
+// either a file:// URI or prompt, in which case key is passed on stdin.
+func (z *zfs) ChangeKey(name string, format string, keylocation string, key []byte) error {
+	args := []string{"change-key", "-o", "keyformat=" + format, "-o", "keylocation=" + keylocation, name}
+
+	var in io.Reader
+	if keylocation == "prompt" {
+		in = bytes.NewReader(key)
+	}
+
+	_, err := z.run(in, nil, "zfs", args...)
+	return err
+}
+
+// LockedDataset returns the first dataset among guids whose encryption
+// key is not loaded, or nil if all of them are usable.
+func LockedDataset(guids []string) (*EncryptionInfo, error) {
+	if len(guids) == 0 {
+		return nil, nil
+	}
+
+	infos, err := z.EncryptionStatus("")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range infos {
+		for _, guid := range guids {
+			if info.GUID == guid && info.Locked() {
+				return &info, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,264 @@
+package zfs
+
+import (
+	"bytes"
+	"strings"
+)
+
+type ImportablePool struct {
+	Name       string         `json:"name"`
+	GUID       string         `json:"guid"`
+	State      string         `json:"state"`
+	Status     string         `json:"status"`
+	Action     string         `json:"action"`
+	Comment    string         `json:"comment"`
+	LastHost   string         `json:"lastHost"`
+	LastHostID string         `json:"lastHostId"`
+	Devices    []*ZpoolDevice `json:"devices"`
+}
+
+type ImportOptions struct {
+	NewName  string
+	ReadOnly bool
+	AltRoot  string
+	Force    bool
+	Dirs     []string
+}
+
+func (z *zfs) ImportablePools(dirs ...string) ([]ImportablePool, error) {
+	args := []string{"import"}
+	for _, dir := range dirs {
+		args = append(args, "-d", dir)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := z.exec.Run(nil, &stdout, &stderr, "zpool", args...); err != nil {
+		if strings.Contains(stderr.String(), "no pools available") {
+			return []ImportablePool{}, nil
+		}
+
+		return nil, &Error{
+			Err:    err,
+			Debug:  "zpool " + strings.Join(args, " "),
+			Stderr: stderr.String(),
+		}
+	}
+
+	pools := parseImportablePools(stdout.String())
+
+	for i := range pools {
+		for _, device := range leafDevices(pools[i].Devices) {
+			pools[i].LastHost, pools[i].LastHostID = z.labelHost(device)
+			if pools[i].LastHost != "" || pools[i].LastHostID != "" {
+				break
+			}
+		}
+	}
+
+	return pools, nil
+}
+
+func (z *zfs) ImportPool(pool string, opts ImportOptions) error {
+	args := []string{"import"}
+
+	if opts.Force {
+		args = append(args, "-f")
+	}
+
+	for _, dir := range opts.Dirs {
+		args = append(args, "-d", dir)
+	}
+
+	if opts.ReadOnly {
+		args = append(args, "-o", "readonly=on")
+	}
+
+	if opts.AltRoot != "" {
+		args = append(args, "-R", opts.AltRoot)
+	}
+
+	args = append(args, pool)
+
+	if opts.NewName != "" {
+		args = append(args, opts.NewName)
+	}
+
+	return z.zpool(args...)
+}
+
+func (z *Zpool) Export(force bool) error {
+	args := []string{"export"}
+	if force {
+		args = append(args, "-f")
+	}
+
+	return z.z.zpool(append(args, z.Name)...)
+}
+
+// labelHost reads the hostname and hostid that last wrote the vdev label
+// of device, which is the closest thing to "who owned this pool" that an
+// exported pool still carries.
+func (z *zfs) labelHost(device string) (string, string) {
+	if !strings.HasPrefix(device, "/") {
+		device = "/dev/" + device
+	}
+
+	out, err := z.zdbOutput("-l", device)
+	if err != nil {
+		return "", ""
+	}
+
+	var host, hostID string
+
+	for _, line := range out {
+		if len(line) < 2 {
+			continue
+		}
+
+		switch line[0] {
+		case "hostname:":
+			if host == "" {
+				host = strings.Trim(strings.Join(line[1:], " "), "'")
+			}
+		case "hostid:":
+			if hostID == "" {
+				hostID = strings.Trim(line[1], "'")
+			}
+		}
+	}
+
+	return host, hostID
+}
+
+func leafDevices(devices []*ZpoolDevice) []string {
+	var leaves []string
+	for _, dev := range devices {
+		if len(dev.Children) > 0 {
+			leaves = append(leaves, leafDevices(dev.Children)...)
+		} else if dev.State != "" {
+			leaves = append(leaves, dev.Name)
+		}
+	}
+	return leaves
+}
+
+func lineIndent(line string) int {
+	indent := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			indent++
+		case '\t':
+			indent += 8 - indent%8
+		default:
+			return indent
+		}
+	}
+	return indent
+}
+
+func parseImportablePools(out string) []ImportablePool {
+	type level struct {
+		indent int
+		dev    *ZpoolDevice
+	}
+
+	var pools []ImportablePool
+	var current *ImportablePool
+	var section string
+	var root *ZpoolDevice
+	var stack []level
+
+	for _, raw := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "pool:") {
+			pools = append(pools, ImportablePool{
+				Name:    strings.TrimSpace(strings.TrimPrefix(trimmed, "pool:")),
+				Devices: []*ZpoolDevice{},
+			})
+			current = &pools[len(pools)-1]
+			section = ""
+			root = nil
+			stack = nil
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if section != "config" {
+			key, value, isKey := strings.Cut(trimmed, ":")
+			if isKey && !strings.ContainsAny(key, " \t") {
+				value = strings.TrimSpace(value)
+				section = key
+
+				switch key {
+				case "id":
+					current.GUID = value
+				case "state":
+					current.State = value
+				case "status":
+					current.Status = value
+				case "action":
+					current.Action = value
+				case "comment":
+					current.Comment = value
+				}
+				continue
+			}
+
+			switch section {
+			case "status":
+				current.Status = strings.TrimSpace(current.Status + " " + trimmed)
+			case "action":
+				current.Action = strings.TrimSpace(current.Action + " " + trimmed)
+			}
+			continue
+		}
+
+		fields := strings.Fields(trimmed)
+		if fields[0] == "NAME" {
+			continue
+		}
+
+		dev := &ZpoolDevice{
+			Name:     fields[0],
+			Children: []*ZpoolDevice{},
+		}
+
+		if len(fields) > 1 {
+			dev.State = fields[1]
+		}
+
+		if len(fields) > 2 {
+			dev.Note = strings.Join(fields[2:], " ")
+		}
+
+		indent := lineIndent(raw)
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		switch {
+		case len(stack) > 0:
+			parent := stack[len(stack)-1].dev
+			parent.Children = append(parent.Children, dev)
+		case root == nil:
+			root = dev
+			current.Devices = append(current.Devices, dev)
+		default:
+			// logs, cache, spares etc. sit at the same depth as the pool
+			// itself, so hang them off the root like GetZpoolStatus does
+			root.Children = append(root.Children, dev)
+		}
+
+		stack = append(stack, level{indent: indent, dev: dev})
+	}
+
+	return pools
+}
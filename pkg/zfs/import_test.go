@@ -0,0 +1,184 @@
+package zfs
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Captured from zpool import on FreeBSD 14.2 with two exported pools, one of
+// them missing a raidz member.
+const zpoolImportOutput = `   pool: tank
+     id: 15872934718291830921
+  state: ONLINE
+ status: Some supported features are not enabled on the pool.
+	(Note that they may be intentionally disabled if the
+	'compatibility' property is set.)
+ action: The pool can be imported using its name or numeric identifier, though
+	some features will not be available without an explicit 'zpool upgrade'.
+ config:
+
+	tank        ONLINE
+	  mirror-0  ONLINE
+	    ada1    ONLINE
+	    ada2    ONLINE
+	logs
+	  ada3      ONLINE
+
+   pool: backup
+     id: 4410292918471029301
+  state: DEGRADED
+ status: One or more devices are missing from the system.
+ action: The pool can be imported despite missing or damaged devices.  The
+	fault tolerance of the pool may be compromised if imported.
+   see: https://openzfs.github.io/openzfs-docs/msg/ZFS-8000-2Q
+ comment: offsite
+ config:
+
+	backup                    DEGRADED
+	  raidz1-0                DEGRADED
+	    da0                   ONLINE
+	    da1                   ONLINE
+	    12837461928374619283  UNAVAIL  cannot open
+`
+
+// Captured from zpool import of a single-disk pool last used by another host.
+const zpoolImportForeignOutput = `   pool: zroot
+     id: 9278127740118263551
+  state: ONLINE
+ status: The pool was last accessed by another system.
+ action: The pool can be imported using its name or numeric identifier and
+	the '-f' flag.
+   see: https://openzfs.github.io/openzfs-docs/msg/ZFS-8000-EY
+ config:
+
+	zroot       ONLINE
+	  nda0p4    ONLINE
+`
+
+func renderDevices(devices []*ZpoolDevice) string {
+	var parts []string
+	for _, dev := range devices {
+		part := dev.Name
+		if dev.State != "" {
+			part += "(" + dev.State + ")"
+		}
+
+		if dev.Note != "" {
+			part += "{" + dev.Note + "}"
+		}
+
+		if len(dev.Children) > 0 {
+			part += "[" + renderDevices(dev.Children) + "]"
+		}
+
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func TestParseImportablePools(t *testing.T) {
+	type pool struct {
+		name    string
+		guid    string
+		state   string
+		status  string
+		action  string
+		comment string
+		devices string
+		leaves  []string
+	}
+
+	tests := []struct {
+		input    string
+		expected []pool
+	}{
+		{"", nil},
+		{"no pools available to import\n", nil},
+		{zpoolImportOutput, []pool{
+			{
+				name:    "tank",
+				guid:    "15872934718291830921",
+				state:   "ONLINE",
+				status:  "Some supported features are not enabled on the pool. (Note that they may be intentionally disabled if the 'compatibility' property is set.)",
+				action:  "The pool can be imported using its name or numeric identifier, though some features will not be available without an explicit 'zpool upgrade'.",
+				devices: "tank(ONLINE)[mirror-0(ONLINE)[ada1(ONLINE) ada2(ONLINE)] logs[ada3(ONLINE)]]",
+				leaves:  []string{"ada1", "ada2", "ada3"},
+			},
+			{
+				name:    "backup",
+				guid:    "4410292918471029301",
+				state:   "DEGRADED",
+				status:  "One or more devices are missing from the system.",
+				action:  "The pool can be imported despite missing or damaged devices.  The fault tolerance of the pool may be compromised if imported.",
+				comment: "offsite",
+				devices: "backup(DEGRADED)[raidz1-0(DEGRADED)[da0(ONLINE) da1(ONLINE) 12837461928374619283(UNAVAIL){cannot open}]]",
+				leaves:  []string{"da0", "da1", "12837461928374619283"},
+			},
+		}},
+		{zpoolImportForeignOutput, []pool{
+			{
+				name:    "zroot",
+				guid:    "9278127740118263551",
+				state:   "ONLINE",
+				status:  "The pool was last accessed by another system.",
+				action:  "The pool can be imported using its name or numeric identifier and the '-f' flag.",
+				devices: "zroot(ONLINE)[nda0p4(ONLINE)]",
+				leaves:  []string{"nda0p4"},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		got := parseImportablePools(tt.input)
+		if len(got) != len(tt.expected) {
+			t.Errorf("parseImportablePools returned %d pools, want %d", len(got), len(tt.expected))
+			continue
+		}
+
+		for i, want := range tt.expected {
+			p := got[i]
+			if p.Name != want.name || p.GUID != want.guid || p.State != want.state || p.Comment != want.comment {
+				t.Errorf("parseImportablePools pool %d = %q %q %q %q, want %q %q %q %q", i,
+					p.Name, p.GUID, p.State, p.Comment, want.name, want.guid, want.state, want.comment)
+			}
+
+			if p.Status != want.status {
+				t.Errorf("parseImportablePools(%s) status = %q, want %q", want.name, p.Status, want.status)
+			}
+
+			if p.Action != want.action {
+				t.Errorf("parseImportablePools(%s) action = %q, want %q", want.name, p.Action, want.action)
+			}
+
+			if devices := renderDevices(p.Devices); devices != want.devices {
+				t.Errorf("parseImportablePools(%s) devices = %s, want %s", want.name, devices, want.devices)
+			}
+
+			if leaves := leafDevices(p.Devices); !slices.Equal(leaves, want.leaves) {
+				t.Errorf("leafDevices(%s) = %v, want %v", want.name, leaves, want.leaves)
+			}
+		}
+	}
+}
+
+func TestLineIndent(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"tank", 0},
+		{"  pool: tank", 2},
+		{"\ttank", 8},
+		{"\t  mirror-0", 10},
+		{"  \tada1", 8},
+		{"\t\t", 16},
+	}
+
+	for _, tt := range tests {
+		if got := lineIndent(tt.input); got != tt.expected {
+			t.Errorf("lineIndent(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
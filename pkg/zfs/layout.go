@@ -0,0 +1,283 @@
+package zfs
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/alchemillahq/sylve/pkg/utils"
+)
+
+const (
+	VdevClassData    = "data"
+	VdevClassLog     = "log"
+	VdevClassCache   = "cache"
+	VdevClassSpare   = "spare"
+	VdevClassSpecial = "special"
+	VdevClassDedup   = "dedup"
+)
+
+type PoolVdev struct {
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Size     uint64     `json:"size"`
+	Alloc    uint64     `json:"alloc"`
+	Health   string     `json:"health"`
+	Children []PoolVdev `json:"children"`
+}
+
+type PoolLayout struct {
+	Data    []PoolVdev `json:"data"`
+	Log     []PoolVdev `json:"log"`
+	Cache   []PoolVdev `json:"cache"`
+	Spare   []PoolVdev `json:"spare"`
+	Special []PoolVdev `json:"special"`
+	Dedup   []PoolVdev `json:"dedup"`
+}
+
+// VdevType maps a vdev name as printed by zpool (mirror-0, raidz2-1,
+// draid1:4d:6c:0s-0, /dev/ada1) to its type.
+func VdevType(name string) string {
+	base := name
+	if i := strings.LastIndex(name, "-"); i > 0 && !strings.HasPrefix(name, "/") {
+		base = name[:i]
+	}
+
+	switch {
+	case base == "mirror", base == "replacing", base == "spare":
+		return base
+	case base == "raidz":
+		return "raidz1"
+	case strings.HasPrefix(base, "raidz"):
+		return base
+	case strings.HasPrefix(base, "draid"):
+		if i := strings.Index(base, ":"); i > 0 {
+			base = base[:i]
+		}
+		if base == "draid" {
+			return "draid1"
+		}
+		return base
+	}
+
+	return "disk"
+}
+
+// Redundancy is the number of member devices that can fail without the
+// vdev losing data.
+func (v PoolVdev) Redundancy() int {
+	switch {
+	case v.Type == "mirror":
+		return max(len(v.Children)-1, 0)
+	case strings.HasPrefix(v.Type, "raidz"), strings.HasPrefix(v.Type, "draid"):
+		return int(v.Type[len(v.Type)-1] - '0')
+	}
+
+	return 0
+}
+
+func (v PoolVdev) Leaves() []PoolVdev {
+	if len(v.Children) == 0 {
+		return []PoolVdev{v}
+	}
+
+	var leaves []PoolVdev
+	for _, c := range v.Children {
+		leaves = append(leaves, c.Leaves()...)
+	}
+	return leaves
+}
+
+// Class returns the vdev list for class so callers can inspect or rewrite
+// it in place, or nil for an unknown class.
+func (l *PoolLayout) Class(class string) *[]PoolVdev {
+	switch class {
+	case VdevClassData:
+		return &l.Data
+	case VdevClassLog:
+		return &l.Log
+	case VdevClassCache:
+		return &l.Cache
+	case VdevClassSpare:
+		return &l.Spare
+	case VdevClassSpecial:
+		return &l.Special
+	case VdevClassDedup:
+		return &l.Dedup
+	}
+
+	return nil
+}
+
+func cloneVdevs(vdevs []PoolVdev) []PoolVdev {
+	out := make([]PoolVdev, len(vdevs))
+	for i, v := range vdevs {
+		out[i] = v
+		out[i].Children = cloneVdevs(v.Children)
+	}
+	return out
+}
+
+func (l PoolLayout) Clone() PoolLayout {
+	return PoolLayout{
+		Data:    cloneVdevs(l.Data),
+		Log:     cloneVdevs(l.Log),
+		Cache:   cloneVdevs(l.Cache),
+		Spare:   cloneVdevs(l.Spare),
+		Special: cloneVdevs(l.Special),
+		Dedup:   cloneVdevs(l.Dedup),
+	}
+}
+
+func (z *zfs) GetPoolLayout(name string) (PoolLayout, error) {
+	args := []string{"list", "-v", "-P", "-p", name}
+
+	var stdout, stderr bytes.Buffer
+	if err := z.exec.Run(nil, &stdout, &stderr, "zpool", args...); err != nil {
+		return PoolLayout{}, &Error{
+			Err:    err,
+			Debug:  "zpool " + strings.Join(args, " "),
+			Stderr: stderr.String(),
+		}
+	}
+
+	return parsePoolLayout(name, stdout.String()), nil
+}
+
+func parsePoolLayout(pool string, out string) PoolLayout {
+	type level struct {
+		indent int
+		vdev   *PoolVdev
+	}
+
+	classes := map[string]*PoolVdev{}
+	var stack []level
+
+	for _, raw := range strings.Split(out, "\n") {
+		fields := strings.Fields(raw)
+		if len(fields) == 0 || fields[0] == "NAME" {
+			continue
+		}
+
+		indent := lineIndent(raw)
+		if indent == 0 {
+			class := fields[0]
+			switch class {
+			case pool:
+				class = VdevClassData
+			case "logs":
+				class = VdevClassLog
+			case "spares":
+				class = VdevClassSpare
+			}
+
+			classes[class] = &PoolVdev{Name: class}
+			stack = []level{{indent: 0, vdev: classes[class]}}
+			continue
+		}
+
+		if len(stack) == 0 {
+			continue
+		}
+
+		vdev := PoolVdev{
+			Name:     fields[0],
+			Type:     VdevType(fields[0]),
+			Children: []PoolVdev{},
+		}
+
+		if len(fields) > 1 {
+			vdev.Size = utils.StringToUint64(fields[1])
+		}
+
+		if len(fields) > 2 {
+			vdev.Alloc = utils.StringToUint64(fields[2])
+		}
+
+		if len(fields) > 9 {
+			vdev.Health = fields[9]
+		} else if len(fields) > 1 {
+			vdev.Health = fields[len(fields)-1]
+		}
+
+		for len(stack) > 1 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		parent := stack[len(stack)-1].vdev
+		parent.Children = append(parent.Children, vdev)
+		stack = append(stack, level{indent: indent, vdev: &parent.Children[len(parent.Children)-1]})
+	}
+
+	children := func(class string) []PoolVdev {
+		if v, ok := classes[class]; ok {
+			return v.Children
+		}
+		return []PoolVdev{}
+	}
+
+	return PoolLayout{
+		Data:    children(VdevClassData),
+		Log:     children(VdevClassLog),
+		Cache:   children(VdevClassCache),
+		Spare:   children(VdevClassSpare),
+		Special: children(VdevClassSpecial),
+		Dedup:   children(VdevClassDedup),
+	}
+}
+
+func (z *zfs) GetPoolFeature(pool string, feature string) string {
+	out, err := z.zpoolOutput("get", "-Hpo", "value", "feature@"+feature, pool)
+	if err != nil || len(out) == 0 || len(out[0]) == 0 {
+		return ""
+	}
+
+	return out[0][0]
+}
+
+// AddVdev runs zpool add for class. With dryRun the resulting configuration
+// that zpool would write is returned instead of being applied.
+func (z *Zpool) AddVdev(class string, raidType string, devices []string, force bool, dryRun bool) (string, error) {
+	args := []string{"add"}
+	if force {
+		args = append(args, "-f")
+	}
+
+	if dryRun {
+		args = append(args, "-n")
+	}
+
+	args = append(args, z.Name)
+
+	if class != "" && class != VdevClassData {
+		args = append(args, class)
+	}
+
+	if raidType != "" {
+		args = append(args, raidType)
+	}
+
+	args = append(args, devices...)
+
+	var stdout, stderr bytes.Buffer
+	if err := z.z.exec.Run(nil, &stdout, &stderr, "zpool", args...); err != nil {
+		return "", &Error{
+			Err:    err,
+			Debug:  "zpool " + strings.Join(args, " "),
+			Stderr: stderr.String(),
+		}
+	}
+
+	return stdout.String(), nil
+}
+
+func (z *Zpool) Attach(target string, device string) error {
+	return z.z.zpool("attach", z.Name, target, device)
+}
+
+func (z *Zpool) Detach(device string) error {
+	return z.z.zpool("detach", z.Name, device)
+}
+
+func (z *Zpool) RemoveVdev(vdev string) error {
+	return z.z.zpool("remove", z.Name, vdev)
+}
@@ -0,0 +1,163 @@
+package zfs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Captured from zpool list -v -P -p tank: a raidz1 data vdev with a mirrored
+// log, a cache device and a hot spare.
+const zpoolListRaidzOutput = `NAME                   SIZE        ALLOC           FREE  CKPOINT  EXPANDSZ   FRAG    CAP  DEDUP    HEALTH  ALTROOT
+tank          5991496908800   1224998912  5990271909888        -         -      0      0   1.00    ONLINE  -
+  raidz1-0    5991496908800   1224998912  5990271909888        -         -      0      0      -    ONLINE
+    /dev/ada1 2000398934016            -              -        -         -      -      -      -    ONLINE
+    /dev/ada2 2000398934016            -              -        -         -      -      -      -    ONLINE
+    /dev/ada3 2000398934016            -              -        -         -      -      -      -    ONLINE
+logs                      -            -              -        -         -      -      -      -         -
+  mirror-1      16642998272       327680    16642670592        -         -      0      0      -    ONLINE
+    /dev/nda0p1 17179869184            -              -        -         -      -      -      -    ONLINE
+    /dev/nda1p1 17179869184            -              -        -         -      -      -      -    ONLINE
+cache                     -            -              -        -         -      -      -      -         -
+  /dev/nda0p2  256060514304      1032192   256059482112        -         -      0      0      -    ONLINE
+spares                    -            -              -        -         -      -      -      -         -
+  /dev/ada4               -            -              -        -         -      -      -      -     AVAIL
+`
+
+// Captured from zpool list -v -P -p zroot while a failed mirror member is
+// being replaced, with a mirrored special vdev.
+const zpoolListReplacingOutput = `NAME                     SIZE        ALLOC          FREE  CKPOINT  EXPANDSZ   FRAG    CAP  DEDUP    HEALTH  ALTROOT
+zroot            996432412672  50659999744  945772412928        -         -      1      5   1.00  DEGRADED  -
+  mirror-0       996432412672  50659999744  945772412928        -         -      1      5      -  DEGRADED
+    /dev/ada0p4  1000204886016           -             -        -         -      -      -      -    ONLINE
+    replacing-1              -           -             -        -         -      -      -      -  DEGRADED
+      /dev/ada1p4            -           -             -        -         -      -      -      -   UNAVAIL
+      /dev/ada2p4 1000204886016          -             -        -         -      -      -      -    ONLINE
+special                      -           -             -        -         -      -      -      -         -
+  mirror-1        255013683200   104857600  254908825600        -         -      0      0      -    ONLINE
+    /dev/nda0     256060514304           -             -        -         -      -      -      -    ONLINE
+    /dev/nda1     256060514304           -             -        -         -      -      -      -    ONLINE
+`
+
+func renderVdevs(vdevs []PoolVdev) string {
+	var parts []string
+	for _, v := range vdevs {
+		part := fmt.Sprintf("%s(%s,%d,%d,%s)", v.Name, v.Type, v.Size, v.Alloc, v.Health)
+		if len(v.Children) > 0 {
+			part += "[" + renderVdevs(v.Children) + "]"
+		}
+
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func TestVdevType(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"mirror-0", "mirror"},
+		{"mirror", "mirror"},
+		{"raidz-0", "raidz1"},
+		{"raidz1-0", "raidz1"},
+		{"raidz2-1", "raidz2"},
+		{"raidz3-12", "raidz3"},
+		{"draid1:4d:6c:0s-0", "draid1"},
+		{"draid2:8d:11c:1s-3", "draid2"},
+		{"draid:4d:6c:0s-0", "draid1"},
+		{"replacing-1", "replacing"},
+		{"spare-2", "spare"},
+		{"/dev/ada1", "disk"},
+		{"/dev/gpt/mirror-0", "disk"},
+		{"gpt/zfs-mirror-0", "disk"},
+		{"ada1", "disk"},
+		{"12837461928374619283", "disk"},
+	}
+
+	for _, tt := range tests {
+		if got := VdevType(tt.input); got != tt.expected {
+			t.Errorf("VdevType(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestParsePoolLayout(t *testing.T) {
+	tests := []struct {
+		pool     string
+		input    string
+		expected map[string]string
+	}{
+		{"tank", zpoolListRaidzOutput, map[string]string{
+			VdevClassData: "raidz1-0(raidz1,5991496908800,1224998912,ONLINE)[" +
+				"/dev/ada1(disk,2000398934016,0,ONLINE) /dev/ada2(disk,2000398934016,0,ONLINE) /dev/ada3(disk,2000398934016,0,ONLINE)]",
+			VdevClassLog: "mirror-1(mirror,16642998272,327680,ONLINE)[" +
+				"/dev/nda0p1(disk,17179869184,0,ONLINE) /dev/nda1p1(disk,17179869184,0,ONLINE)]",
+			VdevClassCache:   "/dev/nda0p2(disk,256060514304,1032192,ONLINE)",
+			VdevClassSpare:   "/dev/ada4(disk,0,0,AVAIL)",
+			VdevClassSpecial: "",
+			VdevClassDedup:   "",
+		}},
+		{"zroot", zpoolListReplacingOutput, map[string]string{
+			VdevClassData: "mirror-0(mirror,996432412672,50659999744,DEGRADED)[" +
+				"/dev/ada0p4(disk,1000204886016,0,ONLINE) replacing-1(replacing,0,0,DEGRADED)[" +
+				"/dev/ada1p4(disk,0,0,UNAVAIL) /dev/ada2p4(disk,1000204886016,0,ONLINE)]]",
+			VdevClassLog:   "",
+			VdevClassCache: "",
+			VdevClassSpare: "",
+			VdevClassSpecial: "mirror-1(mirror,255013683200,104857600,ONLINE)[" +
+				"/dev/nda0(disk,256060514304,0,ONLINE) /dev/nda1(disk,256060514304,0,ONLINE)]",
+			VdevClassDedup: "",
+		}},
+		{"tank", "", map[string]string{
+			VdevClassData: "", VdevClassLog: "", VdevClassCache: "",
+			VdevClassSpare: "", VdevClassSpecial: "", VdevClassDedup: "",
+		}},
+	}
+
+	for _, tt := range tests {
+		layout := parsePoolLayout(tt.pool, tt.input)
+		for class, want := range tt.expected {
+			vdevs := layout.Class(class)
+			if *vdevs == nil {
+				t.Errorf("parsePoolLayout(%s) %s = nil, want an empty list", tt.pool, class)
+				continue
+			}
+
+			if got := renderVdevs(*vdevs); got != want {
+				t.Errorf("parsePoolLayout(%s) %s = %s, want %s", tt.pool, class, got, want)
+			}
+		}
+	}
+}
+
+func TestPoolVdevRedundancy(t *testing.T) {
+	tank := parsePoolLayout("tank", zpoolListRaidzOutput)
+	zroot := parsePoolLayout("zroot", zpoolListReplacingOutput)
+
+	tests := []struct {
+		vdev     PoolVdev
+		expected int
+		leaves   int
+	}{
+		{tank.Data[0], 1, 3},
+		{tank.Log[0], 1, 2},
+		{tank.Cache[0], 0, 1},
+		{zroot.Data[0], 1, 3},
+		{zroot.Data[0].Children[1], 0, 2},
+		{PoolVdev{Name: "raidz2-0", Type: "raidz2"}, 2, 1},
+		{PoolVdev{Name: "draid3:8d:12c:0s-0", Type: "draid3"}, 3, 1},
+		{PoolVdev{Name: "mirror-0", Type: "mirror"}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.vdev.Redundancy(); got != tt.expected {
+			t.Errorf("%s.Redundancy() = %v, want %v", tt.vdev.Name, got, tt.expected)
+		}
+
+		if got := len(tt.vdev.Leaves()); got != tt.leaves {
+			t.Errorf("%s.Leaves() returned %d devices, want %d", tt.vdev.Name, got, tt.leaves)
+		}
+	}
+}
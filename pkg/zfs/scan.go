@@ -0,0 +1,127 @@
+package zfs
+
+import (
+	"strings"
+	"time"
+
+	"github.com/alchemillahq/sylve/pkg/utils"
+)
+
+const (
+	ScanStateNone     = "none"
+	ScanStateScanning = "scanning"
+	ScanStatePaused   = "paused"
+	ScanStateFinished = "finished"
+	ScanStateCanceled = "canceled"
+)
+
+// ScanStatus is the parsed scan: line of zpool status, which describes the
+// last (or running) scrub or resilver of a pool.
+type ScanStatus struct {
+	Function  string    `json:"function"`
+	State     string    `json:"state"`
+	Repaired  uint64    `json:"repaired"`
+	Errors    uint64    `json:"errors"`
+	Duration  int64     `json:"duration"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+}
+
+// zpool prints timestamps with ctime(3); GetZpoolStatus has already folded
+// the padding of single digit days into one space.
+const scanTimeLayout = "Mon Jan 2 15:04:05 2006"
+
+// ParseScan understands the scan lines printed by zpool status -p, e.g.
+//
+//	scrub repaired 0 in 00:00:01 with 0 errors on Sun Oct 19 12:00:00 2025
+//	resilvered 1048576 in 1 days 02:00:00 with 0 errors on Sun Oct 19 12:00:00 2025
+//	scrub in progress since Sun Oct 19 12:00:00 2025
+//	scrub canceled on Sun Oct 19 12:00:00 2025
+func ParseScan(scan string) ScanStatus {
+	status := ScanStatus{State: ScanStateNone}
+
+	fields := strings.Fields(scan)
+	if len(fields) < 2 || scan == "none requested" {
+		return status
+	}
+
+	status.Function = fields[0]
+	if status.Function == "resilvered" {
+		status.Function = "resilver"
+		fields = append([]string{"resilver", "repaired"}, fields[1:]...)
+	}
+
+	at := func(words []string) time.Time {
+		if len(words) < 5 {
+			return time.Time{}
+		}
+
+		t, err := time.ParseInLocation(scanTimeLayout, strings.Join(words[:5], " "), time.Local)
+		if err != nil {
+			return time.Time{}
+		}
+		return t
+	}
+
+	switch fields[1] {
+	case "in":
+		if len(fields) > 4 && fields[2] == "progress" && fields[3] == "since" {
+			status.State = ScanStateScanning
+			status.StartedAt = at(fields[4:])
+		}
+	case "paused":
+		status.State = ScanStatePaused
+		if len(fields) > 3 {
+			status.StartedAt = at(fields[3:])
+		}
+	case "canceled":
+		status.State = ScanStateCanceled
+		if len(fields) > 3 {
+			status.EndedAt = at(fields[3:])
+		}
+	case "repaired":
+		status.State = ScanStateFinished
+		rest := fields[2:]
+		if len(rest) > 0 {
+			status.Repaired = utils.StringToUint64(rest[0])
+			rest = rest[1:]
+		}
+
+		if len(rest) > 1 && rest[0] == "in" {
+			rest = rest[1:]
+			if len(rest) > 2 && rest[1] == "days" {
+				status.Duration = int64(utils.StringToUint64(rest[0])) * 86400
+				rest = rest[2:]
+			}
+			status.Duration += clockSeconds(rest[0])
+			rest = rest[1:]
+		}
+
+		if len(rest) > 2 && rest[0] == "with" {
+			status.Errors = utils.StringToUint64(rest[1])
+			rest = rest[3:]
+		}
+
+		if len(rest) > 1 && rest[0] == "on" {
+			status.EndedAt = at(rest[1:])
+		}
+
+		if !status.EndedAt.IsZero() {
+			status.StartedAt = status.EndedAt.Add(-time.Duration(status.Duration) * time.Second)
+		}
+	}
+
+	return status
+}
+
+func clockSeconds(clock string) int64 {
+	var total int64
+	for _, part := range strings.Split(clock, ":") {
+		total = total*60 + int64(utils.StringToUint64(part))
+	}
+	return total
+}
+
+func (z *Zpool) Trim() error {
+	return z.z.zpool("trim", z.Name)
+}
@@ -3,11 +3,9 @@ package zfs
 import (
 	"fmt"
 	"io"
-	"os"
 	"strconv"
 
 	"github.com/alchemillahq/sylve/pkg/exe"
-	"github.com/alchemillahq/sylve/pkg/utils"
 )
 
 type InodeType int
@@ -57,10 +55,20 @@ type ZFS interface {
 	GetZpool(name string) (*Zpool, error)
 	GetZpoolByGUID(guid string) (*Zpool, error)
 	ScrubPool(name string) error
+	ImportablePools(dirs ...string) ([]ImportablePool, error)
+	ImportPool(pool string, opts ImportOptions) error
+	GetPoolLayout(name string) (PoolLayout, error)
+	GetPoolFeature(pool string, feature string) string
 	CreateZpool(name string, properties map[string]string, args ...string) (*Zpool, error)
 	GetPoolIODelay(poolName string) (float64, error)
 	GetTotalIODelay() float64
+	GetDatasetIO() (map[string]DatasetIO, error)
 	SetZpoolProperty(pool string, property string, value string) error
+
+	EncryptionStatus(filter string) ([]EncryptionInfo, error)
+	LoadKey(name string, key []byte) error
+	UnloadKey(name string) error
+	ChangeKey(name string, format string, keylocation string, key []byte) error
 }
 
 func (z *zfs) do(arg ...string) error {
@@ -117,29 +125,11 @@ func (z *zfs) CreateVolume(name string, size uint64, properties map[string]strin
 	args[2] = "-V"
 	args[3] = strconv.FormatUint(size, 10)
 
-	if _, ok := properties["encryptionKey"]; ok {
-		if properties["encryptionKey"] != "" && properties["encryption"] != "off" {
-			if len([]byte(properties["encryptionKey"])) < 32 || len([]byte(properties["encryptionKey"])) > 512 {
-				return nil, fmt.Errorf("invalid_encryption_key_length")
-			}
-
-			seed := fmt.Sprintf("%s-%s", name, properties["encryptionKey"])
-			randomFile := fmt.Sprintf("/etc/zfs/keys/%s", utils.GenerateDeterministicUUID(seed))
-
-			if _, err := os.Stat(randomFile); err == nil {
-				return nil, fmt.Errorf("dont_reuse_encryption_keys")
-			}
-
-			if err := os.WriteFile(randomFile, []byte(properties["encryptionKey"]), 0600); err != nil {
-				return nil, fmt.Errorf("failed_to_write_encryption_key")
-			}
-
-			properties["keylocation"] = fmt.Sprintf("file://%s", randomFile)
-			properties["keyformat"] = "passphrase"
-		}
+	keyInput, err := prepareEncryption(name, properties)
+	if err != nil {
+		return nil, err
 	}
 
-	delete(properties, "encryptionKey")
 	delete(properties, "parent")
 	delete(properties, "size")
 
@@ -148,7 +138,7 @@ func (z *zfs) CreateVolume(name string, size uint64, properties map[string]strin
 	}
 
 	args = append(args, name)
-	if err := z.do(args...); err != nil {
+	if _, err := z.run(keyInput, nil, "zfs", args...); err != nil {
 		return nil, err
 	}
 
@@ -165,9 +155,8 @@ func (z *zfs) EditVolume(name string, props map[string]string) error {
 		return fmt.Errorf("no_properties_to_edit")
 	}
 
-	if _, ok := props["encryptionKey"]; ok {
-		delete(props, "encryptionKey")
-	}
+	delete(props, "encryptionKey")
+	delete(props, "storeKey")
 
 	if _, ok := props["quota"]; ok {
 		if props["quota"] == "" {
@@ -191,30 +180,11 @@ func (z *zfs) CreateFilesystem(name string, properties map[string]string) (*Data
 	args := make([]string, 1, 4)
 	args[0] = "create"
 
-	if _, ok := properties["encryptionKey"]; ok {
-		if properties["encryptionKey"] != "" && properties["encryption"] != "off" {
-			if len([]byte(properties["encryptionKey"])) < 32 || len([]byte(properties["encryptionKey"])) > 512 {
-				return nil, fmt.Errorf("invalid_encryption_key_length")
-			}
-
-			seed := fmt.Sprintf("%s-%s", name, properties["encryptionKey"])
-			randomFile := fmt.Sprintf("/etc/zfs/keys/%s", utils.GenerateDeterministicUUID(seed))
-
-			if _, err := os.Stat(randomFile); err == nil {
-				return nil, fmt.Errorf("dont_reuse_encryption_keys")
-			}
-
-			if err := os.WriteFile(randomFile, []byte(properties["encryptionKey"]), 0600); err != nil {
-				return nil, fmt.Errorf("failed_to_write_encryption_key")
-			}
-
-			properties["keylocation"] = fmt.Sprintf("file://%s", randomFile)
-			properties["keyformat"] = "passphrase"
-		}
+	keyInput, err := prepareEncryption(name, properties)
+	if err != nil {
+		return nil, err
 	}
 
-	delete(properties, "encryptionKey")
-
 	if _, ok := properties["quota"]; ok {
 		if properties["quota"] == "" {
 			delete(properties, "quota")
@@ -226,7 +196,7 @@ func (z *zfs) CreateFilesystem(name string, properties map[string]string) (*Data
 	}
 
 	args = append(args, name)
-	if err := z.do(args...); err != nil {
+	if _, err := z.run(keyInput, nil, "zfs", args...); err != nil {
 		return nil, err
 	}
 	return z.GetDataset(name)
@@ -242,9 +212,8 @@ func (z *zfs) EditFilesystem(name string, props map[string]string) error {
 		return fmt.Errorf("no_properties_to_edit")
 	}
 
-	if _, ok := props["encryptionKey"]; ok {
-		delete(props, "encryptionKey")
-	}
+	delete(props, "encryptionKey")
+	delete(props, "storeKey")
 
 	if _, ok := props["quota"]; ok {
 		if props["quota"] == "" || props["quota"] == "0B" {